@@ -13,6 +13,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/validators"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/sirupsen/logrus"
@@ -26,7 +27,7 @@ type Service struct {
 	ctx                  context.Context
 	cancel               context.CancelFunc
 	beaconDB             db.NoHeadAccessDatabase
-	headFetcher          blockchain.HeadFetcher
+	stateGen             *stategen.State
 	participationFetcher blockchain.ParticipationFetcher
 	stateNotifier        statefeed.Notifier
 	lastArchivedEpoch    uint64
@@ -35,7 +36,7 @@ type Service struct {
 // Config options for the archiver service.
 type Config struct {
 	BeaconDB             db.NoHeadAccessDatabase
-	HeadFetcher          blockchain.HeadFetcher
+	StateGen             *stategen.State
 	ParticipationFetcher blockchain.ParticipationFetcher
 	StateNotifier        statefeed.Notifier
 }
@@ -47,7 +48,7 @@ func NewArchiverService(ctx context.Context, cfg *Config) *Service {
 		ctx:                  ctx,
 		cancel:               cancel,
 		beaconDB:             cfg.BeaconDB,
-		headFetcher:          cfg.HeadFetcher,
+		stateGen:             cfg.StateGen,
 		participationFetcher: cfg.ParticipationFetcher,
 		stateNotifier:        cfg.StateNotifier,
 	}
@@ -149,9 +150,9 @@ func (s *Service) run(ctx context.Context) {
 			if event.Type == statefeed.BlockProcessed {
 				data := event.Data.(*statefeed.BlockProcessedData)
 				log.WithField("headRoot", fmt.Sprintf("%#x", data.BlockRoot)).Debug("Received block processed event")
-				headState, err := s.headFetcher.HeadState(ctx)
+				headState, err := s.stateGen.StateByRoot(ctx, data.BlockRoot)
 				if err != nil {
-					log.WithError(err).Error("Head state is not available")
+					log.WithError(err).Error("Post state of the processed block is not available")
 					continue
 				}
 				slot := headState.Slot()
@@ -18,6 +18,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/db"
 	dbutil "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/testutil"
@@ -40,8 +41,8 @@ func TestArchiverService_ReceivesBlockProcessedEvent(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	svc.headFetcher = &mock.ChainService{
-		State: st,
+	if err := beaconDB.SaveState(context.Background(), st, [32]byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
 	}
 
 	event := &feed.Event{
@@ -67,8 +68,8 @@ func TestArchiverService_OnlyArchiveAtEpochEnd(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	svc.headFetcher = &mock.ChainService{
-		State: st,
+	if err := beaconDB.SaveState(context.Background(), st, [32]byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
 	}
 	event := &feed.Event{
 		Type: statefeed.BlockProcessed,
@@ -117,8 +118,8 @@ func TestArchiverService_ArchivesEvenThroughSkipSlot(t *testing.T) {
 		if err := headState.SetSlot(i); err != nil {
 			t.Fatal(err)
 		}
-		svc.headFetcher = &mock.ChainService{
-			State: headState,
+		if err := beaconDB.SaveState(context.Background(), headState, [32]byte{1, 2, 3}); err != nil {
+			t.Fatal(err)
 		}
 		if helpers.IsEpochEnd(i) {
 			continue
@@ -153,8 +154,8 @@ func TestArchiverService_ComputesAndSavesParticipation(t *testing.T) {
 	}
 	svc, beaconDB := setupService(t)
 	defer dbutil.TeardownDB(t, beaconDB)
-	svc.headFetcher = &mock.ChainService{
-		State: headState,
+	if err := beaconDB.SaveState(context.Background(), headState, [32]byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
 	}
 	event := &feed.Event{
 		Type: statefeed.BlockProcessed,
@@ -194,8 +195,8 @@ func TestArchiverService_SavesIndicesAndBalances(t *testing.T) {
 	}
 	svc, beaconDB := setupService(t)
 	defer dbutil.TeardownDB(t, beaconDB)
-	svc.headFetcher = &mock.ChainService{
-		State: headState,
+	if err := beaconDB.SaveState(context.Background(), headState, [32]byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
 	}
 	event := &feed.Event{
 		Type: statefeed.BlockProcessed,
@@ -230,8 +231,8 @@ func TestArchiverService_SavesCommitteeInfo(t *testing.T) {
 	}
 	svc, beaconDB := setupService(t)
 	defer dbutil.TeardownDB(t, beaconDB)
-	svc.headFetcher = &mock.ChainService{
-		State: headState,
+	if err := beaconDB.SaveState(context.Background(), headState, [32]byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
 	}
 	event := &feed.Event{
 		Type: statefeed.BlockProcessed,
@@ -280,8 +281,8 @@ func TestArchiverService_SavesActivatedValidatorChanges(t *testing.T) {
 	}
 	svc, beaconDB := setupService(t)
 	defer dbutil.TeardownDB(t, beaconDB)
-	svc.headFetcher = &mock.ChainService{
-		State: headState,
+	if err := beaconDB.SaveState(context.Background(), headState, [32]byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
 	}
 	prevEpoch := helpers.PrevEpoch(headState)
 	delayedActEpoch := helpers.DelayedActivationExitEpoch(prevEpoch)
@@ -332,8 +333,8 @@ func TestArchiverService_SavesSlashedValidatorChanges(t *testing.T) {
 	}
 	svc, beaconDB := setupService(t)
 	defer dbutil.TeardownDB(t, beaconDB)
-	svc.headFetcher = &mock.ChainService{
-		State: headState,
+	if err := beaconDB.SaveState(context.Background(), headState, [32]byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
 	}
 	prevEpoch := helpers.PrevEpoch(headState)
 	val1, err := headState.ValidatorAtIndex(95)
@@ -383,8 +384,8 @@ func TestArchiverService_SavesExitedValidatorChanges(t *testing.T) {
 	}
 	svc, beaconDB := setupService(t)
 	defer dbutil.TeardownDB(t, beaconDB)
-	svc.headFetcher = &mock.ChainService{
-		State: headState,
+	if err := beaconDB.SaveState(context.Background(), headState, [32]byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
 	}
 	prevEpoch := helpers.PrevEpoch(headState)
 	val, err := headState.ValidatorAtIndex(95)
@@ -455,6 +456,7 @@ func setupService(t *testing.T) (*Service, db.Database) {
 	mockChainService := &mock.ChainService{}
 	return &Service{
 		beaconDB:      beaconDB,
+		stateGen:      stategen.New(beaconDB),
 		ctx:           ctx,
 		cancel:        cancel,
 		stateNotifier: mockChainService.StateNotifier(),
@@ -0,0 +1,68 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/blocks"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+)
+
+// BadBlockChecker defines the methods of chain service which keep track of the blocks that
+// failed processing.
+type BadBlockChecker interface {
+	IsBadBlock(root [32]byte) bool
+	MarkBadBlock(root [32]byte)
+}
+
+// IsBadBlock returns true if the block of the given root failed processing, or descends
+// from a block which did.
+func (s *Service) IsBadBlock(root [32]byte) bool {
+	return s.badBlocks.Has(root)
+}
+
+// MarkBadBlock records the root of an invalid block, so that the block and its descendants
+// are rejected without being processed.
+func (s *Service) MarkBadBlock(root [32]byte) {
+	s.badBlocks.Add(root)
+}
+
+// invalidBlockError is returned when a block fails processing because of its own content, as
+// opposed to a failure of the node such as a database error or a missing pre-state.
+type invalidBlockError struct {
+	error
+}
+
+// IsInvalidBlock returns true if the error, or the error it wraps, comes from a block which is
+// invalid. The peer which served the block is to blame for such an error.
+func IsInvalidBlock(err error) bool {
+	_, ok := errors.Cause(err).(invalidBlockError)
+	return ok
+}
+
+// invalidBlock records a block which failed its state transition and returns the error as an
+// invalid block error, unless the failure comes from the context being canceled. A block with
+// an invalid proposer signature is not recorded, as the signature is not part of its root.
+func (s *Service) invalidBlock(ctx context.Context, root [32]byte, slot uint64, err error) error {
+	if ctx.Err() != nil {
+		return err
+	}
+	if blocks.IsProposerSigError(err) {
+		return invalidBlockError{err}
+	}
+	log.WithField("slot", slot).WithField("root", fmt.Sprintf("%#x", bytesutil.Trunc(root[:]))).Debug("Marking block as bad")
+	s.MarkBadBlock(root)
+	return invalidBlockError{err}
+}
+
+// verifyParentNotBad rejects a block whose parent is a bad block, and marks the block itself as
+// bad so that its own descendants are rejected too.
+func (s *Service) verifyParentNotBad(b *ethpb.BeaconBlock, root [32]byte) error {
+	if !s.IsBadBlock(bytesutil.ToBytes32(b.ParentRoot)) {
+		return nil
+	}
+	s.MarkBadBlock(root)
+	return invalidBlockError{errors.Errorf("parent block %#x of block %d is a bad block", bytesutil.Trunc(b.ParentRoot), b.Slot)}
+}
@@ -27,6 +27,11 @@ type TimeFetcher interface {
 	CurrentSlot() uint64
 }
 
+// GenesisFetcher retrieves the data identifying the chain since genesis.
+type GenesisFetcher interface {
+	GenesisValidatorRoot() []byte
+}
+
 // HeadFetcher defines a common interface for methods in blockchain service which
 // directly retrieves head related data.
 type HeadFetcher interface {
@@ -147,13 +152,24 @@ func (s *Service) HeadBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, erro
 
 // HeadState returns the head state of the chain.
 // If the head state is nil from service struct,
-// it will attempt to get the head state from DB.
+// it will attempt to get the state of the DB head block from the state generator.
 func (s *Service) HeadState(ctx context.Context) (*state.BeaconState, error) {
 	if s.hasHeadState() {
 		return s.headState(), nil
 	}
 
-	return s.beaconDB.HeadState(ctx)
+	headBlock, err := s.beaconDB.HeadBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if headBlock == nil || headBlock.Block == nil {
+		return nil, nil
+	}
+	headRoot, err := ssz.HashTreeRoot(headBlock.Block)
+	if err != nil {
+		return nil, err
+	}
+	return s.stateGen.StateByRoot(ctx, headRoot)
 }
 
 // HeadValidatorsIndices returns a list of active validator indices from the head view of a given epoch.
@@ -178,6 +194,12 @@ func (s *Service) GenesisTime() time.Time {
 	return s.genesisTime
 }
 
+// GenesisValidatorRoot returns the hash tree root of the validators at genesis, which together with
+// the fork version identifies the chain on the network.
+func (s *Service) GenesisValidatorRoot() []byte {
+	return s.genesisValidatorsRoot[:]
+}
+
 // CurrentFork retrieves the latest fork information of the beacon chain.
 func (s *Service) CurrentFork() *pb.Fork {
 	if !s.hasHeadState() {
@@ -2,13 +2,18 @@ package blockchain
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain/metrics"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
 	"go.opencensus.io/trace"
 )
 
@@ -59,7 +64,7 @@ func (s *Service) saveHead(ctx context.Context, headRoot [32]byte) error {
 	// If the head state is not available, just return nil.
 	// There's nothing to cache
 	_, cached := s.initSyncState[headRoot]
-	if !cached && !s.beaconDB.HasState(ctx, headRoot) {
+	if !cached && !s.stateGen.HasState(ctx, headRoot) {
 		return nil
 	}
 
@@ -77,7 +82,7 @@ func (s *Service) saveHead(ctx context.Context, headRoot [32]byte) error {
 	var exists bool
 	newHeadState, exists = s.initSyncState[headRoot]
 	if !exists {
-		newHeadState, err = s.beaconDB.State(ctx, headRoot)
+		newHeadState, err = s.stateGen.StateByRoot(ctx, headRoot)
 		if err != nil {
 			return errors.Wrap(err, "could not retrieve head state in DB")
 		}
@@ -89,6 +94,12 @@ func (s *Service) saveHead(ctx context.Context, headRoot [32]byte) error {
 		return errors.New("cannot save nil head state")
 	}
 
+	// Keep track of the previous head, to tell whether the new head descends from it.
+	oldHeadRoot, oldHeadSlot := s.headRoot(), uint64(0)
+	if s.head != nil {
+		oldHeadSlot = s.headSlot()
+	}
+
 	// Cache the new head info.
 	s.setHead(headRoot, newHeadBlock, newHeadState)
 
@@ -97,9 +108,104 @@ func (s *Service) saveHead(ctx context.Context, headRoot [32]byte) error {
 		return errors.Wrap(err, "could not save head root in DB")
 	}
 
+	s.notifyNewHead(ctx, oldHeadRoot, oldHeadSlot, headRoot, newHeadBlock.Block)
+
 	return nil
 }
 
+// This notifies the state feed subscribers that the head changed. If the new head does not
+// descend from the previous head, the reorg is reported before the new head.
+func (s *Service) notifyNewHead(ctx context.Context, oldRoot [32]byte, oldSlot uint64, newRoot [32]byte, newBlock *ethpb.BeaconBlock) {
+	// Most of the time the new head is a child of the previous head, which is not a reorg.
+	if oldRoot != params.BeaconConfig().ZeroHash && bytesutil.ToBytes32(newBlock.ParentRoot) != oldRoot {
+		ancestorRoot, ancestorSlot, err := s.commonAncestor(ctx, oldRoot, newRoot)
+		if err != nil {
+			log.WithError(err).Debug("Could not find common ancestor of previous and new head")
+		} else if ancestorRoot != oldRoot {
+			s.reportReorg(&statefeed.ReorgData{
+				OldHeadSlot:        oldSlot,
+				OldHeadRoot:        oldRoot,
+				NewHeadSlot:        newBlock.Slot,
+				NewHeadRoot:        newRoot,
+				CommonAncestorSlot: ancestorSlot,
+				CommonAncestorRoot: ancestorRoot,
+				Depth:              oldSlot - ancestorSlot,
+			})
+		}
+	}
+
+	s.stateNotifier.StateFeed().Send(&feed.Event{
+		Type: statefeed.NewHead,
+		Data: &statefeed.NewHeadData{
+			Slot:              newBlock.Slot,
+			BlockRoot:         newRoot,
+			PreviousBlockRoot: oldRoot,
+		},
+	})
+}
+
+// This logs and records the metrics of a reorg, and notifies the state feed subscribers of it.
+func (s *Service) reportReorg(data *statefeed.ReorgData) {
+	log.WithFields(logrus.Fields{
+		"oldHeadSlot":        data.OldHeadSlot,
+		"oldHeadRoot":        fmt.Sprintf("%#x", bytesutil.Trunc(data.OldHeadRoot[:])),
+		"newHeadSlot":        data.NewHeadSlot,
+		"newHeadRoot":        fmt.Sprintf("%#x", bytesutil.Trunc(data.NewHeadRoot[:])),
+		"commonAncestorSlot": data.CommonAncestorSlot,
+		"depth":              data.Depth,
+	}).Warn("Chain reorg occurred")
+	metrics.ReorgCount.Inc()
+	metrics.ReorgDepth.Observe(float64(data.Depth))
+
+	s.stateNotifier.StateFeed().Send(&feed.Event{
+		Type: statefeed.Reorg,
+		Data: data,
+	})
+}
+
+// This returns the root and slot of the latest block both blocks descend from. It walks back
+// the chains of both blocks in the DB, stepping back the chain with the later block first.
+func (s *Service) commonAncestor(ctx context.Context, a [32]byte, b [32]byte) ([32]byte, uint64, error) {
+	block := func(root [32]byte) (*ethpb.BeaconBlock, error) {
+		signed, err := s.beaconDB.Block(ctx, root)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get block")
+		}
+		if signed == nil || signed.Block == nil {
+			return nil, fmt.Errorf("block %#x is not in the db", bytesutil.Trunc(root[:]))
+		}
+		return signed.Block, nil
+	}
+
+	aBlock, err := block(a)
+	if err != nil {
+		return [32]byte{}, 0, err
+	}
+	bBlock, err := block(b)
+	if err != nil {
+		return [32]byte{}, 0, err
+	}
+	for a != b {
+		if ctx.Err() != nil {
+			return [32]byte{}, 0, ctx.Err()
+		}
+		aSlot, bSlot := aBlock.Slot, bBlock.Slot
+		if aSlot >= bSlot {
+			a = bytesutil.ToBytes32(aBlock.ParentRoot)
+			if aBlock, err = block(a); err != nil {
+				return [32]byte{}, 0, err
+			}
+		}
+		if bSlot >= aSlot {
+			b = bytesutil.ToBytes32(bBlock.ParentRoot)
+			if bBlock, err = block(b); err != nil {
+				return [32]byte{}, 0, err
+			}
+		}
+	}
+	return a, aBlock.Slot, nil
+}
+
 // This gets called to update canonical root mapping. It does not save head block
 // root in DB. With the inception of inital-sync-cache-state flag, it uses finalized
 // check point as anchors to resume sync therefore head is no longer needed to be saved on per slot basis.
@@ -108,17 +214,15 @@ func (s *Service) saveHeadNoDB(ctx context.Context, b *ethpb.SignedBeaconBlock,
 		return errors.New("cannot save nil head block")
 	}
 
-	headState, err := s.beaconDB.State(ctx, r)
-	if err != nil {
-		return errors.Wrap(err, "could not retrieve head state in DB")
-	}
-	if headState == nil {
-		s.initSyncStateLock.RLock()
-		cachedHeadState, ok := s.initSyncState[r]
-		if ok {
-			headState = cachedHeadState
+	s.initSyncStateLock.RLock()
+	headState, cached := s.initSyncState[r]
+	s.initSyncStateLock.RUnlock()
+	if !cached {
+		var err error
+		headState, err = s.stateGen.StateByRoot(ctx, r)
+		if err != nil {
+			return errors.Wrap(err, "could not retrieve head state in DB")
 		}
-		s.initSyncStateLock.RUnlock()
 	}
 
 	if headState == nil {
@@ -8,6 +8,8 @@ import (
 
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
 	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
@@ -70,3 +72,73 @@ func TestSaveHead_Different(t *testing.T) {
 		t.Error("Head did not change")
 	}
 }
+
+func TestNotifyNewHead_Reorg(t *testing.T) {
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	service := setupBeaconChain(t, db)
+	ctx := context.Background()
+
+	// Build the tree below, where 2 is the previous head and 3 the new head.
+	//       0
+	//      / \
+	//     1   3
+	//     |
+	//     2
+	saveBlock := func(slot uint64, parentRoot [32]byte) ([32]byte, *ethpb.BeaconBlock) {
+		b := &ethpb.BeaconBlock{Slot: slot, ParentRoot: parentRoot[:]}
+		if err := db.SaveBlock(ctx, &ethpb.SignedBeaconBlock{Block: b}); err != nil {
+			t.Fatal(err)
+		}
+		r, err := ssz.HashTreeRoot(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r, b
+	}
+	r0, _ := saveBlock(0, [32]byte{})
+	r1, _ := saveBlock(1, r0)
+	r2, _ := saveBlock(2, r1)
+	r3, b3 := saveBlock(3, r0)
+
+	ancestor, slot, err := service.commonAncestor(ctx, r2, r3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ancestor != r0 || slot != 0 {
+		t.Errorf("Wanted common ancestor %#x at slot 0, received %#x at slot %d", r0, ancestor, slot)
+	}
+
+	stateChannel := make(chan *feed.Event, 2)
+	stateSub := service.stateNotifier.StateFeed().Subscribe(stateChannel)
+	defer stateSub.Unsubscribe()
+	service.notifyNewHead(ctx, r2, 2, r3, b3)
+
+	reorg := <-stateChannel
+	if reorg.Type != statefeed.Reorg {
+		t.Fatalf("Wanted reorg event, received event of type %d", reorg.Type)
+	}
+	wanted := &statefeed.ReorgData{
+		OldHeadSlot:        2,
+		OldHeadRoot:        r2,
+		NewHeadSlot:        3,
+		NewHeadRoot:        r3,
+		CommonAncestorSlot: 0,
+		CommonAncestorRoot: r0,
+		Depth:              2,
+	}
+	if !reflect.DeepEqual(reorg.Data, wanted) {
+		t.Errorf("Wanted reorg %+v, received %+v", wanted, reorg.Data)
+	}
+	newHead := <-stateChannel
+	if newHead.Type != statefeed.NewHead {
+		t.Fatalf("Wanted new head event, received event of type %d", newHead.Type)
+	}
+
+	// Moving the head to a descendant of the previous head is not a reorg.
+	r4, b4 := saveBlock(5, r3)
+	service.notifyNewHead(ctx, r3, 3, r4, b4)
+	if event := <-stateChannel; event.Type != statefeed.NewHead {
+		t.Errorf("Wanted new head event, received event of type %d", event.Type)
+	}
+}
@@ -163,13 +163,13 @@ func (s *Service) pruneOldNonFinalizedStates() {
 }
 
 func (s *Service) generateState(ctx context.Context, startRoot [32]byte, endRoot [32]byte) (*stateTrie.BeaconState, error) {
-	preState, err := s.beaconDB.State(ctx, startRoot)
+	if !s.stateGen.HasState(ctx, startRoot) {
+		return nil, errors.New("finalized state does not exist in db")
+	}
+	preState, err := s.stateGen.StateByRoot(ctx, startRoot)
 	if err != nil {
 		return nil, err
 	}
-	if preState == nil {
-		return nil, errors.New("finalized state does not exist in db")
-	}
 	endBlock, err := s.beaconDB.Block(ctx, endRoot)
 	if err != nil {
 		return nil, err
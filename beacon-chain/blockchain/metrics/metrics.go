@@ -24,6 +24,17 @@ var (
 		Name: "competing_blocks",
 		Help: "The # of blocks received and processed from a competing chain",
 	})
+	// ReorgCount is the number of times the head moved to a chain which does not descend from the previous head.
+	ReorgCount = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "beacon_reorg_total",
+		Help: "Count the number of times the head moved to a chain which does not descend from the previous head",
+	})
+	// ReorgDepth is the number of slots of the previous chain reverted by reorgs.
+	ReorgDepth = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "beacon_reorg_depth",
+		Help:    "The number of slots of the previous chain reverted by reorgs",
+		Buckets: []float64{1, 2, 4, 8, 16, 32, 64, 128},
+	})
 	headFinalizedEpoch = promauto.NewGauge(prometheus.GaugeOpts{
 		Name: "head_finalized_epoch",
 		Help: "Last finalized epoch of the head state",
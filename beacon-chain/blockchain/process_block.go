@@ -60,6 +60,14 @@ func (s *Service) onBlock(ctx context.Context, signed *ethpb.SignedBeaconBlock)
 
 	b := signed.Block
 
+	root, err := ssz.HashTreeRoot(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get signing root of block %d", b.Slot)
+	}
+	if err := s.verifyParentNotBad(b, root); err != nil {
+		return nil, err
+	}
+
 	// Retrieve incoming block's pre state.
 	preState, err := s.getBlockPreState(ctx, b)
 	if err != nil {
@@ -67,10 +75,6 @@ func (s *Service) onBlock(ctx context.Context, signed *ethpb.SignedBeaconBlock)
 	}
 	preStateValidatorCount := preState.NumValidators()
 
-	root, err := ssz.HashTreeRoot(b)
-	if err != nil {
-		return nil, errors.Wrapf(err, "could not get signing root of block %d", b.Slot)
-	}
 	log.WithFields(logrus.Fields{
 		"slot": b.Slot,
 		"root": fmt.Sprintf("0x%s...", hex.EncodeToString(root[:])[:8]),
@@ -78,7 +82,7 @@ func (s *Service) onBlock(ctx context.Context, signed *ethpb.SignedBeaconBlock)
 
 	postState, err := state.ExecuteStateTransition(ctx, preState, signed)
 	if err != nil {
-		return nil, errors.Wrap(err, "could not execute state transition")
+		return nil, s.invalidBlock(ctx, root, b.Slot, errors.Wrap(err, "could not execute state transition"))
 	}
 
 	if err := s.beaconDB.SaveBlock(ctx, signed); err != nil {
@@ -89,7 +93,7 @@ func (s *Service) onBlock(ctx context.Context, signed *ethpb.SignedBeaconBlock)
 		return nil, errors.Wrapf(err, "could not insert block %d to fork choice store", b.Slot)
 	}
 
-	if err := s.beaconDB.SaveState(ctx, postState, root); err != nil {
+	if err := s.stateGen.SaveState(ctx, postState, root); err != nil {
 		return nil, errors.Wrap(err, "could not save state")
 	}
 
@@ -102,25 +106,29 @@ func (s *Service) onBlock(ctx context.Context, signed *ethpb.SignedBeaconBlock)
 
 	// Update finalized check point. Prune the block cache and helper caches on every new finalized epoch.
 	if postState.FinalizedCheckpointEpoch() > s.finalizedCheckpt.Epoch {
+		finalizedRoot := bytesutil.ToBytes32(postState.FinalizedCheckpoint().Root)
+		if err := s.stateGen.ForceCheckpoint(ctx, finalizedRoot); err != nil {
+			return nil, errors.Wrap(err, "could not save finalized state")
+		}
 		if err := s.beaconDB.SaveFinalizedCheckpoint(ctx, postState.FinalizedCheckpoint()); err != nil {
 			return nil, errors.Wrap(err, "could not save finalized checkpoint")
 		}
 
-		startSlot := helpers.StartSlot(s.prevFinalizedCheckpt.Epoch)
-		endSlot := helpers.StartSlot(s.finalizedCheckpt.Epoch)
-		if endSlot > startSlot {
-			if err := s.rmStatesOlderThanLastFinalized(ctx, startSlot, endSlot); err != nil {
-				return nil, errors.Wrapf(err, "could not delete states prior to finalized check point, range: %d, %d",
-					startSlot, endSlot)
-			}
+		// Migrate the states before the new finalized checkpoint to the cold section.
+		if err := s.stateGen.MigrateToCold(ctx, finalizedRoot); err != nil {
+			return nil, errors.Wrap(err, "could not migrate states to the cold section")
 		}
 
 		// Prune proto array fork choice nodes, all nodes before finalized check point will
 		// be pruned.
 		s.forkChoiceStore.Prune(ctx, bytesutil.ToBytes32(postState.FinalizedCheckpoint().Root))
+		if err := s.saveForkChoiceSnapshot(ctx); err != nil {
+			log.WithError(err).Error("Could not save fork choice snapshot")
+		}
 
 		s.prevFinalizedCheckpt = s.finalizedCheckpt
 		s.finalizedCheckpt = postState.FinalizedCheckpoint()
+		s.notifyFinalizedCheckpoint(s.finalizedCheckpt)
 
 		if err := s.finalizedImpliesNewJustified(ctx, postState); err != nil {
 			return nil, errors.Wrap(err, "could not save new justified")
@@ -162,8 +170,8 @@ func (s *Service) onBlock(ctx context.Context, signed *ethpb.SignedBeaconBlock)
 }
 
 // onBlockInitialSyncStateTransition is called when an initial sync block is received.
-// It runs state transition on the block and without any BLS verification. The excluded BLS verification
-// includes attestation's aggregated signature. It also does not save attestations.
+// It runs state transition on the block and verifies all the BLS signatures of the block in a single
+// batch. It also does not save attestations.
 func (s *Service) onBlockInitialSyncStateTransition(ctx context.Context, signed *ethpb.SignedBeaconBlock) error {
 	ctx, span := trace.StartSpan(ctx, "blockchain.onBlock")
 	defer span.End()
@@ -174,6 +182,14 @@ func (s *Service) onBlockInitialSyncStateTransition(ctx context.Context, signed
 
 	b := signed.Block
 
+	root, err := ssz.HashTreeRoot(b)
+	if err != nil {
+		return errors.Wrapf(err, "could not get signing root of block %d", b.Slot)
+	}
+	if err := s.verifyParentNotBad(b, root); err != nil {
+		return err
+	}
+
 	s.initSyncStateLock.Lock()
 	defer s.initSyncStateLock.Unlock()
 
@@ -184,18 +200,17 @@ func (s *Service) onBlockInitialSyncStateTransition(ctx context.Context, signed
 	}
 	preStateValidatorCount := preState.NumValidators()
 
-	postState, err := state.ExecuteStateTransitionNoVerifyAttSigs(ctx, preState, signed)
+	batch, postState, err := state.ExecuteStateTransitionNoVerifySigs(ctx, preState, signed)
 	if err != nil {
-		return errors.Wrap(err, "could not execute state transition")
+		return s.invalidBlock(ctx, root, b.Slot, errors.Wrap(err, "could not execute state transition"))
+	}
+	if err := batch.Verify(); err != nil {
+		return s.invalidBlock(ctx, root, b.Slot, errors.Wrap(err, "could not verify block signatures"))
 	}
 
 	if err := s.beaconDB.SaveBlock(ctx, signed); err != nil {
 		return errors.Wrapf(err, "could not save block from slot %d", b.Slot)
 	}
-	root, err := ssz.HashTreeRoot(b)
-	if err != nil {
-		return errors.Wrapf(err, "could not get signing root of block %d", b.Slot)
-	}
 
 	if err := s.insertBlockToForkChoiceStore(ctx, b, root, postState); err != nil {
 		return errors.Wrapf(err, "could not insert block %d to fork choice store", b.Slot)
@@ -227,15 +242,6 @@ func (s *Service) onBlockInitialSyncStateTransition(ctx context.Context, signed
 
 	// Update finalized check point. Prune the block cache and helper caches on every new finalized epoch.
 	if postState.FinalizedCheckpointEpoch() > s.finalizedCheckpt.Epoch {
-		startSlot := helpers.StartSlot(s.prevFinalizedCheckpt.Epoch)
-		endSlot := helpers.StartSlot(s.finalizedCheckpt.Epoch)
-		if endSlot > startSlot {
-			if err := s.rmStatesOlderThanLastFinalized(ctx, startSlot, endSlot); err != nil {
-				return errors.Wrapf(err, "could not delete states prior to finalized check point, range: %d, %d",
-					startSlot, endSlot)
-			}
-		}
-
 		if err := s.saveInitState(ctx, postState); err != nil {
 			return errors.Wrap(err, "could not save init sync finalized state")
 		}
@@ -244,8 +250,14 @@ func (s *Service) onBlockInitialSyncStateTransition(ctx context.Context, signed
 			return errors.Wrap(err, "could not save finalized checkpoint")
 		}
 
+		// Migrate the states before the new finalized checkpoint to the cold section.
+		if err := s.stateGen.MigrateToCold(ctx, bytesutil.ToBytes32(postState.FinalizedCheckpoint().Root)); err != nil {
+			return errors.Wrap(err, "could not migrate states to the cold section")
+		}
+
 		s.prevFinalizedCheckpt = s.finalizedCheckpt
 		s.finalizedCheckpt = postState.FinalizedCheckpoint()
+		s.notifyFinalizedCheckpoint(s.finalizedCheckpt)
 
 		if err := s.finalizedImpliesNewJustified(ctx, postState); err != nil {
 			return errors.Wrap(err, "could not save new justified")
@@ -281,7 +293,7 @@ func (s *Service) onBlockInitialSyncStateTransition(ctx context.Context, signed
 		}
 
 		if helpers.IsEpochStart(postState.Slot()) {
-			if err := s.beaconDB.SaveState(ctx, postState, root); err != nil {
+			if err := s.stateGen.SaveState(ctx, postState, root); err != nil {
 				return errors.Wrap(err, "could not save state")
 			}
 		}
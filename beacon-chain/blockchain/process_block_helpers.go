@@ -9,8 +9,9 @@ import (
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
-	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
@@ -60,11 +61,7 @@ func (s *Service) verifyBlkPreState(ctx context.Context, b *ethpb.BeaconBlock) (
 	preState := s.initSyncState[bytesutil.ToBytes32(b.ParentRoot)]
 	var err error
 	if preState == nil {
-		preState, err = s.beaconDB.State(ctx, bytesutil.ToBytes32(b.ParentRoot))
-		if err != nil {
-			return nil, errors.Wrapf(err, "could not get pre state for slot %d", b.Slot)
-		}
-		if preState == nil {
+		if !s.stateGen.HasState(ctx, bytesutil.ToBytes32(b.ParentRoot)) {
 			if bytes.Equal(s.finalizedCheckpt.Root, b.ParentRoot) {
 				return nil, fmt.Errorf("pre state of slot %d does not exist", b.Slot)
 			}
@@ -72,6 +69,11 @@ func (s *Service) verifyBlkPreState(ctx context.Context, b *ethpb.BeaconBlock) (
 			if err != nil {
 				return nil, err
 			}
+			return preState, nil
+		}
+		preState, err = s.stateGen.StateByRoot(ctx, bytesutil.ToBytes32(b.ParentRoot))
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not get pre state for slot %d", b.Slot)
 		}
 		return preState, nil // No copy needed from newly hydrated DB object.
 	}
@@ -139,64 +141,6 @@ func (s *Service) saveNewValidators(ctx context.Context, preStateValidatorCount
 	return nil
 }
 
-// rmStatesOlderThanLastFinalized deletes the states in db since last finalized check point.
-func (s *Service) rmStatesOlderThanLastFinalized(ctx context.Context, startSlot uint64, endSlot uint64) error {
-	ctx, span := trace.StartSpan(ctx, "forkchoice.rmStatesBySlots")
-	defer span.End()
-
-	// Make sure start slot is not a skipped slot
-	for i := startSlot; i > 0; i-- {
-		filter := filters.NewFilter().SetStartSlot(i).SetEndSlot(i)
-		b, err := s.beaconDB.Blocks(ctx, filter)
-		if err != nil {
-			return err
-		}
-		if len(b) > 0 {
-			startSlot = i
-			break
-		}
-	}
-
-	// Make sure finalized slot is not a skipped slot.
-	for i := endSlot; i > 0; i-- {
-		filter := filters.NewFilter().SetStartSlot(i).SetEndSlot(i)
-		b, err := s.beaconDB.Blocks(ctx, filter)
-		if err != nil {
-			return err
-		}
-		if len(b) > 0 {
-			endSlot = i - 1
-			break
-		}
-	}
-
-	// Do not remove genesis state
-	if startSlot == 0 {
-		startSlot++
-	}
-	// If end slot comes less than start slot
-	if endSlot < startSlot {
-		endSlot = startSlot
-	}
-
-	filter := filters.NewFilter().SetStartSlot(startSlot).SetEndSlot(endSlot)
-	roots, err := s.beaconDB.BlockRoots(ctx, filter)
-	if err != nil {
-		return err
-	}
-
-	roots, err = s.filterBlockRoots(ctx, roots)
-	if err != nil {
-		return err
-	}
-
-	if err := s.beaconDB.DeleteStates(ctx, roots); err != nil {
-		return err
-	}
-
-	return nil
-}
-
 // shouldUpdateCurrentJustified prevents bouncing attack, by only update conflicting justified
 // checkpoints in the fork choice if in the early slots of the epoch.
 // Otherwise, delay incorporation of new justified checkpoint until next epoch boundary.
@@ -254,14 +198,11 @@ func (s *Service) updateJustified(ctx context.Context, state *stateTrie.BeaconSt
 	// If justified state is nil, resume back to normal syncing process and save
 	// justified check point.
 	if justifiedState == nil {
-		if s.beaconDB.HasState(ctx, justifiedRoot) {
-			return s.beaconDB.SaveJustifiedCheckpoint(ctx, cpt)
-		}
-		justifiedState, err = s.generateState(ctx, bytesutil.ToBytes32(s.finalizedCheckpt.Root), justifiedRoot)
-		if err != nil {
+		// The DB only accepts checkpoints whose states are saved in full.
+		if err := s.stateGen.ForceCheckpoint(ctx, justifiedRoot); err != nil {
 			log.Error(err)
-			return s.beaconDB.SaveJustifiedCheckpoint(ctx, cpt)
 		}
+		return s.beaconDB.SaveJustifiedCheckpoint(ctx, cpt)
 	}
 	if err := s.beaconDB.SaveState(ctx, justifiedState, justifiedRoot); err != nil {
 		return errors.Wrap(err, "could not save justified state")
@@ -277,19 +218,16 @@ func (s *Service) saveInitState(ctx context.Context, state *stateTrie.BeaconStat
 	finalizedRoot := bytesutil.ToBytes32(cpt.Root)
 	fs := s.initSyncState[finalizedRoot]
 	if fs == nil {
+		if s.stateGen.HasState(ctx, finalizedRoot) {
+			return s.stateGen.ForceCheckpoint(ctx, finalizedRoot)
+		}
 		var err error
-		fs, err = s.beaconDB.State(ctx, finalizedRoot)
+		fs, err = s.generateState(ctx, bytesutil.ToBytes32(s.prevFinalizedCheckpt.Root), finalizedRoot)
 		if err != nil {
+			// This might happen if the client was in sync and is now re-syncing for whatever reason.
+			log.Warn("Initial sync cache did not have finalized state root cached")
 			return err
 		}
-		if fs == nil {
-			fs, err = s.generateState(ctx, bytesutil.ToBytes32(s.prevFinalizedCheckpt.Root), finalizedRoot)
-			if err != nil {
-				// This might happen if the client was in sync and is now re-syncing for whatever reason.
-				log.Warn("Initial sync cache did not have finalized state root cached")
-				return err
-			}
-		}
 	}
 
 	if err := s.beaconDB.SaveState(ctx, fs, finalizedRoot); err != nil {
@@ -298,34 +236,6 @@ func (s *Service) saveInitState(ctx context.Context, state *stateTrie.BeaconStat
 	return nil
 }
 
-// This filters block roots that are not known as head root and finalized root in DB.
-// It serves as the last line of defence before we prune states.
-func (s *Service) filterBlockRoots(ctx context.Context, roots [][32]byte) ([][32]byte, error) {
-	f, err := s.beaconDB.FinalizedCheckpoint(ctx)
-	if err != nil {
-		return nil, err
-	}
-	fRoot := f.Root
-	h, err := s.beaconDB.HeadBlock(ctx)
-	if err != nil {
-		return nil, err
-	}
-	hRoot, err := ssz.HashTreeRoot(h.Block)
-	if err != nil {
-		return nil, err
-	}
-
-	filtered := make([][32]byte, 0, len(roots))
-	for _, root := range roots {
-		if bytes.Equal(root[:], fRoot[:]) || bytes.Equal(root[:], hRoot[:]) {
-			continue
-		}
-		filtered = append(filtered, root)
-	}
-
-	return filtered, nil
-}
-
 // ancestor returns the block root of an ancestry block from the input block root.
 //
 // Spec pseudocode definition:
@@ -368,6 +278,17 @@ func (s *Service) ancestor(ctx context.Context, root []byte, slot uint64) ([]byt
 	return s.ancestor(ctx, b.ParentRoot, slot)
 }
 
+// This notifies the state feed subscribers of a new finalized checkpoint.
+func (s *Service) notifyFinalizedCheckpoint(checkpoint *ethpb.Checkpoint) {
+	s.stateNotifier.StateFeed().Send(&feed.Event{
+		Type: statefeed.FinalizedCheckpoint,
+		Data: &statefeed.FinalizedCheckpointData{
+			Epoch:     checkpoint.Epoch,
+			BlockRoot: bytesutil.ToBytes32(checkpoint.Root),
+		},
+	})
+}
+
 // This updates justified check point in store, if the new justified is later than stored justified or
 // the store's justified is not in chain with finalized check point.
 //
@@ -4,6 +4,7 @@
 package blockchain
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"runtime"
@@ -33,6 +34,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/powchain"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"go.opencensus.io/trace"
@@ -56,6 +58,7 @@ type Service struct {
 	headLock               sync.RWMutex
 	stateNotifier          statefeed.Notifier
 	genesisRoot            [32]byte
+	genesisValidatorsRoot  [32]byte
 	epochParticipation     map[uint64]*precompute.Balance
 	epochParticipationLock sync.RWMutex
 	forkChoiceStore        f.ForkChoicer
@@ -72,6 +75,7 @@ type Service struct {
 	checkpointState        *cache.CheckpointStateCache
 	checkpointStateLock    sync.Mutex
 	stateGen               *stategen.State
+	badBlocks              *cache.BadBlockCache
 }
 
 // Config options for the service.
@@ -87,12 +91,17 @@ type Config struct {
 	MaxRoutines       int64
 	StateNotifier     statefeed.Notifier
 	ForkChoiceStore   f.ForkChoicer
+	StateGen          *stategen.State
 }
 
 // NewService instantiates a new block service instance that will
 // be registered into a running beacon node.
 func NewService(ctx context.Context, cfg *Config) (*Service, error) {
 	ctx, cancel := context.WithCancel(ctx)
+	stateGen := cfg.StateGen
+	if stateGen == nil {
+		stateGen = stategen.New(cfg.BeaconDB)
+	}
 	return &Service{
 		ctx:                ctx,
 		cancel:             cancel,
@@ -110,7 +119,8 @@ func NewService(ctx context.Context, cfg *Config) (*Service, error) {
 		initSyncState:      make(map[[32]byte]*stateTrie.BeaconState),
 		boundaryRoots:      [][32]byte{},
 		checkpointState:    cache.NewCheckpointStateCache(),
-		stateGen:           stategen.New(cfg.BeaconDB),
+		stateGen:           stateGen,
+		badBlocks:          cache.NewBadBlockCache(),
 	}, nil
 }
 
@@ -129,8 +139,8 @@ func (s *Service) Start() {
 	if err != nil {
 		log.Fatalf("Could not fetch finalized cp: %v", err)
 	}
-	if beaconState == nil {
-		beaconState, err = s.beaconDB.State(ctx, bytesutil.ToBytes32(cp.Root))
+	if beaconState == nil && s.stateGen.HasState(ctx, bytesutil.ToBytes32(cp.Root)) {
+		beaconState, err = s.stateGen.StateByRoot(ctx, bytesutil.ToBytes32(cp.Root))
 		if err != nil {
 			log.Fatalf("Could not fetch beacon state: %v", err)
 		}
@@ -158,7 +168,19 @@ func (s *Service) Start() {
 		s.bestJustifiedCheckpt = stateTrie.CopyCheckpoint(justifiedCheckpoint)
 		s.finalizedCheckpt = stateTrie.CopyCheckpoint(finalizedCheckpoint)
 		s.prevFinalizedCheckpt = stateTrie.CopyCheckpoint(finalizedCheckpoint)
-		s.resumeForkChoice(justifiedCheckpoint, finalizedCheckpoint)
+		store, err := s.restoreForkChoice(ctx, finalizedCheckpoint)
+		if err != nil {
+			log.WithError(err).Warn("Could not restore fork choice snapshot, rebuilding fork choice from the finalized checkpoint")
+		}
+		if store != nil {
+			s.forkChoiceStore = store
+			log.WithField("nodes", len(store.Nodes())).Info("Restored fork choice from snapshot")
+		} else {
+			s.resumeForkChoice(justifiedCheckpoint, finalizedCheckpoint)
+			if err := s.insertAnchorIntoForkChoice(ctx, justifiedCheckpoint, finalizedCheckpoint); err != nil {
+				log.Fatalf("Could not insert checkpoint sync anchor into fork choice: %v", err)
+			}
+		}
 
 		if finalizedCheckpoint.Epoch > 1 {
 			if err := s.pruneGarbageState(ctx, helpers.StartSlot(finalizedCheckpoint.Epoch)-params.BeaconConfig().SlotsPerEpoch); err != nil {
@@ -169,7 +191,8 @@ func (s *Service) Start() {
 		s.stateNotifier.StateFeed().Send(&feed.Event{
 			Type: statefeed.Initialized,
 			Data: &statefeed.InitializedData{
-				StartTime: s.genesisTime,
+				StartTime:             s.genesisTime,
+				GenesisValidatorsRoot: s.genesisValidatorsRoot[:],
 			},
 		})
 	} else {
@@ -215,7 +238,8 @@ func (s *Service) processChainStartTime(ctx context.Context, genesisTime time.Ti
 	s.stateNotifier.StateFeed().Send(&feed.Event{
 		Type: statefeed.Initialized,
 		Data: &statefeed.InitializedData{
-			StartTime: genesisTime,
+			StartTime:             genesisTime,
+			GenesisValidatorsRoot: s.genesisValidatorsRoot[:],
 		},
 	})
 }
@@ -261,9 +285,34 @@ func (s *Service) initializeBeaconChain(
 // Stop the blockchain service's main event loop and associated goroutines.
 func (s *Service) Stop() error {
 	defer s.cancel()
+	if err := s.saveForkChoiceSnapshot(s.ctx); err != nil {
+		log.WithError(err).Error("Could not save fork choice snapshot")
+	}
+	// The head state is saved once on shutdown so that it can be read from the DB on restart.
+	if err := s.CheckpointHeadState(s.ctx); err != nil {
+		log.WithError(err).Error("Could not save head state")
+	}
 	return nil
 }
 
+// CheckpointHeadState saves the head state in full in the DB. Hot states are only saved in full at
+// epoch boundaries, so this is needed before the DB is read without the chain service, such as on
+// restart or from a backup.
+func (s *Service) CheckpointHeadState(ctx context.Context) error {
+	s.headLock.RLock()
+	if s.head == nil || s.head.state == nil {
+		s.headLock.RUnlock()
+		return nil
+	}
+	root, st := s.head.root, s.head.state.Copy()
+	s.headLock.RUnlock()
+
+	if s.beaconDB.HasState(ctx, root) {
+		return nil
+	}
+	return s.beaconDB.SaveState(ctx, st, root)
+}
+
 // Status always returns nil unless there is an error condition that causes
 // this service to be unhealthy.
 func (s *Service) Status() error {
@@ -318,6 +367,13 @@ func (s *Service) saveGenesisData(ctx context.Context, genesisState *stateTrie.B
 	if err := s.saveGenesisValidators(ctx, genesisState); err != nil {
 		return errors.Wrap(err, "could not save genesis validators")
 	}
+	s.genesisValidatorsRoot, err = stateutil.ValidatorRegistryRoot(genesisState.Validators())
+	if err != nil {
+		return errors.Wrap(err, "could not get genesis validators root")
+	}
+	if err := s.beaconDB.SaveGenesisValidatorsRoot(ctx, s.genesisValidatorsRoot); err != nil {
+		return errors.Wrap(err, "could not save genesis validators root")
+	}
 
 	genesisCheckpoint := &ethpb.Checkpoint{Root: genesisBlkRoot[:]}
 
@@ -357,6 +413,18 @@ func (s *Service) initializeChainInfo(ctx context.Context) error {
 	}
 	s.genesisRoot = genesisBlkRoot
 
+	// The genesis validators root identifies the chain on the network. It cannot be computed from
+	// the genesis state of the database, which is the anchor state of a database started from a
+	// checkpoint.
+	genesisValidatorsRoot, err := s.beaconDB.GenesisValidatorsRoot(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get genesis validators root from db")
+	}
+	if len(genesisValidatorsRoot) != 32 {
+		return errors.New("no genesis validators root in db")
+	}
+	s.genesisValidatorsRoot = bytesutil.ToBytes32(genesisValidatorsRoot)
+
 	if flags.Get().UnsafeSync {
 		headBlock, err := s.beaconDB.HeadBlock(ctx)
 		if err != nil {
@@ -366,7 +434,7 @@ func (s *Service) initializeChainInfo(ctx context.Context) error {
 		if err != nil {
 			return errors.Wrap(err, "could not hash head block")
 		}
-		headState, err := s.beaconDB.HeadState(ctx)
+		headState, err := s.stateGen.StateByRoot(ctx, headRoot)
 		if err != nil {
 			return errors.Wrap(err, "could not retrieve head state")
 		}
@@ -383,7 +451,7 @@ func (s *Service) initializeChainInfo(ctx context.Context) error {
 		// would be the genesis state and block.
 		return errors.New("no finalized epoch in the database")
 	}
-	finalizedState, err := s.beaconDB.State(ctx, bytesutil.ToBytes32(finalized.Root))
+	finalizedState, err := s.stateGen.StateByRoot(ctx, bytesutil.ToBytes32(finalized.Root))
 	if err != nil {
 		return errors.Wrap(err, "could not get finalized state from db")
 	}
@@ -423,6 +491,99 @@ func (s *Service) resumeForkChoice(justifiedCheckpoint *ethpb.Checkpoint, finali
 	s.forkChoiceStore = store
 }
 
+// This saves a snapshot of the fork choice store to the DB, so that fork choice resumes with all the
+// known branches and latest votes after a restart.
+func (s *Service) saveForkChoiceSnapshot(ctx context.Context) error {
+	if s.forkChoiceStore == nil {
+		return nil
+	}
+	snapshot := s.forkChoiceStore.Snapshot()
+	// Nothing to persist before the chain has started.
+	if len(snapshot.Nodes) == 0 {
+		return nil
+	}
+	return s.beaconDB.SaveForkChoiceSnapshot(ctx, snapshot)
+}
+
+// This restores the fork choice store from the snapshot saved in the DB. The snapshot is only used
+// if the finalized block and the head block of the DB are among its nodes, and every node matches a
+// block in the DB. It returns nil if no snapshot was saved, and an error if the snapshot is stale or
+// corrupt.
+func (s *Service) restoreForkChoice(ctx context.Context, finalizedCheckpoint *ethpb.Checkpoint) (*protoarray.ForkChoice, error) {
+	snapshot, err := s.beaconDB.ForkChoiceSnapshot(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get fork choice snapshot")
+	}
+	if snapshot == nil {
+		return nil, nil
+	}
+	store, err := protoarray.Restore(snapshot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not restore fork choice snapshot")
+	}
+	if !store.HasNode(bytesutil.ToBytes32(finalizedCheckpoint.Root)) {
+		return nil, fmt.Errorf("finalized block %#x is not in the snapshot", finalizedCheckpoint.Root)
+	}
+	// Blocks processed after the snapshot was saved are missing from it, the head saved with the
+	// last of them is not in the snapshot then.
+	headBlock, err := s.beaconDB.HeadBlock(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get head block")
+	}
+	if headBlock == nil || headBlock.Block == nil {
+		return nil, errors.New("no head block in db")
+	}
+	headRoot, err := ssz.HashTreeRoot(headBlock.Block)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not hash head block")
+	}
+	if !store.HasNode(headRoot) {
+		return nil, fmt.Errorf("head block %#x is not in the snapshot", headRoot)
+	}
+	for _, node := range snapshot.Nodes {
+		b, err := s.beaconDB.Block(ctx, bytesutil.ToBytes32(node.Root))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get block")
+		}
+		if b == nil || b.Block == nil {
+			return nil, fmt.Errorf("block %#x of the snapshot is not in the db", node.Root)
+		}
+		if b.Block.Slot != node.Slot {
+			return nil, fmt.Errorf("block %#x has slot %d in the snapshot and %d in the db", node.Root, node.Slot, b.Block.Slot)
+		}
+		if node.Parent < uint64(len(snapshot.Nodes)) && !bytes.Equal(b.Block.ParentRoot, snapshot.Nodes[node.Parent].Root) {
+			return nil, fmt.Errorf("block %#x has a different parent in the snapshot and in the db", node.Root)
+		}
+	}
+	return store, nil
+}
+
+// This is called when a client starts from non-genesis slot. If the node was started by checkpoint sync
+// and the anchor block is still the finalized block, it is inserted into the fork choice store as the
+// root of the block tree, as none of its ancestors are in the DB.
+func (s *Service) insertAnchorIntoForkChoice(ctx context.Context, justifiedCheckpoint *ethpb.Checkpoint, finalizedCheckpoint *ethpb.Checkpoint) error {
+	anchor, err := s.beaconDB.AnchorBlock(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get anchor block")
+	}
+	if anchor == nil || anchor.Block == nil {
+		return nil
+	}
+	anchorRoot, err := ssz.HashTreeRoot(anchor.Block)
+	if err != nil {
+		return errors.Wrap(err, "could not hash anchor block")
+	}
+	if !bytes.Equal(anchorRoot[:], finalizedCheckpoint.Root) {
+		return nil
+	}
+	return s.forkChoiceStore.ProcessBlock(ctx,
+		anchor.Block.Slot,
+		anchorRoot,
+		bytesutil.ToBytes32(anchor.Block.ParentRoot),
+		justifiedCheckpoint.Epoch,
+		finalizedCheckpoint.Epoch)
+}
+
 // This returns true if block has been processed before. Two ways to verify the block has been processed:
 // 1.) Check fork choice store.
 // 2.) Check DB.
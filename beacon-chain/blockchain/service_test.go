@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"io/ioutil"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,6 +28,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
 	"github.com/prysmaticlabs/prysm/beacon-chain/powchain"
 	beaconstate "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
 	protodb "github.com/prysmaticlabs/prysm/proto/beacon/db"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/event"
@@ -239,6 +241,9 @@ func TestChainStartStop_Initialized(t *testing.T) {
 	if err := db.SaveGenesisBlockRoot(ctx, blkRoot); err != nil {
 		t.Fatal(err)
 	}
+	if err := db.SaveGenesisValidatorsRoot(ctx, [32]byte{'v'}); err != nil {
+		t.Fatal(err)
+	}
 	if err := db.SaveJustifiedCheckpoint(ctx, &ethpb.Checkpoint{Root: blkRoot[:]}); err != nil {
 		t.Fatal(err)
 	}
@@ -315,6 +320,126 @@ func TestChainService_InitializeBeaconChain(t *testing.T) {
 	if bc.headRoot() == params.BeaconConfig().ZeroHash {
 		t.Error("Canonical root for slot 0 can't be zeros after initialize beacon chain")
 	}
+	validatorsRoot, err := stateutil.ValidatorRegistryRoot(s.Validators())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bc.GenesisValidatorRoot(), validatorsRoot[:]) {
+		t.Errorf("Wanted genesis validators root %#x, received %#x", validatorsRoot, bc.GenesisValidatorRoot())
+	}
+}
+
+func TestChainService_InsertAnchorIntoForkChoice(t *testing.T) {
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	ctx := context.Background()
+
+	anchor := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: params.BeaconConfig().SlotsPerEpoch * 2}}
+	anchorRoot, err := ssz.HashTreeRoot(anchor.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkpoint := &ethpb.Checkpoint{Epoch: 2, Root: anchorRoot[:]}
+	c := &Service{beaconDB: db, forkChoiceStore: protoarray.New(2, 2, anchorRoot)}
+
+	// Nodes started from genesis have no anchor.
+	if err := c.insertAnchorIntoForkChoice(ctx, checkpoint, checkpoint); err != nil {
+		t.Fatal(err)
+	}
+	if c.forkChoiceStore.HasNode(anchorRoot) {
+		t.Error("Expected no node to be inserted without an anchor block")
+	}
+
+	if err := db.SaveBlock(ctx, anchor); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveAnchorBlockRoot(ctx, anchorRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.insertAnchorIntoForkChoice(ctx, checkpoint, checkpoint); err != nil {
+		t.Fatal(err)
+	}
+	if !c.forkChoiceStore.HasNode(anchorRoot) {
+		t.Error("Expected the anchor block to be inserted into fork choice")
+	}
+}
+
+func TestChainService_SaveAndRestoreForkChoice(t *testing.T) {
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	ctx := context.Background()
+
+	c := &Service{beaconDB: db, forkChoiceStore: protoarray.New(0, 0, [32]byte{})}
+	store, err := c.restoreForkChoice(ctx, &ethpb.Checkpoint{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store != nil {
+		t.Error("Expected no fork choice to be restored without a snapshot")
+	}
+
+	// Save a chain of three blocks, the first of which is finalized.
+	var roots [][32]byte
+	parentRoot := [32]byte{}
+	for slot := uint64(0); slot < 3; slot++ {
+		b := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: slot, ParentRoot: parentRoot[:]}}
+		if err := db.SaveBlock(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+		r, err := ssz.HashTreeRoot(b.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.forkChoiceStore.ProcessBlock(ctx, slot, r, parentRoot, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+		roots = append(roots, r)
+		parentRoot = r
+	}
+	c.forkChoiceStore.ProcessAttestation(ctx, []uint64{0}, roots[2], 0)
+	if err := c.saveForkChoiceSnapshot(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The snapshot is stale if a block processed after it was saved is the head.
+	finalized := &ethpb.Checkpoint{Root: roots[0][:]}
+	later := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 3, ParentRoot: parentRoot[:]}}
+	if err := db.SaveBlock(ctx, later); err != nil {
+		t.Fatal(err)
+	}
+	laterRoot, err := ssz.HashTreeRoot(later.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveHeadBlockRoot(ctx, laterRoot); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.restoreForkChoice(ctx, finalized); err == nil {
+		t.Error("Expected an error restoring a snapshot without the head block")
+	}
+
+	if err := db.SaveHeadBlockRoot(ctx, roots[2]); err != nil {
+		t.Fatal(err)
+	}
+	store, err = c.restoreForkChoice(ctx, finalized)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.Nodes()) != 3 || !store.HasNode(roots[2]) {
+		t.Errorf("Expected the restored store to have the 3 saved nodes, received %d", len(store.Nodes()))
+	}
+
+	// The snapshot is stale if it does not know the finalized block.
+	if _, err := c.restoreForkChoice(ctx, &ethpb.Checkpoint{Epoch: 1, Root: []byte{'a'}}); err == nil {
+		t.Error("Expected an error restoring a snapshot without the finalized block")
+	}
+	// The snapshot is corrupt if one of its blocks is not in the DB.
+	if err := db.DeleteBlock(ctx, roots[2]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.restoreForkChoice(ctx, finalized); err == nil {
+		t.Error("Expected an error restoring a snapshot with a block missing from the DB")
+	}
 }
 
 func TestChainService_InitializeChainInfo(t *testing.T) {
@@ -357,9 +482,19 @@ func TestChainService_InitializeChainInfo(t *testing.T) {
 		t.Fatal(err)
 	}
 	c := &Service{beaconDB: db}
+	if err := c.initializeChainInfo(ctx); err == nil || !strings.Contains(err.Error(), "no genesis validators root") {
+		t.Fatalf("Expected initializing without a genesis validators root to fail, received %v", err)
+	}
+	validatorsRoot := [32]byte{'v'}
+	if err := db.SaveGenesisValidatorsRoot(ctx, validatorsRoot); err != nil {
+		t.Fatal(err)
+	}
 	if err := c.initializeChainInfo(ctx); err != nil {
 		t.Fatal(err)
 	}
+	if !bytes.Equal(c.GenesisValidatorRoot(), validatorsRoot[:]) {
+		t.Errorf("Wanted genesis validators root %#x, received %#x", validatorsRoot, c.GenesisValidatorRoot())
+	}
 	headBlk, err := c.HeadBlock(ctx)
 	if err != nil {
 		t.Fatal(err)
@@ -414,6 +549,42 @@ func TestChainService_SaveHeadNoDB(t *testing.T) {
 	}
 }
 
+func TestChainService_CheckpointHeadState(t *testing.T) {
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	ctx := context.Background()
+	s := &Service{
+		beaconDB: db,
+	}
+	if err := s.CheckpointHeadState(ctx); err != nil {
+		t.Fatalf("Could not checkpoint without a head: %v", err)
+	}
+
+	b := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 1}}
+	r, err := ssz.HashTreeRoot(b.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := beaconstate.InitializeFromProto(&pb.BeaconState{Slot: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.setHead(r, b, st)
+	if db.HasState(ctx, r) {
+		t.Fatal("Expected the head state not to be saved yet")
+	}
+	if err := s.CheckpointHeadState(ctx); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := db.State(ctx, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved == nil || saved.Slot() != 1 {
+		t.Error("Expected the head state to be saved")
+	}
+}
+
 func TestChainService_PruneOldStates(t *testing.T) {
 	db := testDB.SetupDB(t)
 	defer testDB.TeardownDB(t, db)
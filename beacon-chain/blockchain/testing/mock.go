@@ -16,6 +16,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/db"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
 	"github.com/prysmaticlabs/prysm/shared/event"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/sirupsen/logrus"
@@ -32,12 +33,14 @@ type ChainService struct {
 	BlocksReceived              []*ethpb.SignedBeaconBlock
 	Balance                     *precompute.Balance
 	Genesis                     time.Time
+	ValidatorsRoot              [32]byte
 	Fork                        *pb.Fork
 	DB                          db.Database
 	stateNotifier               statefeed.Notifier
 	blockNotifier               blockfeed.Notifier
 	opNotifier                  opfeed.Notifier
 	ValidAttestation            bool
+	BadBlocks                   map[[32]byte]bool
 }
 
 // StateNotifier mocks the same method in the chain service.
@@ -217,6 +220,11 @@ func (ms *ChainService) GenesisTime() time.Time {
 	return ms.Genesis
 }
 
+// GenesisValidatorRoot mocks the same method in the chain service.
+func (ms *ChainService) GenesisValidatorRoot() []byte {
+	return ms.ValidatorsRoot[:]
+}
+
 // CurrentSlot mocks the same method in the chain service.
 func (ms *ChainService) CurrentSlot() uint64 {
 	return 0
@@ -232,5 +240,27 @@ func (ms *ChainService) IsValidAttestation(ctx context.Context, att *ethpb.Attes
 	return ms.ValidAttestation
 }
 
+// AttestationSignatureBatch returns an empty signature batch, or an error when attestations are
+// not set as valid.
+func (ms *ChainService) AttestationSignatureBatch(ctx context.Context, att *ethpb.Attestation) (*bls.SignatureBatch, error) {
+	if !ms.ValidAttestation {
+		return nil, errors.New("invalid attestation")
+	}
+	return bls.NewSignatureBatch(), nil
+}
+
+// IsBadBlock mocks the same method in the chain service.
+func (ms *ChainService) IsBadBlock(root [32]byte) bool {
+	return ms.BadBlocks[root]
+}
+
+// MarkBadBlock mocks the same method in the chain service.
+func (ms *ChainService) MarkBadBlock(root [32]byte) {
+	if ms.BadBlocks == nil {
+		ms.BadBlocks = make(map[[32]byte]bool)
+	}
+	ms.BadBlocks[root] = true
+}
+
 // ClearCachedStates does nothing.
 func (ms *ChainService) ClearCachedStates() {}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// maxBadBlocksSize defines the max number of invalid block roots the bad block cache can contain.
+	maxBadBlocksSize = 1024
+
+	// Metrics.
+	badBlocksAdded = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bad_block_cache_added_total",
+		Help: "The number of invalid block roots added to the bad block cache.",
+	})
+	badBlocksHit = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bad_block_cache_hit",
+		Help: "The number of blocks rejected because they or their parent are in the bad block cache.",
+	})
+)
+
+// BadBlockCache remembers the roots of the most recent blocks which failed processing, so that
+// the same invalid blocks and their descendants can be rejected without processing them again.
+type BadBlockCache struct {
+	cache *lru.Cache
+}
+
+// NewBadBlockCache creates a new bad block cache holding up to maxBadBlocksSize roots.
+func NewBadBlockCache() *BadBlockCache {
+	cache, err := lru.New(maxBadBlocksSize)
+	if err != nil {
+		panic(err)
+	}
+	return &BadBlockCache{cache: cache}
+}
+
+// Add a block root to the cache.
+func (c *BadBlockCache) Add(root [32]byte) {
+	if !c.cache.Contains(root) {
+		badBlocksAdded.Inc()
+	}
+	c.cache.Add(root, true)
+}
+
+// Has returns whether the block root is in the cache.
+func (c *BadBlockCache) Has(root [32]byte) bool {
+	if c.cache.Contains(root) {
+		badBlocksHit.Inc()
+		return true
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package cache
+
+import (
+	"testing"
+)
+
+func TestBadBlockCache_AddHas(t *testing.T) {
+	c := NewBadBlockCache()
+	root := [32]byte{'A'}
+	if c.Has(root) {
+		t.Error("Expected root not to be in the cache")
+	}
+	c.Add(root)
+	if !c.Has(root) {
+		t.Error("Expected root to be in the cache")
+	}
+}
+
+func TestBadBlockCache_IsBounded(t *testing.T) {
+	c := NewBadBlockCache()
+	for i := 0; i <= maxBadBlocksSize; i++ {
+		c.Add([32]byte{byte(i), byte(i >> 8)})
+	}
+	if c.Has([32]byte{}) {
+		t.Error("Expected the oldest root to be evicted")
+	}
+	last := [32]byte{byte(maxBadBlocksSize), byte(maxBadBlocksSize >> 8)}
+	if !c.Has(last) {
+		t.Error("Expected the latest root to be in the cache")
+	}
+}
@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/prysmaticlabs/prysm/shared/sliceutil"
+)
+
+// maxAttesterSubnetSlots defines the max number of slots the attester subnets cache holds
+// subnets for. Duties are requested for at most the current and the next epoch.
+var maxAttesterSubnetSlots = int(2 * params.BeaconConfig().SlotsPerEpoch)
+
+type persistentSubnets struct {
+	subnets []uint64
+	expiry  time.Time
+}
+
+// SubnetIDsCache holds the attestation subnets the validators connected to this node attest on.
+// Attester subnets are only needed around the slot of the attestation, while persistent subnets
+// are long lived random subnets of each validator which are advertised to other peers.
+type SubnetIDsCache struct {
+	attester     *lru.Cache
+	attesterLock sync.RWMutex
+	persistent   map[string]*persistentSubnets
+	lock         sync.RWMutex
+}
+
+// SubnetIDs for attester and persistent attestation subnets.
+var SubnetIDs = NewSubnetIDsCache()
+
+// NewSubnetIDsCache initializes an empty subnet ids cache.
+func NewSubnetIDsCache() *SubnetIDsCache {
+	attester, err := lru.New(maxAttesterSubnetSlots)
+	if err != nil {
+		panic(err)
+	}
+	return &SubnetIDsCache{
+		attester:   attester,
+		persistent: make(map[string]*persistentSubnets),
+	}
+}
+
+// AddAttesterSubnetID adds the subnet of a committee attesting at the slot.
+func (c *SubnetIDsCache) AddAttesterSubnetID(slot uint64, subnetID uint64) {
+	c.attesterLock.Lock()
+	defer c.attesterLock.Unlock()
+
+	ids := []uint64{subnetID}
+	if val, ok := c.attester.Get(slot); ok {
+		ids = sliceutil.UnionUint64(val.([]uint64), ids)
+	}
+	c.attester.Add(slot, ids)
+}
+
+// GetAttesterSubnetIDs returns the subnets of the committees attesting at the slot.
+func (c *SubnetIDsCache) GetAttesterSubnetIDs(slot uint64) []uint64 {
+	c.attesterLock.RLock()
+	defer c.attesterLock.RUnlock()
+
+	val, ok := c.attester.Get(slot)
+	if !ok {
+		return nil
+	}
+	return val.([]uint64)
+}
+
+// AddPersistentCommittee sets the persistent subnets of the validator with the given public key,
+// which stay subscribed to for the given duration.
+func (c *SubnetIDsCache) AddPersistentCommittee(pubkey []byte, subnets []uint64, duration time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.persistent[string(pubkey)] = &persistentSubnets{
+		subnets: subnets,
+		expiry:  roughtime.Now().Add(duration),
+	}
+}
+
+// GetPersistentSubnets returns the persistent subnets of the validator with the given public key,
+// and whether the validator has any which have not expired yet.
+func (c *SubnetIDsCache) GetPersistentSubnets(pubkey []byte) ([]uint64, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	p, ok := c.persistent[string(pubkey)]
+	if !ok || !roughtime.Now().Before(p.expiry) {
+		return nil, false
+	}
+	return p.subnets, true
+}
+
+// GetAllSubnets returns the persistent subnets of all validators, dropping the expired ones.
+func (c *SubnetIDsCache) GetAllSubnets() []uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := roughtime.Now()
+	var subnets []uint64
+	for k, p := range c.persistent {
+		if !now.Before(p.expiry) {
+			delete(c.persistent, k)
+			continue
+		}
+		subnets = sliceutil.UnionUint64(subnets, p.subnets)
+	}
+	return subnets
+}
@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSubnetIDsCache_AttesterSubnets(t *testing.T) {
+	c := NewSubnetIDsCache()
+	if ids := c.GetAttesterSubnetIDs(1); len(ids) != 0 {
+		t.Errorf("Expected no subnets, received %v", ids)
+	}
+
+	c.AddAttesterSubnetID(1, 3)
+	c.AddAttesterSubnetID(1, 5)
+	c.AddAttesterSubnetID(1, 3)
+	c.AddAttesterSubnetID(2, 7)
+	if ids := c.GetAttesterSubnetIDs(1); !reflect.DeepEqual(ids, []uint64{3, 5}) {
+		t.Errorf("Unexpected subnets for slot 1: %v", ids)
+	}
+	if ids := c.GetAttesterSubnetIDs(2); !reflect.DeepEqual(ids, []uint64{7}) {
+		t.Errorf("Unexpected subnets for slot 2: %v", ids)
+	}
+}
+
+func TestSubnetIDsCache_PersistentSubnets(t *testing.T) {
+	c := NewSubnetIDsCache()
+	pubkey1 := []byte{'A'}
+	pubkey2 := []byte{'B'}
+	if _, ok := c.GetPersistentSubnets(pubkey1); ok {
+		t.Error("Expected no persistent subnets")
+	}
+
+	c.AddPersistentCommittee(pubkey1, []uint64{1, 2}, time.Hour)
+	c.AddPersistentCommittee(pubkey2, []uint64{2, 4}, time.Hour)
+	subnets, ok := c.GetPersistentSubnets(pubkey1)
+	if !ok || !reflect.DeepEqual(subnets, []uint64{1, 2}) {
+		t.Errorf("Unexpected persistent subnets: %v", subnets)
+	}
+	if all := c.GetAllSubnets(); !reflect.DeepEqual(all, []uint64{1, 2, 4}) && !reflect.DeepEqual(all, []uint64{2, 4, 1}) {
+		t.Errorf("Unexpected subnets of all validators: %v", all)
+	}
+
+	// Expired subnets are dropped.
+	c.AddPersistentCommittee(pubkey2, []uint64{2, 4}, 0)
+	if _, ok := c.GetPersistentSubnets(pubkey2); ok {
+		t.Error("Expected expired subnets not to be returned")
+	}
+	if all := c.GetAllSubnets(); !reflect.DeepEqual(all, []uint64{1, 2}) {
+		t.Errorf("Unexpected subnets of all validators: %v", all)
+	}
+}
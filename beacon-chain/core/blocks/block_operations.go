@@ -39,20 +39,51 @@ var eth1DataCache = cache.NewEth1DataVoteCache()
 // failed to verify.
 var ErrSigFailedToVerify = errors.New("signature did not verify")
 
-func verifySigningRoot(obj interface{}, pub []byte, signature []byte, domain uint64) error {
+// proposerSigDescription names the proposer signature of a block in signature batches.
+const proposerSigDescription = "block proposer"
+
+// IsProposerSigError returns true if the error, or the error it wraps, comes from an invalid
+// proposer signature of a block. The signature is not part of the block root, so such a
+// block must not be recorded as bad by its root: the same block may exist with a valid
+// signature.
+func IsProposerSigError(err error) bool {
+	sigErr, ok := errors.Cause(err).(*bls.SignatureError)
+	return ok && sigErr.Description == proposerSigDescription
+}
+
+// signatureBatch returns a batch holding the signature of a 32 byte message by the given
+// public key.
+func signatureBatch(msg [32]byte, pub []byte, signature []byte, domain uint64, description string) (*bls.SignatureBatch, error) {
 	publicKey, err := bls.PublicKeyFromBytes(pub)
 	if err != nil {
-		return errors.Wrap(err, "could not convert bytes to public key")
+		return nil, errors.Wrap(err, "could not convert bytes to public key")
 	}
 	sig, err := bls.SignatureFromBytes(signature)
 	if err != nil {
-		return errors.Wrap(err, "could not convert bytes to signature")
+		return nil, errors.Wrap(err, "could not convert bytes to signature")
 	}
+	batch := bls.NewSignatureBatch()
+	batch.Add(sig, publicKey, msg, domain, description)
+	return batch, nil
+}
+
+// signingRootSignatureBatch returns a batch holding the signature of the hash tree root of obj.
+func signingRootSignatureBatch(obj interface{}, pub []byte, signature []byte, domain uint64, description string) (*bls.SignatureBatch, error) {
 	root, err := ssz.HashTreeRoot(obj)
 	if err != nil {
-		return errors.Wrap(err, "could not get signing root")
+		return nil, errors.Wrap(err, "could not get signing root")
 	}
-	if !sig.Verify(root[:], publicKey, domain) {
+	return signatureBatch(root, pub, signature, domain, description)
+}
+
+// verifyOrBatch verifies the signatures of set right away when batch is nil. Otherwise their
+// verification is deferred by adding them to batch.
+func verifyOrBatch(set *bls.SignatureBatch, batch *bls.SignatureBatch) error {
+	if batch != nil {
+		batch.Join(set)
+		return nil
+	}
+	if err := set.Verify(); err != nil {
 		return ErrSigFailedToVerify
 	}
 	return nil
@@ -78,21 +109,6 @@ func verifyDepositDataSigningRoot(obj *ethpb.Deposit_Data, pub []byte, signature
 	return nil
 }
 
-func verifySignature(signedData []byte, pub []byte, signature []byte, domain uint64) error {
-	publicKey, err := bls.PublicKeyFromBytes(pub)
-	if err != nil {
-		return errors.Wrap(err, "could not convert bytes to public key")
-	}
-	sig, err := bls.SignatureFromBytes(signature)
-	if err != nil {
-		return errors.Wrap(err, "could not convert bytes to signature")
-	}
-	if !sig.Verify(signedData, publicKey, domain) {
-		return ErrSigFailedToVerify
-	}
-	return nil
-}
-
 // ProcessEth1DataInBlock is an operation performed on each
 // beacon block to ensure the ETH1 data votes are processed
 // into the beacon state.
@@ -199,6 +215,24 @@ func ProcessBlockHeader(
 		return nil, err
 	}
 
+	// Verify proposer signature.
+	set, err := BlockSignatureBatch(beaconState, block)
+	if err != nil {
+		return nil, err
+	}
+	if err := set.Verify(); err != nil {
+		return nil, err
+	}
+
+	return beaconState, nil
+}
+
+// BlockSignatureBatch returns the proposer signature of a block as a signature batch, for
+// a state processed up to the slot of the block.
+func BlockSignatureBatch(
+	beaconState *stateTrie.BeaconState,
+	block *ethpb.SignedBeaconBlock,
+) (*bls.SignatureBatch, error) {
 	idx, err := helpers.BeaconProposerIndex(beaconState)
 	if err != nil {
 		return nil, err
@@ -207,15 +241,13 @@ func ProcessBlockHeader(
 	if err != nil {
 		return nil, err
 	}
-
-	// Verify proposer signature.
 	currentEpoch := helpers.SlotToEpoch(beaconState.Slot())
 	domain := helpers.Domain(beaconState.Fork(), currentEpoch, params.BeaconConfig().DomainBeaconProposer)
-	if err := verifySigningRoot(block.Block, proposer.PublicKey, block.Signature, domain); err != nil {
-		return nil, ErrSigFailedToVerify
+	set, err := signingRootSignatureBatch(block.Block, proposer.PublicKey, block.Signature, domain, proposerSigDescription)
+	if err != nil {
+		return nil, &bls.SignatureError{Description: proposerSigDescription}
 	}
-
-	return beaconState, nil
+	return set, nil
 }
 
 // ProcessBlockHeaderNoVerify validates a block by its header but skips proposer
@@ -312,6 +344,27 @@ func ProcessRandao(
 	beaconState *stateTrie.BeaconState,
 	body *ethpb.BeaconBlockBody,
 ) (*stateTrie.BeaconState, error) {
+	set, err := RandaoSignatureBatch(beaconState, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyOrBatch(set, nil); err != nil {
+		return nil, errors.Wrap(err, "could not verify block randao")
+	}
+
+	beaconState, err = ProcessRandaoNoVerify(beaconState, body)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not process randao")
+	}
+	return beaconState, nil
+}
+
+// RandaoSignatureBatch returns the randao reveal of a block body as a signature batch, for
+// a state processed up to the slot of the block.
+func RandaoSignatureBatch(
+	beaconState *stateTrie.BeaconState,
+	body *ethpb.BeaconBlockBody,
+) (*bls.SignatureBatch, error) {
 	proposerIdx, err := helpers.BeaconProposerIndex(beaconState)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not get beacon proposer index")
@@ -319,19 +372,15 @@ func ProcessRandao(
 	proposerPub := beaconState.PubkeyAtIndex(proposerIdx)
 
 	currentEpoch := helpers.SlotToEpoch(beaconState.Slot())
-	buf := make([]byte, 32)
-	binary.LittleEndian.PutUint64(buf, currentEpoch)
+	var msg [32]byte
+	binary.LittleEndian.PutUint64(msg[:], currentEpoch)
 
 	domain := helpers.Domain(beaconState.Fork(), currentEpoch, params.BeaconConfig().DomainRandao)
-	if err := verifySignature(buf, proposerPub[:], body.RandaoReveal, domain); err != nil {
-		return nil, errors.Wrap(err, "could not verify block randao")
-	}
-
-	beaconState, err = ProcessRandaoNoVerify(beaconState, body)
+	set, err := signatureBatch(msg, proposerPub[:], body.RandaoReveal, domain, "randao reveal")
 	if err != nil {
-		return nil, errors.Wrap(err, "could not process randao")
+		return nil, errors.Wrap(err, "could not verify block randao")
 	}
-	return beaconState, nil
+	return set, nil
 }
 
 // ProcessRandaoNoVerify generates a new randao mix to update
@@ -391,13 +440,33 @@ func ProcessProposerSlashings(
 	ctx context.Context,
 	beaconState *stateTrie.BeaconState,
 	body *ethpb.BeaconBlockBody,
+) (*stateTrie.BeaconState, error) {
+	return processProposerSlashings(ctx, beaconState, body, nil)
+}
+
+// ProcessProposerSlashingsNoVerifySigs processes the proposer slashings of a block body like
+// ProcessProposerSlashings, adding their signatures to the batch instead of verifying them.
+func ProcessProposerSlashingsNoVerifySigs(
+	ctx context.Context,
+	beaconState *stateTrie.BeaconState,
+	body *ethpb.BeaconBlockBody,
+	batch *bls.SignatureBatch,
+) (*stateTrie.BeaconState, error) {
+	return processProposerSlashings(ctx, beaconState, body, batch)
+}
+
+func processProposerSlashings(
+	ctx context.Context,
+	beaconState *stateTrie.BeaconState,
+	body *ethpb.BeaconBlockBody,
+	batch *bls.SignatureBatch,
 ) (*stateTrie.BeaconState, error) {
 	var err error
 	for idx, slashing := range body.ProposerSlashings {
 		if int(slashing.ProposerIndex) >= beaconState.NumValidators() {
 			return nil, fmt.Errorf("invalid proposer index given in slashing %d", slashing.ProposerIndex)
 		}
-		if err = VerifyProposerSlashing(beaconState, slashing); err != nil {
+		if err = verifyProposerSlashing(beaconState, slashing, batch); err != nil {
 			return nil, errors.Wrapf(err, "could not verify proposer slashing %d", idx)
 		}
 		beaconState, err = v.SlashValidator(
@@ -414,6 +483,14 @@ func ProcessProposerSlashings(
 func VerifyProposerSlashing(
 	beaconState *stateTrie.BeaconState,
 	slashing *ethpb.ProposerSlashing,
+) error {
+	return verifyProposerSlashing(beaconState, slashing, nil)
+}
+
+func verifyProposerSlashing(
+	beaconState *stateTrie.BeaconState,
+	slashing *ethpb.ProposerSlashing,
+	batch *bls.SignatureBatch,
 ) error {
 	proposer, err := beaconState.ValidatorAtIndex(slashing.ProposerIndex)
 	if err != nil {
@@ -433,7 +510,11 @@ func VerifyProposerSlashing(
 	domain := helpers.Domain(beaconState.Fork(), helpers.StartSlot(slashing.Header_1.Header.Slot), params.BeaconConfig().DomainBeaconProposer)
 	headers := []*ethpb.SignedBeaconBlockHeader{slashing.Header_1, slashing.Header_2}
 	for _, header := range headers {
-		if err := verifySigningRoot(header.Header, proposer.PublicKey, header.Signature, domain); err != nil {
+		set, err := signingRootSignatureBatch(header.Header, proposer.PublicKey, header.Signature, domain, "proposer slashing header")
+		if err != nil {
+			return errors.Wrap(err, "could not verify beacon block header")
+		}
+		if err := verifyOrBatch(set, batch); err != nil {
 			return errors.Wrap(err, "could not verify beacon block header")
 		}
 	}
@@ -463,9 +544,29 @@ func ProcessAttesterSlashings(
 	ctx context.Context,
 	beaconState *stateTrie.BeaconState,
 	body *ethpb.BeaconBlockBody,
+) (*stateTrie.BeaconState, error) {
+	return processAttesterSlashings(ctx, beaconState, body, nil)
+}
+
+// ProcessAttesterSlashingsNoVerifySigs processes the attester slashings of a block body like
+// ProcessAttesterSlashings, adding their signatures to the batch instead of verifying them.
+func ProcessAttesterSlashingsNoVerifySigs(
+	ctx context.Context,
+	beaconState *stateTrie.BeaconState,
+	body *ethpb.BeaconBlockBody,
+	batch *bls.SignatureBatch,
+) (*stateTrie.BeaconState, error) {
+	return processAttesterSlashings(ctx, beaconState, body, batch)
+}
+
+func processAttesterSlashings(
+	ctx context.Context,
+	beaconState *stateTrie.BeaconState,
+	body *ethpb.BeaconBlockBody,
+	batch *bls.SignatureBatch,
 ) (*stateTrie.BeaconState, error) {
 	for idx, slashing := range body.AttesterSlashings {
-		if err := VerifyAttesterSlashing(ctx, beaconState, slashing); err != nil {
+		if err := verifyAttesterSlashing(ctx, beaconState, slashing, batch); err != nil {
 			return nil, errors.Wrapf(err, "could not verify attester slashing %d", idx)
 		}
 		slashableIndices := slashableAttesterIndices(slashing)
@@ -499,6 +600,15 @@ func ProcessAttesterSlashings(
 
 // VerifyAttesterSlashing validates the attestation data in both attestations in the slashing object.
 func VerifyAttesterSlashing(ctx context.Context, beaconState *stateTrie.BeaconState, slashing *ethpb.AttesterSlashing) error {
+	return verifyAttesterSlashing(ctx, beaconState, slashing, nil)
+}
+
+func verifyAttesterSlashing(
+	ctx context.Context,
+	beaconState *stateTrie.BeaconState,
+	slashing *ethpb.AttesterSlashing,
+	batch *bls.SignatureBatch,
+) error {
 	att1 := slashing.Attestation_1
 	att2 := slashing.Attestation_2
 	data1 := att1.Data
@@ -506,10 +616,10 @@ func VerifyAttesterSlashing(ctx context.Context, beaconState *stateTrie.BeaconSt
 	if !IsSlashableAttestationData(data1, data2) {
 		return errors.New("attestations are not slashable")
 	}
-	if err := VerifyIndexedAttestation(ctx, beaconState, att1); err != nil {
+	if err := verifyIndexedAttestation(ctx, beaconState, att1, batch, "attester slashing attestation"); err != nil {
 		return errors.Wrap(err, "could not validate indexed attestation")
 	}
-	if err := VerifyIndexedAttestation(ctx, beaconState, att2); err != nil {
+	if err := verifyIndexedAttestation(ctx, beaconState, att2, batch, "attester slashing attestation"); err != nil {
 		return errors.Wrap(err, "could not validate indexed attestation")
 	}
 	return nil
@@ -558,6 +668,28 @@ func ProcessAttestations(
 	return beaconState, nil
 }
 
+// ProcessAttestationsNoVerifySigs applies processing operations to a block's inner attestation
+// records like ProcessAttestations, adding their signatures to the batch instead of verifying them.
+func ProcessAttestationsNoVerifySigs(
+	ctx context.Context,
+	beaconState *stateTrie.BeaconState,
+	body *ethpb.BeaconBlockBody,
+	batch *bls.SignatureBatch,
+) (*stateTrie.BeaconState, error) {
+	var err error
+	for idx, attestation := range body.Attestations {
+		beaconState, err = ProcessAttestationNoVerify(ctx, beaconState, attestation)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not verify attestation at index %d in block", idx)
+		}
+		description := fmt.Sprintf("attestation %d", idx)
+		if err := verifyAttestation(ctx, beaconState, attestation, batch, description); err != nil {
+			return nil, errors.Wrapf(err, "could not verify attestation at index %d in block", idx)
+		}
+	}
+	return beaconState, nil
+}
+
 // ProcessAttestationsNoVerify applies processing operations to a block's inner attestation
 // records. The only difference would be that the attestation signature would not be verified.
 func ProcessAttestationsNoVerify(
@@ -740,6 +872,16 @@ func ProcessAttestationNoVerify(
 //        return False
 //    return True
 func VerifyIndexedAttestation(ctx context.Context, beaconState *stateTrie.BeaconState, indexedAtt *ethpb.IndexedAttestation) error {
+	return verifyIndexedAttestation(ctx, beaconState, indexedAtt, nil, "indexed attestation")
+}
+
+func verifyIndexedAttestation(
+	ctx context.Context,
+	beaconState *stateTrie.BeaconState,
+	indexedAtt *ethpb.IndexedAttestation,
+	batch *bls.SignatureBatch,
+	description string,
+) error {
 	ctx, span := trace.StartSpan(ctx, "core.VerifyIndexedAttestation")
 	defer span.End()
 
@@ -795,15 +937,37 @@ func VerifyIndexedAttestation(ctx context.Context, beaconState *stateTrie.Beacon
 	}
 
 	voted := len(indices) > 0
-	if voted && !sig.Verify(messageHash[:], pubkey, domain) {
-		return ErrSigFailedToVerify
+	if !voted {
+		return nil
 	}
-	return nil
+	set := bls.NewSignatureBatch()
+	set.Add(sig, pubkey, messageHash, domain, description)
+	return verifyOrBatch(set, batch)
 }
 
 // VerifyAttestation converts and attestation into an indexed attestation and verifies
 // the signature in that attestation.
 func VerifyAttestation(ctx context.Context, beaconState *stateTrie.BeaconState, att *ethpb.Attestation) error {
+	return verifyAttestation(ctx, beaconState, att, nil, "attestation")
+}
+
+// AttestationSignatureBatch converts an attestation into an indexed attestation and returns its
+// signature as a signature batch, without verifying it.
+func AttestationSignatureBatch(ctx context.Context, beaconState *stateTrie.BeaconState, att *ethpb.Attestation) (*bls.SignatureBatch, error) {
+	batch := bls.NewSignatureBatch()
+	if err := verifyAttestation(ctx, beaconState, att, batch, "attestation"); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+func verifyAttestation(
+	ctx context.Context,
+	beaconState *stateTrie.BeaconState,
+	att *ethpb.Attestation,
+	batch *bls.SignatureBatch,
+	description string,
+) error {
 	committee, err := helpers.BeaconCommitteeFromState(beaconState, att.Data.Slot, att.Data.CommitteeIndex)
 	if err != nil {
 		return err
@@ -812,7 +976,7 @@ func VerifyAttestation(ctx context.Context, beaconState *stateTrie.BeaconState,
 	if err != nil {
 		return errors.Wrap(err, "could not convert to indexed attestation")
 	}
-	return VerifyIndexedAttestation(ctx, beaconState, indexedAtt)
+	return verifyIndexedAttestation(ctx, beaconState, indexedAtt, batch, description)
 }
 
 // ProcessDeposits is one of the operations performed on each processed
@@ -1021,6 +1185,26 @@ func ProcessVoluntaryExits(
 	ctx context.Context,
 	beaconState *stateTrie.BeaconState,
 	body *ethpb.BeaconBlockBody,
+) (*stateTrie.BeaconState, error) {
+	return processVoluntaryExits(ctx, beaconState, body, nil)
+}
+
+// ProcessVoluntaryExitsNoVerifySigs processes the voluntary exits of a block body like
+// ProcessVoluntaryExits, adding their signatures to the batch instead of verifying them.
+func ProcessVoluntaryExitsNoVerifySigs(
+	ctx context.Context,
+	beaconState *stateTrie.BeaconState,
+	body *ethpb.BeaconBlockBody,
+	batch *bls.SignatureBatch,
+) (*stateTrie.BeaconState, error) {
+	return processVoluntaryExits(ctx, beaconState, body, batch)
+}
+
+func processVoluntaryExits(
+	ctx context.Context,
+	beaconState *stateTrie.BeaconState,
+	body *ethpb.BeaconBlockBody,
+	batch *bls.SignatureBatch,
 ) (*stateTrie.BeaconState, error) {
 	exits := body.VoluntaryExits
 	for idx, exit := range exits {
@@ -1035,7 +1219,8 @@ func ProcessVoluntaryExits(
 		if err != nil {
 			return nil, err
 		}
-		if err := VerifyExit(val, beaconState.Slot(), beaconState.Fork(), exit); err != nil {
+		description := fmt.Sprintf("voluntary exit %d", idx)
+		if err := verifyExit(val, beaconState.Slot(), beaconState.Fork(), exit, batch, description); err != nil {
 			return nil, errors.Wrapf(err, "could not verify exit %d", idx)
 		}
 		beaconState, err = v.InitiateValidatorExit(beaconState, exit.Exit.ValidatorIndex)
@@ -1084,6 +1269,17 @@ func ProcessVoluntaryExitsNoVerify(
 //    domain = get_domain(state, DOMAIN_VOLUNTARY_EXIT, exit.epoch)
 //    assert bls_verify(validator.pubkey, signing_root(exit), exit.signature, domain)
 func VerifyExit(validator *ethpb.Validator, currentSlot uint64, fork *pb.Fork, signed *ethpb.SignedVoluntaryExit) error {
+	return verifyExit(validator, currentSlot, fork, signed, nil, "voluntary exit")
+}
+
+func verifyExit(
+	validator *ethpb.Validator,
+	currentSlot uint64,
+	fork *pb.Fork,
+	signed *ethpb.SignedVoluntaryExit,
+	batch *bls.SignatureBatch,
+	description string,
+) error {
 	if signed == nil || signed.Exit == nil {
 		return errors.New("nil exit")
 	}
@@ -1111,10 +1307,11 @@ func VerifyExit(validator *ethpb.Validator, currentSlot uint64, fork *pb.Fork, s
 		)
 	}
 	domain := helpers.Domain(fork, exit.Epoch, params.BeaconConfig().DomainVoluntaryExit)
-	if err := verifySigningRoot(exit, validator.PublicKey, signed.Signature, domain); err != nil {
+	set, err := signingRootSignatureBatch(exit, validator.PublicKey, signed.Signature, domain, description)
+	if err != nil {
 		return ErrSigFailedToVerify
 	}
-	return nil
+	return verifyOrBatch(set, batch)
 }
 
 // ClearEth1DataVoteCache clears the eth1 data vote count cache.
@@ -67,6 +67,9 @@ func TestProcessBlockHeader_WrongProposerSig(t *testing.T) {
 	if !strings.Contains(err.Error(), want) {
 		t.Errorf("Expected %v, received %v", want, err)
 	}
+	if !blocks.IsProposerSigError(err) {
+		t.Error("Expected the error to be a proposer signature error")
+	}
 
 }
 
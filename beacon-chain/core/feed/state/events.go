@@ -9,6 +9,12 @@ const (
 	ChainStarted
 	// Initialized is sent when the internal beacon node's state is ready to be accessed.
 	Initialized
+	// NewHead is sent when the head of the chain changes.
+	NewHead
+	// Reorg is sent when the new head of the chain does not descend from the previous head.
+	Reorg
+	// FinalizedCheckpoint is sent when a new checkpoint is finalized.
+	FinalizedCheckpoint
 )
 
 // BlockProcessedData is the data sent with BlockProcessed events.
@@ -31,4 +37,42 @@ type ChainStartedData struct {
 type InitializedData struct {
 	// StartTime is the time at which the chain started.
 	StartTime time.Time
+	// GenesisValidatorsRoot is the hash tree root of the validators at genesis.
+	GenesisValidatorsRoot []byte
+}
+
+// NewHeadData is the data sent with NewHead events.
+type NewHeadData struct {
+	// Slot is the slot of the new head block.
+	Slot uint64
+	// BlockRoot is the hash of the new head block.
+	BlockRoot [32]byte
+	// PreviousBlockRoot is the hash of the previous head block.
+	PreviousBlockRoot [32]byte
+}
+
+// ReorgData is the data sent with Reorg events.
+type ReorgData struct {
+	// OldHeadSlot is the slot of the previous head block.
+	OldHeadSlot uint64
+	// OldHeadRoot is the hash of the previous head block.
+	OldHeadRoot [32]byte
+	// NewHeadSlot is the slot of the new head block.
+	NewHeadSlot uint64
+	// NewHeadRoot is the hash of the new head block.
+	NewHeadRoot [32]byte
+	// CommonAncestorSlot is the slot of the latest block both heads descend from.
+	CommonAncestorSlot uint64
+	// CommonAncestorRoot is the hash of the latest block both heads descend from.
+	CommonAncestorRoot [32]byte
+	// Depth is the number of slots of the previous chain reverted by the reorg.
+	Depth uint64
+}
+
+// FinalizedCheckpointData is the data sent with FinalizedCheckpoint events.
+type FinalizedCheckpointData struct {
+	// Epoch is the epoch of the finalized checkpoint.
+	Epoch uint64
+	// BlockRoot is the hash of the finalized block.
+	BlockRoot [32]byte
 }
@@ -0,0 +1,46 @@
+package helpers
+
+import (
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/hashutil"
+)
+
+// ComputeForkDigest returns the 4 byte digest of the fork version and the genesis validators root,
+// which identifies the fork of a chain on the network.
+//
+// Spec pseudocode definition:
+//  def compute_fork_data_root(current_version: Version, genesis_validators_root: Root) -> Root:
+//    """
+//    Return the 32-byte fork data root for the ``current_version`` and ``genesis_validators_root``.
+//    This is used primarily in signature domains to avoid collisions across forks/chains.
+//    """
+//    return hash_tree_root(ForkData(
+//        current_version=current_version,
+//        genesis_validators_root=genesis_validators_root,
+//    ))
+//
+//  def compute_fork_digest(current_version: Version, genesis_validators_root: Root) -> ForkDigest:
+//    """
+//    Return the 4-byte fork digest for the ``current_version`` and ``genesis_validators_root``.
+//    This is a digest primarily used for domain separation on the p2p layer.
+//    4-bytes suffices for practical separation of forks/chains.
+//    """
+//    return ForkDigest(compute_fork_data_root(current_version, genesis_validators_root)[:4])
+func ComputeForkDigest(version []byte, genesisValidatorsRoot []byte) ([4]byte, error) {
+	if len(version) != 4 {
+		return [4]byte{}, errors.Errorf("fork version has length %d, wanted 4", len(version))
+	}
+	if len(genesisValidatorsRoot) != 32 {
+		return [4]byte{}, errors.Errorf("genesis validators root has length %d, wanted 32", len(genesisValidatorsRoot))
+	}
+	// The fork data container has two fields, each packed into a 32 byte chunk, so its hash tree
+	// root is the hash of the two chunks.
+	chunks := make([]byte, 64)
+	copy(chunks[:32], version)
+	copy(chunks[32:], genesisValidatorsRoot)
+	root := hashutil.Hash(chunks)
+
+	var digest [4]byte
+	copy(digest[:], root[:4])
+	return digest, nil
+}
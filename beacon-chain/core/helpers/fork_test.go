@@ -0,0 +1,46 @@
+package helpers
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/prysmaticlabs/go-ssz"
+)
+
+func TestComputeForkDigest_OK(t *testing.T) {
+	type forkData struct {
+		CurrentVersion        []byte `ssz-size:"4"`
+		GenesisValidatorsRoot []byte `ssz-size:"32"`
+	}
+	version := []byte{0, 0, 0, 1}
+	root := bytes.Repeat([]byte{'A'}, 32)
+
+	digest, err := ComputeForkDigest(version, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wanted, err := ssz.HashTreeRoot(&forkData{CurrentVersion: version, GenesisValidatorsRoot: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(digest[:], wanted[:4]) {
+		t.Errorf("Wanted digest %#x, received %#x", wanted[:4], digest)
+	}
+
+	other, err := ComputeForkDigest([]byte{0, 0, 0, 2}, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == digest {
+		t.Error("Expected different fork versions to have different digests")
+	}
+}
+
+func TestComputeForkDigest_InvalidLengths(t *testing.T) {
+	if _, err := ComputeForkDigest([]byte{0, 0, 0}, make([]byte, 32)); err == nil {
+		t.Error("Expected error for short fork version")
+	}
+	if _, err := ComputeForkDigest([]byte{0, 0, 0, 0}, make([]byte, 31)); err == nil {
+		t.Error("Expected error for short genesis validators root")
+	}
+}
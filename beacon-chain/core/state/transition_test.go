@@ -190,6 +190,46 @@ func TestProcessBlock_IncorrectProcessBlockAttestations(t *testing.T) {
 	}
 }
 
+func TestProcessBlockNoVerifySigs_CollectsSignatures(t *testing.T) {
+	beaconState, privKeys := testutil.DeterministicGenesisState(t, 100)
+
+	block, err := testutil.GenerateFullBlock(beaconState, privKeys, nil, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	beaconState, err = state.ProcessSlots(context.Background(), beaconState, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch, _, err := state.ProcessBlockNoVerifySigs(context.Background(), beaconState.Copy(), block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The proposer signature, the randao reveal and the attestation signature.
+	if batch.Len() != 3 {
+		t.Errorf("Expected 3 signature sets, received %d", batch.Len())
+	}
+	if err := batch.Verify(); err != nil {
+		t.Errorf("Could not verify block signatures: %v", err)
+	}
+
+	// Replace the attestation signature by another valid signature, and sign the modified block.
+	block.Block.Body.Attestations[0].Signature = block.Block.Body.RandaoReveal
+	sig, err := testutil.BlockSignature(beaconState, block.Block, privKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block.Signature = sig.Marshal()
+	batch, _, err = state.ProcessBlockNoVerifySigs(context.Background(), beaconState.Copy(), block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "attestation 0 signature did not verify"
+	if err := batch.Verify(); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Expected %s, received %v", want, err)
+	}
+}
+
 func TestProcessBlock_IncorrectProcessExits(t *testing.T) {
 	beaconState, _ := testutil.DeterministicGenesisState(t, 100)
 
@@ -0,0 +1,42 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/kv"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+)
+
+// Database backends which can be selected with the --db-backend flag.
+const (
+	BoltBackend    = "bolt"
+	LevelDBBackend = "leveldb"
+	MemoryBackend  = "memory"
+)
+
+// newBackend opens the database backend selected by the global flags at the directory
+// path specified, defaulting to bolt.
+func newBackend(dirPath string) (Database, error) {
+	switch backend := flags.Get().DBBackend; backend {
+	case "", BoltBackend:
+		store, err := kv.NewKVStore(dirPath)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	case LevelDBBackend:
+		store, err := kv.NewLevelDBStore(dirPath)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	case MemoryBackend:
+		store, err := kv.NewMemoryStore(dirPath)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown database backend %q", backend)
+	}
+}
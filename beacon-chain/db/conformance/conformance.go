@@ -0,0 +1,890 @@
+// Package conformance defines a test suite which every implementation of the beacon node
+// database interface must pass, so that the behaviour of filters, indices and checkpoints
+// stays identical across database backends.
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+	dbpb "github.com/prysmaticlabs/prysm/proto/beacon/db"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// Setup opens an empty database for a single test case, returning it along with a function
+// which closes and removes it.
+type Setup func(t *testing.T) (iface.Database, func())
+
+// Run runs every test case of the conformance suite against the databases opened by setup.
+func Run(t *testing.T, setup Setup) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, db iface.Database)
+	}{
+		{name: "BlocksCRUD", fn: testBlocksCRUD},
+		{name: "BlocksFilters", fn: testBlocksFilters},
+		{name: "BlocksUnsupportedFilter", fn: testBlocksUnsupportedFilter},
+		{name: "GenesisAndHeadBlock", fn: testGenesisAndHeadBlock},
+		{name: "AttestationsCRUD", fn: testAttestationsCRUD},
+		{name: "AttestationsFilters", fn: testAttestationsFilters},
+		{name: "StatesCRUD", fn: testStatesCRUD},
+		{name: "StatesDeletionGuard", fn: testStatesDeletionGuard},
+		{name: "Checkpoints", fn: testCheckpoints},
+		{name: "FinalizedBlockIndex", fn: testFinalizedBlockIndex},
+		{name: "ValidatorIndices", fn: testValidatorIndices},
+		{name: "Operations", fn: testOperations},
+		{name: "ArchivedData", fn: testArchivedData},
+		{name: "ChainMetadata", fn: testChainMetadata},
+		{name: "ForkChoiceSnapshot", fn: testForkChoiceSnapshot},
+		{name: "BucketStats", fn: testBucketStats},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, teardown := setup(t)
+			defer teardown()
+			tt.fn(t, db)
+		})
+	}
+}
+
+func testBlocksCRUD(t *testing.T, db iface.Database) {
+	ctx := context.Background()
+	block := &ethpb.SignedBeaconBlock{
+		Block: &ethpb.BeaconBlock{
+			Slot:       20,
+			ParentRoot: []byte{1, 2, 3},
+		},
+	}
+	root := blockRoot(t, block)
+	if db.HasBlock(ctx, root) {
+		t.Error("Expected block to not exist in the db")
+	}
+	if err := db.SaveBlock(ctx, block); err != nil {
+		t.Fatal(err)
+	}
+	if !db.HasBlock(ctx, root) {
+		t.Error("Expected block to exist in the db")
+	}
+	retrieved, err := db.Block(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(block, retrieved) {
+		t.Errorf("Wanted %v, received %v", block, retrieved)
+	}
+	if err := db.DeleteBlock(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	if db.HasBlock(ctx, root) {
+		t.Error("Expected block to have been deleted from the db")
+	}
+	retrieved, err = db.Block(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retrieved != nil {
+		t.Errorf("Expected nil block, received %v", retrieved)
+	}
+	// The indices of a deleted block must not return it anymore.
+	roots, err := db.BlockRoots(ctx, filters.NewFilter().SetParentRoot([]byte{1, 2, 3}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roots) != 0 {
+		t.Errorf("Expected no block roots for deleted block, received %d", len(roots))
+	}
+}
+
+func testBlocksFilters(t *testing.T, db iface.Database) {
+	ctx := context.Background()
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	blocks := make([]*ethpb.SignedBeaconBlock, 0)
+	for i := uint64(0); i < 3*slotsPerEpoch; i++ {
+		parentRoot := []byte("even")
+		if i%2 == 1 {
+			parentRoot = []byte("odd")
+		}
+		blocks = append(blocks, &ethpb.SignedBeaconBlock{
+			Block: &ethpb.BeaconBlock{
+				Slot:       i,
+				ParentRoot: parentRoot,
+			},
+		})
+	}
+	if err := db.SaveBlocks(ctx, blocks); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		f     *filters.QueryFilter
+		slots []uint64
+	}{
+		{
+			name:  "parent root",
+			f:     filters.NewFilter().SetParentRoot([]byte("odd")),
+			slots: slotRange(1, 3*slotsPerEpoch-1, 2),
+		},
+		{
+			name:  "slot range",
+			f:     filters.NewFilter().SetStartSlot(5).SetEndSlot(9),
+			slots: slotRange(5, 9, 1),
+		},
+		{
+			name:  "open ended slot range",
+			f:     filters.NewFilter().SetStartSlot(2*slotsPerEpoch + 1),
+			slots: slotRange(2*slotsPerEpoch+1, 3*slotsPerEpoch-1, 1),
+		},
+		{
+			name:  "slot range with step",
+			f:     filters.NewFilter().SetStartSlot(3).SetEndSlot(15).SetSlotStep(4),
+			slots: []uint64{3, 7, 11, 15},
+		},
+		{
+			name:  "epoch range",
+			f:     filters.NewFilter().SetStartEpoch(1).SetEndEpoch(1),
+			slots: slotRange(slotsPerEpoch, 2*slotsPerEpoch-1, 1),
+		},
+		{
+			name:  "parent root and slot range",
+			f:     filters.NewFilter().SetParentRoot([]byte("even")).SetStartSlot(4).SetEndSlot(10),
+			slots: []uint64{4, 6, 8, 10},
+		},
+	}
+	for _, tt := range tests {
+		retrieved, err := db.Blocks(ctx, tt.f)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if slots := blockSlots(retrieved); !reflect.DeepEqual(slots, tt.slots) {
+			t.Errorf("%s: expected slots %v, received %v", tt.name, tt.slots, slots)
+		}
+		roots, err := db.BlockRoots(ctx, tt.f)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(roots) != len(tt.slots) {
+			t.Errorf("%s: expected %d block roots, received %d", tt.name, len(tt.slots), len(roots))
+		}
+	}
+}
+
+func testBlocksUnsupportedFilter(t *testing.T, db iface.Database) {
+	ctx := context.Background()
+	if _, err := db.Blocks(ctx, nil); err == nil {
+		t.Error("Expected error when retrieving blocks without a filter")
+	}
+	if _, err := db.BlockRoots(ctx, nil); err == nil {
+		t.Error("Expected error when retrieving block roots without a filter")
+	}
+	if _, err := db.Blocks(ctx, filters.NewFilter().SetTargetEpoch(1)); err == nil {
+		t.Error("Expected error for a filter criterion which does not apply to blocks")
+	}
+	if _, err := db.Attestations(ctx, filters.NewFilter().SetStartSlot(1)); err == nil {
+		t.Error("Expected error for a filter criterion which does not apply to attestations")
+	}
+}
+
+func testGenesisAndHeadBlock(t *testing.T, db iface.Database) {
+	ctx := context.Background()
+	genesis := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 0}}
+	head := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 1, ParentRoot: []byte("genesis")}}
+	genesisRoot := blockRoot(t, genesis)
+	headRoot := blockRoot(t, head)
+	if err := db.SaveBlocks(ctx, []*ethpb.SignedBeaconBlock{genesis, head}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	retrieved, err := db.GenesisBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(genesis, retrieved) {
+		t.Errorf("Wanted genesis block %v, received %v", genesis, retrieved)
+	}
+
+	retrieved, err = db.AnchorBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retrieved != nil {
+		t.Errorf("Expected no anchor block before one is saved, received %v", retrieved)
+	}
+	if err := db.SaveAnchorBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	retrieved, err = db.AnchorBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(genesis, retrieved) {
+		t.Errorf("Wanted anchor block %v, received %v", genesis, retrieved)
+	}
+
+	retrieved, err = db.BackfillBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retrieved != nil {
+		t.Errorf("Expected no backfill block before one is saved, received %v", retrieved)
+	}
+	if err := db.SaveBackfillBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	retrieved, err = db.BackfillBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(genesis, retrieved) {
+		t.Errorf("Wanted backfill block %v, received %v", genesis, retrieved)
+	}
+
+	if err := db.SaveHeadBlockRoot(ctx, [32]byte{'u', 'n', 'k', 'n', 'o', 'w', 'n'}); err == nil {
+		t.Error("Expected error when saving a head block root without a state or block")
+	}
+	if err := db.SaveHeadBlockRoot(ctx, headRoot); err != nil {
+		t.Fatal(err)
+	}
+	retrieved, err = db.HeadBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(head, retrieved) {
+		t.Errorf("Wanted head block %v, received %v", head, retrieved)
+	}
+	if err := db.SaveState(ctx, newState(t, 1), headRoot); err != nil {
+		t.Fatal(err)
+	}
+	headState, err := db.HeadState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headState == nil || headState.Slot() != 1 {
+		t.Errorf("Expected head state at slot 1, received %v", headState)
+	}
+}
+
+func testAttestationsCRUD(t *testing.T, db iface.Database) {
+	ctx := context.Background()
+	data := &ethpb.AttestationData{Slot: 10}
+	att1 := &ethpb.Attestation{Data: data, AggregationBits: bitfield.Bitlist{0b00000011, 0b1}}
+	att2 := &ethpb.Attestation{Data: data, AggregationBits: bitfield.Bitlist{0b00001100, 0b1}}
+	aggregate := &ethpb.Attestation{Data: data, AggregationBits: bitfield.Bitlist{0b00001111, 0b1}}
+	root, err := ssz.HashTreeRoot(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveAttestation(ctx, &ethpb.Attestation{Data: data}); err == nil {
+		t.Error("Expected error when saving an attestation without aggregation bits")
+	}
+	if err := db.SaveAttestations(ctx, []*ethpb.Attestation{att1, att2}); err != nil {
+		t.Fatal(err)
+	}
+	if !db.HasAttestation(ctx, root) {
+		t.Error("Expected attestation to exist in the db")
+	}
+	retrieved, err := db.AttestationsByDataRoot(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(retrieved) != 2 {
+		t.Errorf("Expected 2 attestations for the data root, received %d", len(retrieved))
+	}
+	// An aggregate of every saved attestation replaces them.
+	if err := db.SaveAttestation(ctx, aggregate); err != nil {
+		t.Fatal(err)
+	}
+	retrieved, err = db.AttestationsByDataRoot(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(retrieved) != 1 || !proto.Equal(retrieved[0], aggregate) {
+		t.Errorf("Wanted %v, received %v", aggregate, retrieved)
+	}
+	if err := db.DeleteAttestation(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	if db.HasAttestation(ctx, root) {
+		t.Error("Expected attestation to have been deleted from the db")
+	}
+}
+
+func testAttestationsFilters(t *testing.T, db iface.Database) {
+	ctx := context.Background()
+	atts := make([]*ethpb.Attestation, 0)
+	for i := uint64(0); i < 8; i++ {
+		atts = append(atts, &ethpb.Attestation{
+			Data: &ethpb.AttestationData{
+				Slot:            i,
+				BeaconBlockRoot: []byte{byte(i % 2)},
+				Source:          &ethpb.Checkpoint{Epoch: i / 4, Root: []byte("source")},
+				Target:          &ethpb.Checkpoint{Epoch: i/4 + 1, Root: []byte{byte(i % 4)}},
+			},
+			AggregationBits: bitfield.Bitlist{0b00000011, 0b1},
+		})
+	}
+	if err := db.SaveAttestations(ctx, atts); err != nil {
+		t.Fatal(err)
+	}
+	deletedRoot, err := ssz.HashTreeRoot(atts[6].Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DeleteAttestations(ctx, [][32]byte{deletedRoot}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		f     *filters.QueryFilter
+		slots []uint64
+	}{
+		{
+			name:  "head block root",
+			f:     filters.NewFilter().SetHeadBlockRoot([]byte{0}),
+			slots: []uint64{0, 2, 4},
+		},
+		{
+			name:  "source epoch",
+			f:     filters.NewFilter().SetSourceEpoch(1),
+			slots: []uint64{4, 5, 7},
+		},
+		{
+			name:  "source root and target epoch",
+			f:     filters.NewFilter().SetSourceRoot([]byte("source")).SetTargetEpoch(1),
+			slots: []uint64{0, 1, 2, 3},
+		},
+		{
+			name:  "target root and head block root",
+			f:     filters.NewFilter().SetTargetRoot([]byte{1}).SetHeadBlockRoot([]byte{1}),
+			slots: []uint64{1, 5},
+		},
+	}
+	for _, tt := range tests {
+		retrieved, err := db.Attestations(ctx, tt.f)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		slots := make([]uint64, len(retrieved))
+		for i, att := range retrieved {
+			slots[i] = att.Data.Slot
+		}
+		sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+		if !reflect.DeepEqual(slots, tt.slots) {
+			t.Errorf("%s: expected slots %v, received %v", tt.name, tt.slots, slots)
+		}
+	}
+}
+
+func testStatesCRUD(t *testing.T, db iface.Database) {
+	ctx := context.Background()
+	roots := [][32]byte{{'A'}, {'B'}, {'C'}}
+	states := []*state.BeaconState{newState(t, 1), newState(t, 2), newState(t, 3)}
+	if db.HasState(ctx, roots[0]) {
+		t.Error("Expected state to not exist in the db")
+	}
+	if err := db.SaveState(ctx, nil, roots[0]); err == nil {
+		t.Error("Expected error when saving a nil state")
+	}
+	if err := db.SaveStates(ctx, states, roots); err != nil {
+		t.Fatal(err)
+	}
+	for i, root := range roots {
+		if !db.HasState(ctx, root) {
+			t.Errorf("Expected state %d to exist in the db", i)
+		}
+		retrieved, err := db.State(ctx, root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(states[i].InnerStateUnsafe(), retrieved.InnerStateUnsafe()) {
+			t.Errorf("Wanted state %v, received %v", states[i].InnerStateUnsafe(), retrieved.InnerStateUnsafe())
+		}
+	}
+	if err := db.DeleteState(ctx, roots[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DeleteStates(ctx, roots[1:]); err != nil {
+		t.Fatal(err)
+	}
+	for i, root := range roots {
+		if db.HasState(ctx, root) {
+			t.Errorf("Expected state %d to have been deleted from the db", i)
+		}
+		retrieved, err := db.State(ctx, root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if retrieved != nil {
+			t.Errorf("Expected nil state, received %v", retrieved)
+		}
+	}
+}
+
+func testStatesDeletionGuard(t *testing.T, db iface.Database) {
+	ctx := context.Background()
+	genesisRoot := [32]byte{'G'}
+	headRoot := [32]byte{'H'}
+	if err := db.SaveGenesisBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveStates(ctx, []*state.BeaconState{newState(t, 0), newState(t, 1)}, [][32]byte{genesisRoot, headRoot}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveHeadBlockRoot(ctx, headRoot); err != nil {
+		t.Fatal(err)
+	}
+	retrieved, err := db.GenesisState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retrieved == nil || retrieved.Slot() != 0 {
+		t.Errorf("Expected genesis state at slot 0, received %v", retrieved)
+	}
+	for _, root := range [][32]byte{genesisRoot, headRoot} {
+		if err := db.DeleteState(ctx, root); err == nil {
+			t.Errorf("Expected error when deleting protected state %#x", root)
+		}
+		if err := db.DeleteStates(ctx, [][32]byte{root}); err == nil {
+			t.Errorf("Expected error when deleting protected states %#x", root)
+		}
+		if !db.HasState(ctx, root) {
+			t.Errorf("Expected protected state %#x to be kept", root)
+		}
+	}
+}
+
+func testCheckpoints(t *testing.T, db iface.Database) {
+	ctx := context.Background()
+	genesisRoot := [32]byte{'G'}
+	if err := db.SaveGenesisBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	justified, err := db.JustifiedCheckpoint(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	finalized, err := db.FinalizedCheckpoint(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesisCheckpoint := &ethpb.Checkpoint{Root: genesisRoot[:]}
+	if !proto.Equal(justified, genesisCheckpoint) || !proto.Equal(finalized, genesisCheckpoint) {
+		t.Errorf("Expected checkpoints to default to the genesis root, received %v and %v", justified, finalized)
+	}
+
+	block := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 40, ParentRoot: genesisRoot[:]}}
+	if err := db.SaveBlock(ctx, block); err != nil {
+		t.Fatal(err)
+	}
+	root := blockRoot(t, block)
+	cp := &ethpb.Checkpoint{Epoch: 5, Root: root[:]}
+	if err := db.SaveJustifiedCheckpoint(ctx, cp); err == nil {
+		t.Error("Expected error when saving a justified checkpoint without a state")
+	}
+	if err := db.SaveFinalizedCheckpoint(ctx, cp); err == nil {
+		t.Error("Expected error when saving a finalized checkpoint without a state")
+	}
+	if err := db.SaveState(ctx, newState(t, 40), root); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveJustifiedCheckpoint(ctx, cp); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveFinalizedCheckpoint(ctx, cp); err != nil {
+		t.Fatal(err)
+	}
+	justified, err = db.JustifiedCheckpoint(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	finalized, err = db.FinalizedCheckpoint(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(justified, cp) || !proto.Equal(finalized, cp) {
+		t.Errorf("Wanted checkpoints %v, received %v and %v", cp, justified, finalized)
+	}
+	if err := db.DeleteState(ctx, root); err == nil {
+		t.Error("Expected error when deleting the finalized state")
+	}
+}
+
+func testFinalizedBlockIndex(t *testing.T, db iface.Database) {
+	ctx := context.Background()
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	genesisRoot := [32]byte{'G'}
+	if err := db.SaveGenesisBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	// A canonical chain of 4 epochs and a fork block from the first epoch.
+	canonical := make([]*ethpb.SignedBeaconBlock, 0)
+	parentRoot := genesisRoot
+	for i := uint64(1); i < 4*slotsPerEpoch; i++ {
+		block := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: i, ParentRoot: parentRoot[:]}}
+		canonical = append(canonical, block)
+		parentRoot = blockRoot(t, block)
+	}
+	fork := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 2, ParentRoot: []byte("fork")}}
+	if err := db.SaveBlocks(ctx, append(canonical, fork)); err != nil {
+		t.Fatal(err)
+	}
+
+	finalizedBlock := canonical[slotsPerEpoch-1]
+	finalizedRoot := blockRoot(t, finalizedBlock)
+	if err := db.SaveState(ctx, newState(t, finalizedBlock.Block.Slot), finalizedRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveFinalizedCheckpoint(ctx, &ethpb.Checkpoint{Epoch: 1, Root: finalizedRoot[:]}); err != nil {
+		t.Fatal(err)
+	}
+	// Every block up to the end of the finalized epoch is in the index, blocks after the
+	// following epoch are not.
+	for _, block := range canonical {
+		slot := block.Block.Slot
+		if slot < 2*slotsPerEpoch && !db.IsFinalizedBlock(ctx, blockRoot(t, block)) {
+			t.Errorf("Expected block at slot %d to be finalized", slot)
+		}
+		if slot >= 3*slotsPerEpoch && db.IsFinalizedBlock(ctx, blockRoot(t, block)) {
+			t.Errorf("Expected block at slot %d to not be finalized", slot)
+		}
+	}
+	if db.IsFinalizedBlock(ctx, blockRoot(t, fork)) {
+		t.Error("Expected fork block to not be finalized")
+	}
+}
+
+func testValidatorIndices(t *testing.T, db iface.Database) {
+	ctx := context.Background()
+	pubKey := [48]byte{'A'}
+	if _, _, err := db.ValidatorIndex(ctx, []byte{'A'}); err == nil {
+		t.Error("Expected error for a public key of the wrong length")
+	}
+	if err := db.SaveValidatorIndex(ctx, pubKey[:], 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveValidatorIndices(ctx, [][48]byte{{'B'}, {'C'}}, []uint64{11}); err == nil {
+		t.Error("Expected error for a different number of public keys and indices")
+	}
+	if err := db.SaveValidatorIndices(ctx, [][48]byte{{'B'}, {'C'}}, []uint64{11, 12}); err != nil {
+		t.Fatal(err)
+	}
+	index, ok, err := db.ValidatorIndex(ctx, pubKey[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || index != 10 {
+		t.Errorf("Expected validator index 10, received %d (exists: %v)", index, ok)
+	}
+	keyC := [48]byte{'C'}
+	index, ok, err = db.ValidatorIndex(ctx, keyC[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || index != 12 {
+		t.Errorf("Expected validator index 12, received %d (exists: %v)", index, ok)
+	}
+	if err := db.DeleteValidatorIndex(ctx, pubKey[:]); err != nil {
+		t.Fatal(err)
+	}
+	if db.HasValidatorIndex(ctx, pubKey[:]) {
+		t.Error("Expected validator index to have been deleted from the db")
+	}
+	if _, ok, err := db.ValidatorIndex(ctx, pubKey[:]); err != nil || ok {
+		t.Errorf("Expected no validator index, received exists: %v, err: %v", ok, err)
+	}
+}
+
+func testOperations(t *testing.T, db iface.Database) {
+	ctx := context.Background()
+	proposerSlashing := &ethpb.ProposerSlashing{ProposerIndex: 5}
+	attesterSlashing := &ethpb.AttesterSlashing{
+		Attestation_1: &ethpb.IndexedAttestation{
+			AttestingIndices: []uint64{1, 2},
+			Data:             &ethpb.AttestationData{BeaconBlockRoot: make([]byte, 32), Slot: 5},
+		},
+		Attestation_2: &ethpb.IndexedAttestation{
+			AttestingIndices: []uint64{2, 3},
+			Data:             &ethpb.AttestationData{BeaconBlockRoot: make([]byte, 32), Slot: 7},
+		},
+	}
+	exit := &ethpb.VoluntaryExit{Epoch: 5, ValidatorIndex: 7}
+	proposerRoot, err := ssz.HashTreeRoot(proposerSlashing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attesterRoot, err := ssz.HashTreeRoot(attesterSlashing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exitRoot, err := ssz.HashTreeRoot(exit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveProposerSlashing(ctx, proposerSlashing); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveAttesterSlashing(ctx, attesterSlashing); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveVoluntaryExit(ctx, exit); err != nil {
+		t.Fatal(err)
+	}
+	if !db.HasProposerSlashing(ctx, proposerRoot) || !db.HasAttesterSlashing(ctx, attesterRoot) || !db.HasVoluntaryExit(ctx, exitRoot) {
+		t.Error("Expected operations to exist in the db")
+	}
+	retrievedProposerSlashing, err := db.ProposerSlashing(ctx, proposerRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(proposerSlashing, retrievedProposerSlashing) {
+		t.Errorf("Wanted %v, received %v", proposerSlashing, retrievedProposerSlashing)
+	}
+	retrievedAttesterSlashing, err := db.AttesterSlashing(ctx, attesterRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(attesterSlashing, retrievedAttesterSlashing) {
+		t.Errorf("Wanted %v, received %v", attesterSlashing, retrievedAttesterSlashing)
+	}
+	retrievedExit, err := db.VoluntaryExit(ctx, exitRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(exit, retrievedExit) {
+		t.Errorf("Wanted %v, received %v", exit, retrievedExit)
+	}
+	if err := db.DeleteProposerSlashing(ctx, proposerRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DeleteAttesterSlashing(ctx, attesterRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DeleteVoluntaryExit(ctx, exitRoot); err != nil {
+		t.Fatal(err)
+	}
+	if db.HasProposerSlashing(ctx, proposerRoot) || db.HasAttesterSlashing(ctx, attesterRoot) || db.HasVoluntaryExit(ctx, exitRoot) {
+		t.Error("Expected operations to have been deleted from the db")
+	}
+}
+
+func testArchivedData(t *testing.T, db iface.Database) {
+	ctx := context.Background()
+	epoch := uint64(10)
+	changes := &pb.ArchivedActiveSetChanges{Activated: []uint64{1, 2}, Exited: []uint64{3}}
+	info := &pb.ArchivedCommitteeInfo{ProposerSeed: []byte{'P'}, AttesterSeed: []byte{'A'}}
+	balances := []uint64{32, 31, 30}
+	participation := &ethpb.ValidatorParticipation{GlobalParticipationRate: 0.5, VotedEther: 10, EligibleEther: 20}
+	if err := db.SaveArchivedActiveValidatorChanges(ctx, epoch, changes); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveArchivedCommitteeInfo(ctx, epoch, info); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveArchivedBalances(ctx, epoch, balances); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveArchivedValidatorParticipation(ctx, epoch, participation); err != nil {
+		t.Fatal(err)
+	}
+	retrievedChanges, err := db.ArchivedActiveValidatorChanges(ctx, epoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(changes, retrievedChanges) {
+		t.Errorf("Wanted %v, received %v", changes, retrievedChanges)
+	}
+	retrievedInfo, err := db.ArchivedCommitteeInfo(ctx, epoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(info, retrievedInfo) {
+		t.Errorf("Wanted %v, received %v", info, retrievedInfo)
+	}
+	retrievedBalances, err := db.ArchivedBalances(ctx, epoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(balances, retrievedBalances) {
+		t.Errorf("Wanted %v, received %v", balances, retrievedBalances)
+	}
+	retrievedParticipation, err := db.ArchivedValidatorParticipation(ctx, epoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(participation, retrievedParticipation) {
+		t.Errorf("Wanted %v, received %v", participation, retrievedParticipation)
+	}
+	missing, err := db.ArchivedCommitteeInfo(ctx, epoch+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if missing != nil {
+		t.Errorf("Expected no committee info for an unknown epoch, received %v", missing)
+	}
+}
+
+func testChainMetadata(t *testing.T, db iface.Database) {
+	ctx := context.Background()
+	addr := common.HexToAddress("0x0cd549b0d8ab1d1f8a1f6e3f6e2a0de8e3c1d0e5")
+	if err := db.SaveDepositContractAddress(ctx, addr); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveDepositContractAddress(ctx, common.HexToAddress("0x01")); err == nil {
+		t.Error("Expected error when overriding the deposit contract address")
+	}
+	retrievedAddr, err := db.DepositContractAddress(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if common.BytesToAddress(retrievedAddr) != addr {
+		t.Errorf("Wanted deposit contract address %#x, received %#x", addr, retrievedAddr)
+	}
+
+	root, err := db.GenesisValidatorsRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != nil {
+		t.Errorf("Expected no genesis validators root, received %#x", root)
+	}
+	genesisValidatorsRoot := [32]byte{'v'}
+	if err := db.SaveGenesisValidatorsRoot(ctx, genesisValidatorsRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveGenesisValidatorsRoot(ctx, genesisValidatorsRoot); err != nil {
+		t.Errorf("Expected saving the same genesis validators root again to succeed, received %v", err)
+	}
+	if err := db.SaveGenesisValidatorsRoot(ctx, [32]byte{'w'}); err == nil {
+		t.Error("Expected error when overriding the genesis validators root")
+	}
+	root, err = db.GenesisValidatorsRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(root, genesisValidatorsRoot[:]) {
+		t.Errorf("Wanted genesis validators root %#x, received %#x", genesisValidatorsRoot, root)
+	}
+
+	data, err := db.PowchainData(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data != nil {
+		t.Errorf("Expected no powchain data, received %v", data)
+	}
+	data = &dbpb.ETH1ChainData{ChainstartData: &dbpb.ChainStartData{Chainstarted: true, GenesisTime: 100}}
+	if err := db.SavePowchainData(ctx, data); err != nil {
+		t.Fatal(err)
+	}
+	retrievedData, err := db.PowchainData(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(data, retrievedData) {
+		t.Errorf("Wanted %v, received %v", data, retrievedData)
+	}
+}
+
+func testForkChoiceSnapshot(t *testing.T, db iface.Database) {
+	ctx := context.Background()
+	snapshot, err := db.ForkChoiceSnapshot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot != nil {
+		t.Errorf("Expected no fork choice snapshot, received %v", snapshot)
+	}
+	root := [32]byte{'A'}
+	for _, epoch := range []uint64{1, 2} {
+		snapshot = &dbpb.ForkChoiceSnapshot{
+			JustifiedEpoch: epoch,
+			FinalizedEpoch: epoch,
+			FinalizedRoot:  root[:],
+			Nodes:          []*dbpb.ForkChoiceNode{{Slot: epoch, Root: root[:], Parent: ^uint64(0), Weight: 32}},
+			Votes:          []*dbpb.ForkChoiceVote{{CurrentRoot: root[:], NextRoot: root[:], NextEpoch: epoch}},
+			Balances:       []uint64{32},
+		}
+		if err := db.SaveForkChoiceSnapshot(ctx, snapshot); err != nil {
+			t.Fatal(err)
+		}
+	}
+	retrieved, err := db.ForkChoiceSnapshot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(snapshot, retrieved) {
+		t.Errorf("Wanted the last saved snapshot %v, received %v", snapshot, retrieved)
+	}
+}
+
+func testBucketStats(t *testing.T, db iface.Database) {
+	ctx := context.Background()
+	for i := byte(0); i < 3; i++ {
+		pubKey := [48]byte{i}
+		if err := db.SaveValidatorIndex(ctx, pubKey[:], uint64(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stats, err := db.BucketStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range stats {
+		if s.Name != "validators" {
+			continue
+		}
+		if s.KeyCount != 3 || s.Size <= 0 {
+			t.Errorf("Expected 3 keys and a positive size in the validators bucket, received %+v", s)
+		}
+		return
+	}
+	t.Errorf("Expected stats for the validators bucket, received %v", stats)
+}
+
+func newState(t *testing.T, slot uint64) *state.BeaconState {
+	st, err := state.InitializeFromProto(&pb.BeaconState{Slot: slot})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
+func blockRoot(t *testing.T, block *ethpb.SignedBeaconBlock) [32]byte {
+	root, err := ssz.HashTreeRoot(block.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func blockSlots(blocks []*ethpb.SignedBeaconBlock) []uint64 {
+	slots := make([]uint64, len(blocks))
+	for i, b := range blocks {
+		slots[i] = b.Block.Slot
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+	return slots
+}
+
+func slotRange(start uint64, end uint64, step uint64) []uint64 {
+	slots := make([]uint64, 0)
+	for i := start; i <= end; i += step {
+		slots = append(slots, i)
+	}
+	return slots
+}
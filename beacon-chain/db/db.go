@@ -1,8 +1,13 @@
 package db
 
-import "github.com/prysmaticlabs/prysm/beacon-chain/db/kv"
+import "github.com/prysmaticlabs/prysm/beacon-chain/db/export"
 
 // NewDB initializes a new DB.
 func NewDB(dirPath string) (Database, error) {
-	return kv.NewKVStore(dirPath)
+	db, err := newBackend(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return export.Wrap(db, nil)
 }
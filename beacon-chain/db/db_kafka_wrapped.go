@@ -1,16 +1,16 @@
 package db
 
 import (
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/export"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db/kafka"
-	"github.com/prysmaticlabs/prysm/beacon-chain/db/kv"
 )
 
-// NewDB initializes a new DB with kafka wrapper.
+// NewDB initializes a new DB with an exporter which may also publish to kafka.
 func NewDB(dirPath string) (Database, error) {
-	db, err := kv.NewKVStore(dirPath)
+	db, err := newBackend(dirPath)
 	if err != nil {
 		return nil, err
 	}
 
-	return kafka.Wrap(db)
+	return export.Wrap(db, map[string]export.SinkFactory{"kafka": kafka.NewSink})
 }
@@ -0,0 +1,303 @@
+// Package export defines a database wrapper which exports the blocks, attestations, slashings and
+// voluntary exits saved to the database to pluggable sinks, so that downstream indexers can consume
+// chain data as it is processed by the beacon node.
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/traceutil"
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+var _ = iface.Database(&Exporter{})
+var log = logrus.WithField("prefix", "exporter")
+var marshaler = &jsonpb.Marshaler{}
+
+const (
+	// exportQueueSize bounds the number of saves waiting to be exported.
+	exportQueueSize = 1024
+	// exportDrainTimeout bounds how long Close waits for the queued saves to be exported, the
+	// exports still running afterwards are canceled.
+	exportDrainTimeout = 10 * time.Second
+)
+
+// Exporter wraps a database interface and exports certain objects to the sinks configured for
+// their type. The objects are exported by a single worker in the order they were saved.
+type Exporter struct {
+	db    iface.Database
+	sinks map[ObjectType][]ExportSink
+	all   []ExportSink
+	queue *exportQueue
+}
+
+// exportJob holds the messages of a single save.
+type exportJob struct {
+	span  *trace.Span
+	sinks []ExportSink
+	topic string
+	msgs  []proto.Message
+}
+
+// exportQueue is the bounded queue of the export worker. Once it is closed, saves are no longer
+// queued and the worker exits after the queued jobs are exported.
+type exportQueue struct {
+	lock   sync.RWMutex
+	closed bool
+	jobs   chan *exportJob
+	done   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newExportQueue() *exportQueue {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &exportQueue{
+		jobs:   make(chan *exportJob, exportQueueSize),
+		done:   make(chan struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// push queues a job without blocking, returning false if the queue is full or closed.
+func (q *exportQueue) push(job *exportJob) bool {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+	if q.closed {
+		return false
+	}
+	select {
+	case q.jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// close stops accepting jobs and waits for the worker to export the queued ones, canceling the
+// remaining exports after the drain timeout.
+func (q *exportQueue) close() {
+	q.lock.Lock()
+	if q.closed {
+		q.lock.Unlock()
+		return
+	}
+	q.closed = true
+	close(q.jobs)
+	q.lock.Unlock()
+
+	select {
+	case <-q.done:
+	case <-time.After(exportDrainTimeout):
+		log.WithField("queued", len(q.jobs)).Warn("Timed out exporting queued objects, canceling the remaining exports")
+		q.cancel()
+		<-q.done
+	}
+	q.cancel()
+}
+
+// Wrap the db with an exporter for the export sinks configured by the global flags. Sinks of the
+// types in the factories map are available in addition to the built-in file and webhook sinks.
+// If no export sink is configured, this does not wrap the database, but returns the underlying
+// database pointer itself.
+func Wrap(db iface.Database, factories map[string]SinkFactory) (iface.Database, error) {
+	specs := append([]string{}, flags.Get().ExportSinks...)
+	if servers := featureconfig.Get().KafkaBootstrapServers; servers != "" {
+		specs = append(specs, fmt.Sprintf("%s,%s=kafka:%s", Blocks, Attestations, servers))
+	}
+	if len(specs) == 0 {
+		return db, nil
+	}
+
+	available := map[string]SinkFactory{
+		"file": func(target string) (ExportSink, error) {
+			return NewFileSink(target, flags.Get().ExportFileMaxSize)
+		},
+		"webhook": func(target string) (ExportSink, error) {
+			return NewWebhookSink(target, flags.Get().ExportWebhookRetries)
+		},
+	}
+	for name, factory := range factories {
+		available[name] = factory
+	}
+
+	e := &Exporter{db: db, sinks: make(map[ObjectType][]ExportSink), queue: newExportQueue()}
+	// Sinks with the same target are shared between specifications, so a file is never written
+	// by two sinks at once.
+	byTarget := make(map[string]ExportSink)
+	for _, s := range specs {
+		spec, err := parseSinkSpec(s)
+		if err != nil {
+			e.closeSinks()
+			return nil, err
+		}
+		key := spec.sink + ":" + spec.target
+		sink, ok := byTarget[key]
+		if !ok {
+			factory, ok := available[spec.sink]
+			if !ok {
+				e.closeSinks()
+				return nil, fmt.Errorf("export sink %q is not available in this build", spec.sink)
+			}
+			sink, err = factory(spec.target)
+			if err != nil {
+				e.closeSinks()
+				return nil, err
+			}
+			byTarget[key] = sink
+			e.all = append(e.all, sink)
+		}
+		for _, t := range spec.types {
+			if !containsSink(e.sinks[t], sink) {
+				e.sinks[t] = append(e.sinks[t], sink)
+			}
+		}
+		log.WithFields(logrus.Fields{
+			"sink":  spec.sink,
+			"types": spec.types,
+		}).Info("Exporting database objects")
+	}
+	go e.run()
+	return e, nil
+}
+
+func (e Exporter) closeSinks() {
+	for _, sink := range e.all {
+		if err := sink.Close(); err != nil {
+			log.WithError(err).Error("Failed to close export sink")
+		}
+	}
+}
+
+func containsSink(sinks []ExportSink, sink ExportSink) bool {
+	for _, s := range sinks {
+		if s == sink {
+			return true
+		}
+	}
+	return false
+}
+
+// export queues messages for the sinks of an object type. Messages are dropped if the export
+// worker falls too far behind, so that a slow sink never blocks the database.
+func (e Exporter) export(ctx context.Context, t ObjectType, topic string, msgs ...proto.Message) {
+	sinks := e.sinks[t]
+	if len(sinks) == 0 {
+		return
+	}
+	// The export outlives the database call, so it keeps the trace span but not the
+	// cancellation of its context.
+	job := &exportJob{span: trace.FromContext(ctx), sinks: sinks, topic: topic, msgs: msgs}
+	if !e.queue.push(job) {
+		log.WithField("topic", topic).Warn("Export queue is full or closed, dropping objects")
+	}
+}
+
+// run exports the queued jobs until the queue is closed and drained.
+func (e Exporter) run() {
+	defer close(e.queue.done)
+	for job := range e.queue.jobs {
+		ctx := trace.NewContext(e.queue.ctx, job.span)
+		for _, msg := range job.msgs {
+			if err := e.publish(ctx, job.sinks, job.topic, msg); err != nil {
+				log.WithError(err).WithField("topic", job.topic).Error("Failed to export object")
+			}
+		}
+	}
+}
+
+func (e Exporter) publish(ctx context.Context, sinks []ExportSink, topic string, msg proto.Message) error {
+	ctx, span := trace.StartSpan(ctx, "export.publish")
+	defer span.End()
+
+	buf := bytes.NewBuffer(nil)
+	if err := marshaler.Marshal(buf, msg); err != nil {
+		traceutil.AnnotateError(span, err)
+		return err
+	}
+
+	key, err := ssz.HashTreeRoot(msg)
+	if err != nil {
+		traceutil.AnnotateError(span, err)
+		return err
+	}
+
+	var lastErr error
+	for _, sink := range sinks {
+		if err := sink.Export(ctx, topic, key[:], buf.Bytes()); err != nil {
+			traceutil.AnnotateError(span, err)
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// Close exports the queued objects, then closes the export sinks and underlying db.
+func (e Exporter) Close() error {
+	e.queue.close()
+	e.closeSinks()
+	return e.db.Close()
+}
+
+// SaveAttestation exports the attestation to the attestation sinks.
+func (e Exporter) SaveAttestation(ctx context.Context, att *eth.Attestation) error {
+	e.export(ctx, Attestations, attestationTopic, att)
+	return e.db.SaveAttestation(ctx, att)
+}
+
+// SaveAttestations exports the attestations to the attestation sinks.
+func (e Exporter) SaveAttestations(ctx context.Context, atts []*eth.Attestation) error {
+	msgs := make([]proto.Message, len(atts))
+	for i, att := range atts {
+		msgs[i] = att
+	}
+	e.export(ctx, Attestations, attestationTopic, msgs...)
+	return e.db.SaveAttestations(ctx, atts)
+}
+
+// SaveBlock exports the block to the block sinks.
+func (e Exporter) SaveBlock(ctx context.Context, block *eth.SignedBeaconBlock) error {
+	e.export(ctx, Blocks, blockTopic, block)
+	return e.db.SaveBlock(ctx, block)
+}
+
+// SaveBlocks exports the blocks to the block sinks.
+func (e Exporter) SaveBlocks(ctx context.Context, blocks []*eth.SignedBeaconBlock) error {
+	msgs := make([]proto.Message, len(blocks))
+	for i, block := range blocks {
+		msgs[i] = block
+	}
+	e.export(ctx, Blocks, blockTopic, msgs...)
+	return e.db.SaveBlocks(ctx, blocks)
+}
+
+// SaveProposerSlashing exports the proposer slashing to the slashing sinks.
+func (e Exporter) SaveProposerSlashing(ctx context.Context, slashing *eth.ProposerSlashing) error {
+	e.export(ctx, Slashings, proposerSlashingTopic, slashing)
+	return e.db.SaveProposerSlashing(ctx, slashing)
+}
+
+// SaveAttesterSlashing exports the attester slashing to the slashing sinks.
+func (e Exporter) SaveAttesterSlashing(ctx context.Context, slashing *eth.AttesterSlashing) error {
+	e.export(ctx, Slashings, attesterSlashingTopic, slashing)
+	return e.db.SaveAttesterSlashing(ctx, slashing)
+}
+
+// SaveVoluntaryExit exports the voluntary exit to the exit sinks.
+func (e Exporter) SaveVoluntaryExit(ctx context.Context, exit *eth.VoluntaryExit) error {
+	e.export(ctx, Exits, voluntaryExitTopic, exit)
+	return e.db.SaveVoluntaryExit(ctx, exit)
+}
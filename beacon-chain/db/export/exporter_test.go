@@ -0,0 +1,112 @@
+package export
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/kv"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+)
+
+type mockSink struct {
+	lock   sync.Mutex
+	topics []string
+	closed bool
+}
+
+func (m *mockSink) Export(ctx context.Context, topic string, key []byte, value []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.topics = append(m.topics, topic)
+	return nil
+}
+
+func (m *mockSink) Close() error {
+	m.closed = true
+	return nil
+}
+
+func TestWrap_NoSinks(t *testing.T) {
+	flags.Init(&flags.GlobalFlags{})
+	db, err := kv.NewMemoryStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	wrapped, err := Wrap(db, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wrapped != db {
+		t.Error("Expected the database to not be wrapped without export sinks")
+	}
+}
+
+func TestWrap_UnavailableSink(t *testing.T) {
+	flags.Init(&flags.GlobalFlags{ExportSinks: []string{"all=kafka:localhost:9092"}})
+	defer flags.Init(&flags.GlobalFlags{})
+	db, err := kv.NewMemoryStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := Wrap(db, nil); err == nil {
+		t.Error("Expected error for a sink which is not available")
+	}
+}
+
+func TestExporter_ExportsPerObjectType(t *testing.T) {
+	blockSink := &mockSink{}
+	opsSink := &mockSink{}
+	flags.Init(&flags.GlobalFlags{ExportSinks: []string{
+		"blocks=mock:blocks",
+		"slashings,exits=mock:operations",
+		"exits=mock:operations",
+	}})
+	defer flags.Init(&flags.GlobalFlags{})
+	factories := map[string]SinkFactory{
+		"mock": func(target string) (ExportSink, error) {
+			if target == "blocks" {
+				return blockSink, nil
+			}
+			return opsSink, nil
+		},
+	}
+	db, err := kv.NewMemoryStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapped, err := Wrap(db, factories)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := wrapped.SaveBlock(ctx, &eth.SignedBeaconBlock{Block: &eth.BeaconBlock{Slot: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := wrapped.SaveVoluntaryExit(ctx, &eth.VoluntaryExit{Epoch: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := wrapped.SaveProposerSlashing(ctx, &eth.ProposerSlashing{ProposerIndex: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := wrapped.SaveAttestation(ctx, &eth.Attestation{Data: &eth.AttestationData{}, AggregationBits: []byte{0b1}}); err != nil {
+		t.Fatal(err)
+	}
+	// Close exports the queued messages before closing the sinks.
+	if err := wrapped.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !blockSink.closed || !opsSink.closed {
+		t.Error("Expected the sinks to be closed with the database")
+	}
+	if !reflect.DeepEqual(blockSink.topics, []string{blockTopic}) {
+		t.Errorf("Expected a single block export, received %v", blockSink.topics)
+	}
+	if !reflect.DeepEqual(opsSink.topics, []string{voluntaryExitTopic, proposerSlashingTopic}) {
+		t.Errorf("Expected the exit and slashing to be exported once each in order, received %v", opsSink.topics)
+	}
+}
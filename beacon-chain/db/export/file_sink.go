@@ -0,0 +1,126 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var errFileSinkClosed = errors.New("file sink is closed")
+
+const (
+	exportFileExtension = ".ndjson"
+	rotatedFileTimeFmt  = "20060102T150405.000000000"
+)
+
+// FileSink writes the messages of each topic as newline-delimited JSON to a file named after the
+// topic in its directory. Once a file would grow beyond the maximum size, it is renamed with the
+// time of the rotation as a suffix and a new file is started.
+type FileSink struct {
+	dir     string
+	maxSize int64
+	lock    sync.Mutex
+	files   map[string]*exportFile
+	closed  bool
+}
+
+type exportFile struct {
+	f    *os.File
+	size int64
+}
+
+// NewFileSink creates a file sink in the directory specified. A max size of 0 disables rotation.
+func NewFileSink(dir string, maxSize int64) (*FileSink, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "could not create export directory")
+	}
+	return &FileSink{
+		dir:     dir,
+		maxSize: maxSize,
+		files:   make(map[string]*exportFile),
+	}, nil
+}
+
+// Export appends the message to the file of the topic.
+func (s *FileSink) Export(ctx context.Context, topic string, key []byte, value []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return errFileSinkClosed
+	}
+
+	line := append(append(make([]byte, 0, len(value)+1), value...), '\n')
+	file, err := s.file(topic)
+	if err != nil {
+		return err
+	}
+	if s.maxSize > 0 && file.size > 0 && file.size+int64(len(line)) > s.maxSize {
+		if err := s.rotate(topic); err != nil {
+			return errors.Wrap(err, "could not rotate export file")
+		}
+		if file, err = s.file(topic); err != nil {
+			return err
+		}
+	}
+	n, err := file.f.Write(line)
+	file.size += int64(n)
+	return err
+}
+
+// Close syncs and closes the open export files, the sink exports nothing afterwards.
+func (s *FileSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.closed = true
+
+	var lastErr error
+	for topic, file := range s.files {
+		if err := file.f.Sync(); err != nil {
+			lastErr = err
+		}
+		if err := file.f.Close(); err != nil {
+			lastErr = err
+		}
+		delete(s.files, topic)
+	}
+	return lastErr
+}
+
+// file returns the open file of a topic, opening it for appending if needed.
+func (s *FileSink) file(topic string) (*exportFile, error) {
+	if file, ok := s.files[topic]; ok {
+		return file, nil
+	}
+	f, err := os.OpenFile(s.path(topic), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	file := &exportFile{f: f, size: info.Size()}
+	s.files[topic] = file
+	return file, nil
+}
+
+// rotate closes the file of a topic and moves it aside.
+func (s *FileSink) rotate(topic string) error {
+	file := s.files[topic]
+	delete(s.files, topic)
+	if err := file.f.Close(); err != nil {
+		return err
+	}
+	rotated := path.Join(s.dir, fmt.Sprintf("%s.%s%s", topic, time.Now().UTC().Format(rotatedFileTimeFmt), exportFileExtension))
+	return os.Rename(s.path(topic), rotated)
+}
+
+func (s *FileSink) path(topic string) string {
+	return path.Join(s.dir, topic+exportFileExtension)
+}
@@ -0,0 +1,115 @@
+package export
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestFileSink_RotatesFiles(t *testing.T) {
+	dir := path.Join(testutil.TempDir(), "file_sink_rotation")
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// Room for two 9 byte messages per file.
+	sink, err := NewFileSink(dir, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, msg := range []string{`{"a":"1"}`, `{"a":"2"}`, `{"a":"3"}`} {
+		if err := sink.Export(ctx, blockTopic, nil, []byte(msg)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.Export(ctx, attestationTopic, nil, []byte(`{"b":"1"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := make(map[string]string)
+	var rotated []string
+	for _, f := range files {
+		b, err := ioutil.ReadFile(path.Join(dir, f.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents[f.Name()] = string(b)
+		if strings.HasPrefix(f.Name(), blockTopic+".") && f.Name() != blockTopic+exportFileExtension {
+			rotated = append(rotated, f.Name())
+		}
+	}
+	if len(files) != 3 || len(rotated) != 1 {
+		t.Fatalf("Expected a current and a rotated block file and an attestation file, received %v", files)
+	}
+	if want := "{\"a\":\"1\"}\n{\"a\":\"2\"}\n"; contents[rotated[0]] != want {
+		t.Errorf("Wanted rotated file %q, received %q", want, contents[rotated[0]])
+	}
+	if want := "{\"a\":\"3\"}\n"; contents[blockTopic+exportFileExtension] != want {
+		t.Errorf("Wanted current file %q, received %q", want, contents[blockTopic+exportFileExtension])
+	}
+	if want := "{\"b\":\"1\"}\n"; contents[attestationTopic+exportFileExtension] != want {
+		t.Errorf("Wanted attestation file %q, received %q", want, contents[attestationTopic+exportFileExtension])
+	}
+}
+
+func TestFileSink_AppendsToExistingFile(t *testing.T) {
+	dir := path.Join(testutil.TempDir(), "file_sink_append")
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ctx := context.Background()
+	for _, msg := range []string{"1", "2"} {
+		sink, err := NewFileSink(dir, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sink.Export(ctx, blockTopic, nil, []byte(msg)); err != nil {
+			t.Fatal(err)
+		}
+		if err := sink.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b, err := ioutil.ReadFile(path.Join(dir, blockTopic+exportFileExtension))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "1\n2\n" {
+		t.Errorf("Expected messages to be appended after restarting the sink, received %q", b)
+	}
+}
+
+func TestFileSink_ExportAfterClose(t *testing.T) {
+	dir := path.Join(testutil.TempDir(), "file_sink_closed")
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	sink, err := NewFileSink(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Export(context.Background(), blockTopic, nil, []byte("1")); err != errFileSinkClosed {
+		t.Errorf("Expected error %v, received %v", errFileSinkClosed, err)
+	}
+	if _, err := os.Stat(path.Join(dir, blockTopic+exportFileExtension)); !os.IsNotExist(err) {
+		t.Error("Expected no export file to be opened after the sink is closed")
+	}
+}
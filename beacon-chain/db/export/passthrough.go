@@ -1,4 +1,4 @@
-package kafka
+package export
 
 import (
 	"context"
@@ -7,6 +7,7 @@ import (
 	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/proto/beacon/db"
 	ethereum_beacon_p2p_v1 "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
@@ -22,6 +23,11 @@ func (e Exporter) ClearDB() error {
 	return e.db.ClearDB()
 }
 
+// BucketStats -- passthrough.
+func (e Exporter) BucketStats(ctx context.Context) ([]*iface.BucketStats, error) {
+	return e.db.BucketStats(ctx)
+}
+
 // Backup -- passthrough.
 func (e Exporter) Backup(ctx context.Context) error {
 	return e.db.Backup(ctx)
@@ -117,6 +123,11 @@ func (e Exporter) GenesisState(ctx context.Context) (*state.BeaconState, error)
 	return e.db.GenesisState(ctx)
 }
 
+// GenesisValidatorsRoot -- passthrough.
+func (e Exporter) GenesisValidatorsRoot(ctx context.Context) ([]byte, error) {
+	return e.db.GenesisValidatorsRoot(ctx)
+}
+
 // ProposerSlashing -- passthrough.
 func (e Exporter) ProposerSlashing(ctx context.Context, slashingRoot [32]byte) (*eth.ProposerSlashing, error) {
 	return e.db.ProposerSlashing(ctx, slashingRoot)
@@ -212,6 +223,26 @@ func (e Exporter) SaveGenesisBlockRoot(ctx context.Context, blockRoot [32]byte)
 	return e.db.SaveGenesisBlockRoot(ctx, blockRoot)
 }
 
+// AnchorBlock -- passthrough.
+func (e Exporter) AnchorBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, error) {
+	return e.db.AnchorBlock(ctx)
+}
+
+// SaveAnchorBlockRoot -- passthrough.
+func (e Exporter) SaveAnchorBlockRoot(ctx context.Context, blockRoot [32]byte) error {
+	return e.db.SaveAnchorBlockRoot(ctx, blockRoot)
+}
+
+// BackfillBlock -- passthrough.
+func (e Exporter) BackfillBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, error) {
+	return e.db.BackfillBlock(ctx)
+}
+
+// SaveBackfillBlockRoot -- passthrough.
+func (e Exporter) SaveBackfillBlockRoot(ctx context.Context, blockRoot [32]byte) error {
+	return e.db.SaveBackfillBlockRoot(ctx, blockRoot)
+}
+
 // SaveValidatorIndex -- passthrough.
 func (e Exporter) SaveValidatorIndex(ctx context.Context, publicKey []byte, validatorIdx uint64) error {
 	return e.db.SaveValidatorIndex(ctx, publicKey, validatorIdx)
@@ -232,21 +263,6 @@ func (e Exporter) SaveStates(ctx context.Context, states []*state.BeaconState, b
 	return e.db.SaveStates(ctx, states, blockRoots)
 }
 
-// SaveProposerSlashing -- passthrough.
-func (e Exporter) SaveProposerSlashing(ctx context.Context, slashing *eth.ProposerSlashing) error {
-	return e.db.SaveProposerSlashing(ctx, slashing)
-}
-
-// SaveAttesterSlashing -- passthrough.
-func (e Exporter) SaveAttesterSlashing(ctx context.Context, slashing *eth.AttesterSlashing) error {
-	return e.db.SaveAttesterSlashing(ctx, slashing)
-}
-
-// SaveVoluntaryExit -- passthrough.
-func (e Exporter) SaveVoluntaryExit(ctx context.Context, exit *eth.VoluntaryExit) error {
-	return e.db.SaveVoluntaryExit(ctx, exit)
-}
-
 // SaveJustifiedCheckpoint -- passthrough.
 func (e Exporter) SaveJustifiedCheckpoint(ctx context.Context, checkpoint *eth.Checkpoint) error {
 	return e.db.SaveJustifiedCheckpoint(ctx, checkpoint)
@@ -292,6 +308,11 @@ func (e Exporter) DeleteStates(ctx context.Context, blockRoots [][32]byte) error
 	return e.db.DeleteStates(ctx, blockRoots)
 }
 
+// SaveGenesisValidatorsRoot -- passthrough.
+func (e Exporter) SaveGenesisValidatorsRoot(ctx context.Context, root [32]byte) error {
+	return e.db.SaveGenesisValidatorsRoot(ctx, root)
+}
+
 // HasState -- passthrough.
 func (e Exporter) HasState(ctx context.Context, blockRoot [32]byte) bool {
 	return e.db.HasState(ctx, blockRoot)
@@ -311,3 +332,13 @@ func (e Exporter) PowchainData(ctx context.Context) (*db.ETH1ChainData, error) {
 func (e Exporter) SavePowchainData(ctx context.Context, data *db.ETH1ChainData) error {
 	return e.db.SavePowchainData(ctx, data)
 }
+
+// ForkChoiceSnapshot -- passthrough
+func (e Exporter) ForkChoiceSnapshot(ctx context.Context) (*db.ForkChoiceSnapshot, error) {
+	return e.db.ForkChoiceSnapshot(ctx)
+}
+
+// SaveForkChoiceSnapshot -- passthrough
+func (e Exporter) SaveForkChoiceSnapshot(ctx context.Context, snapshot *db.ForkChoiceSnapshot) error {
+	return e.db.SaveForkChoiceSnapshot(ctx, snapshot)
+}
@@ -0,0 +1,101 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ObjectType is a type of object which is exported when it is saved to the database.
+type ObjectType string
+
+const (
+	// Blocks are exported to the beacon_block topic.
+	Blocks ObjectType = "blocks"
+	// Attestations are exported to the beacon_attestation topic.
+	Attestations ObjectType = "attestations"
+	// Slashings are exported to the beacon_proposer_slashing and beacon_attester_slashing topics.
+	Slashings ObjectType = "slashings"
+	// Exits are exported to the beacon_voluntary_exit topic.
+	Exits ObjectType = "exits"
+
+	allObjectTypes = "all"
+)
+
+// Topics under which the objects are exported.
+const (
+	blockTopic            = "beacon_block"
+	attestationTopic      = "beacon_attestation"
+	proposerSlashingTopic = "beacon_proposer_slashing"
+	attesterSlashingTopic = "beacon_attester_slashing"
+	voluntaryExitTopic    = "beacon_voluntary_exit"
+)
+
+var objectTypes = []ObjectType{Blocks, Attestations, Slashings, Exits}
+
+// ExportSink receives the objects saved to the database as JSON messages.
+type ExportSink interface {
+	// Export a JSON encoded message, keyed by the hash tree root of the object, to a topic.
+	Export(ctx context.Context, topic string, key []byte, value []byte) error
+	// Close flushes any buffered messages and releases the resources of the sink.
+	Close() error
+}
+
+// SinkFactory creates an export sink for the target of a sink specification.
+type SinkFactory func(target string) (ExportSink, error)
+
+// sinkSpec is a parsed sink specification of the form <types>=<sink>:<target>, for example
+// "blocks,attestations=file:/var/lib/prysm/export".
+type sinkSpec struct {
+	types  []ObjectType
+	sink   string
+	target string
+}
+
+func parseSinkSpec(spec string) (*sinkSpec, error) {
+	parts := strings.SplitN(spec, "=", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid export sink %q, expected <types>=<sink>:<target>", spec)
+	}
+	sinkParts := strings.SplitN(parts[1], ":", 2)
+	if len(sinkParts) != 2 || sinkParts[0] == "" || sinkParts[1] == "" {
+		return nil, fmt.Errorf("invalid export sink %q, expected <types>=<sink>:<target>", spec)
+	}
+	types, err := parseObjectTypes(parts[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid export sink %q", spec)
+	}
+	return &sinkSpec{
+		types:  types,
+		sink:   sinkParts[0],
+		target: sinkParts[1],
+	}, nil
+}
+
+func parseObjectTypes(s string) ([]ObjectType, error) {
+	types := make([]ObjectType, 0, len(objectTypes))
+	seen := make(map[ObjectType]bool)
+	for _, t := range strings.Split(s, ",") {
+		t = strings.TrimSpace(t)
+		if t == allObjectTypes {
+			return objectTypes, nil
+		}
+		valid := false
+		for _, objectType := range objectTypes {
+			if ObjectType(t) == objectType {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown object type %q", t)
+		}
+		if !seen[ObjectType(t)] {
+			seen[ObjectType(t)] = true
+			types = append(types, ObjectType(t))
+		}
+	}
+	return types, nil
+}
@@ -0,0 +1,47 @@
+package export
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSinkSpec(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    *sinkSpec
+		wantErr bool
+	}{
+		{
+			spec: "blocks,attestations=file:/tmp/export",
+			want: &sinkSpec{types: []ObjectType{Blocks, Attestations}, sink: "file", target: "/tmp/export"},
+		},
+		{
+			spec: "all=webhook:https://indexer.example.com/hook",
+			want: &sinkSpec{types: objectTypes, sink: "webhook", target: "https://indexer.example.com/hook"},
+		},
+		{
+			spec: "exits, slashings,exits=kafka:localhost:9092",
+			want: &sinkSpec{types: []ObjectType{Exits, Slashings}, sink: "kafka", target: "localhost:9092"},
+		},
+		{spec: "file:/tmp/export", wantErr: true},
+		{spec: "blocks=file", wantErr: true},
+		{spec: "blocks=:/tmp/export", wantErr: true},
+		{spec: "deposits=file:/tmp/export", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseSinkSpec(tt.spec)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Expected error parsing %q", tt.spec)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Could not parse %q: %v", tt.spec, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parsing %q: wanted %+v, received %+v", tt.spec, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	webhookTimeout        = 10 * time.Second
+	webhookInitialBackoff = 500 * time.Millisecond
+	// Headers which carry the topic and key of an exported message.
+	topicHeader = "X-Prysm-Topic"
+	keyHeader   = "X-Prysm-Key"
+)
+
+// WebhookSink posts every message to an HTTP endpoint. Requests which fail with a network error, a
+// rate limit or a server error are retried with exponential backoff, other client errors are not.
+type WebhookSink struct {
+	url     string
+	retries int
+	backoff time.Duration
+	client  *http.Client
+}
+
+// NewWebhookSink creates a webhook sink which posts to the http or https URL specified.
+func NewWebhookSink(target string, retries int) (*WebhookSink, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid webhook URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid webhook URL %q, expected an http or https URL", target)
+	}
+	return &WebhookSink{
+		url:     target,
+		retries: retries,
+		backoff: webhookInitialBackoff,
+		client:  &http.Client{Timeout: webhookTimeout},
+	}, nil
+}
+
+// Export posts the message to the webhook, retrying failed requests.
+func (s *WebhookSink) Export(ctx context.Context, topic string, key []byte, value []byte) error {
+	backoff := s.backoff
+	var err error
+	for attempt := 0; attempt <= s.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+		var retry bool
+		retry, err = s.post(ctx, topic, key, value)
+		if err == nil || !retry {
+			return err
+		}
+		log.WithError(err).WithField("attempt", attempt+1).Debug("Webhook export failed")
+	}
+	return errors.Wrapf(err, "webhook export failed after %d attempts", s.retries+1)
+}
+
+// Close is a no-op, as the webhook sink does not buffer messages.
+func (s *WebhookSink) Close() error {
+	return nil
+}
+
+// post sends a single request to the webhook, returning whether a failed request may be retried.
+func (s *WebhookSink) post(ctx context.Context, topic string, key []byte, value []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(value))
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(topicHeader, topic)
+	req.Header.Set(keyHeader, fmt.Sprintf("%#x", key))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	// Drain the body so the connection can be reused.
+	if _, err := io.Copy(ioutil.Discard, resp.Body); err != nil {
+		log.WithError(err).Debug("Could not read webhook response")
+	}
+	if err := resp.Body.Close(); err != nil {
+		log.WithError(err).Debug("Could not close webhook response")
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook responded with status %s", resp.Status)
+}
@@ -0,0 +1,80 @@
+package export
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhookSink_RetriesServerErrors(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		if string(body) != `{"slot":"1"}` {
+			t.Errorf("Unexpected body %s", body)
+		}
+		if r.Header.Get(topicHeader) != blockTopic || r.Header.Get(keyHeader) != "0x0102" {
+			t.Errorf("Unexpected headers %v", r.Header)
+		}
+	}))
+	defer srv.Close()
+
+	sink, err := NewWebhookSink(srv.URL, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.backoff = time.Millisecond
+	if err := sink.Export(context.Background(), blockTopic, []byte{1, 2}, []byte(`{"slot":"1"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, received %d", requests)
+	}
+}
+
+func TestWebhookSink_GivesUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		requests int32
+	}{
+		{name: "retries exhausted", status: http.StatusInternalServerError, requests: 3},
+		{name: "client error", status: http.StatusBadRequest, requests: 1},
+	}
+	for _, tt := range tests {
+		var requests int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.WriteHeader(tt.status)
+		}))
+		sink, err := NewWebhookSink(srv.URL, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sink.backoff = time.Millisecond
+		if err := sink.Export(context.Background(), blockTopic, nil, []byte("{}")); err == nil {
+			t.Errorf("%s: expected export to fail", tt.name)
+		}
+		if requests != tt.requests {
+			t.Errorf("%s: expected %d requests, received %d", tt.name, tt.requests, requests)
+		}
+		srv.Close()
+	}
+}
+
+func TestNewWebhookSink_InvalidURL(t *testing.T) {
+	if _, err := NewWebhookSink("ftp://indexer.example.com", 0); err == nil {
+		t.Error("Expected error for a non http URL")
+	}
+}
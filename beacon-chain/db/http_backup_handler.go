@@ -8,13 +8,24 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// BackupHandler for accepting requests to initiate a new database backup.
-func BackupHandler(db Database) func(http.ResponseWriter, *http.Request) {
+// HeadStateCheckpointer saves the head state in full in the database, which a backup requires.
+type HeadStateCheckpointer interface {
+	CheckpointHeadState(ctx context.Context) error
+}
+
+// BackupHandler for accepting requests to initiate a new database backup. The head state is saved
+// in full first, as the backup must contain it.
+func BackupHandler(db Database, chain HeadStateCheckpointer) func(http.ResponseWriter, *http.Request) {
 	log := logrus.WithField("prefix", "db")
 
 	return func(w http.ResponseWriter, _ *http.Request) {
 		log.Debug("Creating database backup from HTTP webhook.")
 
+		if err := chain.CheckpointHeadState(context.Background()); err != nil {
+			log.WithError(err).Error("Failed to save head state for backup")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
 		if err := db.Backup(context.Background()); err != nil {
 			log.WithError(err).Error("Failed to create backup")
 			w.WriteHeader(http.StatusInternalServerError)
@@ -26,6 +26,8 @@ type ReadOnlyDatabase interface {
 	BlockRoots(ctx context.Context, f *filters.QueryFilter) ([][32]byte, error)
 	HasBlock(ctx context.Context, blockRoot [32]byte) bool
 	GenesisBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, error)
+	AnchorBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, error)
+	BackfillBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, error)
 	IsFinalizedBlock(ctx context.Context, blockRoot [32]byte) bool
 	// Validator related methods.
 	ValidatorIndex(ctx context.Context, publicKey []byte) (uint64, bool, error)
@@ -33,6 +35,7 @@ type ReadOnlyDatabase interface {
 	// State related methods.
 	State(ctx context.Context, blockRoot [32]byte) (*state.BeaconState, error)
 	GenesisState(ctx context.Context) (*state.BeaconState, error)
+	GenesisValidatorsRoot(ctx context.Context) ([]byte, error)
 	HasState(ctx context.Context, blockRoot [32]byte) bool
 	// Slashing operations.
 	ProposerSlashing(ctx context.Context, slashingRoot [32]byte) (*eth.ProposerSlashing, error)
@@ -54,6 +57,8 @@ type ReadOnlyDatabase interface {
 	DepositContractAddress(ctx context.Context) ([]byte, error)
 	// Powchain operations.
 	PowchainData(ctx context.Context) (*db.ETH1ChainData, error)
+	// Fork choice operations.
+	ForkChoiceSnapshot(ctx context.Context) (*db.ForkChoiceSnapshot, error)
 }
 
 // NoHeadAccessDatabase -- See github.com/prysmaticlabs/prysm/beacon-chain/db.NoHeadAccessDatabase
@@ -71,6 +76,8 @@ type NoHeadAccessDatabase interface {
 	SaveBlock(ctx context.Context, block *eth.SignedBeaconBlock) error
 	SaveBlocks(ctx context.Context, blocks []*eth.SignedBeaconBlock) error
 	SaveGenesisBlockRoot(ctx context.Context, blockRoot [32]byte) error
+	SaveAnchorBlockRoot(ctx context.Context, blockRoot [32]byte) error
+	SaveBackfillBlockRoot(ctx context.Context, blockRoot [32]byte) error
 	// Validator related methods.
 	DeleteValidatorIndex(ctx context.Context, publicKey []byte) error
 	SaveValidatorIndex(ctx context.Context, publicKey []byte, validatorIdx uint64) error
@@ -80,6 +87,7 @@ type NoHeadAccessDatabase interface {
 	SaveStates(ctx context.Context, states []*state.BeaconState, blockRoots [][32]byte) error
 	DeleteState(ctx context.Context, blockRoot [32]byte) error
 	DeleteStates(ctx context.Context, blockRoots [][32]byte) error
+	SaveGenesisValidatorsRoot(ctx context.Context, root [32]byte) error
 	// Slashing operations.
 	SaveProposerSlashing(ctx context.Context, slashing *eth.ProposerSlashing) error
 	SaveAttesterSlashing(ctx context.Context, slashing *eth.AttesterSlashing) error
@@ -100,6 +108,8 @@ type NoHeadAccessDatabase interface {
 	SaveDepositContractAddress(ctx context.Context, addr common.Address) error
 	// Powchain operations.
 	SavePowchainData(ctx context.Context, data *db.ETH1ChainData) error
+	// Fork choice operations.
+	SaveForkChoiceSnapshot(ctx context.Context, snapshot *db.ForkChoiceSnapshot) error
 }
 
 // HeadAccessDatabase -- See github.com/prysmaticlabs/prysm/beacon-chain/db.HeadAccessDatabase
@@ -120,7 +130,16 @@ type Database interface {
 
 	DatabasePath() string
 	ClearDB() error
+	// BucketStats describes the buckets of the database for inspection tools.
+	BucketStats(ctx context.Context) ([]*BucketStats, error)
 
 	// Backup and restore methods
 	Backup(ctx context.Context) error
 }
+
+// BucketStats describes the number of keys and the approximate size in bytes of a bucket.
+type BucketStats struct {
+	Name     string
+	KeyCount int
+	Size     int64
+}
@@ -0,0 +1,45 @@
+// Package kafka defines an export sink which publishes the objects saved to the database to kafka
+// topics. It requires cgo and is excluded from builds with --define=kafka_enabled=false.
+package kafka
+
+import (
+	"context"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/export"
+	"gopkg.in/confluentinc/confluent-kafka-go.v1/kafka"
+)
+
+// flushTimeoutMs is how long closing the sink waits for outstanding messages to be delivered.
+const flushTimeoutMs = 5000
+
+// Sink publishes exported messages to the kafka topic of the same name.
+type Sink struct {
+	p *kafka.Producer
+}
+
+// NewSink creates a kafka sink for the bootstrap servers specified.
+func NewSink(bootstrapServers string) (export.ExportSink, error) {
+	p, err := kafka.NewProducer(&kafka.ConfigMap{"bootstrap.servers": bootstrapServers})
+	if err != nil {
+		return nil, err
+	}
+	return &Sink{p: p}, nil
+}
+
+// Export produces the message to the kafka topic.
+func (s *Sink) Export(ctx context.Context, topic string, key []byte, value []byte) error {
+	return s.p.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{
+			Topic: &topic,
+		},
+		Value: value,
+		Key:   key,
+	}, nil)
+}
+
+// Close flushes and closes the kafka producer.
+func (s *Sink) Close() error {
+	s.p.Flush(flushTimeoutMs)
+	s.p.Close()
+	return nil
+}
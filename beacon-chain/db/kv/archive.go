@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/binary"
 
-	"github.com/boltdb/bolt"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"go.opencensus.io/trace"
@@ -17,7 +16,7 @@ func (k *Store) ArchivedActiveValidatorChanges(ctx context.Context, epoch uint64
 
 	buf := uint64ToBytes(epoch)
 	var target *pb.ArchivedActiveSetChanges
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.db.View(func(tx txn) error {
 		bkt := tx.Bucket(archivedValidatorSetChangesBucket)
 		enc := bkt.Get(buf)
 		if enc == nil {
@@ -38,7 +37,7 @@ func (k *Store) SaveArchivedActiveValidatorChanges(ctx context.Context, epoch ui
 	if err != nil {
 		return err
 	}
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.db.Update(func(tx txn) error {
 		bucket := tx.Bucket(archivedValidatorSetChangesBucket)
 		return bucket.Put(buf, enc)
 	})
@@ -51,7 +50,7 @@ func (k *Store) ArchivedCommitteeInfo(ctx context.Context, epoch uint64) (*pb.Ar
 
 	buf := uint64ToBytes(epoch)
 	var target *pb.ArchivedCommitteeInfo
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.db.View(func(tx txn) error {
 		bkt := tx.Bucket(archivedCommitteeInfoBucket)
 		enc := bkt.Get(buf)
 		if enc == nil {
@@ -72,7 +71,7 @@ func (k *Store) SaveArchivedCommitteeInfo(ctx context.Context, epoch uint64, inf
 	if err != nil {
 		return err
 	}
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.db.Update(func(tx txn) error {
 		bucket := tx.Bucket(archivedCommitteeInfoBucket)
 		return bucket.Put(buf, enc)
 	})
@@ -85,7 +84,7 @@ func (k *Store) ArchivedBalances(ctx context.Context, epoch uint64) ([]uint64, e
 
 	buf := uint64ToBytes(epoch)
 	var target []uint64
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.db.View(func(tx txn) error {
 		bkt := tx.Bucket(archivedBalancesBucket)
 		enc := bkt.Get(buf)
 		if enc == nil {
@@ -103,7 +102,7 @@ func (k *Store) SaveArchivedBalances(ctx context.Context, epoch uint64, balances
 	defer span.End()
 	buf := uint64ToBytes(epoch)
 	enc := marshalBalances(balances)
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.db.Update(func(tx txn) error {
 		bucket := tx.Bucket(archivedBalancesBucket)
 		return bucket.Put(buf, enc)
 	})
@@ -116,7 +115,7 @@ func (k *Store) ArchivedValidatorParticipation(ctx context.Context, epoch uint64
 
 	buf := uint64ToBytes(epoch)
 	var target *ethpb.ValidatorParticipation
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.db.View(func(tx txn) error {
 		bkt := tx.Bucket(archivedValidatorParticipationBucket)
 		enc := bkt.Get(buf)
 		if enc == nil {
@@ -137,7 +136,7 @@ func (k *Store) SaveArchivedValidatorParticipation(ctx context.Context, epoch ui
 	if err != nil {
 		return err
 	}
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.db.Update(func(tx txn) error {
 		bucket := tx.Bucket(archivedValidatorParticipationBucket)
 		return bucket.Put(buf, enc)
 	})
@@ -1,58 +1,336 @@
 package kv
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/boltdb/bolt"
 	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/sirupsen/logrus"
 	"go.opencensus.io/trace"
 )
 
-const backupsDirectoryName = "backups"
+const (
+	backupsDirectoryName = "backups"
+	backupFilePrefix     = "prysm_beacondb_"
+	backupFileExtension  = ".backup"
+	// restoredDatabaseSuffix is appended to the previous database file when a backup is restored
+	// over it, so the operator can still revert the restore by hand.
+	restoredDatabaseSuffix = ".pre-restore"
+)
 
-// Backup the database to the datadir backup directory.
-// Example for backup at slot 345: $DATADIR/backups/prysm_beacondb_at_slot_0000345.backup
+// Backup the database to the datadir backup directory. The backup is written from a single read
+// transaction so it is a consistent snapshot of the database, verified once it has been written,
+// and old backups are pruned according to the configured retention policy afterwards. The head
+// state must have been saved in full beforehand, as hot states are only saved at epoch boundaries.
+// Example for backup at slot 345: $DATADIR/backups/prysm_beacondb_at_slot_0000345_0x0b1c2d3e_1577836800.backup
 func (k *Store) Backup(ctx context.Context) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.Backup")
 	defer span.End()
 
+	boltDB, ok := k.db.(*boltEngine)
+	if !ok {
+		return errors.New("database backups are only supported by the bolt backend")
+	}
 	backupsDir := path.Join(k.databasePath, backupsDirectoryName)
-	head, err := k.HeadBlock(ctx)
+	// Ensure the backups directory exists.
+	if err := os.MkdirAll(backupsDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	var headRoot, genesisRoot []byte
+	var backupPath string
+	if err := boltDB.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(blocksBucket)
+		headRoot = bytesutil.SafeCopyBytes(bkt.Get(headBlockRootKey))
+		genesisRoot = bytesutil.SafeCopyBytes(bkt.Get(genesisBlockRootKey))
+		// The head block is read from the same transaction as the snapshot, so the backup is named
+		// after the head it contains.
+		enc := bkt.Get(headRoot)
+		if len(headRoot) == 0 || enc == nil {
+			return errors.New("no head block")
+		}
+		head := &ethpb.SignedBeaconBlock{}
+		if err := decode(enc, head); err != nil {
+			return err
+		}
+		backupPath = path.Join(backupsDir, backupFileName(head.Block.Slot, headRoot, time.Now()))
+		logrus.WithField("prefix", "db").WithField("backup", backupPath).Info("Writing backup database.")
+
+		// Write to a temporary file first so a partially written backup is never mistaken for a
+		// complete one by the retention policy or the restore command.
+		tmpPath := backupPath + ".tmp"
+		if err := tx.CopyFile(tmpPath, 0600); err != nil {
+			return errors.Wrap(err, "could not write backup")
+		}
+		return os.Rename(tmpPath, backupPath)
+	}); err != nil {
+		return err
+	}
+
+	if err := verifyBackup(backupPath, genesisRoot, headRoot); err != nil {
+		if rmErr := os.Remove(backupPath); rmErr != nil {
+			logrus.WithField("prefix", "db").WithError(rmErr).Error("Could not remove invalid backup")
+		}
+		return errors.Wrap(err, "backup verification failed")
+	}
+
+	return pruneBackups(backupsDir, flags.Get().BackupRetentionCount, flags.Get().BackupRetentionAge, time.Now())
+}
+
+// Restore validates the database snapshot at the backup path and swaps it in as the database at
+// the directory path specified. The snapshot must contain a genesis block and a head block with
+// its state. The backup is copied next to the database and verified again before the swap, so a
+// failed restore leaves the existing database in place. If a database already exists at the
+// directory path, its genesis root must match the genesis root of the snapshot and it is kept next
+// to the restored database with a ".pre-restore" suffix, which must not exist yet. The database
+// must not be open while it is being restored.
+func Restore(ctx context.Context, backupPath string, dirPath string) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.Restore")
+	defer span.End()
+
+	genesisRoot, headRoot, err := backupRoots(backupPath)
 	if err != nil {
 		return err
 	}
-	if head == nil {
-		return errors.New("no head block")
+	if len(genesisRoot) == 0 {
+		return errors.New("backup has no genesis block root")
 	}
-	// Ensure the backups directory exists.
-	if err := os.MkdirAll(backupsDir, os.ModePerm); err != nil {
+	if err := verifyBackup(backupPath, genesisRoot, headRoot); err != nil {
+		return errors.Wrap(err, "backup verification failed")
+	}
+
+	datafile := path.Join(dirPath, databaseFileName)
+	previous := datafile + restoredDatabaseSuffix
+	_, err = os.Stat(datafile)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if exists {
+		if err := verifyGenesisRoot(datafile, genesisRoot); err != nil {
+			return err
+		}
+		if _, err := os.Stat(previous); err == nil {
+			return fmt.Errorf("%s already exists, move it away before restoring again", previous)
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(dirPath, 0700); err != nil {
 		return err
 	}
-	backupPath := path.Join(backupsDir, fmt.Sprintf("prysm_beacondb_at_slot_%07d.backup", head.Block.Slot))
-	logrus.WithField("prefix", "db").WithField("backup", backupPath).Info("Writing backup database.")
+	tmpPath := datafile + ".tmp"
+	if err := copyFile(backupPath, tmpPath); err != nil {
+		if rmErr := os.Remove(tmpPath); rmErr != nil && !os.IsNotExist(rmErr) {
+			logrus.WithField("prefix", "db").WithError(rmErr).Error("Could not remove partial restore")
+		}
+		return errors.Wrap(err, "could not copy backup")
+	}
+	if err := verifyBackup(tmpPath, genesisRoot, headRoot); err != nil {
+		if rmErr := os.Remove(tmpPath); rmErr != nil {
+			logrus.WithField("prefix", "db").WithError(rmErr).Error("Could not remove invalid restore")
+		}
+		return errors.Wrap(err, "copied backup verification failed")
+	}
 
-	copyDB, err := bolt.Open(backupPath, 0666, nil)
+	if exists {
+		if err := os.Rename(datafile, previous); err != nil {
+			return errors.Wrap(err, "could not move existing database aside")
+		}
+	}
+	if err := os.Rename(tmpPath, datafile); err != nil {
+		if exists {
+			if revertErr := os.Rename(previous, datafile); revertErr != nil {
+				logrus.WithField("prefix", "db").WithError(revertErr).Errorf("Could not move %s back", previous)
+			}
+		}
+		return errors.Wrap(err, "could not swap in restored database")
+	}
+	logrus.WithField("prefix", "db").WithFields(logrus.Fields{
+		"backup":      backupPath,
+		"genesisRoot": fmt.Sprintf("%#x", genesisRoot),
+		"headRoot":    fmt.Sprintf("%#x", headRoot),
+	}).Info("Restored database from backup")
+	return nil
+}
+
+// verifyGenesisRoot checks that the database file, if it recorded a genesis root, has the
+// expected one.
+func verifyGenesisRoot(datafile string, genesisRoot []byte) error {
+	current, err := bolt.Open(datafile, 0600, &bolt.Options{Timeout: 1 * time.Second, ReadOnly: true})
 	if err != nil {
-		panic(err)
+		if err == bolt.ErrTimeout {
+			return errors.New("cannot obtain database lock, database may be in use by another process")
+		}
+		return err
+	}
+	var currentGenesisRoot []byte
+	err = current.View(func(tx *bolt.Tx) error {
+		if bkt := tx.Bucket(blocksBucket); bkt != nil {
+			currentGenesisRoot = bytesutil.SafeCopyBytes(bkt.Get(genesisBlockRootKey))
+		}
+		return nil
+	})
+	if closeErr := current.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if currentGenesisRoot != nil && !bytes.Equal(currentGenesisRoot, genesisRoot) {
+		return fmt.Errorf(
+			"backup genesis root %#x does not match database genesis root %#x",
+			genesisRoot,
+			currentGenesisRoot,
+		)
 	}
-	defer copyDB.Close()
+	return nil
+}
 
-	return k.db.View(func(tx *bolt.Tx) error {
-		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
-			logrus.Debugf("Copying bucket %s\n", name)
-			return copyDB.Update(func(tx2 *bolt.Tx) error {
-				b2, err := tx2.CreateBucketIfNotExists(name)
-				if err != nil {
-					return err
-				}
-				return b.ForEach(func(k []byte, v []byte) error {
-					return b2.Put(k, v)
-				})
-			})
-		})
+// backupFileName names a backup by the slot and root of its head block as well as the time at
+// which it was taken, so several backups at the same head never overwrite each other.
+func backupFileName(slot uint64, headRoot []byte, now time.Time) string {
+	rootPrefix := headRoot
+	if len(rootPrefix) > 4 {
+		rootPrefix = rootPrefix[:4]
+	}
+	return fmt.Sprintf("%sat_slot_%07d_%#x_%d%s", backupFilePrefix, slot, rootPrefix, now.Unix(), backupFileExtension)
+}
+
+// backupRoots reads the genesis and head block roots recorded in a backup.
+func backupRoots(backupPath string) ([]byte, []byte, error) {
+	backupDB, err := openBackup(backupPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer backupDB.Close()
+	var genesisRoot, headRoot []byte
+	err = backupDB.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(blocksBucket)
+		if bkt == nil {
+			return errors.New("backup has no blocks bucket")
+		}
+		genesisRoot = bytesutil.SafeCopyBytes(bkt.Get(genesisBlockRootKey))
+		headRoot = bytesutil.SafeCopyBytes(bkt.Get(headBlockRootKey))
+		return nil
 	})
+	return genesisRoot, headRoot, err
+}
+
+// verifyBackup checks that the backup at the given path can be opened, contains the core buckets
+// of the schema, records the expected genesis and head roots, and holds the genesis block (if one
+// was recorded) as well as the head block and its state.
+func verifyBackup(backupPath string, genesisRoot []byte, headRoot []byte) error {
+	if len(headRoot) == 0 {
+		return errors.New("no head block root")
+	}
+	backupDB, err := openBackup(backupPath)
+	if err != nil {
+		return err
+	}
+	defer backupDB.Close()
+	return backupDB.View(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{blocksBucket, stateBucket, checkpointBucket, migrationBucket} {
+			if tx.Bucket(name) == nil {
+				return fmt.Errorf("missing bucket %s", name)
+			}
+		}
+		blocks := tx.Bucket(blocksBucket)
+		if !bytes.Equal(blocks.Get(genesisBlockRootKey), genesisRoot) {
+			return fmt.Errorf("expected genesis root %#x, received %#x", genesisRoot, blocks.Get(genesisBlockRootKey))
+		}
+		if !bytes.Equal(blocks.Get(headBlockRootKey), headRoot) {
+			return fmt.Errorf("expected head root %#x, received %#x", headRoot, blocks.Get(headBlockRootKey))
+		}
+		if len(genesisRoot) != 0 && blocks.Get(genesisRoot) == nil {
+			return fmt.Errorf("missing genesis block %#x", genesisRoot)
+		}
+		if blocks.Get(headRoot) == nil {
+			return fmt.Errorf("missing head block %#x", headRoot)
+		}
+		if tx.Bucket(stateBucket).Get(headRoot) == nil {
+			return fmt.Errorf("missing head state %#x", headRoot)
+		}
+		if version := schemaVersion(tx); version > SchemaVersion() {
+			return errors.Wrapf(ErrNewerSchemaVersion, "backup schema version is %d", version)
+		}
+		return nil
+	})
+}
+
+// pruneBackups deletes backups in the backup directory which are neither among the most recent
+// keepCount backups nor younger than maxAge. A zero value disables the respective rule, and if
+// both are zero every backup is kept.
+func pruneBackups(backupsDir string, keepCount int, maxAge time.Duration, now time.Time) error {
+	if keepCount <= 0 && maxAge <= 0 {
+		return nil
+	}
+	files, err := ioutil.ReadDir(backupsDir)
+	if err != nil {
+		return err
+	}
+	backups := make([]os.FileInfo, 0, len(files))
+	for _, f := range files {
+		if f.IsDir() || !strings.HasPrefix(f.Name(), backupFilePrefix) || !strings.HasSuffix(f.Name(), backupFileExtension) {
+			continue
+		}
+		backups = append(backups, f)
+	}
+	// Newest backups first.
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].ModTime().After(backups[j].ModTime())
+	})
+	for i, f := range backups {
+		if keepCount > 0 && i < keepCount {
+			continue
+		}
+		if maxAge > 0 && now.Sub(f.ModTime()) <= maxAge {
+			continue
+		}
+		logrus.WithField("prefix", "db").WithField("backup", f.Name()).Info("Removing old database backup")
+		if err := os.Remove(path.Join(backupsDir, f.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func openBackup(backupPath string) (*bolt.DB, error) {
+	if _, err := os.Stat(backupPath); err != nil {
+		return nil, err
+	}
+	return bolt.Open(backupPath, 0600, &bolt.Options{Timeout: 1 * time.Second, ReadOnly: true})
+}
+
+func copyFile(src string, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := out.ReadFrom(in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
 }
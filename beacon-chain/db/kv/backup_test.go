@@ -3,13 +3,16 @@ package kv
 import (
 	"context"
 	"io/ioutil"
+	"os"
 	"path"
 	"testing"
+	"time"
 
 	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/go-ssz"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
 )
 
 func TestStore_Backup(t *testing.T) {
@@ -46,3 +49,242 @@ func TestStore_Backup(t *testing.T) {
 		t.Fatal("No backups created.")
 	}
 }
+
+func TestStore_Backup_Verified(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	ctx := context.Background()
+
+	genesis := &eth.SignedBeaconBlock{Block: &eth.BeaconBlock{Slot: 0}}
+	genesisRoot, err := ssz.HashTreeRoot(genesis.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, genesis); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	head := &eth.SignedBeaconBlock{Block: &eth.BeaconBlock{Slot: 10, ParentRoot: genesisRoot[:]}}
+	headRoot, err := ssz.HashTreeRoot(head.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, head); err != nil {
+		t.Fatal(err)
+	}
+	st, err := state.InitializeFromProto(&pb.BeaconState{Slot: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, st, headRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveHeadBlockRoot(ctx, headRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Backup(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	backupsDir := path.Join(db.databasePath, backupsDirectoryName)
+	files, err := ioutil.ReadDir(backupsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 backup, received %d", len(files))
+	}
+	backupPath := path.Join(backupsDir, files[0].Name())
+	if err := verifyBackup(backupPath, genesisRoot[:], headRoot[:]); err != nil {
+		t.Errorf("Backup did not verify: %v", err)
+	}
+	if err := verifyBackup(backupPath, genesisRoot[:], genesisRoot[:]); err == nil {
+		t.Error("Expected verification against the wrong head root to fail")
+	}
+}
+
+func TestStore_Restore(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	genesis := &eth.SignedBeaconBlock{Block: &eth.BeaconBlock{Slot: 0}}
+	genesisRoot, err := ssz.HashTreeRoot(genesis.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, genesis); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	st, err := state.InitializeFromProto(&pb.BeaconState{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, st, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveHeadBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Backup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	backupsDir := path.Join(db.databasePath, backupsDirectoryName)
+	files, err := ioutil.ReadDir(backupsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backupPath := path.Join(backupsDir, files[0].Name())
+
+	// Advance the head past the backup before restoring it.
+	block := &eth.SignedBeaconBlock{Block: &eth.BeaconBlock{Slot: 1, ParentRoot: genesisRoot[:]}}
+	blockRoot, err := ssz.HashTreeRoot(block.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, block); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Restore(ctx, backupPath, db.databasePath); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := NewKVStore(db.databasePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardownDB(t, restored)
+	if restored.HasBlock(ctx, blockRoot) {
+		t.Error("Expected block saved after the backup to be gone")
+	}
+	if !restored.HasBlock(ctx, genesisRoot) {
+		t.Error("Expected genesis block to be restored")
+	}
+	if _, err := os.Stat(path.Join(db.databasePath, databaseFileName+restoredDatabaseSuffix)); err != nil {
+		t.Errorf("Expected previous database to be kept: %v", err)
+	}
+
+	// A second restore must not overwrite the database kept by the first one.
+	if err := restored.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Restore(ctx, backupPath, db.databasePath); err == nil {
+		t.Error("Expected restore to refuse overwriting the previous database")
+	}
+	reopened, err := openKVStore(db.databasePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	*restored = *reopened
+	if !restored.HasBlock(ctx, genesisRoot) {
+		t.Error("Expected the restored database to be left in place")
+	}
+}
+
+func TestRestore_GenesisMismatch(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	ctx := context.Background()
+
+	genesis := &eth.SignedBeaconBlock{Block: &eth.BeaconBlock{Slot: 0}}
+	genesisRoot, err := ssz.HashTreeRoot(genesis.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, genesis); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	st, err := state.InitializeFromProto(&pb.BeaconState{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, st, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveHeadBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Backup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	backupsDir := path.Join(db.databasePath, backupsDirectoryName)
+	files, err := ioutil.ReadDir(backupsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backupPath := path.Join(backupsDir, files[0].Name())
+
+	other := setupDB(t)
+	defer teardownDB(t, other)
+	if err := other.SaveGenesisBlockRoot(ctx, [32]byte{'a'}); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Restore(ctx, backupPath, other.databasePath); err == nil {
+		t.Error("Expected restore over a database with a different genesis root to fail")
+	}
+	reopened, err := openKVStore(other.databasePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	*other = *reopened
+}
+
+func TestPruneBackups(t *testing.T) {
+	dir := path.Join(testutil.TempDir(), "prune-backups")
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	var names []string
+	for i := 0; i < 5; i++ {
+		name := backupFileName(uint64(i), []byte{byte(i), 2, 3, 4}, now.Add(-time.Duration(i)*time.Hour))
+		p := path.Join(dir, name)
+		if err := ioutil.WriteFile(p, []byte{}, 0600); err != nil {
+			t.Fatal(err)
+		}
+		modTime := now.Add(-time.Duration(i) * time.Hour)
+		if err := os.Chtimes(p, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+	// Unrelated files are never removed.
+	if err := ioutil.WriteFile(path.Join(dir, "notes.txt"), []byte{}, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Keep the 2 most recent backups, plus anything younger than 3.5 hours.
+	if err := pruneBackups(dir, 2, 210*time.Minute, now); err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range names {
+		_, err := os.Stat(path.Join(dir, name))
+		if i < 4 && err != nil {
+			t.Errorf("Expected backup %s to be kept: %v", name, err)
+		}
+		if i == 4 && !os.IsNotExist(err) {
+			t.Errorf("Expected backup %s to be removed", name)
+		}
+	}
+	if _, err := os.Stat(path.Join(dir, "notes.txt")); err != nil {
+		t.Errorf("Expected unrelated file to be kept: %v", err)
+	}
+}
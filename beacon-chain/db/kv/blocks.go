@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"strconv"
 
-	"github.com/boltdb/bolt"
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/go-ssz"
@@ -28,7 +27,7 @@ func (k *Store) Block(ctx context.Context, blockRoot [32]byte) (*ethpb.SignedBea
 		return v.(*ethpb.SignedBeaconBlock), nil
 	}
 	var block *ethpb.SignedBeaconBlock
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.db.View(func(tx txn) error {
 		bkt := tx.Bucket(blocksBucket)
 		enc := bkt.Get(blockRoot[:])
 		if enc == nil {
@@ -45,7 +44,7 @@ func (k *Store) HeadBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, error)
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.HeadBlock")
 	defer span.End()
 	var headBlock *ethpb.SignedBeaconBlock
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.db.View(func(tx txn) error {
 		bkt := tx.Bucket(blocksBucket)
 		headRoot := bkt.Get(headBlockRootKey)
 		if headRoot == nil {
@@ -66,7 +65,7 @@ func (k *Store) Blocks(ctx context.Context, f *filters.QueryFilter) ([]*ethpb.Si
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.Blocks")
 	defer span.End()
 	blocks := make([]*ethpb.SignedBeaconBlock, 0)
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.db.View(func(tx txn) error {
 		bkt := tx.Bucket(blocksBucket)
 
 		// If no filter criteria are specified, return an error.
@@ -131,7 +130,7 @@ func (k *Store) BlockRoots(ctx context.Context, f *filters.QueryFilter) ([][32]b
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.BlockRoots")
 	defer span.End()
 	blockRoots := make([][32]byte, 0)
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.db.View(func(tx txn) error {
 		// If no filter criteria are specified, return an error.
 		if f == nil {
 			return errors.New("must specify a filter criteria for retrieving block roots")
@@ -194,7 +193,7 @@ func (k *Store) HasBlock(ctx context.Context, blockRoot [32]byte) bool {
 	}
 	exists := false
 	// #nosec G104. Always returns nil.
-	k.db.View(func(tx *bolt.Tx) error {
+	k.db.View(func(tx txn) error {
 		bkt := tx.Bucket(blocksBucket)
 		exists = bkt.Get(blockRoot[:]) != nil
 		return nil
@@ -206,7 +205,7 @@ func (k *Store) HasBlock(ctx context.Context, blockRoot [32]byte) bool {
 func (k *Store) DeleteBlock(ctx context.Context, blockRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.DeleteBlock")
 	defer span.End()
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.db.Update(func(tx txn) error {
 		bkt := tx.Bucket(blocksBucket)
 		enc := bkt.Get(blockRoot[:])
 		if enc == nil {
@@ -230,7 +229,7 @@ func (k *Store) DeleteBlocks(ctx context.Context, blockRoots [][32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.DeleteBlocks")
 	defer span.End()
 
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.db.Update(func(tx txn) error {
 		bkt := tx.Bucket(blocksBucket)
 		for _, blockRoot := range blockRoots {
 			enc := bkt.Get(blockRoot[:])
@@ -265,7 +264,7 @@ func (k *Store) SaveBlock(ctx context.Context, signed *ethpb.SignedBeaconBlock)
 	if v, ok := k.blockCache.Get(string(blockRoot[:])); v != nil && ok {
 		return nil
 	}
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.db.Update(func(tx txn) error {
 		bkt := tx.Bucket(blocksBucket)
 		if existingBlock := bkt.Get(blockRoot[:]); existingBlock != nil {
 			return nil
@@ -288,7 +287,7 @@ func (k *Store) SaveBlocks(ctx context.Context, blocks []*ethpb.SignedBeaconBloc
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveBlocks")
 	defer span.End()
 
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.db.Update(func(tx txn) error {
 		for _, block := range blocks {
 			blockRoot, err := ssz.HashTreeRoot(block.Block)
 			if err != nil {
@@ -315,15 +314,18 @@ func (k *Store) SaveBlocks(ctx context.Context, blocks []*ethpb.SignedBeaconBloc
 	})
 }
 
-// SaveHeadBlockRoot to the db.
+// SaveHeadBlockRoot to the db. The state of the head block must be saved or be
+// regenerable from its block.
 func (k *Store) SaveHeadBlockRoot(ctx context.Context, blockRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveHeadBlockRoot")
 	defer span.End()
-	return k.db.Update(func(tx *bolt.Tx) error {
-		if tx.Bucket(stateBucket).Get(blockRoot[:]) == nil {
-			return errors.New("no state found with head block root")
-		}
+	return k.db.Update(func(tx txn) error {
 		bucket := tx.Bucket(blocksBucket)
+		// Hot states are only saved in full at epoch boundaries, the state generator
+		// regenerates the head state from its block otherwise.
+		if tx.Bucket(stateBucket).Get(blockRoot[:]) == nil && bucket.Get(blockRoot[:]) == nil {
+			return errors.New("no state or block found with head block root")
+		}
 		return bucket.Put(headBlockRootKey, blockRoot[:])
 	})
 }
@@ -333,7 +335,7 @@ func (k *Store) GenesisBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, err
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.GenesisBlock")
 	defer span.End()
 	var block *ethpb.SignedBeaconBlock
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.db.View(func(tx txn) error {
 		bkt := tx.Bucket(blocksBucket)
 		root := bkt.Get(genesisBlockRootKey)
 		enc := bkt.Get(root)
@@ -350,17 +352,73 @@ func (k *Store) GenesisBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, err
 func (k *Store) SaveGenesisBlockRoot(ctx context.Context, blockRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveGenesisBlockRoot")
 	defer span.End()
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.db.Update(func(tx txn) error {
 		bucket := tx.Bucket(blocksBucket)
 		return bucket.Put(genesisBlockRootKey, blockRoot[:])
 	})
 }
 
+// AnchorBlock retrieves the block the node was started from by checkpoint sync, which
+// is also saved as the genesis block root. It returns nil if the node was started from genesis.
+func (k *Store) AnchorBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.AnchorBlock")
+	defer span.End()
+	return k.blockAtKey(anchorBlockRootKey)
+}
+
+// SaveAnchorBlockRoot to the db.
+func (k *Store) SaveAnchorBlockRoot(ctx context.Context, blockRoot [32]byte) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveAnchorBlockRoot")
+	defer span.End()
+	return k.db.Update(func(tx txn) error {
+		bucket := tx.Bucket(blocksBucket)
+		return bucket.Put(anchorBlockRootKey, blockRoot[:])
+	})
+}
+
+// BackfillBlock retrieves the lowest block of the history before the anchor block which has been
+// filled in by the backfill. It returns nil if no block has been backfilled yet.
+func (k *Store) BackfillBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.BackfillBlock")
+	defer span.End()
+	return k.blockAtKey(backfillBlockRootKey)
+}
+
+// SaveBackfillBlockRoot to the db.
+func (k *Store) SaveBackfillBlockRoot(ctx context.Context, blockRoot [32]byte) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveBackfillBlockRoot")
+	defer span.End()
+	return k.db.Update(func(tx txn) error {
+		bucket := tx.Bucket(blocksBucket)
+		return bucket.Put(backfillBlockRootKey, blockRoot[:])
+	})
+}
+
+// blockAtKey retrieves the block whose root is stored under the key of the blocks bucket, or nil
+// if either does not exist.
+func (k *Store) blockAtKey(key []byte) (*ethpb.SignedBeaconBlock, error) {
+	var block *ethpb.SignedBeaconBlock
+	err := k.db.View(func(tx txn) error {
+		bkt := tx.Bucket(blocksBucket)
+		root := bkt.Get(key)
+		if root == nil {
+			return nil
+		}
+		enc := bkt.Get(root)
+		if enc == nil {
+			return nil
+		}
+		block = &ethpb.SignedBeaconBlock{}
+		return decode(enc, block)
+	})
+	return block, err
+}
+
 // fetchBlockRootsBySlotRange looks into a boltDB bucket and performs a binary search
 // range scan using sorted left-padded byte keys using a start slot and an end slot.
 // If both the start and end slot are the same, and are 0, the function returns nil.
 func fetchBlockRootsBySlotRange(
-	bkt *bolt.Bucket,
+	bkt bucket,
 	startSlotEncoded interface{},
 	endSlotEncoded interface{},
 	startEpochEncoded interface{},
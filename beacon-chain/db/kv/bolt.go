@@ -0,0 +1,187 @@
+package kv
+
+import (
+	"os"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/mdlayher/prombolt"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
+)
+
+// boltEngine runs the transactions of a Store on a boltDB database file.
+type boltEngine struct {
+	db        *bolt.DB
+	datafile  string
+	collector prometheus.Collector
+}
+
+// boltMode is how a boltDB database file is opened.
+type boltMode int
+
+const (
+	// boltReadWrite opens the database for the beacon node and creates the buckets of the schema
+	// which do not exist yet.
+	boltReadWrite boltMode = iota
+	// boltReadOnly opens the database for inspection tools, every write fails.
+	boltReadOnly
+	// boltDryRun opens the database for writes which are all rolled back. The buckets of the schema
+	// are only created within the transactions which write to them.
+	boltDryRun
+)
+
+// openBoltEngine opens the boltDB database file in the given mode. The boltDB metrics collector is
+// registered by the caller.
+func openBoltEngine(datafile string, mode boltMode) (*boltEngine, error) {
+	readOnly := mode == boltReadOnly
+	boltDB, err := bolt.Open(datafile, 0600, &bolt.Options{Timeout: 1 * time.Second, InitialMmapSize: 10e6, ReadOnly: readOnly})
+	if err != nil {
+		if err == bolt.ErrTimeout {
+			return nil, errors.New("cannot obtain database lock, database may be in use by another process")
+		}
+		return nil, err
+	}
+	e := &boltEngine{
+		db:        boltDB,
+		datafile:  datafile,
+		collector: prombolt.New("boltDB", boltDB),
+	}
+	if readOnly {
+		return e, nil
+	}
+	boltDB.AllocSize = boltAllocSize
+	if mode == boltDryRun {
+		return e, nil
+	}
+	if err := boltDB.Update(func(tx *bolt.Tx) error {
+		for _, name := range schemaBuckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// View executes a function within a read-only bolt transaction.
+func (e *boltEngine) View(fn func(tx txn) error) error {
+	return e.db.View(func(tx *bolt.Tx) error {
+		return fn(boltTx{tx})
+	})
+}
+
+// Update executes a function within a read-write bolt transaction.
+func (e *boltEngine) Update(fn func(tx txn) error) error {
+	return e.db.Update(func(tx *bolt.Tx) error {
+		return fn(boltTx{tx})
+	})
+}
+
+// BucketStats returns the number of keys and the bytes in use by the pages of every bucket,
+// in the byte order of the bucket names.
+func (e *boltEngine) BucketStats() ([]*iface.BucketStats, error) {
+	stats := make([]*iface.BucketStats, 0)
+	err := e.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			s := b.Stats()
+			stats = append(stats, &iface.BucketStats{
+				Name:     string(name),
+				KeyCount: s.KeyN,
+				Size:     int64(s.BranchInuse + s.LeafInuse + s.InlineBucketInuse),
+			})
+			return nil
+		})
+	})
+	return stats, err
+}
+
+// Close closes the boltDB database.
+func (e *boltEngine) Close() error {
+	prometheus.Unregister(e.collector)
+	return e.db.Close()
+}
+
+// Clear removes the boltDB database file.
+func (e *boltEngine) Clear() error {
+	prometheus.Unregister(e.collector)
+	if err := os.Remove(e.datafile); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+type boltTx struct {
+	tx *bolt.Tx
+}
+
+func (tx boltTx) Bucket(name []byte) bucket {
+	b := tx.tx.Bucket(name)
+	if b == nil && tx.tx.Writable() {
+		// Only a database opened for a dry run can lack a bucket of the schema in a writable
+		// transaction, the bucket is rolled back along with the transaction.
+		created, err := tx.tx.CreateBucket(name)
+		if err == nil {
+			return boltBucket{created}
+		}
+	}
+	if b == nil {
+		// Only a database opened read-only can lack a bucket of the schema.
+		return emptyBucket{}
+	}
+	return boltBucket{b}
+}
+
+type boltBucket struct {
+	*bolt.Bucket
+}
+
+func (b boltBucket) Cursor() cursor {
+	return b.Bucket.Cursor()
+}
+
+// emptyBucket stands in for a bucket of the schema which a database opened read-only was written
+// without.
+type emptyBucket struct{}
+
+func (emptyBucket) Get(k []byte) []byte {
+	return nil
+}
+
+func (emptyBucket) Put(k []byte, v []byte) error {
+	return errReadOnlyTx
+}
+
+func (emptyBucket) Delete(k []byte) error {
+	return errReadOnlyTx
+}
+
+func (emptyBucket) ForEach(fn func(k []byte, v []byte) error) error {
+	return nil
+}
+
+func (emptyBucket) Cursor() cursor {
+	return emptyCursor{}
+}
+
+type emptyCursor struct{}
+
+func (emptyCursor) First() ([]byte, []byte) {
+	return nil, nil
+}
+
+func (emptyCursor) Seek(k []byte) ([]byte, []byte) {
+	return nil, nil
+}
+
+func (emptyCursor) Next() ([]byte, []byte) {
+	return nil, nil
+}
+
+func (emptyCursor) Delete() error {
+	return errReadOnlyTx
+}
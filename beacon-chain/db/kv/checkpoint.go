@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 
-	"github.com/boltdb/bolt"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/shared/traceutil"
 	"go.opencensus.io/trace"
@@ -17,7 +16,7 @@ func (k *Store) JustifiedCheckpoint(ctx context.Context) (*ethpb.Checkpoint, err
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.JustifiedCheckpoint")
 	defer span.End()
 	var checkpoint *ethpb.Checkpoint
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.db.View(func(tx txn) error {
 		bkt := tx.Bucket(checkpointBucket)
 		enc := bkt.Get(justifiedCheckpointKey)
 		if enc == nil {
@@ -37,7 +36,7 @@ func (k *Store) FinalizedCheckpoint(ctx context.Context) (*ethpb.Checkpoint, err
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.FinalizedCheckpoint")
 	defer span.End()
 	var checkpoint *ethpb.Checkpoint
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.db.View(func(tx txn) error {
 		bkt := tx.Bucket(checkpointBucket)
 		enc := bkt.Get(finalizedCheckpointKey)
 		if enc == nil {
@@ -61,7 +60,7 @@ func (k *Store) SaveJustifiedCheckpoint(ctx context.Context, checkpoint *ethpb.C
 	if err != nil {
 		return err
 	}
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.db.Update(func(tx txn) error {
 		bucket := tx.Bucket(checkpointBucket)
 		// The corresponding state must exist or there is a risk that the beacondb enters a state
 		// where the justified beaconState is missing. This may be a fatal condition requiring
@@ -83,7 +82,7 @@ func (k *Store) SaveFinalizedCheckpoint(ctx context.Context, checkpoint *ethpb.C
 	if err != nil {
 		return err
 	}
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.db.Update(func(tx txn) error {
 		bucket := tx.Bucket(checkpointBucket)
 		// The corresponding state must exist or there is a risk that the beacondb enters a state
 		// where the finalized beaconState is missing. This would be a fatal condition requiring
@@ -0,0 +1,36 @@
+package kv
+
+import (
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/conformance"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
+)
+
+func TestStore_Conformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) (iface.Database, func()) {
+		db := setupDB(t)
+		return db, func() { teardownDB(t, db) }
+	})
+}
+
+func TestLevelDBStore_Conformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) (iface.Database, func()) {
+		db := setupLevelDB(t)
+		return db, func() { teardownDB(t, db) }
+	})
+}
+
+func TestMemoryStore_Conformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) (iface.Database, func()) {
+		db, err := NewMemoryStore("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return db, func() {
+			if err := db.Close(); err != nil {
+				t.Fatalf("Failed to close database: %v", err)
+			}
+		}
+	})
+}
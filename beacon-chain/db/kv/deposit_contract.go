@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/boltdb/bolt"
 	"github.com/ethereum/go-ethereum/common"
 	"go.opencensus.io/trace"
 )
@@ -16,7 +15,7 @@ func (k *Store) DepositContractAddress(ctx context.Context) ([]byte, error) {
 	defer span.End()
 	var addr []byte
 	// #nosec G104. Always returns nil.
-	k.db.View(func(tx *bolt.Tx) error {
+	k.db.View(func(tx txn) error {
 		chainInfo := tx.Bucket(chainMetadataBucket)
 		addr = chainInfo.Get(depositContractAddressKey)
 		return nil
@@ -29,7 +28,7 @@ func (k *Store) SaveDepositContractAddress(ctx context.Context, addr common.Addr
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.VerifyContractAddress")
 	defer span.End()
 
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.db.Update(func(tx txn) error {
 		chainInfo := tx.Bucket(chainMetadataBucket)
 		expectedAddress := chainInfo.Get(depositContractAddressKey)
 		if expectedAddress != nil {
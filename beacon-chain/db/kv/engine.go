@@ -0,0 +1,51 @@
+package kv
+
+import "github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
+
+// engine is the transactional key-value store underneath a Store. The schema only relies on
+// named buckets of keys in byte order, which bolt provides natively and the LevelDB engine
+// emulates with key prefixes.
+type engine interface {
+	// View executes a function within a read-only transaction.
+	View(fn func(tx txn) error) error
+	// Update executes a function within a read-write transaction, which is only committed if
+	// the function returns no error.
+	Update(fn func(tx txn) error) error
+	// BucketStats describes the buckets of the engine for inspection tools.
+	BucketStats() ([]*iface.BucketStats, error)
+	// Close closes the engine.
+	Close() error
+	// Clear removes every item stored by the engine, it must not be used afterwards.
+	Clear() error
+}
+
+// txn is a transaction of an engine.
+type txn interface {
+	// Bucket returns the bucket with the given name, the buckets of the schema always exist.
+	Bucket(name []byte) bucket
+}
+
+// bucket is a set of keys in byte order within a transaction.
+type bucket interface {
+	// Get retrieves the value for a key, returning nil if it does not exist. The value is only
+	// valid for the life of the transaction.
+	Get(key []byte) []byte
+	Put(key []byte, value []byte) error
+	// Delete removes a key, it is a no-op if the key does not exist.
+	Delete(key []byte) error
+	// ForEach executes a function for each key and value of the bucket, the bucket must not be
+	// modified from within the function.
+	ForEach(fn func(k []byte, v []byte) error) error
+	Cursor() cursor
+}
+
+// cursor iterates over the keys of a bucket in byte order, returning nil keys once it is
+// exhausted.
+type cursor interface {
+	First() (key []byte, value []byte)
+	// Seek moves the cursor to the first key which is greater than or equal to seek.
+	Seek(seek []byte) (key []byte, value []byte)
+	Next() (key []byte, value []byte)
+	// Delete removes the key at the current position of the cursor.
+	Delete() error
+}
@@ -0,0 +1,42 @@
+package kv
+
+import (
+	"context"
+
+	"github.com/prysmaticlabs/prysm/proto/beacon/db"
+	"go.opencensus.io/trace"
+)
+
+// SaveForkChoiceSnapshot saves the snapshot of the fork choice store, replacing the previous one.
+func (k *Store) SaveForkChoiceSnapshot(ctx context.Context, snapshot *db.ForkChoiceSnapshot) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveForkChoiceSnapshot")
+	defer span.End()
+
+	enc, err := encode(snapshot)
+	if err != nil {
+		return err
+	}
+	return k.db.Update(func(tx txn) error {
+		bkt := tx.Bucket(chainMetadataBucket)
+		return bkt.Put(forkChoiceSnapshotKey, enc)
+	})
+}
+
+// ForkChoiceSnapshot retrieves the last saved snapshot of the fork choice store.
+// It returns nil if no snapshot was saved.
+func (k *Store) ForkChoiceSnapshot(ctx context.Context) (*db.ForkChoiceSnapshot, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.ForkChoiceSnapshot")
+	defer span.End()
+
+	var snapshot *db.ForkChoiceSnapshot
+	err := k.db.View(func(tx txn) error {
+		bkt := tx.Bucket(chainMetadataBucket)
+		enc := bkt.Get(forkChoiceSnapshotKey)
+		if len(enc) == 0 {
+			return nil
+		}
+		snapshot = &db.ForkChoiceSnapshot{}
+		return decode(enc, snapshot)
+	})
+	return snapshot, err
+}
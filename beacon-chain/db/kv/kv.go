@@ -3,11 +3,8 @@ package kv
 import (
 	"os"
 	"path"
-	"time"
 
-	"github.com/boltdb/bolt"
 	"github.com/dgraph-io/ristretto"
-	"github.com/mdlayher/prombolt"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
@@ -29,30 +26,93 @@ const (
 var BlockCacheSize = int64(1 << 21)
 
 // Store defines an implementation of the Prysm Database interface
-// using BoltDB as the underlying persistent kv-store for eth2.
+// on top of a transactional key-value engine for eth2, BoltDB by default.
 type Store struct {
-	db                  *bolt.DB
+	db                  engine
 	databasePath        string
 	blockCache          *ristretto.Cache
 	validatorIndexCache *ristretto.Cache
 }
 
+// schemaBuckets are the buckets of the schema, which are created when the store is opened.
+var schemaBuckets = [][]byte{
+	attestationsBucket,
+	blocksBucket,
+	stateBucket,
+	validatorsBucket,
+	proposerSlashingsBucket,
+	attesterSlashingsBucket,
+	voluntaryExitsBucket,
+	chainMetadataBucket,
+	checkpointBucket,
+	archivedValidatorSetChangesBucket,
+	archivedCommitteeInfoBucket,
+	archivedBalancesBucket,
+	archivedValidatorParticipationBucket,
+	powchainBucket,
+	// Indices buckets.
+	attestationHeadBlockRootBucket,
+	attestationSourceRootIndicesBucket,
+	attestationSourceEpochIndicesBucket,
+	attestationTargetRootIndicesBucket,
+	attestationTargetEpochIndicesBucket,
+	blockSlotIndicesBucket,
+	blockParentRootIndicesBucket,
+	finalizedBlockRootsIndexBucket,
+	// Migration bucket.
+	migrationBucket,
+}
+
 // NewKVStore initializes a new boltDB key-value store at the directory
-// path specified, creates the kv-buckets based on the schema, and stores
-// an open connection db object as a property of the Store struct.
+// path specified, creates the kv-buckets based on the schema, applies any
+// pending schema migrations, and stores an open connection db object as a
+// property of the Store struct.
 func NewKVStore(dirPath string) (*Store, error) {
+	kv, err := openKVStore(dirPath)
+	if err != nil {
+		return kv, err
+	}
+	return migrateStore(kv)
+}
+
+// openKVStore opens the boltDB key-value store and creates the kv-buckets
+// based on the schema without running any migrations.
+func openKVStore(dirPath string) (*Store, error) {
 	if err := os.MkdirAll(dirPath, 0700); err != nil {
 		return nil, err
 	}
+	boltDB, err := openBoltEngine(path.Join(dirPath, databaseFileName), boltReadWrite)
+	if err != nil {
+		return nil, err
+	}
+	kv, err := newStore(boltDB, dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	err = prometheus.Register(boltDB.collector)
+
+	return kv, err
+}
+
+// NewKVStoreReadOnly opens the existing boltDB key-value store at the directory path specified
+// for inspection tools. Neither buckets nor migrations are applied and every write fails, so the
+// database is left exactly as the beacon node wrote it. The database cannot be opened while a
+// beacon node holds it.
+func NewKVStoreReadOnly(dirPath string) (*Store, error) {
 	datafile := path.Join(dirPath, databaseFileName)
-	boltDB, err := bolt.Open(datafile, 0600, &bolt.Options{Timeout: 1 * time.Second, InitialMmapSize: 10e6})
+	if _, err := os.Stat(datafile); err != nil {
+		return nil, err
+	}
+	boltDB, err := openBoltEngine(datafile, boltReadOnly)
 	if err != nil {
-		if err == bolt.ErrTimeout {
-			return nil, errors.New("cannot obtain database lock, database may be in use by another process")
-		}
 		return nil, err
 	}
-	boltDB.AllocSize = boltAllocSize
+	return newStore(boltDB, dirPath)
+}
+
+// newStore creates a Store on top of an open engine.
+func newStore(db engine, dirPath string) (*Store, error) {
 	blockCache, err := ristretto.NewCache(&ristretto.Config{
 		NumCounters: 1000,           // number of keys to track frequency of (1000).
 		MaxCost:     BlockCacheSize, // maximum cost of cache (1000 Blocks).
@@ -71,63 +131,33 @@ func NewKVStore(dirPath string) (*Store, error) {
 		return nil, err
 	}
 
-	kv := &Store{
-		db:                  boltDB,
+	return &Store{
+		db:                  db,
 		databasePath:        dirPath,
 		blockCache:          blockCache,
 		validatorIndexCache: validatorCache,
-	}
+	}, nil
+}
 
-	if err := kv.db.Update(func(tx *bolt.Tx) error {
-		return createBuckets(
-			tx,
-			attestationsBucket,
-			blocksBucket,
-			stateBucket,
-			validatorsBucket,
-			proposerSlashingsBucket,
-			attesterSlashingsBucket,
-			voluntaryExitsBucket,
-			chainMetadataBucket,
-			checkpointBucket,
-			archivedValidatorSetChangesBucket,
-			archivedCommitteeInfoBucket,
-			archivedBalancesBucket,
-			archivedValidatorParticipationBucket,
-			powchainBucket,
-			// Indices buckets.
-			attestationHeadBlockRootBucket,
-			attestationSourceRootIndicesBucket,
-			attestationSourceEpochIndicesBucket,
-			attestationTargetRootIndicesBucket,
-			attestationTargetEpochIndicesBucket,
-			blockSlotIndicesBucket,
-			blockParentRootIndicesBucket,
-			finalizedBlockRootsIndexBucket,
-			// Migration bucket.
-			migrationBucket,
-		)
-	}); err != nil {
+// migrateStore applies any pending schema migrations to a newly opened store, which is closed
+// if a migration fails.
+func migrateStore(kv *Store) (*Store, error) {
+	if _, err := kv.migrate(false /* dryRun */); err != nil {
+		if closeErr := kv.Close(); closeErr != nil {
+			return nil, errors.Wrap(closeErr, err.Error())
+		}
 		return nil, err
 	}
-
-	err = prometheus.Register(createBoltCollector(kv.db))
-
-	return kv, err
+	return kv, nil
 }
 
 // ClearDB removes the previously stored database in the data directory.
 func (k *Store) ClearDB() error {
-	if _, err := os.Stat(k.databasePath); os.IsNotExist(err) {
-		return nil
-	}
-	prometheus.Unregister(createBoltCollector(k.db))
-	return os.Remove(path.Join(k.databasePath, databaseFileName))
+	return k.db.Clear()
 }
 
-// Close closes the underlying BoltDB database.
+// Close closes the underlying database engine.
 func (k *Store) Close() error {
-	prometheus.Unregister(createBoltCollector(k.db))
 	return k.db.Close()
 }
 
@@ -135,17 +165,3 @@ func (k *Store) Close() error {
 func (k *Store) DatabasePath() string {
 	return k.databasePath
 }
-
-func createBuckets(tx *bolt.Tx, buckets ...[]byte) error {
-	for _, bucket := range buckets {
-		if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-// createBoltCollector returns a prometheus collector specifically configured for boltdb.
-func createBoltCollector(db *bolt.DB) prometheus.Collector {
-	return prombolt.New("boltDB", db)
-}
@@ -0,0 +1,291 @@
+package kv
+
+import (
+	"bytes"
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const levelDBDirName = "beaconchain.ldb"
+
+var errReadOnlyTx = errors.New("cannot write in a read-only transaction")
+
+// NewLevelDBStore initializes a new store at the directory path specified which keeps its
+// buckets in LevelDB, a log-structured merge tree suited to large archival nodes, and applies
+// any pending schema migrations.
+func NewLevelDBStore(dirPath string) (*Store, error) {
+	if err := os.MkdirAll(dirPath, 0700); err != nil {
+		return nil, err
+	}
+	dir := path.Join(dirPath, levelDBDirName)
+	db, err := leveldb.OpenFile(dir, &opt.Options{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open leveldb database, it may be in use by another process")
+	}
+	kv, err := newStore(&levelDBEngine{db: db, dir: dir}, dirPath)
+	if err != nil {
+		return nil, err
+	}
+	return migrateStore(kv)
+}
+
+// NewLevelDBStoreReadOnly opens the existing LevelDB store at the directory path specified for
+// inspection tools. No migrations are applied and every write fails. The database cannot be
+// opened while a beacon node holds it.
+func NewLevelDBStoreReadOnly(dirPath string) (*Store, error) {
+	dir := path.Join(dirPath, levelDBDirName)
+	db, err := leveldb.OpenFile(dir, &opt.Options{ReadOnly: true, ErrorIfMissing: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open leveldb database, it may be in use by another process")
+	}
+	return newStore(&levelDBEngine{db: db, dir: dir}, dirPath)
+}
+
+// NewMemoryStore initializes a new store which is held entirely in memory, for tests and
+// ephemeral devnet nodes, and discarded once it is closed. The directory path is only reported
+// by DatabasePath.
+func NewMemoryStore(dirPath string) (*Store, error) {
+	db, err := leveldb.Open(storage.NewMemStorage(), &opt.Options{})
+	if err != nil {
+		return nil, err
+	}
+	kv, err := newStore(&levelDBEngine{db: db}, dirPath)
+	if err != nil {
+		return nil, err
+	}
+	return migrateStore(kv)
+}
+
+// levelDBEngine runs the transactions of a Store on LevelDB. The buckets of the schema are
+// emulated by prefixing every key with the bucket name and a zero byte, which never occurs in
+// the names of the schema. The directory is empty for an in-memory database.
+type levelDBEngine struct {
+	db  *leveldb.DB
+	dir string
+}
+
+// View executes a function within a read-only transaction, which reads from a snapshot of the
+// database.
+func (e *levelDBEngine) View(fn func(tx txn) error) error {
+	snap, err := e.db.GetSnapshot()
+	if err != nil {
+		return err
+	}
+	defer snap.Release()
+	tx := &levelDBTx{r: snap}
+	return tx.finish(fn(tx))
+}
+
+// Update executes a function within a read-write transaction, which sees its own writes.
+func (e *levelDBEngine) Update(fn func(tx txn) error) error {
+	tr, err := e.db.OpenTransaction()
+	if err == leveldb.ErrReadOnly {
+		return errReadOnlyTx
+	}
+	if err != nil {
+		return err
+	}
+	tx := &levelDBTx{r: tr, w: tr}
+	if err := tx.finish(fn(tx)); err != nil {
+		tr.Discard()
+		return err
+	}
+	return tr.Commit()
+}
+
+// BucketStats returns the number of keys and the bytes of keys and values of every bucket which
+// holds at least one key, in the byte order of the bucket names.
+func (e *levelDBEngine) BucketStats() ([]*iface.BucketStats, error) {
+	stats := make([]*iface.BucketStats, 0)
+	err := e.View(func(tx txn) error {
+		ltx := tx.(*levelDBTx)
+		it := ltx.r.NewIterator(nil, nil)
+		ltx.iterators = append(ltx.iterators, it)
+		var current *iface.BucketStats
+		for it.Next() {
+			sep := bytes.IndexByte(it.Key(), 0)
+			if sep < 0 {
+				continue
+			}
+			name := string(it.Key()[:sep])
+			if current == nil || current.Name != name {
+				current = &iface.BucketStats{Name: name}
+				stats = append(stats, current)
+			}
+			current.KeyCount++
+			current.Size += int64(len(it.Key()) - sep - 1 + len(it.Value()))
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// Close closes the LevelDB database.
+func (e *levelDBEngine) Close() error {
+	return e.db.Close()
+}
+
+// Clear closes the LevelDB database and removes its directory.
+func (e *levelDBEngine) Clear() error {
+	if err := e.db.Close(); err != nil && err != leveldb.ErrClosed {
+		return err
+	}
+	if e.dir == "" {
+		return nil
+	}
+	return os.RemoveAll(e.dir)
+}
+
+// reader is the subset of methods shared by LevelDB snapshots and transactions.
+type reader interface {
+	Get(key []byte, ro *opt.ReadOptions) ([]byte, error)
+	NewIterator(slice *util.Range, ro *opt.ReadOptions) iterator.Iterator
+}
+
+// levelDBTx mirrors a bolt transaction. Read errors cannot be returned by the bolt style
+// methods of buckets and cursors, the first one is recorded and fails the transaction instead.
+type levelDBTx struct {
+	r         reader
+	w         *leveldb.Transaction
+	iterators []iterator.Iterator
+	err       error
+}
+
+// Bucket returns the bucket with the given name, which exists for every name in the schema.
+func (tx *levelDBTx) Bucket(name []byte) bucket {
+	prefix := make([]byte, len(name)+1)
+	copy(prefix, name)
+	return &levelDBBucket{tx: tx, prefix: prefix}
+}
+
+func (tx *levelDBTx) recordErr(err error) {
+	if tx.err == nil {
+		tx.err = err
+	}
+}
+
+// finish releases the iterators of the transaction and returns the error of its function or
+// else the first read error of the transaction.
+func (tx *levelDBTx) finish(err error) error {
+	for _, it := range tx.iterators {
+		if itErr := it.Error(); itErr != nil {
+			tx.recordErr(itErr)
+		}
+		it.Release()
+	}
+	tx.iterators = nil
+	if err != nil {
+		return err
+	}
+	return tx.err
+}
+
+type levelDBBucket struct {
+	tx     *levelDBTx
+	prefix []byte
+}
+
+func (b *levelDBBucket) key(k []byte) []byte {
+	key := make([]byte, len(b.prefix)+len(k))
+	copy(key, b.prefix)
+	copy(key[len(b.prefix):], k)
+	return key
+}
+
+// Get retrieves the value for a key in the bucket, returning nil if it does not exist.
+func (b *levelDBBucket) Get(k []byte) []byte {
+	v, err := b.tx.r.Get(b.key(k), nil)
+	if err != nil {
+		if err != leveldb.ErrNotFound {
+			b.tx.recordErr(err)
+		}
+		return nil
+	}
+	return v
+}
+
+// Put sets the value for a key in the bucket.
+func (b *levelDBBucket) Put(k []byte, v []byte) error {
+	if b.tx.w == nil {
+		return errReadOnlyTx
+	}
+	return b.tx.w.Put(b.key(k), v, nil)
+}
+
+// Delete removes a key from the bucket, it is a no-op if the key does not exist.
+func (b *levelDBBucket) Delete(k []byte) error {
+	if b.tx.w == nil {
+		return errReadOnlyTx
+	}
+	return b.tx.w.Delete(b.key(k), nil)
+}
+
+// ForEach executes a function for each key and value of the bucket in byte order.
+func (b *levelDBBucket) ForEach(fn func(k []byte, v []byte) error) error {
+	c := b.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Cursor creates a cursor over the keys of the bucket in byte order.
+func (b *levelDBBucket) Cursor() cursor {
+	it := b.tx.r.NewIterator(util.BytesPrefix(b.prefix), nil)
+	b.tx.iterators = append(b.tx.iterators, it)
+	return &levelDBCursor{bkt: b, it: it}
+}
+
+// levelDBCursor mirrors a bolt cursor. The returned keys and values are copies and remain valid
+// after the cursor is moved.
+type levelDBCursor struct {
+	bkt *levelDBBucket
+	it  iterator.Iterator
+	key []byte
+}
+
+// First moves the cursor to the first key in the bucket.
+func (c *levelDBCursor) First() ([]byte, []byte) {
+	return c.item(c.it.First())
+}
+
+// Seek moves the cursor to the first key in the bucket which is greater than or equal to k.
+func (c *levelDBCursor) Seek(k []byte) ([]byte, []byte) {
+	return c.item(c.it.Seek(c.bkt.key(k)))
+}
+
+// Next moves the cursor to the next key in the bucket.
+func (c *levelDBCursor) Next() ([]byte, []byte) {
+	return c.item(c.it.Next())
+}
+
+// Delete removes the key at the current position of the cursor from the bucket.
+func (c *levelDBCursor) Delete() error {
+	if c.key == nil {
+		return errors.New("cursor is not positioned on a key")
+	}
+	return c.bkt.Delete(c.key)
+}
+
+func (c *levelDBCursor) item(ok bool) ([]byte, []byte) {
+	if !ok {
+		c.key = nil
+		return nil, nil
+	}
+	prefix := c.bkt.prefix
+	c.key = make([]byte, len(c.it.Key())-len(prefix))
+	copy(c.key, c.it.Key()[len(prefix):])
+	v := make([]byte, len(c.it.Value()))
+	copy(v, c.it.Value())
+	return c.key, v
+}
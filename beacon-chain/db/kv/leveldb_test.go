@@ -0,0 +1,132 @@
+package kv
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+// setupLevelDB instantiates and returns an on-disk LevelDB Store instance.
+func setupLevelDB(t testing.TB) *Store {
+	randPath, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		t.Fatalf("Could not generate random file path: %v", err)
+	}
+	path := path.Join(testutil.TempDir(), fmt.Sprintf("/%d", randPath))
+	if err := os.RemoveAll(path); err != nil {
+		t.Fatalf("Failed to remove directory: %v", err)
+	}
+	db, err := NewLevelDBStore(path)
+	if err != nil {
+		t.Fatalf("Failed to instantiate DB: %v", err)
+	}
+	return db
+}
+
+func TestLevelDBStore_ReopenKeepsData(t *testing.T) {
+	db := setupLevelDB(t)
+	ctx := context.Background()
+	pubKey := [48]byte{'A'}
+	if err := db.SaveValidatorIndex(ctx, pubKey[:], 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	db, err := NewLevelDBStore(db.DatabasePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardownDB(t, db)
+	index, ok, err := db.ValidatorIndex(ctx, pubKey[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || index != 5 {
+		t.Errorf("Expected validator index 5 after reopening the database, received %d", index)
+	}
+}
+
+func TestMemoryStore_ClearDB(t *testing.T) {
+	db, err := NewMemoryStore("memory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	pubKey := [48]byte{'A'}
+	if err := db.SaveValidatorIndex(ctx, pubKey[:], 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.ClearDB(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat("memory"); !os.IsNotExist(err) {
+		t.Error("Expected the in-memory database to not write to disk")
+	}
+}
+
+func TestLevelDBBucket_Cursor(t *testing.T) {
+	db, err := NewMemoryStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.db.Update(func(tx txn) error {
+		for _, k := range []string{"b", "a", "c"} {
+			if err := tx.Bucket(blocksBucket).Put([]byte(k), []byte(k)); err != nil {
+				return err
+			}
+		}
+		// Keys of other buckets must not show up in the blocks bucket.
+		return tx.Bucket(stateBucket).Put([]byte("d"), []byte("d"))
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.db.View(func(tx txn) error {
+		if err := tx.Bucket(blocksBucket).Put([]byte("e"), nil); err == nil {
+			t.Error("Expected error when writing in a read-only transaction")
+		}
+		keys := make([]string, 0)
+		c := tx.Bucket(blocksBucket).Cursor()
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			keys = append(keys, string(k))
+		}
+		if fmt.Sprint(keys) != "[a b c]" {
+			t.Errorf("Expected keys [a b c], received %v", keys)
+		}
+		if k, _ := c.Seek([]byte("bb")); string(k) != "c" {
+			t.Errorf("Expected seek to return key c, received %s", k)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLevelDBTx_ReadErrorFailsTransaction(t *testing.T) {
+	db, err := NewMemoryStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	err = db.db.View(func(tx txn) error {
+		ltx := tx.(*levelDBTx)
+		if v := ltx.Bucket(blocksBucket).Get([]byte("missing")); v != nil {
+			return fmt.Errorf("expected no value for a missing key, received %x", v)
+		}
+		if ltx.err != nil {
+			return fmt.Errorf("expected a missing key to not fail the transaction, received %v", ltx.err)
+		}
+		ltx.recordErr(errReadOnlyTx)
+		return nil
+	})
+	if err != errReadOnlyTx {
+		t.Errorf("Expected the recorded read error to fail the transaction, received %v", err)
+	}
+}
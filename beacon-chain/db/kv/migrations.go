@@ -0,0 +1,227 @@
+package kv
+
+import (
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/sirupsen/logrus"
+)
+
+// migration is a named transformation of the database layout or encoding.
+// Each migration runs inside a single read-write transaction, so it is either
+// fully applied and recorded in the migration bucket or not applied at all.
+type migration struct {
+	name string
+	fn   func(tx txn) error
+}
+
+// migrations is the ordered registry of schema changes for the beacon node
+// database. The schema version of a database is the number of migrations which
+// have been applied to it, so new migrations must only ever be appended to the
+// end of this list and existing entries must never be reordered or removed.
+var migrations = []migration{
+	{name: "prune-empty-index-entries", fn: pruneEmptyIndexEntries},
+	{name: "save-genesis-validators-root", fn: saveGenesisValidatorsRoot},
+}
+
+// schemaVersionKey stores the number of migrations applied to the database.
+var schemaVersionKey = []byte("schema-version")
+
+// errDryRunRollback is returned from within a dry run transaction in order
+// to discard the changes made by the migrations.
+var errDryRunRollback = errors.New("rollback dry run migration")
+
+// ErrNewerSchemaVersion is returned when the database was written by a node
+// which knows about more migrations than this one.
+var ErrNewerSchemaVersion = errors.New("database was written by a newer schema version")
+
+// SchemaVersion returns the latest database schema version known to this node.
+func SchemaVersion() uint64 {
+	return uint64(len(migrations))
+}
+
+// schemaVersion retrieves the schema version recorded in the migration bucket. Databases
+// created before the migration registry existed have no version and are at version 0.
+func schemaVersion(tx txn) uint64 {
+	enc := tx.Bucket(migrationBucket).Get(schemaVersionKey)
+	if len(enc) != 8 {
+		return 0
+	}
+	return bytesutil.FromBytes8(enc)
+}
+
+// pendingMigrations returns the current schema version of the database along with the migrations
+// which have not yet been applied to it, failing if the database schema is newer than the one this
+// node understands.
+func (k *Store) pendingMigrations() (uint64, []migration, error) {
+	var version uint64
+	if err := k.db.View(func(tx txn) error {
+		version = schemaVersion(tx)
+		return nil
+	}); err != nil {
+		return 0, nil, err
+	}
+	if version > SchemaVersion() {
+		return 0, nil, errors.Wrapf(
+			ErrNewerSchemaVersion,
+			"database schema version is %d but this node only supports up to version %d, please upgrade your node",
+			version,
+			SchemaVersion(),
+		)
+	}
+	return version, migrations[version:], nil
+}
+
+// migrate applies every pending migration in order, each one in its own transaction. In dry run
+// mode the pending migrations are all executed in a single transaction, so each migration sees the
+// changes of the ones before it, and that transaction is rolled back so the database is left
+// untouched. The names of the applied (or, in dry run mode, applicable) migrations are returned in
+// order.
+func (k *Store) migrate(dryRun bool) ([]string, error) {
+	current, pending, err := k.pendingMigrations()
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return k.dryRunMigrations(current, pending)
+	}
+	log := logrus.WithField("prefix", "db")
+	applied := make([]string, 0, len(pending))
+	for i, m := range pending {
+		version := current + uint64(i) + 1
+		if err := k.db.Update(func(tx txn) error {
+			return applyMigration(tx, m, version)
+		}); err != nil {
+			return applied, errors.Wrapf(err, "could not apply migration %q", m.name)
+		}
+		log.WithFields(logrus.Fields{
+			"migration": m.name,
+			"version":   version,
+		}).Info("Applied database migration")
+		applied = append(applied, m.name)
+	}
+	return applied, nil
+}
+
+// dryRunMigrations applies the pending migrations one after the other in a single transaction
+// which is then rolled back.
+func (k *Store) dryRunMigrations(current uint64, pending []migration) ([]string, error) {
+	log := logrus.WithField("prefix", "db")
+	applied := make([]string, 0, len(pending))
+	err := k.db.Update(func(tx txn) error {
+		for i, m := range pending {
+			version := current + uint64(i) + 1
+			if err := applyMigration(tx, m, version); err != nil {
+				return errors.Wrapf(err, "could not apply migration %q", m.name)
+			}
+			log.WithFields(logrus.Fields{
+				"migration": m.name,
+				"version":   version,
+			}).Info("Pending database migration would apply cleanly")
+			applied = append(applied, m.name)
+		}
+		return errDryRunRollback
+	})
+	if err != nil && err != errDryRunRollback {
+		return applied, err
+	}
+	return applied, nil
+}
+
+// applyMigration runs the migration and records the schema version it brings the database to.
+func applyMigration(tx txn, m migration, version uint64) error {
+	if err := m.fn(tx); err != nil {
+		return err
+	}
+	bkt := tx.Bucket(migrationBucket)
+	if err := bkt.Put([]byte(m.name), bytesutil.Bytes8(version)); err != nil {
+		return err
+	}
+	return bkt.Put(schemaVersionKey, bytesutil.Bytes8(version))
+}
+
+// DryRunMigrations opens the database at the directory path specified, runs every pending schema
+// migration in a transaction which is then rolled back, and returns the names of the migrations
+// which would be applied the next time the database is opened. The database must already exist,
+// it is neither created nor modified.
+func DryRunMigrations(dirPath string) ([]string, error) {
+	datafile := path.Join(dirPath, databaseFileName)
+	if _, err := os.Stat(datafile); err != nil {
+		return nil, errors.Wrap(err, "could not find database")
+	}
+	boltDB, err := openBoltEngine(datafile, boltDryRun)
+	if err != nil {
+		return nil, err
+	}
+	kv, err := newStore(boltDB, dirPath)
+	if err != nil {
+		return nil, err
+	}
+	names, err := kv.migrate(true /* dryRun */)
+	if closeErr := kv.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	return names, err
+}
+
+// pruneEmptyIndexEntries deletes index keys which no longer point to any root. Deleting the last
+// root stored under an index used to leave the key behind with an empty value.
+func pruneEmptyIndexEntries(tx txn) error {
+	indices := [][]byte{
+		attestationHeadBlockRootBucket,
+		attestationSourceRootIndicesBucket,
+		attestationSourceEpochIndicesBucket,
+		attestationTargetRootIndicesBucket,
+		attestationTargetEpochIndicesBucket,
+		blockSlotIndicesBucket,
+		blockParentRootIndicesBucket,
+	}
+	for _, name := range indices {
+		bkt := tx.Bucket(name)
+		var emptyKeys [][]byte
+		if err := bkt.ForEach(func(k, v []byte) error {
+			if len(v) == 0 {
+				emptyKeys = append(emptyKeys, bytesutil.SafeCopyBytes(k))
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, k := range emptyKeys {
+			if err := bkt.Delete(k); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// saveGenesisValidatorsRoot records the genesis validators root of a database started from genesis,
+// computed from its genesis state. The genesis state of a database started from a checkpoint is the
+// anchor state, whose validators are not the ones at genesis, so its root is left missing.
+func saveGenesisValidatorsRoot(tx txn) error {
+	chainInfo := tx.Bucket(chainMetadataBucket)
+	if chainInfo.Get(genesisValidatorsRootKey) != nil || tx.Bucket(blocksBucket).Get(anchorBlockRootKey) != nil {
+		return nil
+	}
+	genesisBlockRoot := tx.Bucket(blocksBucket).Get(genesisBlockRootKey)
+	if genesisBlockRoot == nil {
+		return nil
+	}
+	enc := tx.Bucket(stateBucket).Get(genesisBlockRoot)
+	if enc == nil {
+		return nil
+	}
+	genesisState, err := createState(enc)
+	if err != nil {
+		return err
+	}
+	root, err := stateutil.ValidatorRegistryRoot(genesisState.Validators)
+	if err != nil {
+		return err
+	}
+	return chainInfo.Put(genesisValidatorsRootKey, root[:])
+}
@@ -0,0 +1,301 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestStore_MigrationsAppliedOnOpen(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+
+	if err := db.db.View(func(tx txn) error {
+		if v := schemaVersion(tx); v != SchemaVersion() {
+			t.Errorf("Expected schema version %d, received %d", SchemaVersion(), v)
+		}
+		bkt := tx.Bucket(migrationBucket)
+		for i, m := range migrations {
+			enc := bkt.Get([]byte(m.name))
+			if enc == nil {
+				t.Errorf("Migration %q was not recorded", m.name)
+				continue
+			}
+			if v := bytesutil.FromBytes8(enc); v != uint64(i+1) {
+				t.Errorf("Expected migration %q at version %d, received %d", m.name, i+1, v)
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing is pending once the database has been migrated.
+	names, err := db.migrate(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 0 {
+		t.Errorf("Expected no pending migrations, received %v", names)
+	}
+}
+
+func TestStore_RefusesNewerSchemaVersion(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+
+	if err := db.db.Update(func(tx txn) error {
+		return tx.Bucket(migrationBucket).Put(schemaVersionKey, bytesutil.Bytes8(SchemaVersion()+1))
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewKVStore(db.databasePath); errors.Cause(err) != ErrNewerSchemaVersion {
+		t.Fatalf("Expected error %v, received %v", ErrNewerSchemaVersion, err)
+	}
+	reopened, err := openKVStore(db.databasePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	*db = *reopened
+}
+
+func TestStore_DryRunMigrations(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+
+	emptyKey := []byte("empty")
+	if err := db.db.Update(func(tx txn) error {
+		if err := tx.Bucket(migrationBucket).Delete(schemaVersionKey); err != nil {
+			return err
+		}
+		return tx.Bucket(blockSlotIndicesBucket).Put(emptyKey, []byte{})
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := DryRunMigrations(db.databasePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make([]string, len(migrations))
+	for i, m := range migrations {
+		want[i] = m.name
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected pending migrations %v, received %v", want, names)
+	}
+
+	reopened, err := openKVStore(db.databasePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	*db = *reopened
+	if err := db.db.View(func(tx txn) error {
+		if v := schemaVersion(tx); v != 0 {
+			t.Errorf("Dry run should not change the schema version, received %d", v)
+		}
+		if tx.Bucket(blockSlotIndicesBucket).Get(emptyKey) == nil {
+			t.Error("Dry run should not modify the database")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_DryRunMigrations_MissingDatabase(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "dry-run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dirPath)
+
+	if _, err := DryRunMigrations(dirPath); err == nil {
+		t.Fatal("Expected a dry run without a database to fail")
+	}
+	if _, err := os.Stat(path.Join(dirPath, databaseFileName)); !os.IsNotExist(err) {
+		t.Errorf("Expected the dry run not to create a database, received %v", err)
+	}
+}
+
+func TestStore_DryRunMigrations_AppliesInSequence(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+
+	key := []byte("dry-run")
+	registered := migrations
+	defer func() {
+		migrations = registered
+	}()
+	migrations = append(registered,
+		migration{name: "put-key", fn: func(tx txn) error {
+			return tx.Bucket(blockSlotIndicesBucket).Put(key, []byte{'a'})
+		}},
+		migration{name: "require-key", fn: func(tx txn) error {
+			if tx.Bucket(blockSlotIndicesBucket).Get(key) == nil {
+				return errors.New("previous migration was not applied")
+			}
+			return nil
+		}},
+	)
+
+	names, err := db.migrate(true /* dryRun */)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"put-key", "require-key"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected pending migrations %v, received %v", want, names)
+	}
+	if err := db.db.View(func(tx txn) error {
+		if tx.Bucket(blockSlotIndicesBucket).Get(key) != nil {
+			t.Error("Dry run should not modify the database")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPruneEmptyIndexEntries(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+
+	emptyKey := []byte("empty")
+	fullKey := []byte("full")
+	if err := db.db.Update(func(tx txn) error {
+		bkt := tx.Bucket(blockParentRootIndicesBucket)
+		if err := bkt.Put(emptyKey, []byte{}); err != nil {
+			return err
+		}
+		if err := bkt.Put(fullKey, make([]byte, 32)); err != nil {
+			return err
+		}
+		return pruneEmptyIndexEntries(tx)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.db.View(func(tx txn) error {
+		bkt := tx.Bucket(blockParentRootIndicesBucket)
+		if bkt.Get(emptyKey) != nil {
+			t.Error("Expected empty index entry to be pruned")
+		}
+		if bkt.Get(fullKey) == nil {
+			t.Error("Expected non-empty index entry to be kept")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_ReadOnlyDoesNotMigrate(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+
+	if err := db.db.Update(func(tx txn) error {
+		return tx.Bucket(migrationBucket).Delete(schemaVersionKey)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	readOnly, err := NewKVStoreReadOnly(db.databasePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := readOnly.db.View(func(tx txn) error {
+		if v := schemaVersion(tx); v != 0 {
+			t.Errorf("Opening read-only should not apply migrations, received schema version %d", v)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := readOnly.SaveGenesisBlockRoot(context.Background(), [32]byte{'a'}); err == nil {
+		t.Error("Expected writes to fail on a read-only store")
+	}
+	if err := readOnly.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := openKVStore(db.databasePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	*db = *reopened
+}
+
+func TestSaveGenesisValidatorsRoot(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	ctx := context.Background()
+
+	st, _ := testutil.DeterministicGenesisState(t, 8)
+	genesisRoot := [32]byte{'g'}
+	if err := db.SaveState(ctx, st, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.db.Update(saveGenesisValidatorsRoot); err != nil {
+		t.Fatal(err)
+	}
+	want, err := stateutil.ValidatorRegistryRoot(st.Validators())
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := db.GenesisValidatorsRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(root, want[:]) {
+		t.Errorf("Wanted genesis validators root %#x, received %#x", want, root)
+	}
+}
+
+func TestSaveGenesisValidatorsRoot_SkipsCheckpointDatabase(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	ctx := context.Background()
+
+	// The genesis state of a database started from a checkpoint is the anchor state.
+	st, _ := testutil.DeterministicGenesisState(t, 8)
+	anchorRoot := [32]byte{'a'}
+	if err := db.SaveState(ctx, st, anchorRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, anchorRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveAnchorBlockRoot(ctx, anchorRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.db.Update(saveGenesisValidatorsRoot); err != nil {
+		t.Fatal(err)
+	}
+	root, err := db.GenesisValidatorsRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != nil {
+		t.Errorf("Expected no genesis validators root for a checkpoint database, received %#x", root)
+	}
+}
@@ -0,0 +1,356 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	dbpb "github.com/prysmaticlabs/prysm/proto/beacon/db"
+	"go.opencensus.io/trace"
+)
+
+// blockIndexBuckets are derived from the blocks bucket.
+var blockIndexBuckets = [][]byte{
+	blockSlotIndicesBucket,
+	blockParentRootIndicesBucket,
+}
+
+// attestationIndexBuckets are derived from the attestations bucket.
+var attestationIndexBuckets = [][]byte{
+	attestationHeadBlockRootBucket,
+	attestationSourceRootIndicesBucket,
+	attestationSourceEpochIndicesBucket,
+	attestationTargetRootIndicesBucket,
+	attestationTargetEpochIndicesBucket,
+}
+
+// IndexIssue is an index entry which is either missing for an object in a primary bucket or
+// dangling, pointing to an object which does not exist or does not match the index key.
+type IndexIssue struct {
+	Bucket string
+	Index  []byte
+	Root   []byte
+}
+
+// String describes the index entry.
+func (i *IndexIssue) String() string {
+	return fmt.Sprintf("bucket=%s index=%#x root=%#x", i.Bucket, i.Index, i.Root)
+}
+
+// IndexReport is the result of checking the index buckets against the primary buckets.
+type IndexReport struct {
+	Blocks       int
+	Attestations int
+	Missing      []*IndexIssue
+	Dangling     []*IndexIssue
+}
+
+// Consistent returns true if no index entry is missing or dangling.
+func (r *IndexReport) Consistent() bool {
+	return len(r.Missing) == 0 && len(r.Dangling) == 0
+}
+
+// VerifyIndices checks the index buckets of the DB at the directory path specified against
+// the blocks and attestations buckets and the finalized checkpoint, without modifying the DB.
+func VerifyIndices(ctx context.Context, dirPath string) (*IndexReport, error) {
+	if _, err := os.Stat(path.Join(dirPath, databaseFileName)); err != nil {
+		return nil, err
+	}
+	kv, err := NewKVStoreReadOnly(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	report, err := kv.verifyIndices(ctx)
+	if closeErr := kv.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	return report, err
+}
+
+// RebuildIndices verifies the index buckets of the DB at the directory path specified and
+// rebuilds all of them from the primary buckets, returning the issues found before the rebuild.
+// Pending schema migrations are not applied. The DB must not be in use by a beacon node while its
+// indices are rebuilt.
+func RebuildIndices(ctx context.Context, dirPath string) (*IndexReport, error) {
+	if _, err := os.Stat(path.Join(dirPath, databaseFileName)); err != nil {
+		return nil, err
+	}
+	kv, err := openKVStore(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	report, err := kv.verifyIndices(ctx)
+	if err == nil {
+		err = kv.rebuildIndices(ctx)
+	}
+	if closeErr := kv.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	return report, err
+}
+
+// verifyIndices compares the roots stored in every index bucket with the roots expected from the
+// primary buckets, and checks that the canonical chain from the finalized checkpoint to genesis
+// is in the finalized block roots index.
+func (k *Store) verifyIndices(ctx context.Context) (*IndexReport, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.verifyIndices")
+	defer span.End()
+
+	report := &IndexReport{}
+	err := k.db.View(func(tx txn) error {
+		expected, err := expectedIndices(tx, report)
+		if err != nil {
+			return err
+		}
+		for _, name := range append(append([][]byte{}, blockIndexBuckets...), attestationIndexBuckets...) {
+			compareIndexBucket(tx.Bucket(name), string(name), expected[string(name)], report)
+		}
+		return verifyFinalizedIndex(tx, report)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// expectedIndices scans the blocks and attestations buckets and returns the roots expected under
+// each key of each index bucket.
+func expectedIndices(tx txn, report *IndexReport) (map[string]map[string]map[string]bool, error) {
+	expected := make(map[string]map[string]map[string]bool)
+	add := func(indicesByBucket map[string][]byte, root []byte) {
+		for bkt, idx := range indicesByBucket {
+			if expected[bkt] == nil {
+				expected[bkt] = make(map[string]map[string]bool)
+			}
+			if expected[bkt][string(idx)] == nil {
+				expected[bkt][string(idx)] = make(map[string]bool)
+			}
+			expected[bkt][string(idx)][string(root)] = true
+		}
+	}
+
+	if err := tx.Bucket(blocksBucket).ForEach(func(root []byte, enc []byte) error {
+		// The blocks bucket also holds the genesis and head block root keys.
+		if len(root) != 32 {
+			return nil
+		}
+		block := &ethpb.SignedBeaconBlock{}
+		if err := decode(enc, block); err != nil {
+			return errors.Wrapf(err, "could not decode block %#x", root)
+		}
+		report.Blocks++
+		add(createBlockIndicesFromBlock(block.Block), root)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := tx.Bucket(attestationsBucket).ForEach(func(root []byte, enc []byte) error {
+		ac := &dbpb.AttestationContainer{}
+		if err := decode(enc, ac); err != nil {
+			return errors.Wrapf(err, "could not decode attestation container %#x", root)
+		}
+		report.Attestations++
+		add(createAttestationIndicesFromData(ac.Data), root)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return expected, nil
+}
+
+// compareIndexBucket reports the roots in the index bucket which are not expected as dangling
+// and the expected roots which are not in the index bucket as missing.
+func compareIndexBucket(bkt bucket, name string, expected map[string]map[string]bool, report *IndexReport) {
+	seen := make(map[string]map[string]bool)
+	c := bkt.Cursor()
+	for idx, roots := c.First(); idx != nil; idx, roots = c.Next() {
+		seen[string(idx)] = make(map[string]bool)
+		for i := 0; i < len(roots); i += 32 {
+			end := i + 32
+			if end > len(roots) {
+				end = len(roots)
+			}
+			root := roots[i:end]
+			seen[string(idx)][string(root)] = true
+			if !expected[string(idx)][string(root)] {
+				report.Dangling = append(report.Dangling, newIndexIssue(name, idx, root))
+			}
+		}
+	}
+	for idx, roots := range expected {
+		for root := range roots {
+			if !seen[idx][root] {
+				report.Missing = append(report.Missing, newIndexIssue(name, []byte(idx), []byte(root)))
+			}
+		}
+	}
+}
+
+// verifyFinalizedIndex reports entries of the finalized block roots index for blocks which do not
+// exist as dangling, and blocks of the canonical chain from the finalized checkpoint to genesis
+// which are not in the index as missing.
+func verifyFinalizedIndex(tx txn, report *IndexReport) error {
+	name := string(finalizedBlockRootsIndexBucket)
+	blocks := tx.Bucket(blocksBucket)
+	index := tx.Bucket(finalizedBlockRootsIndexBucket)
+	if err := index.ForEach(func(root []byte, _ []byte) error {
+		if !bytes.Equal(root, previousFinalizedCheckpointKey) && blocks.Get(root) == nil {
+			report.Dangling = append(report.Dangling, newIndexIssue(name, root, root))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	enc := tx.Bucket(checkpointBucket).Get(finalizedCheckpointKey)
+	if enc == nil {
+		return nil
+	}
+	checkpoint := &ethpb.Checkpoint{}
+	if err := decode(enc, checkpoint); err != nil {
+		return err
+	}
+	genesisRoot := blocks.Get(genesisBlockRootKey)
+	root := checkpoint.Root
+	for !bytes.Equal(root, genesisRoot) {
+		enc := blocks.Get(root)
+		if enc == nil {
+			// The finalized block root itself may be missing in a corrupted DB, which is not an
+			// index issue and cannot be fixed by reindexing.
+			return fmt.Errorf("finalized chain is missing block %#x", root)
+		}
+		if index.Get(root) == nil {
+			report.Missing = append(report.Missing, newIndexIssue(name, root, root))
+		}
+		block := &ethpb.SignedBeaconBlock{}
+		if err := decode(enc, block); err != nil {
+			return err
+		}
+		root = block.Block.ParentRoot
+	}
+	return nil
+}
+
+// rebuildIndices rebuilds the index buckets from the primary buckets, followed by the finalized
+// block roots index, in a single transaction so that an interrupted rebuild leaves the DB as it
+// was.
+func (k *Store) rebuildIndices(ctx context.Context) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.rebuildIndices")
+	defer span.End()
+
+	return k.db.Update(func(tx txn) error {
+		for _, name := range append(append([][]byte{}, blockIndexBuckets...), attestationIndexBuckets...) {
+			if err := clearBucket(tx, name); err != nil {
+				return err
+			}
+		}
+		if err := tx.Bucket(blocksBucket).ForEach(func(root []byte, enc []byte) error {
+			if len(root) != 32 {
+				return nil
+			}
+			block := &ethpb.SignedBeaconBlock{}
+			if err := decode(enc, block); err != nil {
+				return err
+			}
+			return updateValueForIndices(createBlockIndicesFromBlock(block.Block), root, tx)
+		}); err != nil {
+			return errors.Wrap(err, "could not rebuild block indices")
+		}
+		if err := tx.Bucket(attestationsBucket).ForEach(func(root []byte, enc []byte) error {
+			ac := &dbpb.AttestationContainer{}
+			if err := decode(enc, ac); err != nil {
+				return err
+			}
+			return updateValueForIndices(createAttestationIndicesFromData(ac.Data), root, tx)
+		}); err != nil {
+			return errors.Wrap(err, "could not rebuild attestation indices")
+		}
+		return errors.Wrap(rebuildFinalizedIndex(tx), "could not rebuild finalized block roots index")
+	})
+}
+
+// rebuildFinalizedIndex builds the finalized block roots index from scratch like
+// updateFinalizedBlockRoots does for a new checkpoint, but only reads from the transaction, which
+// holds the rebuilt block slot indices before they are committed.
+func rebuildFinalizedIndex(tx txn) error {
+	if err := clearBucket(tx, finalizedBlockRootsIndexBucket); err != nil {
+		return err
+	}
+	enc := tx.Bucket(checkpointBucket).Get(finalizedCheckpointKey)
+	if enc == nil {
+		return nil
+	}
+	checkpoint := &ethpb.Checkpoint{}
+	if err := decode(enc, checkpoint); err != nil {
+		return err
+	}
+
+	blocks := tx.Bucket(blocksBucket)
+	index := tx.Bucket(finalizedBlockRootsIndexBucket)
+	genesisRoot := blocks.Get(genesisBlockRootKey)
+	var childRoot []byte
+	for root := checkpoint.Root; !bytes.Equal(root, genesisRoot); {
+		blockEnc := blocks.Get(root)
+		if blockEnc == nil {
+			return fmt.Errorf("missing block in database: block root=%#x", root)
+		}
+		block := &ethpb.SignedBeaconBlock{}
+		if err := decode(blockEnc, block); err != nil {
+			return err
+		}
+		container, err := encode(&dbpb.FinalizedBlockRootContainer{
+			ParentRoot: block.Block.ParentRoot,
+			ChildRoot:  childRoot,
+		})
+		if err != nil {
+			return err
+		}
+		if err := index.Put(root, container); err != nil {
+			return err
+		}
+		childRoot, root = root, block.Block.ParentRoot
+	}
+
+	// The other blocks from the finalized epoch are final, but not known to be canonical.
+	roots := fetchBlockRootsBySlotRange(tx.Bucket(blockSlotIndicesBucket), nil, nil, checkpoint.Epoch, checkpoint.Epoch+1, nil)
+	for _, root := range roots {
+		if bytes.Equal(root, checkpoint.Root) || index.Get(root) != nil {
+			continue
+		}
+		if err := index.Put(root, containerFinalizedButNotCanonical); err != nil {
+			return err
+		}
+	}
+	return index.Put(previousFinalizedCheckpointKey, enc)
+}
+
+// clearBucket deletes every key of the bucket. The keys are collected first, as a bucket must
+// not be modified while it is iterated over.
+func clearBucket(tx txn, name []byte) error {
+	bkt := tx.Bucket(name)
+	keys := make([][]byte, 0)
+	if err := bkt.ForEach(func(k []byte, _ []byte) error {
+		keys = append(keys, append([]byte{}, k...))
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := bkt.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newIndexIssue(bucket string, index []byte, root []byte) *IndexIssue {
+	return &IndexIssue{
+		Bucket: bucket,
+		Index:  append([]byte{}, index...),
+		Root:   append([]byte{}, root...),
+	}
+}
@@ -0,0 +1,124 @@
+package kv
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestStore_VerifyAndRebuildIndices(t *testing.T) {
+	slotsPerEpoch := int(params.BeaconConfig().SlotsPerEpoch)
+	db := setupDB(t)
+	ctx := context.Background()
+
+	if err := db.SaveGenesisBlockRoot(ctx, genesisBlockRoot); err != nil {
+		t.Fatal(err)
+	}
+	blks := makeBlocks(t, 0, slotsPerEpoch*2, genesisBlockRoot)
+	if err := db.SaveBlocks(ctx, blks); err != nil {
+		t.Fatal(err)
+	}
+	att := &ethpb.Attestation{
+		Data: &ethpb.AttestationData{
+			Slot:            1,
+			BeaconBlockRoot: []byte("head"),
+			Source:          &ethpb.Checkpoint{Root: []byte("source")},
+			Target:          &ethpb.Checkpoint{Epoch: 1, Root: []byte("target")},
+		},
+		AggregationBits: bitfield.Bitlist{0b00000001, 0b1},
+	}
+	if err := db.SaveAttestation(ctx, att); err != nil {
+		t.Fatal(err)
+	}
+	finalizedRoot, err := ssz.HashTreeRoot(blks[slotsPerEpoch-1].Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := state.InitializeFromProto(&pb.BeaconState{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, st, finalizedRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveFinalizedCheckpoint(ctx, &ethpb.Checkpoint{Epoch: 1, Root: finalizedRoot[:]}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := db.verifyIndices(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Consistent() {
+		t.Fatalf("Expected consistent indices, received missing %v and dangling %v", report.Missing, report.Dangling)
+	}
+	if report.Blocks != len(blks) || report.Attestations != 1 {
+		t.Errorf("Expected %d blocks and 1 attestation to be scanned, received %d and %d", len(blks), report.Blocks, report.Attestations)
+	}
+
+	// Simulate a crash which left the indices half written.
+	firstRoot, err := ssz.HashTreeRoot(blks[0].Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.db.Update(func(tx txn) error {
+		if err := tx.Bucket(blockSlotIndicesBucket).Delete([]byte(fmt.Sprintf("%07d", 3))); err != nil {
+			return err
+		}
+		if err := tx.Bucket(attestationTargetRootIndicesBucket).Put([]byte("target"), make([]byte, 32)); err != nil {
+			return err
+		}
+		return tx.Bucket(finalizedBlockRootsIndexBucket).Delete(firstRoot[:])
+	}); err != nil {
+		t.Fatal(err)
+	}
+	report, err = db.verifyIndices(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Missing) != 3 || len(report.Dangling) != 1 {
+		t.Errorf("Expected 3 missing and 1 dangling index entries, received missing %v and dangling %v", report.Missing, report.Dangling)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err = RebuildIndices(ctx, db.DatabasePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Consistent() {
+		t.Error("Expected the issues found before the rebuild to be reported")
+	}
+	report, err = VerifyIndices(ctx, db.DatabasePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Consistent() {
+		t.Errorf("Expected consistent indices after rebuild, received missing %v and dangling %v", report.Missing, report.Dangling)
+	}
+
+	db, err = NewKVStore(db.DatabasePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardownDB(t, db)
+	retrieved, err := db.Blocks(ctx, filters.NewFilter().SetStartSlot(3).SetEndSlot(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(retrieved) != 1 || retrieved[0].Block.Slot != 3 {
+		t.Errorf("Expected the block at slot 3 to be found after the rebuild, received %v", retrieved)
+	}
+	if !db.IsFinalizedBlock(ctx, firstRoot) {
+		t.Error("Expected the first block to be finalized after the rebuild")
+	}
+}
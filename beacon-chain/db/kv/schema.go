@@ -35,10 +35,14 @@ var (
 	// Specific item keys.
 	headBlockRootKey          = []byte("head-root")
 	genesisBlockRootKey       = []byte("genesis-root")
+	anchorBlockRootKey        = []byte("anchor-root")
+	backfillBlockRootKey      = []byte("backfill-root")
 	depositContractAddressKey = []byte("deposit-contract")
+	genesisValidatorsRootKey  = []byte("genesis-validators-root")
 	justifiedCheckpointKey    = []byte("justified-checkpoint")
 	finalizedCheckpointKey    = []byte("finalized-checkpoint")
 	powchainDataKey           = []byte("powchain-data")
+	forkChoiceSnapshotKey     = []byte("fork-choice-snapshot")
 
 	// Migration bucket.
 	migrationBucket = []byte("migrations")
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 
-	"github.com/boltdb/bolt"
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state"
@@ -19,7 +18,7 @@ func (k *Store) State(ctx context.Context, blockRoot [32]byte) (*state.BeaconSta
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.State")
 	defer span.End()
 	var s *pb.BeaconState
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.db.View(func(tx txn) error {
 		bucket := tx.Bucket(stateBucket)
 		enc := bucket.Get(blockRoot[:])
 		if enc == nil {
@@ -44,7 +43,7 @@ func (k *Store) HeadState(ctx context.Context) (*state.BeaconState, error) {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.HeadState")
 	defer span.End()
 	var s *pb.BeaconState
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.db.View(func(tx txn) error {
 		// Retrieve head block's signing root from blocks bucket,
 		// to look up what the head state is.
 		bucket := tx.Bucket(blocksBucket)
@@ -78,7 +77,7 @@ func (k *Store) GenesisState(ctx context.Context) (*state.BeaconState, error) {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.GenesisState")
 	defer span.End()
 	var s *pb.BeaconState
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.db.View(func(tx txn) error {
 		// Retrieve genesis block's signing root from blocks bucket,
 		// to look up what the genesis state is.
 		bucket := tx.Bucket(blocksBucket)
@@ -103,6 +102,33 @@ func (k *Store) GenesisState(ctx context.Context) (*state.BeaconState, error) {
 	return state.InitializeFromProtoUnsafe(s)
 }
 
+// GenesisValidatorsRoot returns the hash tree root of the validators at genesis, which identifies
+// the chain on the network. It is nil if it was never saved.
+func (k *Store) GenesisValidatorsRoot(ctx context.Context) ([]byte, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.GenesisValidatorsRoot")
+	defer span.End()
+	var root []byte
+	err := k.db.View(func(tx txn) error {
+		root = bytesutil.SafeCopyBytes(tx.Bucket(chainMetadataBucket).Get(genesisValidatorsRootKey))
+		return nil
+	})
+	return root, err
+}
+
+// SaveGenesisValidatorsRoot to the db. It returns an error if a different root has been previously
+// saved.
+func (k *Store) SaveGenesisValidatorsRoot(ctx context.Context, root [32]byte) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveGenesisValidatorsRoot")
+	defer span.End()
+	return k.db.Update(func(tx txn) error {
+		chainInfo := tx.Bucket(chainMetadataBucket)
+		if saved := chainInfo.Get(genesisValidatorsRootKey); saved != nil && !bytes.Equal(saved, root[:]) {
+			return errors.Errorf("cannot override genesis validators root %#x", saved)
+		}
+		return chainInfo.Put(genesisValidatorsRootKey, root[:])
+	})
+}
+
 // SaveState stores a state to the db using block's signing root which was used to generate the state.
 func (k *Store) SaveState(ctx context.Context, state *state.BeaconState, blockRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveState")
@@ -115,7 +141,7 @@ func (k *Store) SaveState(ctx context.Context, state *state.BeaconState, blockRo
 		return err
 	}
 
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.db.Update(func(tx txn) error {
 		bucket := tx.Bucket(stateBucket)
 		return bucket.Put(blockRoot[:], enc)
 	})
@@ -137,7 +163,7 @@ func (k *Store) SaveStates(ctx context.Context, states []*state.BeaconState, blo
 		}
 	}
 
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.db.Update(func(tx txn) error {
 		bucket := tx.Bucket(stateBucket)
 		for i, rt := range blockRoots {
 			err = bucket.Put(rt[:], multipleEncs[i])
@@ -155,7 +181,7 @@ func (k *Store) HasState(ctx context.Context, blockRoot [32]byte) bool {
 	defer span.End()
 	var exists bool
 	// #nosec G104. Always returns nil.
-	k.db.View(func(tx *bolt.Tx) error {
+	k.db.View(func(tx txn) error {
 		bucket := tx.Bucket(stateBucket)
 		exists = bucket.Get(blockRoot[:]) != nil
 		return nil
@@ -168,7 +194,7 @@ func (k *Store) DeleteState(ctx context.Context, blockRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.DeleteState")
 	defer span.End()
 
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.db.Update(func(tx txn) error {
 		bkt := tx.Bucket(blocksBucket)
 		genesisBlockRoot := bkt.Get(genesisBlockRootKey)
 
@@ -209,7 +235,7 @@ func (k *Store) DeleteStates(ctx context.Context, blockRoots [][32]byte) error {
 		rootMap[blockRoot] = true
 	}
 
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.db.Update(func(tx txn) error {
 		bkt := tx.Bucket(blocksBucket)
 		genesisBlockRoot := bkt.Get(genesisBlockRootKey)
 
@@ -0,0 +1,16 @@
+package kv
+
+import (
+	"context"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
+	"go.opencensus.io/trace"
+)
+
+// BucketStats returns the number of keys and the approximate size in bytes of the buckets of
+// the database, in the byte order of the bucket names.
+func (k *Store) BucketStats(ctx context.Context) ([]*iface.BucketStats, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.BucketStats")
+	defer span.End()
+	return k.db.BucketStats()
+}
@@ -1,10 +1,6 @@
 package kv
 
-import (
-	"bytes"
-
-	"github.com/boltdb/bolt"
-)
+import "bytes"
 
 // lookupValuesForIndices takes in a list of indices and looks up
 // their corresponding values in the DB, returning a list of
@@ -13,7 +9,7 @@ import (
 // attestations and we have an index `[]byte("5")` under the shard indices bucket,
 // we might find roots `0x23` and `0x45` stored under that index. We can then
 // do a batch read for attestations corresponding to those roots.
-func lookupValuesForIndices(indicesByBucket map[string][]byte, tx *bolt.Tx) [][][]byte {
+func lookupValuesForIndices(indicesByBucket map[string][]byte, tx txn) [][][]byte {
 	values := make([][][]byte, 0)
 	for k, v := range indicesByBucket {
 		bkt := tx.Bucket([]byte(k))
@@ -30,7 +26,7 @@ func lookupValuesForIndices(indicesByBucket map[string][]byte, tx *bolt.Tx) [][]
 // updateValueForIndices updates the value for each index by appending it to the previous
 // values stored at said index. Typically, indices are roots of data that can then
 // be used for reads or batch reads from the DB.
-func updateValueForIndices(indicesByBucket map[string][]byte, root []byte, tx *bolt.Tx) error {
+func updateValueForIndices(indicesByBucket map[string][]byte, root []byte, tx txn) error {
 	for k, idx := range indicesByBucket {
 		bkt := tx.Bucket([]byte(k))
 		valuesAtIndex := bkt.Get(idx)
@@ -54,7 +50,7 @@ func updateValueForIndices(indicesByBucket map[string][]byte, root []byte, tx *b
 }
 
 // deleteValueForIndices clears a root stored at each index.
-func deleteValueForIndices(indicesByBucket map[string][]byte, root []byte, tx *bolt.Tx) error {
+func deleteValueForIndices(indicesByBucket map[string][]byte, root []byte, tx txn) error {
 	for k, idx := range indicesByBucket {
 		bkt := tx.Bucket([]byte(k))
 		valuesAtIndex := bkt.Get(idx)
@@ -75,6 +71,13 @@ func deleteValueForIndices(indicesByBucket map[string][]byte, root []byte, tx *b
 			copy(valuesEnd, valuesAtIndex[start+len(root):])
 
 			valuesAtIndex = append(valuesStart, valuesEnd...)
+			// Remove the index entirely once there are no more roots stored under it.
+			if len(valuesAtIndex) == 0 {
+				if err := bkt.Delete(idx); err != nil {
+					return err
+				}
+				continue
+			}
 			if err := bkt.Put(idx, valuesAtIndex); err != nil {
 				return err
 			}
@@ -5,7 +5,6 @@ import (
 	"encoding/binary"
 	"fmt"
 
-	"github.com/boltdb/bolt"
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"go.opencensus.io/trace"
@@ -22,7 +21,7 @@ func (k *Store) ValidatorIndex(ctx context.Context, publicKey []byte) (uint64, b
 	}
 	var validatorIdx uint64
 	var ok bool
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.db.View(func(tx txn) error {
 		bkt := tx.Bucket(validatorsBucket)
 		enc := bkt.Get(publicKey)
 		if enc == nil {
@@ -45,7 +44,7 @@ func (k *Store) HasValidatorIndex(ctx context.Context, publicKey []byte) bool {
 	}
 	exists := false
 	// #nosec G104. Always returns nil.
-	k.db.View(func(tx *bolt.Tx) error {
+	k.db.View(func(tx txn) error {
 		bkt := tx.Bucket(validatorsBucket)
 		exists = bkt.Get(publicKey) != nil
 		return nil
@@ -57,7 +56,7 @@ func (k *Store) HasValidatorIndex(ctx context.Context, publicKey []byte) bool {
 func (k *Store) DeleteValidatorIndex(ctx context.Context, publicKey []byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.DeleteValidatorIndex")
 	defer span.End()
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.db.Update(func(tx txn) error {
 		bucket := tx.Bucket(validatorsBucket)
 		k.validatorIndexCache.Del(string(publicKey))
 		return bucket.Delete(publicKey)
@@ -71,7 +70,7 @@ func (k *Store) SaveValidatorIndex(ctx context.Context, publicKey []byte, valida
 	}
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveValidatorIndex")
 	defer span.End()
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.db.Update(func(tx txn) error {
 		bucket := tx.Bucket(validatorsBucket)
 		buf := uint64ToBytes(validatorIdx)
 		k.validatorIndexCache.Set(string(publicKey), validatorIdx, int64(len(buf)))
@@ -90,7 +89,7 @@ func (k *Store) SaveValidatorIndices(ctx context.Context, publicKeys [][48]byte,
 	}
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveValidatorIndices")
 	defer span.End()
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.db.Update(func(tx txn) error {
 		bucket := tx.Bucket(validatorsBucket)
 		var err error
 		for i := 0; i < len(publicKeys); i++ {
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/kv"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+)
+
+// NewReadOnlyDB opens the existing DB at the directory path specified with the backend selected
+// by the global flags for inspection, without applying migrations or allowing any write.
+func NewReadOnlyDB(dirPath string) (Database, error) {
+	switch backend := flags.Get().DBBackend; backend {
+	case "", BoltBackend:
+		store, err := kv.NewKVStoreReadOnly(dirPath)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	case LevelDBBackend:
+		store, err := kv.NewLevelDBStoreReadOnly(dirPath)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("database backend %s cannot be opened read-only", backend)
+	}
+}
+
+// DryRunMigrations reports the schema migrations which would be applied to the
+// DB at the directory path specified without committing any of them.
+func DryRunMigrations(dirPath string) ([]string, error) {
+	if err := requireBoltBackend("migrations"); err != nil {
+		return nil, err
+	}
+	return kv.DryRunMigrations(dirPath)
+}
+
+// Restore validates the DB backup at the backup path and swaps it in as the DB
+// at the directory path specified.
+func Restore(ctx context.Context, backupPath string, dirPath string) error {
+	if err := requireBoltBackend("backups"); err != nil {
+		return err
+	}
+	return kv.Restore(ctx, backupPath, dirPath)
+}
+
+// IndexReport is the result of checking the index buckets of the DB against its
+// blocks and attestations.
+type IndexReport = kv.IndexReport
+
+// VerifyIndices checks the index buckets of the DB at the directory path specified
+// without modifying the DB.
+func VerifyIndices(ctx context.Context, dirPath string) (*IndexReport, error) {
+	if err := requireBoltBackend("index checks"); err != nil {
+		return nil, err
+	}
+	return kv.VerifyIndices(ctx, dirPath)
+}
+
+// RebuildIndices rebuilds the index buckets of the DB at the directory path specified,
+// returning the issues found before the rebuild.
+func RebuildIndices(ctx context.Context, dirPath string) (*IndexReport, error) {
+	if err := requireBoltBackend("index checks"); err != nil {
+		return nil, err
+	}
+	return kv.RebuildIndices(ctx, dirPath)
+}
+
+// requireBoltBackend returns an error if a database backend other than bolt is selected,
+// as the maintenance commands operate on the bolt database file.
+func requireBoltBackend(feature string) error {
+	if backend := flags.Get().DBBackend; backend != "" && backend != BoltBackend {
+		return fmt.Errorf("database %s are only supported by the %s backend, not %s", feature, BoltBackend, backend)
+	}
+	return nil
+}
@@ -86,6 +86,120 @@ var (
 		Name:  "unsafe-sync",
 		Usage: "Starts the beacon node with the previously saved head state instead of finalized state.",
 	}
+	// DBBackend selects the key-value store used by the beacon node database.
+	DBBackend = cli.StringFlag{
+		Name:  "db-backend",
+		Usage: "Database backend to use: bolt, leveldb (for large archival nodes) or memory (discarded on shutdown, for tests and ephemeral devnet nodes).",
+		Value: "bolt",
+	}
+	// DBMigrationsDryRun runs pending database schema migrations without committing them and exits.
+	DBMigrationsDryRun = cli.BoolFlag{
+		Name:  "db-migrations-dry-run",
+		Usage: "Checks that any pending database schema migrations apply cleanly without committing them, then exits.",
+	}
+	// BackupRetentionCount defines how many of the most recent database backups are always kept.
+	BackupRetentionCount = cli.IntFlag{
+		Name:  "db-backup-retention-count",
+		Usage: "Number of most recent database backups to keep. Older backups are removed unless they are within the retention age. 0 disables this rule.",
+	}
+	// BackupRetentionAge defines how long database backups are kept for.
+	BackupRetentionAge = cli.DurationFlag{
+		Name:  "db-backup-retention-age",
+		Usage: "Maximum age of database backups to keep, for example 72h. Older backups are removed unless they are among the most recent retention count. 0 disables this rule.",
+	}
+	// RestoreSourceFlag defines the database backup to restore from.
+	RestoreSourceFlag = cli.StringFlag{
+		Name:  "from",
+		Usage: "Path to the database backup file to restore.",
+	}
+	// SlotsPerArchivedPoint specifies the number of slots between the finalized states kept in the database.
+	SlotsPerArchivedPoint = cli.IntFlag{
+		Name:  "slots-per-archive-point",
+		Usage: "The slot durations of when an archived state gets saved in the DB. Finalized states in between are regenerated from blocks when requested.",
+		Value: 2048,
+	}
+	// ExportSinks defines where the objects saved to the database are exported to.
+	ExportSinks = cli.StringSliceFlag{
+		Name: "export-sink",
+		Usage: "Export objects saved to the database, in the form <types>=<sink>:<target>. Types is a comma separated list of " +
+			"blocks, attestations, slashings, exits or all. Sink is one of file (newline-delimited JSON files in the target " +
+			"directory), webhook (HTTP POST to the target URL) or kafka (target bootstrap servers, requires a kafka enabled build). " +
+			"This flag may be used multiple times.",
+	}
+	// ExportFileMaxSize defines the size at which the files of the file export sink are rotated.
+	ExportFileMaxSize = cli.IntFlag{
+		Name:  "export-file-max-size-mb",
+		Usage: "Size in megabytes after which an export file is rotated.",
+		Value: 100,
+	}
+	// ExportWebhookRetries defines how often the webhook export sink retries a failed request.
+	ExportWebhookRetries = cli.IntFlag{
+		Name:  "export-webhook-retries",
+		Usage: "Number of times a failed webhook export request is retried, with exponential backoff.",
+		Value: 3,
+	}
+	// CheckpointStateFlag defines the trusted finalized state the beacon node is started from.
+	CheckpointStateFlag = cli.StringFlag{
+		Name: "checkpoint-state",
+		Usage: "Start an empty database from the trusted finalized beacon state in this file (.SSZ) instead of genesis. " +
+			"Requires the block of the state from --checkpoint-block or --checkpoint-block-provider, and --checkpoint-genesis-validators-root.",
+	}
+	// CheckpointBlockFlag defines the block of the trusted finalized state.
+	CheckpointBlockFlag = cli.StringFlag{
+		Name:  "checkpoint-block",
+		Usage: "The signed beacon block file (.SSZ) of the state given by --checkpoint-state.",
+	}
+	// CheckpointBlockProviderFlag defines the beacon node RPC endpoint the block of the trusted finalized state is fetched from.
+	CheckpointBlockProviderFlag = cli.StringFlag{
+		Name:  "checkpoint-block-provider",
+		Usage: "The gRPC endpoint of a beacon node to fetch the block of the state given by --checkpoint-state from, if --checkpoint-block is not set.",
+	}
+	// CheckpointGenesisValidatorsRootFlag defines the genesis validators root of the chain of the trusted finalized state.
+	CheckpointGenesisValidatorsRootFlag = cli.StringFlag{
+		Name:  "checkpoint-genesis-validators-root",
+		Usage: "The hex encoded genesis validators root of the chain of the state given by --checkpoint-state, required with it.",
+	}
+	// DisableBackfillFlag disables requesting the blocks before the checkpoint sync anchor from peers.
+	DisableBackfillFlag = cli.BoolFlag{
+		Name:  "disable-backfill",
+		Usage: "Do not backfill the blocks before the checkpoint state from peers after starting from --checkpoint-state.",
+	}
+	// BackfillBatchSizeFlag defines the number of slots requested from a peer at once by the backfill.
+	BackfillBatchSizeFlag = cli.IntFlag{
+		Name:  "backfill-batch-size",
+		Usage: "The number of slots of blocks requested from a peer at once when backfilling.",
+		Value: 64,
+	}
+	// RPCRequestsPerSecondFlag defines the number of requests a peer may make per second on each req/resp protocol.
+	RPCRequestsPerSecondFlag = cli.IntFlag{
+		Name:  "rpc-requests-per-second",
+		Usage: "The number of requests a peer may make per second on each p2p req/resp protocol before it is rate limited.",
+		Value: 2,
+	}
+	// RPCRequestsBurstFlag defines the number of requests a peer may make at once on each req/resp protocol.
+	RPCRequestsBurstFlag = cli.IntFlag{
+		Name:  "rpc-requests-burst",
+		Usage: "The number of requests a peer may make at once on each p2p req/resp protocol before it is rate limited.",
+		Value: 10,
+	}
+	// RPCBlocksPerSecondFlag defines the number of blocks a peer may request per second.
+	RPCBlocksPerSecondFlag = cli.IntFlag{
+		Name:  "rpc-blocks-per-second",
+		Usage: "The number of blocks a peer may request per second, by range or by root, before it is rate limited.",
+		Value: 32,
+	}
+	// RPCBlocksBurstFactorFlag defines the multiple of the blocks per second a peer may request at once.
+	RPCBlocksBurstFactorFlag = cli.IntFlag{
+		Name:  "rpc-blocks-burst-factor",
+		Usage: "The multiple of --rpc-blocks-per-second a peer may request at once before it is rate limited.",
+		Value: 10,
+	}
+	// P2PTraceDirFlag defines the directory the gossip and req/resp traffic of the node is traced to.
+	P2PTraceDirFlag = cli.StringFlag{
+		Name: "p2p-trace-dir",
+		Usage: "Directory to trace the gossip messages and req/resp requests exchanged with peers to, for " +
+			"post-mortems. The trace can be queried with prysmctl. Tracing is disabled if not set.",
+	}
 	// SlasherCertFlag defines a flag for the slasher TLS certificate.
 	SlasherCertFlag = cli.StringFlag{
 		Name:  "slasher-tls-cert",
@@ -1,6 +1,8 @@
 package flags
 
 import (
+	"time"
+
 	"github.com/prysmaticlabs/prysm/shared/cmd"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
@@ -17,6 +19,13 @@ type GlobalFlags struct {
 	MaxPageSize                       int
 	DeploymentBlock                   int
 	UnsafeSync                        bool
+	BackupRetentionCount              int
+	BackupRetentionAge                time.Duration
+	SlotsPerArchivedPoint             uint64
+	DBBackend                         string
+	ExportSinks                       []string
+	ExportFileMaxSize                 int64
+	ExportWebhookRetries              int
 }
 
 var globalConfig *GlobalFlags
@@ -55,6 +64,13 @@ func ConfigureGlobalFlags(ctx *cli.Context) {
 	}
 	cfg.MaxPageSize = ctx.GlobalInt(RPCMaxPageSize.Name)
 	cfg.DeploymentBlock = ctx.GlobalInt(ContractDeploymentBlock.Name)
+	cfg.BackupRetentionCount = ctx.GlobalInt(BackupRetentionCount.Name)
+	cfg.BackupRetentionAge = ctx.GlobalDuration(BackupRetentionAge.Name)
+	cfg.SlotsPerArchivedPoint = uint64(ctx.GlobalInt(SlotsPerArchivedPoint.Name))
+	cfg.DBBackend = ctx.GlobalString(DBBackend.Name)
+	cfg.ExportSinks = ctx.GlobalStringSlice(ExportSinks.Name)
+	cfg.ExportFileMaxSize = int64(ctx.GlobalInt(ExportFileMaxSize.Name)) << 20
+	cfg.ExportWebhookRetries = ctx.GlobalInt(ExportWebhookRetries.Name)
 	configureMinimumPeers(ctx, cfg)
 
 	Init(cfg)
@@ -0,0 +1,112 @@
+package protoarray
+
+import (
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/proto/beacon/db"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+)
+
+var errInvalidSnapshotRoot = errors.New("snapshot root is not 32 bytes long")
+
+// Snapshot returns a copy of the fork choice store, including its block nodes, the latest
+// votes and the balances the node weights were computed with, to be persisted.
+func (f *ForkChoice) Snapshot() *db.ForkChoiceSnapshot {
+	// Weights, votes and balances are updated together while computing the head,
+	// the write lock ensures the snapshot does not interleave with it.
+	f.store.nodeIndicesLock.Lock()
+	defer f.store.nodeIndicesLock.Unlock()
+
+	nodes := make([]*db.ForkChoiceNode, len(f.store.nodes))
+	for i, n := range f.store.nodes {
+		nodes[i] = &db.ForkChoiceNode{
+			Slot:           n.Slot,
+			Root:           bytesutil.SafeCopyBytes(n.root[:]),
+			Parent:         n.Parent,
+			JustifiedEpoch: n.justifiedEpoch,
+			FinalizedEpoch: n.finalizedEpoch,
+			Weight:         n.Weight,
+			BestChild:      n.bestChild,
+			BestDescendant: n.BestDescendent,
+		}
+	}
+	votes := make([]*db.ForkChoiceVote, len(f.votes))
+	for i, v := range f.votes {
+		votes[i] = &db.ForkChoiceVote{
+			CurrentRoot: bytesutil.SafeCopyBytes(v.currentRoot[:]),
+			NextRoot:    bytesutil.SafeCopyBytes(v.nextRoot[:]),
+			NextEpoch:   v.nextEpoch,
+		}
+	}
+	balances := make([]uint64, len(f.balances))
+	copy(balances, f.balances)
+
+	return &db.ForkChoiceSnapshot{
+		JustifiedEpoch: f.store.justifiedEpoch,
+		FinalizedEpoch: f.store.finalizedEpoch,
+		FinalizedRoot:  bytesutil.SafeCopyBytes(f.store.finalizedRoot[:]),
+		Nodes:          nodes,
+		Votes:          votes,
+		Balances:       balances,
+	}
+}
+
+// Restore initializes a fork choice store from a snapshot. The snapshot is checked to
+// describe a well formed block tree: every root is unique, and every parent, best child
+// and best descendant index refers to a node of the tree, parents preceding their children.
+func Restore(snapshot *db.ForkChoiceSnapshot) (*ForkChoice, error) {
+	if len(snapshot.FinalizedRoot) != 32 {
+		return nil, errInvalidSnapshotRoot
+	}
+	f := New(snapshot.JustifiedEpoch, snapshot.FinalizedEpoch, bytesutil.ToBytes32(snapshot.FinalizedRoot))
+
+	count := uint64(len(snapshot.Nodes))
+	validIndex := func(index uint64) bool {
+		return index == nonExistentNode || index < count
+	}
+	f.store.nodes = make([]*Node, count)
+	for i, n := range snapshot.Nodes {
+		if len(n.Root) != 32 {
+			return nil, errInvalidSnapshotRoot
+		}
+		root := bytesutil.ToBytes32(n.Root)
+		if _, ok := f.store.nodeIndices[root]; ok {
+			return nil, errors.Errorf("duplicate node %#x", root)
+		}
+		if n.Parent != nonExistentNode && n.Parent >= uint64(i) {
+			return nil, errInvalidNodeIndex
+		}
+		if !validIndex(n.BestChild) {
+			return nil, errInvalidBestChildIndex
+		}
+		if !validIndex(n.BestDescendant) {
+			return nil, errInvalidBestDescendantIndex
+		}
+		f.store.nodes[i] = &Node{
+			Slot:           n.Slot,
+			root:           root,
+			Parent:         n.Parent,
+			justifiedEpoch: n.JustifiedEpoch,
+			finalizedEpoch: n.FinalizedEpoch,
+			Weight:         n.Weight,
+			bestChild:      n.BestChild,
+			BestDescendent: n.BestDescendant,
+		}
+		f.store.nodeIndices[root] = uint64(i)
+	}
+
+	f.votes = make([]Vote, len(snapshot.Votes))
+	for i, v := range snapshot.Votes {
+		if len(v.CurrentRoot) != 32 || len(v.NextRoot) != 32 {
+			return nil, errInvalidSnapshotRoot
+		}
+		f.votes[i] = Vote{
+			currentRoot: bytesutil.ToBytes32(v.CurrentRoot),
+			nextRoot:    bytesutil.ToBytes32(v.NextRoot),
+			nextEpoch:   v.NextEpoch,
+		}
+	}
+	f.balances = make([]uint64, len(snapshot.Balances))
+	copy(f.balances, snapshot.Balances)
+
+	return f, nil
+}
@@ -0,0 +1,111 @@
+package protoarray
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/prysmaticlabs/prysm/proto/beacon/db"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestSnapshot_RestoreResumesHead(t *testing.T) {
+	ctx := context.Background()
+	balances := []uint64{1, 1, 1}
+	f := setup(1, 1)
+
+	// Build the tree below, with one vote for 1 and two votes for 2.
+	//          0
+	//         / \
+	//        1   2
+	//            |
+	//            3
+	if err := f.ProcessBlock(ctx, 1, indexToHash(1), params.BeaconConfig().ZeroHash, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.ProcessBlock(ctx, 1, indexToHash(2), params.BeaconConfig().ZeroHash, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.ProcessBlock(ctx, 2, indexToHash(3), indexToHash(2), 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	f.ProcessAttestation(ctx, []uint64{0}, indexToHash(1), 2)
+	f.ProcessAttestation(ctx, []uint64{1, 2}, indexToHash(2), 2)
+	if _, err := f.Head(ctx, 1, params.BeaconConfig().ZeroHash, balances, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	enc, err := proto.Marshal(f.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot := &db.ForkChoiceSnapshot{}
+	if err := proto.Unmarshal(enc, snapshot); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := Restore(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(f.Nodes(), restored.Nodes()) {
+		t.Errorf("Wanted nodes %v, received %v", f.Nodes(), restored.Nodes())
+	}
+	if w := restored.Node(indexToHash(2)).Weight; w != 2 {
+		t.Errorf("Wanted weight 2 for node 2, received %d", w)
+	}
+
+	// Moving two votes to 1 moves the head of both stores, as the restored store
+	// still knows the weights the previous votes were applied with.
+	for _, store := range []*ForkChoice{f, restored} {
+		store.ProcessAttestation(ctx, []uint64{1, 2}, indexToHash(1), 3)
+		head, err := store.Head(ctx, 1, params.BeaconConfig().ZeroHash, balances, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if head != indexToHash(1) {
+			t.Errorf("Wanted head %#x, received %#x", indexToHash(1), head)
+		}
+		if w := store.Node(indexToHash(2)).Weight; w != 0 {
+			t.Errorf("Wanted weight 0 for node 2, received %d", w)
+		}
+	}
+}
+
+func TestRestore_RejectsMalformedSnapshots(t *testing.T) {
+	root := params.BeaconConfig().ZeroHash
+	root0, root1 := indexToHash(0), indexToHash(1)
+	valid := func() *db.ForkChoiceSnapshot {
+		return &db.ForkChoiceSnapshot{
+			FinalizedRoot: root[:],
+			Nodes: []*db.ForkChoiceNode{
+				{Root: root0[:], Parent: nonExistentNode, BestChild: 1, BestDescendant: 1},
+				{Root: root1[:], Parent: 0, BestChild: nonExistentNode, BestDescendant: nonExistentNode},
+			},
+			Votes: []*db.ForkChoiceVote{{CurrentRoot: root[:], NextRoot: root1[:]}},
+		}
+	}
+	if _, err := Restore(valid()); err != nil {
+		t.Fatalf("Could not restore valid snapshot: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(s *db.ForkChoiceSnapshot)
+	}{
+		{name: "short finalized root", modify: func(s *db.ForkChoiceSnapshot) { s.FinalizedRoot = []byte{'a'} }},
+		{name: "short node root", modify: func(s *db.ForkChoiceSnapshot) { s.Nodes[1].Root = []byte{'a'} }},
+		{name: "duplicate root", modify: func(s *db.ForkChoiceSnapshot) { s.Nodes[1].Root = s.Nodes[0].Root }},
+		{name: "parent after child", modify: func(s *db.ForkChoiceSnapshot) { s.Nodes[0].Parent = 1 }},
+		{name: "unknown best child", modify: func(s *db.ForkChoiceSnapshot) { s.Nodes[0].BestChild = 2 }},
+		{name: "unknown best descendant", modify: func(s *db.ForkChoiceSnapshot) { s.Nodes[0].BestDescendant = 2 }},
+		{name: "short vote root", modify: func(s *db.ForkChoiceSnapshot) { s.Votes[0].NextRoot = nil }},
+	}
+	for _, tt := range tests {
+		s := valid()
+		tt.modify(s)
+		if _, err := Restore(s); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
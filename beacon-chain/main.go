@@ -2,13 +2,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"path"
 	"runtime"
 	runtimeDebug "runtime/debug"
 
 	golog "github.com/ipfs/go-log"
 	joonix "github.com/joonix/log"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
 	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
 	"github.com/prysmaticlabs/prysm/beacon-chain/node"
 	"github.com/prysmaticlabs/prysm/shared/cmd"
@@ -34,10 +37,29 @@ var appFlags = []cli.Flag{
 	flags.KeyFlag,
 	flags.GRPCGatewayPort,
 	flags.MinSyncPeers,
+	flags.RPCRequestsPerSecondFlag,
+	flags.RPCRequestsBurstFlag,
+	flags.RPCBlocksPerSecondFlag,
+	flags.RPCBlocksBurstFactorFlag,
+	flags.P2PTraceDirFlag,
 	flags.RPCMaxPageSize,
 	flags.ContractDeploymentBlock,
 	flags.SetGCPercent,
 	flags.UnsafeSync,
+	flags.DBBackend,
+	flags.DBMigrationsDryRun,
+	flags.BackupRetentionCount,
+	flags.BackupRetentionAge,
+	flags.SlotsPerArchivedPoint,
+	flags.ExportSinks,
+	flags.ExportFileMaxSize,
+	flags.ExportWebhookRetries,
+	flags.CheckpointStateFlag,
+	flags.CheckpointBlockFlag,
+	flags.CheckpointBlockProviderFlag,
+	flags.CheckpointGenesisValidatorsRootFlag,
+	flags.DisableBackfillFlag,
+	flags.BackfillBatchSizeFlag,
 	flags.InteropMockEth1DataVotesFlag,
 	flags.InteropGenesisStateFlag,
 	flags.InteropNumValidatorsFlag,
@@ -94,6 +116,37 @@ func main() {
 	app.Version = version.GetVersion()
 
 	app.Flags = appFlags
+	app.Commands = []cli.Command{
+		{
+			Name:     "db",
+			Category: "db",
+			Usage:    "defines commands for maintaining the beacon node database",
+			Subcommands: cli.Commands{
+				cli.Command{
+					Name: "restore",
+					Description: `restores the beacon node database in the data directory from a backup file
+created by the backup webhook. The backup is validated before it is swapped in and the previous
+database is kept next to it with a .pre-restore suffix`,
+					Flags: []cli.Flag{
+						flags.RestoreSourceFlag,
+					},
+					Action: restoreDB,
+				},
+				cli.Command{
+					Name: "verify",
+					Description: `checks the index buckets of the beacon node database in the data directory
+against its blocks, attestations and finalized checkpoint without modifying it`,
+					Action: verifyDB,
+				},
+				cli.Command{
+					Name: "reindex",
+					Description: `rebuilds the index buckets of the beacon node database in the data directory
+from its blocks, attestations and finalized checkpoint. The beacon node must not be running`,
+					Action: reindexDB,
+				},
+			},
+		},
+	}
 
 	app.Before = func(ctx *cli.Context) error {
 		format := ctx.GlobalString(cmd.LogFormat.Name)
@@ -148,6 +201,74 @@ func main() {
 	}
 }
 
+func dryRunMigrations(ctx *cli.Context) error {
+	// The node is not started, the database backend is only known once the global flags are set.
+	flags.ConfigureGlobalFlags(ctx)
+	dbPath := path.Join(ctx.GlobalString(cmd.DataDirFlag.Name), node.BeaconChainDBName)
+	pending, err := db.DryRunMigrations(dbPath)
+	if err != nil {
+		return fmt.Errorf("database migration dry run failed: %v", err)
+	}
+	logrus.WithField("prefix", "db").WithField("pendingMigrations", pending).Info("Database migration dry run complete, no changes were written")
+	return nil
+}
+
+func restoreDB(ctx *cli.Context) error {
+	from := ctx.String(flags.RestoreSourceFlag.Name)
+	if from == "" {
+		return fmt.Errorf("--%s is required", flags.RestoreSourceFlag.Name)
+	}
+	flags.ConfigureGlobalFlags(ctx)
+	dbPath := path.Join(ctx.GlobalString(cmd.DataDirFlag.Name), node.BeaconChainDBName)
+	return db.Restore(context.Background(), from, dbPath)
+}
+
+func verifyDB(ctx *cli.Context) error {
+	flags.ConfigureGlobalFlags(ctx)
+	dbPath := path.Join(ctx.GlobalString(cmd.DataDirFlag.Name), node.BeaconChainDBName)
+	report, err := db.VerifyIndices(context.Background(), dbPath)
+	if err != nil {
+		return err
+	}
+	logIndexReport(report)
+	if !report.Consistent() {
+		return fmt.Errorf(
+			"found %d missing and %d dangling index entries, run the reindex command to rebuild the indices",
+			len(report.Missing),
+			len(report.Dangling),
+		)
+	}
+	return nil
+}
+
+func reindexDB(ctx *cli.Context) error {
+	flags.ConfigureGlobalFlags(ctx)
+	dbPath := path.Join(ctx.GlobalString(cmd.DataDirFlag.Name), node.BeaconChainDBName)
+	report, err := db.RebuildIndices(context.Background(), dbPath)
+	if err != nil {
+		return err
+	}
+	logIndexReport(report)
+	logrus.WithField("prefix", "db").Info("Rebuilt database indices")
+	return nil
+}
+
+func logIndexReport(report *db.IndexReport) {
+	log := logrus.WithField("prefix", "db")
+	for _, issue := range report.Missing {
+		log.WithField("entry", issue).Warn("Missing index entry")
+	}
+	for _, issue := range report.Dangling {
+		log.WithField("entry", issue).Warn("Dangling index entry")
+	}
+	log.WithFields(logrus.Fields{
+		"blocks":       report.Blocks,
+		"attestations": report.Attestations,
+		"missing":      len(report.Missing),
+		"dangling":     len(report.Dangling),
+	}).Info("Checked database indices")
+}
+
 func startNode(ctx *cli.Context) error {
 	verbosity := ctx.GlobalString(cmd.VerbosityFlag.Name)
 	level, err := logrus.ParseLevel(verbosity)
@@ -159,6 +280,11 @@ func startNode(ctx *cli.Context) error {
 		golog.SetAllLoggers(gologging.DEBUG)
 	}
 
+	// Report the pending database migrations and exit without starting the node.
+	if ctx.GlobalBool(flags.DBMigrationsDryRun.Name) {
+		return dryRunMigrations(ctx)
+	}
+
 	beacon, err := node.NewBeaconNode(ctx)
 	if err != nil {
 		return err
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	"github.com/prysmaticlabs/prysm/beacon-chain/node"
+	"github.com/prysmaticlabs/prysm/shared/cmd"
+	"github.com/urfave/cli"
+)
+
+func TestDBCommands_RequireBoltBackend(t *testing.T) {
+	dataDir, err := ioutil.TempDir("", "db-commands")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dataDir)
+	defer flags.Init(&flags.GlobalFlags{})
+
+	commands := map[string]func(*cli.Context) error{
+		"dry run": dryRunMigrations,
+		"restore": restoreDB,
+		"verify":  verifyDB,
+		"reindex": reindexDB,
+	}
+	for name, command := range commands {
+		t.Run(name, func(t *testing.T) {
+			set := flag.NewFlagSet("test", 0)
+			set.String(cmd.DataDirFlag.Name, dataDir, "")
+			set.String(flags.DBBackend.Name, "leveldb", "")
+			set.String(flags.RestoreSourceFlag.Name, path.Join(dataDir, "backup"), "")
+			ctx := cli.NewContext(cli.NewApp(), set, nil)
+
+			want := "only supported by the bolt backend"
+			if err := command(ctx); err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("Expected error %q, received %v", want, err)
+			}
+			if _, err := os.Stat(path.Join(dataDir, node.BeaconChainDBName)); !os.IsNotExist(err) {
+				t.Errorf("Expected no database to be created, received %v", err)
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"syscall"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/beacon-chain/archiver"
 	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
@@ -31,7 +32,10 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
 	"github.com/prysmaticlabs/prysm/beacon-chain/powchain"
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
 	prysmsync "github.com/prysmaticlabs/prysm/beacon-chain/sync"
+	"github.com/prysmaticlabs/prysm/beacon-chain/sync/backfill"
+	checkpointsync "github.com/prysmaticlabs/prysm/beacon-chain/sync/checkpoint-sync"
 	initialsync "github.com/prysmaticlabs/prysm/beacon-chain/sync/initial-sync"
 	"github.com/prysmaticlabs/prysm/shared"
 	"github.com/prysmaticlabs/prysm/shared/cmd"
@@ -49,7 +53,8 @@ import (
 
 var log = logrus.WithField("prefix", "node")
 
-const beaconChainDBName = "beaconchaindata"
+// BeaconChainDBName is the name of the directory within the data directory which holds the beacon chain database.
+const BeaconChainDBName = "beaconchaindata"
 const testSkipPowFlag = "test-skip-pow"
 
 // BeaconNode defines a struct that handles the services running a random beacon chain
@@ -69,6 +74,7 @@ type BeaconNode struct {
 	blockFeed       *event.Feed
 	opFeed          *event.Feed
 	forkChoiceStore forkchoice.ForkChoicer
+	stateGen        *stategen.State
 }
 
 // NewBeaconNode creates a new node instance, sets up configuration options, and registers
@@ -118,6 +124,10 @@ func NewBeaconNode(ctx *cli.Context) (*BeaconNode, error) {
 		return nil, err
 	}
 
+	if err := beacon.startFromCheckpoint(ctx); err != nil {
+		return nil, err
+	}
+
 	if err := beacon.registerP2P(ctx); err != nil {
 		return nil, err
 	}
@@ -144,6 +154,12 @@ func NewBeaconNode(ctx *cli.Context) (*BeaconNode, error) {
 		return nil, err
 	}
 
+	if !ctx.GlobalBool(flags.DisableBackfillFlag.Name) {
+		if err := beacon.registerBackfillService(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := beacon.registerSyncService(ctx); err != nil {
 		return nil, err
 	}
@@ -238,7 +254,7 @@ func (b *BeaconNode) startForkChoice() {
 
 func (b *BeaconNode) startDB(ctx *cli.Context) error {
 	baseDir := ctx.GlobalString(cmd.DataDirFlag.Name)
-	dbPath := path.Join(baseDir, beaconChainDBName)
+	dbPath := path.Join(baseDir, BeaconChainDBName)
 	clearDB := ctx.GlobalBool(cmd.ClearDB.Name)
 	forceClearDB := ctx.GlobalBool(cmd.ForceClearDB.Name)
 
@@ -266,12 +282,39 @@ func (b *BeaconNode) startDB(ctx *cli.Context) error {
 			return err
 		}
 	}
-	log.WithField("database-path", dbPath).Info("Checking DB")
+	log.WithFields(logrus.Fields{
+		"database-path": dbPath,
+		"backend":       ctx.GlobalString(flags.DBBackend.Name),
+	}).Info("Checking DB")
 	b.db = d
+	b.stateGen = stategen.New(d)
 	b.depositCache = depositcache.NewDepositCache()
 	return nil
 }
 
+// startFromCheckpoint initializes an empty database from the trusted finalized state given by
+// the checkpoint sync flags, if any.
+func (b *BeaconNode) startFromCheckpoint(ctx *cli.Context) error {
+	statePath := ctx.GlobalString(flags.CheckpointStateFlag.Name)
+	if statePath == "" {
+		return nil
+	}
+	var genesisValidatorsRoot []byte
+	if enc := ctx.GlobalString(flags.CheckpointGenesisValidatorsRootFlag.Name); enc != "" {
+		var err error
+		genesisValidatorsRoot, err = hexutil.Decode(enc)
+		if err != nil {
+			return errors.Wrapf(err, "could not decode --%s", flags.CheckpointGenesisValidatorsRootFlag.Name)
+		}
+	}
+	return checkpointsync.Initialize(context.Background(), b.db, &checkpointsync.Config{
+		StatePath:             statePath,
+		BlockPath:             ctx.GlobalString(flags.CheckpointBlockFlag.Name),
+		BlockProvider:         ctx.GlobalString(flags.CheckpointBlockProviderFlag.Name),
+		GenesisValidatorsRoot: genesisValidatorsRoot,
+	})
+}
+
 func (b *BeaconNode) registerP2P(ctx *cli.Context) error {
 	// Bootnode ENR may be a filepath to an ENR file.
 	bootnodeAddrs := strings.Split(ctx.GlobalString(cmd.BootstrapNode.Name), ",")
@@ -301,6 +344,8 @@ func (b *BeaconNode) registerP2P(ctx *cli.Context) error {
 		WhitelistCIDR:     ctx.GlobalString(cmd.P2PWhitelist.Name),
 		EnableUPnP:        ctx.GlobalBool(cmd.EnableUPnPFlag.Name),
 		Encoding:          ctx.GlobalString(cmd.P2PEncoding.Name),
+		TraceDir:          ctx.GlobalString(flags.P2PTraceDirFlag.Name),
+		StateNotifier:     b,
 	})
 	if err != nil {
 		return err
@@ -334,6 +379,7 @@ func (b *BeaconNode) registerBlockchainService(ctx *cli.Context) error {
 		MaxRoutines:       maxRoutines,
 		StateNotifier:     b,
 		ForkChoiceStore:   b.forkChoiceStore,
+		StateGen:          b.stateGen,
 	})
 	if err != nil {
 		return errors.Wrap(err, "could not register blockchain service")
@@ -372,6 +418,7 @@ func (b *BeaconNode) registerPOWChainService(cliCtx *cli.Context) error {
 		BeaconDB:        b.db,
 		DepositCache:    b.depositCache,
 		StateNotifier:   b,
+		StateGen:        b.stateGen,
 	}
 	web3Service, err := powchain.NewService(ctx, cfg)
 	if err != nil {
@@ -410,6 +457,7 @@ func (b *BeaconNode) registerSyncService(ctx *cli.Context) error {
 
 	rs := prysmsync.NewRegularSync(&prysmsync.Config{
 		DB:                  b.db,
+		StateGen:            b.stateGen,
 		P2P:                 b.fetchP2P(ctx),
 		Chain:               chainService,
 		InitialSync:         initSync,
@@ -418,6 +466,12 @@ func (b *BeaconNode) registerSyncService(ctx *cli.Context) error {
 		AttestationNotifier: b,
 		AttPool:             b.attestationPool,
 		ExitPool:            b.exitPool,
+		RateLimits: &prysmsync.RateLimits{
+			RequestsPerSecond: ctx.GlobalInt(flags.RPCRequestsPerSecondFlag.Name),
+			RequestsBurst:     ctx.GlobalInt(flags.RPCRequestsBurstFlag.Name),
+			BlocksPerSecond:   ctx.GlobalInt(flags.RPCBlocksPerSecondFlag.Name),
+			BlocksBurstFactor: ctx.GlobalInt(flags.RPCBlocksBurstFactorFlag.Name),
+		},
 	})
 
 	return b.services.RegisterService(rs)
@@ -441,6 +495,16 @@ func (b *BeaconNode) registerInitialSyncService(ctx *cli.Context) error {
 
 }
 
+func (b *BeaconNode) registerBackfillService(ctx *cli.Context) error {
+	bs := backfill.NewService(context.Background(), &backfill.Config{
+		P2P:       b.fetchP2P(ctx),
+		DB:        b.db,
+		BatchSize: uint64(ctx.GlobalInt(flags.BackfillBatchSizeFlag.Name)),
+	})
+
+	return b.services.RegisterService(bs)
+}
+
 func (b *BeaconNode) registerRPCService(ctx *cli.Context) error {
 	var chainService *blockchain.Service
 	if err := b.services.FetchService(&chainService); err != nil {
@@ -457,6 +521,15 @@ func (b *BeaconNode) registerRPCService(ctx *cli.Context) error {
 		return err
 	}
 
+	var backfillFetcher backfill.ProgressFetcher
+	if !ctx.GlobalBool(flags.DisableBackfillFlag.Name) {
+		var backfillService *backfill.Service
+		if err := b.services.FetchService(&backfillService); err != nil {
+			return err
+		}
+		backfillFetcher = backfillService
+	}
+
 	genesisValidators := ctx.GlobalUint64(flags.InteropNumValidatorsFlag.Name)
 	genesisStatePath := ctx.GlobalString(flags.InteropGenesisStateFlag.Name)
 	var depositFetcher depositcache.DepositFetcher
@@ -487,6 +560,7 @@ func (b *BeaconNode) registerRPCService(ctx *cli.Context) error {
 		CertFlag:              cert,
 		KeyFlag:               key,
 		BeaconDB:              b.db,
+		StateGen:              b.stateGen,
 		Broadcaster:           b.fetchP2P(ctx),
 		PeersFetcher:          b.fetchP2P(ctx),
 		HeadFetcher:           chainService,
@@ -503,6 +577,7 @@ func (b *BeaconNode) registerRPCService(ctx *cli.Context) error {
 		ChainStartFetcher:     chainStartFetcher,
 		MockEth1Votes:         mockEth1DataVotes,
 		SyncService:           syncService,
+		BackfillFetcher:       backfillFetcher,
 		DepositFetcher:        depositFetcher,
 		PendingDepositFetcher: b.depositCache,
 		BlockNotifier:         b,
@@ -529,7 +604,7 @@ func (b *BeaconNode) registerPrometheusService(ctx *cli.Context) error {
 	}
 
 	if featureconfig.Get().EnableBackupWebhook {
-		additionalHandlers = append(additionalHandlers, prometheus.Handler{Path: "/db/backup", Handler: db.BackupHandler(b.db)})
+		additionalHandlers = append(additionalHandlers, prometheus.Handler{Path: "/db/backup", Handler: db.BackupHandler(b.db, c)})
 	}
 
 	additionalHandlers = append(additionalHandlers, prometheus.Handler{Path: "/tree", Handler: c.TreeHandler})
@@ -583,7 +658,7 @@ func (b *BeaconNode) registerArchiverService(ctx *cli.Context) error {
 	}
 	svc := archiver.NewArchiverService(context.Background(), &archiver.Config{
 		BeaconDB:             b.db,
-		HeadFetcher:          chainService,
+		StateGen:             b.stateGen,
 		ParticipationFetcher: chainService,
 		StateNotifier:        b,
 	})
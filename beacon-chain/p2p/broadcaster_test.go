@@ -2,15 +2,18 @@ package p2p
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
 	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	p2ptest "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
 	testpb "github.com/prysmaticlabs/prysm/proto/testing"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/testutil"
 )
 
@@ -28,6 +31,7 @@ func TestService_Broadcast(t *testing.T) {
 		cfg: &Config{
 			Encoding: "ssz",
 		},
+		genesisValidatorsRoot: bytesutil.Bytes32(1),
 	}
 
 	msg := &testpb.TestSimpleMessage{
@@ -35,10 +39,14 @@ func TestService_Broadcast(t *testing.T) {
 	}
 
 	// Set a test gossip mapping for testpb.TestSimpleMessage.
-	GossipTypeMapping[reflect.TypeOf(msg)] = "/testing"
+	GossipTypeMapping[reflect.TypeOf(msg)] = "/testing/%x"
+	digest, err := p.forkDigest()
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	// External peer subscribes to the topic.
-	topic := "/testing" + p.Encoding().ProtocolSuffix()
+	topic := fmt.Sprintf("/testing/%x", digest) + p.Encoding().ProtocolSuffix()
 	sub, err := p2.PubSub().Subscribe(topic)
 	if err != nil {
 		t.Fatal(err)
@@ -84,6 +92,13 @@ func TestService_Broadcast_ReturnsErr_TopicNotMapped(t *testing.T) {
 	}
 }
 
+func TestService_Broadcast_ReturnsErr_ForkDigestUnknown(t *testing.T) {
+	p := Service{}
+	if err := p.Broadcast(context.Background(), &eth.SignedBeaconBlock{}); errors.Cause(err) != errForkDigestUnknown {
+		t.Fatalf("Expected error %v, got %v", errForkDigestUnknown, err)
+	}
+}
+
 func TestService_Attestation_Subnet(t *testing.T) {
 	if gtm := GossipTypeMapping[reflect.TypeOf(&eth.Attestation{})]; gtm != attestationSubnetTopicFormat {
 		t.Errorf("Constant is out of date. Wanted %s, got %s", attestationSubnetTopicFormat, gtm)
@@ -99,7 +114,7 @@ func TestService_Attestation_Subnet(t *testing.T) {
 					CommitteeIndex: 0,
 				},
 			},
-			topic: "/eth2/committee_index0_beacon_attestation",
+			topic: "/eth2/01020304/committee_index0_beacon_attestation",
 		},
 		{
 			att: &eth.Attestation{
@@ -107,7 +122,7 @@ func TestService_Attestation_Subnet(t *testing.T) {
 					CommitteeIndex: 11,
 				},
 			},
-			topic: "/eth2/committee_index11_beacon_attestation",
+			topic: "/eth2/01020304/committee_index11_beacon_attestation",
 		},
 		{
 			att: &eth.Attestation{
@@ -115,7 +130,7 @@ func TestService_Attestation_Subnet(t *testing.T) {
 					CommitteeIndex: 55,
 				},
 			},
-			topic: "/eth2/committee_index55_beacon_attestation",
+			topic: "/eth2/01020304/committee_index55_beacon_attestation",
 		},
 		{
 			att:   &eth.Attestation{},
@@ -126,7 +141,7 @@ func TestService_Attestation_Subnet(t *testing.T) {
 		},
 	}
 	for _, tt := range tests {
-		if res := attestationToTopic(tt.att); res != tt.topic {
+		if res := attestationToTopic(tt.att, [4]byte{1, 2, 3, 4}); res != tt.topic {
 			t.Errorf("Wrong topic, got %s wanted %s", res, tt.topic)
 		}
 	}
@@ -1,5 +1,9 @@
 package p2p
 
+import (
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+)
+
 // Config for the p2p service. These parameters are set from application level flags
 // to initialize the p2p service.
 type Config struct {
@@ -20,4 +24,6 @@ type Config struct {
 	WhitelistCIDR         string
 	EnableUPnP            bool
 	Encoding              string
+	TraceDir              string
+	StateNotifier         statefeed.Notifier
 }
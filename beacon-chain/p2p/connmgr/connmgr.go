@@ -48,19 +48,33 @@ var TickerPeriod = 5 * time.Second
 
 var log = logging.Logger("connmgr")
 
+// PeerValuer provides the eth2 level quality of peers, which takes precedence over tags when
+// choosing the peers to prune.
+type PeerValuer interface {
+	// Score returns the score of the peer, negative scores reflect misbehaviour.
+	Score(pid peer.ID) (float64, error)
+	// IsUseful reports whether the peer is worth keeping over others of a similar score, such as
+	// peers covering the attestation subnets we need or ahead of us in finality.
+	IsUseful(pid peer.ID) bool
+}
+
 // BasicConnMgr is a ConnManager that trims connections whenever the count exceeds the
-// high watermark. New connections are given a grace period before they're subject
+// high watermark, or the count of inbound connections exceeds the slots not reserved for
+// outbound connections. New connections are given a grace period before they're subject
 // to trimming. Trims are automatically run on demand, only if the time from the
 // previous trim is higher than 10 seconds. Furthermore, trims can be explicitly
 // requested through the public interface of this struct (see TrimOpenConns).
 //
 // See configuration parameters in NewConnManager.
 type BasicConnMgr struct {
-	highWater   int
-	lowWater    int
-	connCount   int32
-	gracePeriod time.Duration
-	segments    segments
+	highWater       int
+	lowWater        int
+	connCount       int32
+	inboundCount    int32
+	outboundReserve int
+	valuer          PeerValuer
+	gracePeriod     time.Duration
+	segments        segments
 
 	plk       sync.RWMutex
 	protected map[peer.ID]map[string]struct{}
@@ -113,13 +127,34 @@ func (s *segment) tagInfoFor(p peer.ID) *peerInfo {
 	return pi
 }
 
+// Option configures the eth2 specific behaviour of the BasicConnMgr.
+type Option func(*BasicConnMgr)
+
+// WithOutboundReserve reserves a number of connection slots for outbound connections, making it
+// harder for an attacker to eclipse the node by filling all slots with inbound connections.
+// Inbound connections above the remaining slots are pruned, while outbound connections are not
+// pruned below the reserve.
+func WithOutboundReserve(reserve int) Option {
+	return func(cm *BasicConnMgr) {
+		cm.outboundReserve = reserve
+	}
+}
+
+// WithPeerValuer prunes peers by the quality reported by the valuer before their tags.
+func WithPeerValuer(valuer PeerValuer) Option {
+	return func(cm *BasicConnMgr) {
+		cm.valuer = valuer
+	}
+}
+
 // NewConnManager creates a new BasicConnMgr with the provided params:
 // * lo and hi are watermarks governing the number of connections that'll be maintained.
 //   When the peer count exceeds the 'high watermark', as many peers will be pruned (and
 //   their connections terminated) until 'low watermark' peers remain.
 // * grace is the amount of time a newly opened connection is given before it becomes
 //   subject to pruning.
-func NewConnManager(low, hi int, grace time.Duration) *BasicConnMgr {
+// * opts configure the outbound reserve and the peer valuer.
+func NewConnManager(low, hi int, grace time.Duration, opts ...Option) *BasicConnMgr {
 	ctx, cancel := context.WithCancel(context.Background())
 	cm := &BasicConnMgr{
 		highWater:     hi,
@@ -139,10 +174,13 @@ func NewConnManager(low, hi int, grace time.Duration) *BasicConnMgr {
 			return ret
 		}(),
 	}
+	for _, opt := range opts {
+		opt(cm)
+	}
 
 	// Check every TickerPeriod to see if we should trim the number of active connections.
 	runutil.RunEvery(cm.ctx, TickerPeriod, func() {
-		if atomic.LoadInt32(&cm.connCount) > int32(cm.highWater) {
+		if atomic.LoadInt32(&cm.connCount) > int32(cm.highWater) || cm.inboundExcess() > 0 {
 			cm.TrimOpenConns(cm.ctx)
 		}
 	})
@@ -150,6 +188,15 @@ func NewConnManager(low, hi int, grace time.Duration) *BasicConnMgr {
 	return cm
 }
 
+// inboundExcess returns the number of inbound connections above the slots not reserved for
+// outbound connections.
+func (cm *BasicConnMgr) inboundExcess() int {
+	if cm.outboundReserve == 0 || cm.highWater == 0 {
+		return 0
+	}
+	return int(atomic.LoadInt32(&cm.inboundCount)) - (cm.highWater - cm.outboundReserve)
+}
+
 // Close shutsdown the connection manager.
 func (cm *BasicConnMgr) Close() error {
 	cm.cancel()
@@ -200,9 +247,11 @@ type peerInfo struct {
 }
 
 // TrimOpenConns closes the connections of as many peers as needed to make the peer count
-// equal the low watermark. Peers are sorted in ascending order based on their total value,
-// pruning those peers with the lowest scores first, as long as they are not within their
-// grace period.
+// equal the low watermark, and the inbound connection count fit the slots not reserved for
+// outbound connections. Peers with negative scores are pruned first, lowest first, followed
+// by peers which are not useful, and then peers in ascending order of their score and their
+// total tag value, as long as they are not within their grace period. Outbound connections
+// are not pruned below the outbound reserve.
 //
 // (a) there's another trim in progress, or (b) the silence period is in effect.
 func (cm *BasicConnMgr) TrimOpenConns(ctx context.Context) {
@@ -236,20 +285,24 @@ func (cm *BasicConnMgr) getConnsToClose(ctx context.Context) []network.Conn {
 	}
 
 	nconns := int(atomic.LoadInt32(&cm.connCount))
-	if nconns <= cm.lowWater {
+	inboundExcess := cm.inboundExcess()
+	if nconns <= cm.lowWater && inboundExcess <= 0 {
 		log.Info("open connection count below limit")
 		return nil
 	}
 
 	npeers := cm.segments.countPeers()
-	candidates := make([]*peerInfo, 0, npeers)
+	candidates := make([]*candidate, 0, npeers)
 	ncandidates := 0
+	noutbound := 0
 	gracePeriodStart := time.Now().Add(-cm.gracePeriod)
 
 	cm.plk.RLock()
 	for _, s := range cm.segments {
 		s.Lock()
 		for id, inf := range s.peers {
+			inbound, outbound := countDirections(inf.conns)
+			noutbound += outbound
 			if _, ok := cm.protected[id]; ok {
 				// skip over protected peer.
 				continue
@@ -258,14 +311,20 @@ func (cm *BasicConnMgr) getConnsToClose(ctx context.Context) []network.Conn {
 				// skip peers in the grace period.
 				continue
 			}
-			candidates = append(candidates, inf)
+			candidates = append(candidates, &candidate{
+				peerInfo: inf,
+				temp:     inf.temp,
+				value:    inf.value,
+				inbound:  inbound,
+				outbound: outbound,
+			})
 			ncandidates += len(inf.conns)
 		}
 		s.Unlock()
 	}
 	cm.plk.RUnlock()
 
-	if ncandidates < cm.lowWater {
+	if ncandidates < cm.lowWater && inboundExcess <= 0 {
 		log.Info("open connection count above limit but too many are in the grace period")
 		// We have too many connections but fewer than lowWater
 		// connections out of the grace period.
@@ -274,6 +333,16 @@ func (cm *BasicConnMgr) getConnsToClose(ctx context.Context) []network.Conn {
 		return nil
 	}
 
+	// The valuer is consulted outside of the segment locks, as it has locks of its own.
+	if cm.valuer != nil {
+		for _, c := range candidates {
+			if score, err := cm.valuer.Score(c.id); err == nil {
+				c.score = score
+			}
+			c.useful = cm.valuer.IsUseful(c.id)
+		}
+	}
+
 	// Sort peers according to their value.
 	sort.Slice(candidates, func(i, j int) bool {
 		left, right := candidates[i], candidates[j]
@@ -281,7 +350,18 @@ func (cm *BasicConnMgr) getConnsToClose(ctx context.Context) []network.Conn {
 		if left.temp != right.temp {
 			return left.temp
 		}
-		// otherwise, compare by value.
+		// misbehaving peers are pruned next, the worst first.
+		if (left.score < 0 || right.score < 0) && left.score != right.score {
+			return left.score < right.score
+		}
+		// then peers which are not useful to us.
+		if left.useful != right.useful {
+			return !left.useful
+		}
+		// otherwise, compare by score and then by value.
+		if left.score != right.score {
+			return left.score < right.score
+		}
 		return left.value < right.value
 	})
 
@@ -290,31 +370,65 @@ func (cm *BasicConnMgr) getConnsToClose(ctx context.Context) []network.Conn {
 	// slightly overallocate because we may have more than one conns per peer
 	selected := make([]network.Conn, 0, target+10)
 
-	for _, inf := range candidates {
-		if target <= 0 {
+	for _, c := range candidates {
+		if target <= 0 && inboundExcess <= 0 {
 			break
 		}
+		if target <= 0 && c.inbound == 0 {
+			// only inbound connections are over the limit.
+			continue
+		}
+		if c.outbound > 0 && noutbound-c.outbound < cm.outboundReserve {
+			// keep the reserved outbound connections.
+			continue
+		}
 
 		// lock this to protect from concurrent modifications from connect/disconnect events
-		s := cm.segments.get(inf.id)
+		s := cm.segments.get(c.id)
 		s.Lock()
 
-		if len(inf.conns) == 0 && inf.temp {
+		if len(c.conns) == 0 && c.temp {
 			// handle temporary entries for early tags -- this entry has gone past the grace period
 			// and still holds no connections, so prune it.
-			delete(s.peers, inf.id)
+			delete(s.peers, c.id)
 		} else {
-			for c := range inf.conns {
-				selected = append(selected, c)
+			for conn := range c.conns {
+				selected = append(selected, conn)
 			}
 		}
-		target -= len(inf.conns)
+		target -= len(c.conns)
+		inboundExcess -= c.inbound
+		noutbound -= c.outbound
 		s.Unlock()
 	}
 
 	return selected
 }
 
+// candidate is a peer which may be pruned, along with the values it is ranked by.
+type candidate struct {
+	*peerInfo
+	temp     bool
+	value    int
+	score    float64
+	useful   bool
+	inbound  int
+	outbound int
+}
+
+// countDirections returns the number of inbound and outbound connections.
+func countDirections(conns map[network.Conn]time.Time) (inbound int, outbound int) {
+	for c := range conns {
+		switch c.Stat().Direction {
+		case network.DirInbound:
+			inbound++
+		case network.DirOutbound:
+			outbound++
+		}
+	}
+	return inbound, outbound
+}
+
 // GetTagInfo is called to fetch the tag information associated with a given
 // peer, nil is returned if p refers to an unknown peer.
 func (cm *BasicConnMgr) GetTagInfo(p peer.ID) *connmgr.TagInfo {
@@ -467,6 +581,9 @@ func (nn *cmNotifee) Connected(n network.Network, c network.Conn) {
 
 	pinfo.conns[c] = time.Now()
 	atomic.AddInt32(&cm.connCount, 1)
+	if c.Stat().Direction == network.DirInbound {
+		atomic.AddInt32(&cm.inboundCount, 1)
+	}
 }
 
 // Disconnected is called by notifiers to inform that an existing connection has been closed or terminated.
@@ -496,6 +613,9 @@ func (nn *cmNotifee) Disconnected(n network.Network, c network.Conn) {
 		delete(s.peers, p)
 	}
 	atomic.AddInt32(&cm.connCount, -1)
+	if c.Stat().Direction == network.DirInbound {
+		atomic.AddInt32(&cm.inboundCount, -1)
+	}
 }
 
 // Listen is no-op in this implementation.
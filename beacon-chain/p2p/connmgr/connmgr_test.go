@@ -19,9 +19,14 @@ type tconn struct {
 
 	peer             peer.ID
 	closed           bool
+	dir              network.Direction
 	disconnectNotify func(net network.Network, conn network.Conn)
 }
 
+func (c *tconn) Stat() network.Stat {
+	return network.Stat{Direction: c.dir}
+}
+
 func (c *tconn) Close() error {
 	c.closed = true
 	if c.disconnectNotify != nil {
@@ -47,6 +52,23 @@ func randConn(t testing.TB, discNotify func(network.Network, network.Conn)) netw
 	return &tconn{peer: pid, disconnectNotify: discNotify}
 }
 
+func randConnWithDirection(t testing.TB, dir network.Direction) *tconn {
+	return &tconn{peer: tu.RandPeerIDFatal(t), dir: dir}
+}
+
+type testValuer struct {
+	scores map[peer.ID]float64
+	useful map[peer.ID]bool
+}
+
+func (v *testValuer) Score(pid peer.ID) (float64, error) {
+	return v.scores[pid], nil
+}
+
+func (v *testValuer) IsUseful(pid peer.ID) bool {
+	return v.useful[pid]
+}
+
 func TestConnTrimming(t *testing.T) {
 	cm := NewConnManager(200, 300, 0)
 	not := cm.Notifee()
@@ -679,3 +701,95 @@ func TestTemporaryEntryConvertedOnConnection(t *testing.T) {
 		t.Fatal("expected a non-temporary tag with value 20")
 	}
 }
+
+func TestOutboundReserve(t *testing.T) {
+	cm := NewConnManager(4, 4, 0, WithOutboundReserve(2))
+	not := cm.Notifee()
+
+	var outbound, inbound []*tconn
+	for i := 0; i < 2; i++ {
+		c := randConnWithDirection(t, network.DirOutbound)
+		outbound = append(outbound, c)
+		not.Connected(nil, c)
+	}
+	for i := 0; i < 3; i++ {
+		c := randConnWithDirection(t, network.DirInbound)
+		inbound = append(inbound, c)
+		not.Connected(nil, c)
+	}
+
+	// One connection above the watermarks, and one inbound connection in a reserved slot.
+	cm.TrimOpenConns(context.Background())
+	for _, c := range outbound {
+		if c.closed {
+			t.Error("outbound connections in the reserve should not be closed")
+		}
+	}
+	closed := 0
+	for _, c := range inbound {
+		if c.closed {
+			closed++
+		}
+	}
+	if closed != 1 {
+		t.Errorf("expected one inbound connection to be closed, closed %d", closed)
+	}
+}
+
+func TestInboundAboveReserveTrimmed(t *testing.T) {
+	cm := NewConnManager(10, 10, 0, WithOutboundReserve(8))
+	not := cm.Notifee()
+
+	var inbound []*tconn
+	for i := 0; i < 4; i++ {
+		c := randConnWithDirection(t, network.DirInbound)
+		inbound = append(inbound, c)
+		not.Connected(nil, c)
+	}
+
+	// The connection count is below the watermarks, but only two slots are left for inbound connections.
+	cm.TrimOpenConns(context.Background())
+	closed := 0
+	for _, c := range inbound {
+		if c.closed {
+			closed++
+		}
+	}
+	if closed != 2 {
+		t.Errorf("expected two inbound connections to be closed, closed %d", closed)
+	}
+}
+
+func TestPrunesByPeerValue(t *testing.T) {
+	valuer := &testValuer{
+		scores: make(map[peer.ID]float64),
+		useful: make(map[peer.ID]bool),
+	}
+	cm := NewConnManager(2, 2, 0, WithPeerValuer(valuer))
+	not := cm.Notifee()
+
+	misbehaving := randConnWithDirection(t, network.DirInbound)
+	valuer.scores[misbehaving.peer] = -10
+	valuer.useful[misbehaving.peer] = true
+	useless := randConnWithDirection(t, network.DirInbound)
+	valuer.scores[useless.peer] = 50
+	useful := randConnWithDirection(t, network.DirInbound)
+	valuer.scores[useful.peer] = 1
+	valuer.useful[useful.peer] = true
+	best := randConnWithDirection(t, network.DirInbound)
+	valuer.scores[best.peer] = 60
+	valuer.useful[best.peer] = true
+	for _, c := range []*tconn{misbehaving, useless, useful, best} {
+		not.Connected(nil, c)
+		// tags are outweighed by the valuer.
+		cm.TagPeer(c.peer, "tag", 100)
+	}
+
+	cm.TrimOpenConns(context.Background())
+	if !misbehaving.closed || !useless.closed {
+		t.Error("expected the misbehaving and useless peers to be pruned")
+	}
+	if useful.closed || best.closed {
+		t.Error("expected the useful peers to be kept")
+	}
+}
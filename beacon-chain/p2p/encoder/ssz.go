@@ -1,6 +1,7 @@
 package encoder
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 
@@ -11,8 +12,25 @@ import (
 
 var _ = NetworkEncoding(&SszNetworkEncoder{})
 
+// MaxChunkSize is the maximum allowed size of an uncompressed req/resp chunk.
+const MaxChunkSize = uint64(1 << 20)
+
+// MaxGossipSize is the maximum allowed size of an uncompressed gossip message.
+const MaxGossipSize = uint64(1 << 20)
+
+const (
+	// snappyMaxBlockSize is the maximum size of the uncompressed data of a snappy frame chunk.
+	snappyMaxBlockSize = 65536
+	// snappyStreamIdentifierLength is the length of the stream identifier starting a snappy frame stream.
+	snappyStreamIdentifierLength = 10
+	// snappyChunkOverhead is the length of the header and the checksum of a snappy frame chunk.
+	snappyChunkOverhead = 8
+)
+
 // SszNetworkEncoder supports p2p networking encoding using SimpleSerialize
-// with snappy compression (if enabled).
+// with snappy compression (if enabled). Gossip messages are compressed with
+// the snappy block format, while req/resp messages are compressed with the
+// snappy frame format and prefixed with the length of the uncompressed message.
 type SszNetworkEncoder struct {
 	UseSnappyCompression bool
 }
@@ -42,65 +60,74 @@ func (e SszNetworkEncoder) Encode(w io.Writer, msg interface{}) (int, error) {
 }
 
 // EncodeWithLength the proto message to the io.Writer. This encoding prefixes the byte slice with a protobuf varint
-// to indicate the size of the message.
+// to indicate the size of the message. The message may not be larger than MaxChunkSize.
 func (e SszNetworkEncoder) EncodeWithLength(w io.Writer, msg interface{}) (int, error) {
-	if msg == nil {
-		return 0, nil
-	}
-	b, err := e.doEncode(msg)
-	if err != nil {
-		return 0, err
-	}
-	b = append(proto.EncodeVarint(uint64(len(b))), b...)
-	return w.Write(b)
+	return e.EncodeWithMaxLength(w, msg, MaxChunkSize)
 }
 
 // EncodeWithMaxLength the proto message to the io.Writer. This encoding prefixes the byte slice with a protobuf varint
 // to indicate the size of the message. This checks that the encoded message isn't larger than the provided max limit.
+// With snappy compression, the varint is the size of the uncompressed message and the message is written in
+// the snappy frame format.
 func (e SszNetworkEncoder) EncodeWithMaxLength(w io.Writer, msg interface{}, maxSize uint64) (int, error) {
 	if msg == nil {
 		return 0, nil
 	}
-	b, err := e.doEncode(msg)
+	b, err := ssz.Marshal(msg)
 	if err != nil {
 		return 0, err
 	}
 	if uint64(len(b)) > maxSize {
 		return 0, fmt.Errorf("size of encoded message is %d which is larger than the provided max limit of %d", len(b), maxSize)
 	}
-	b = append(proto.EncodeVarint(uint64(len(b))), b...)
-	return w.Write(b)
+	buf := bytes.NewBuffer(proto.EncodeVarint(uint64(len(b))))
+	if e.UseSnappyCompression {
+		sw := snappy.NewBufferedWriter(buf)
+		if _, err := sw.Write(b); err != nil {
+			return 0, err
+		}
+		if err := sw.Close(); err != nil {
+			return 0, err
+		}
+	} else {
+		buf.Write(b)
+	}
+	return w.Write(buf.Bytes())
 }
 
-// Decode the bytes to the protobuf message provided.
+// Decode the bytes to the protobuf message provided. The message may not be larger than MaxGossipSize, which
+// is checked before decompression.
 func (e SszNetworkEncoder) Decode(b []byte, to interface{}) error {
 	if e.UseSnappyCompression {
-		var err error
+		msgLen, err := snappy.DecodedLen(b)
+		if err != nil {
+			return err
+		}
+		if uint64(msgLen) > MaxGossipSize {
+			return fmt.Errorf("size of decoded message is %d which is larger than the provided max limit of %d", msgLen, MaxGossipSize)
+		}
 		b, err = snappy.Decode(nil /*dst*/, b)
 		if err != nil {
 			return err
 		}
 	}
+	if uint64(len(b)) > MaxGossipSize {
+		return fmt.Errorf("size of decoded message is %d which is larger than the provided max limit of %d", len(b), MaxGossipSize)
+	}
 
 	return ssz.Unmarshal(b, to)
 }
 
 // DecodeWithLength the bytes from io.Reader to the protobuf message provided.
+// The message may not be larger than MaxChunkSize.
 func (e SszNetworkEncoder) DecodeWithLength(r io.Reader, to interface{}) error {
-	msgLen, err := readVarint(r)
-	if err != nil {
-		return err
-	}
-	b := make([]byte, msgLen)
-	_, err = r.Read(b)
-	if err != nil {
-		return err
-	}
-	return e.Decode(b, to)
+	return e.DecodeWithMaxLength(r, to, MaxChunkSize)
 }
 
 // DecodeWithMaxLength the bytes from io.Reader to the protobuf message provided.
-// This checks that the decoded message isn't larger than the provided max limit.
+// This checks that the decoded message isn't larger than the provided max limit. With snappy
+// compression, the limit is checked against the uncompressed size before decompressing, and no
+// more compressed bytes are read than the snappy frame format allows for a message of that size.
 func (e SszNetworkEncoder) DecodeWithMaxLength(r io.Reader, to interface{}, maxSize uint64) error {
 	msgLen, err := readVarint(r)
 	if err != nil {
@@ -109,12 +136,14 @@ func (e SszNetworkEncoder) DecodeWithMaxLength(r io.Reader, to interface{}, maxS
 	if msgLen > maxSize {
 		return fmt.Errorf("size of decoded message is %d which is larger than the provided max limit of %d", msgLen, maxSize)
 	}
+	if e.UseSnappyCompression {
+		r = snappy.NewReader(io.LimitReader(r, int64(maxFramedLength(msgLen))))
+	}
 	b := make([]byte, msgLen)
-	_, err = r.Read(b)
-	if err != nil {
+	if _, err := io.ReadFull(r, b); err != nil {
 		return err
 	}
-	return e.Decode(b, to)
+	return ssz.Unmarshal(b, to)
 }
 
 // ProtocolSuffix returns the appropriate suffix for protocol IDs.
@@ -124,3 +153,14 @@ func (e SszNetworkEncoder) ProtocolSuffix() string {
 	}
 	return "/ssz"
 }
+
+// maxFramedLength returns the maximum length of a message of the given uncompressed length in the
+// snappy frame format, which splits the message in chunks of at most snappyMaxBlockSize bytes.
+func maxFramedLength(msgLen uint64) uint64 {
+	chunks := (msgLen + snappyMaxBlockSize - 1) / snappyMaxBlockSize
+	if chunks == 0 {
+		chunks = 1
+	}
+	// Snappy compresses n bytes to at most 32+n+n/6 bytes.
+	return snappyStreamIdentifierLength + chunks*(snappyChunkOverhead+32) + msgLen + msgLen/6
+}
@@ -2,12 +2,14 @@ package encoder_test
 
 import (
 	"bytes"
+	"encoding/hex"
 	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/encoder"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	testpb "github.com/prysmaticlabs/prysm/proto/testing"
 )
 
@@ -103,3 +105,97 @@ func TestSszNetworkEncoder_DecodeWithMaxLength(t *testing.T) {
 		t.Errorf("error did not contain wanted message. Wanted: %s but Got: %s", wanted, err.Error())
 	}
 }
+
+func TestSszNetworkEncoder_DecodeWithLength_SnappyFramedVectors(t *testing.T) {
+	// The status message encoded by other clients, prefixed with its uncompressed length and
+	// framed with an uncompressed and a compressed snappy chunk respectively.
+	want := &pb.Status{
+		ForkDigest:     []byte{1, 2, 3, 4},
+		FinalizedRoot:  make([]byte, 32),
+		FinalizedEpoch: 1,
+		HeadRoot:       make([]byte, 32),
+		HeadSlot:       32,
+	}
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{
+			name: "uncompressed chunk",
+			encoded: "54ff060000734e61507059015800000540c823" +
+				"010203040000000000000000000000000000000000000000000000000000000000000000010000000000000000000000" +
+				"000000000000000000000000000000000000000000000000000000002000000000000000",
+		},
+		{
+			name:    "compressed chunk",
+			encoded: "54ff060000734e61507059001900000540c823541001020304007a010000017e21000d2000200d0a",
+		},
+	}
+	e := &encoder.SszNetworkEncoder{UseSnappyCompression: true}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := hex.DecodeString(tt.encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded := &pb.Status{}
+			if err := e.DecodeWithLength(bytes.NewReader(b), decoded); err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(decoded, want) {
+				t.Errorf("Wanted %v, received %v", want, decoded)
+			}
+		})
+	}
+}
+
+func TestSszNetworkEncoder_EncodeWithLength_SnappyFramed(t *testing.T) {
+	buf := new(bytes.Buffer)
+	msg := &pb.Status{
+		ForkDigest:    []byte{1, 2, 3, 4},
+		FinalizedRoot: make([]byte, 32),
+		HeadRoot:      make([]byte, 32),
+	}
+	e := &encoder.SszNetworkEncoder{UseSnappyCompression: true}
+	if _, err := e.EncodeWithLength(buf, msg); err != nil {
+		t.Fatal(err)
+	}
+	// The uncompressed length of the status message, followed by the snappy stream identifier.
+	wantPrefix := []byte("\x54\xff\x06\x00\x00sNaPpY")
+	if !bytes.HasPrefix(buf.Bytes(), wantPrefix) {
+		t.Errorf("Wanted encoding to start with %#x, received %#x", wantPrefix, buf.Bytes())
+	}
+}
+
+func TestSszNetworkEncoder_DecodeWithMaxLength_SnappyChecksLengthFirst(t *testing.T) {
+	// Only the length prefix is sent, so decoding fails on the length before reading the message.
+	buf := bytes.NewBuffer(proto.EncodeVarint(encoder.MaxChunkSize + 1))
+	e := &encoder.SszNetworkEncoder{UseSnappyCompression: true}
+	err := e.DecodeWithLength(buf, &pb.Status{})
+	wanted := fmt.Sprintf("which is larger than the provided max limit of %d", encoder.MaxChunkSize)
+	if err == nil || !strings.Contains(err.Error(), wanted) {
+		t.Errorf("Wanted error containing %s, received %v", wanted, err)
+	}
+}
+
+func TestSszNetworkEncoder_DecodeWithLength_SnappyCorruptChecksum(t *testing.T) {
+	b, err := hex.DecodeString("54ff060000734e61507059001900000540c824541001020304007a010000017e21000d2000200d0a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &encoder.SszNetworkEncoder{UseSnappyCompression: true}
+	if err := e.DecodeWithLength(bytes.NewReader(b), &pb.Status{}); err == nil {
+		t.Error("Expected error decoding a chunk with an invalid checksum")
+	}
+}
+
+func TestSszNetworkEncoder_Decode_SnappyMaxGossipSize(t *testing.T) {
+	// A snappy block only declaring an uncompressed length above the limit.
+	b := proto.EncodeVarint(encoder.MaxGossipSize + 1)
+	e := &encoder.SszNetworkEncoder{UseSnappyCompression: true}
+	err := e.Decode(b, &pb.Status{})
+	wanted := fmt.Sprintf("which is larger than the provided max limit of %d", encoder.MaxGossipSize)
+	if err == nil || !strings.Contains(err.Error(), wanted) {
+		t.Errorf("Wanted error containing %s, received %v", wanted, err)
+	}
+}
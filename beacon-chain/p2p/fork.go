@@ -0,0 +1,127 @@
+package p2p
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/event"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// eth2ENRKey is the key of the ENR entry holding the fork of the chain a node is following.
+const eth2ENRKey = "eth2"
+
+// errForkDigestUnknown is returned when the fork digest is needed before the genesis validators
+// root of the chain is known.
+var errForkDigestUnknown = errors.New("fork digest is unknown until the chain is initialized")
+
+// awaitStateInitialized waits for the state initialized event of the chain, which carries the
+// genesis data the fork digest is computed from.
+func (s *Service) awaitStateInitialized(stateChannel chan *feed.Event, stateSub event.Subscription) {
+	defer stateSub.Unsubscribe()
+	for {
+		select {
+		case ev := <-stateChannel:
+			if ev.Type == statefeed.Initialized {
+				data := ev.Data.(*statefeed.InitializedData)
+				if err := s.setGenesisValidatorsRoot(data.GenesisValidatorsRoot); err != nil {
+					log.WithError(err).Error("Could not set the fork of the chain")
+				}
+				return
+			}
+		case <-s.ctx.Done():
+			log.Debug("Context closed, exiting goroutine")
+			return
+		case err := <-stateSub.Err():
+			log.WithError(err).Error("Subscription to state notifier failed")
+			return
+		}
+	}
+}
+
+// setGenesisValidatorsRoot records the genesis validators root of the chain and advertises the
+// resulting fork in the ENR of the local node.
+func (s *Service) setGenesisValidatorsRoot(genesisValidatorsRoot []byte) error {
+	s.forkLock.Lock()
+	s.genesisValidatorsRoot = genesisValidatorsRoot
+	s.forkLock.Unlock()
+
+	digest, err := s.forkDigest()
+	if err != nil {
+		return err
+	}
+	if s.dv5Listener == nil {
+		return nil
+	}
+	return addForkEntry(s.dv5Listener.LocalNode(), digest)
+}
+
+// forkDigest returns the digest of the current fork of the chain, which is only known once the
+// chain has been initialized.
+func (s *Service) forkDigest() ([4]byte, error) {
+	s.forkLock.RLock()
+	defer s.forkLock.RUnlock()
+	if len(s.genesisValidatorsRoot) == 0 {
+		return [4]byte{}, errForkDigestUnknown
+	}
+	return helpers.ComputeForkDigest(params.BeaconConfig().GenesisForkVersion, s.genesisValidatorsRoot)
+}
+
+// addForkEntry sets the eth2 entry of the local node to the fork with the given digest. No
+// future fork is scheduled, so the next fork version is the current one.
+func addForkEntry(node *enode.LocalNode, digest [4]byte) error {
+	enrForkID := &pb.ENRForkID{
+		ForkDigest:      digest[:],
+		NextForkVersion: params.BeaconConfig().GenesisForkVersion,
+		NextForkEpoch:   params.BeaconConfig().FarFutureEpoch,
+	}
+	enc, err := ssz.Marshal(enrForkID)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal ENR fork id")
+	}
+	node.Set(enr.WithEntry(eth2ENRKey, enc))
+	return nil
+}
+
+// retrieveForkEntry reads the fork of the chain a node is following from its ENR.
+func retrieveForkEntry(record *enr.Record) (*pb.ENRForkID, error) {
+	enc := []byte{}
+	if err := record.Load(enr.WithEntry(eth2ENRKey, &enc)); err != nil {
+		return nil, err
+	}
+	enrForkID := &pb.ENRForkID{}
+	if err := ssz.Unmarshal(enc, enrForkID); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal ENR fork id")
+	}
+	return enrForkID, nil
+}
+
+// filterByFork drops the nodes which do not advertise the fork of the chain followed by the local
+// node. Until that fork is known, all nodes are kept.
+func (s *Service) filterByFork(nodes []*enode.Node) []*enode.Node {
+	digest, err := s.forkDigest()
+	if err != nil {
+		return nodes
+	}
+	filtered := make([]*enode.Node, 0, len(nodes))
+	for _, node := range nodes {
+		enrForkID, err := retrieveForkEntry(node.Record())
+		if err != nil {
+			log.WithError(err).WithField("nodeID", node.ID()).Trace("Skipping node without fork entry")
+			continue
+		}
+		if !bytes.Equal(enrForkID.ForkDigest, digest[:]) {
+			log.WithField("nodeID", node.ID()).Trace("Skipping node on a different fork")
+			continue
+		}
+		filtered = append(filtered, node)
+	}
+	return filtered
+}
@@ -0,0 +1,81 @@
+package p2p
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestForkEntry_RoundTrip(t *testing.T) {
+	ipAddr, pkey := createAddrAndPrivKey(t)
+	localNode, err := createLocalNode(pkey, ipAddr, 3000, 3001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := [4]byte{1, 2, 3, 4}
+	if err := addForkEntry(localNode, digest); err != nil {
+		t.Fatal(err)
+	}
+
+	enrForkID, err := retrieveForkEntry(localNode.Node().Record())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(enrForkID.ForkDigest, digest[:]) {
+		t.Errorf("Wanted fork digest %#x, received %#x", digest, enrForkID.ForkDigest)
+	}
+	if !bytes.Equal(enrForkID.NextForkVersion, params.BeaconConfig().GenesisForkVersion) {
+		t.Errorf("Wanted next fork version %#x, received %#x", params.BeaconConfig().GenesisForkVersion, enrForkID.NextForkVersion)
+	}
+	if enrForkID.NextForkEpoch != params.BeaconConfig().FarFutureEpoch {
+		t.Errorf("Wanted next fork epoch %d, received %d", params.BeaconConfig().FarFutureEpoch, enrForkID.NextForkEpoch)
+	}
+}
+
+func TestFilterByFork_SkipsIncompatibleNodes(t *testing.T) {
+	s := &Service{}
+	newNode := func(digest *[4]byte) *enode.Node {
+		ipAddr, pkey := createAddrAndPrivKey(t)
+		localNode, err := createLocalNode(pkey, ipAddr, 3000, 3001)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if digest != nil {
+			if err := addForkEntry(localNode, *digest); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return localNode.Node()
+	}
+
+	if err := s.setGenesisValidatorsRoot(bytesutil.Bytes32(1)); err != nil {
+		t.Fatal(err)
+	}
+	digest, err := s.forkDigest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherDigest := [4]byte{'B'}
+	compatible := newNode(&digest)
+	nodes := []*enode.Node{newNode(nil), compatible, newNode(&otherDigest)}
+
+	filtered := s.filterByFork(nodes)
+	if len(filtered) != 1 || filtered[0].ID() != compatible.ID() {
+		t.Errorf("Expected only the node on the same fork to be kept, received %d nodes", len(filtered))
+	}
+}
+
+func TestFilterByFork_KeepsAllNodesBeforeInitialization(t *testing.T) {
+	s := &Service{}
+	ipAddr, pkey := createAddrAndPrivKey(t)
+	localNode, err := createLocalNode(pkey, ipAddr, 3000, 3001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filtered := s.filterByFork([]*enode.Node{localNode.Node()}); len(filtered) != 1 {
+		t.Errorf("Expected node to be kept while the fork is unknown, received %d nodes", len(filtered))
+	}
+}
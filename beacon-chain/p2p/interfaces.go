@@ -9,6 +9,7 @@ import (
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/encoder"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 )
 
 // P2P represents the full p2p interface composed of all of the sub-interfaces.
@@ -21,6 +22,7 @@ type P2P interface {
 	Sender
 	ConnectionHandler
 	PeersProvider
+	MetadataProvider
 }
 
 // Broadcaster broadcasts messages to peers over the p2p pubsub protocol.
@@ -53,11 +55,19 @@ type PubSubProvider interface {
 type PeerManager interface {
 	Disconnect(peer.ID) error
 	PeerID() peer.ID
+	RefreshENR()
+	FindPeersWithSubnet(index uint64) (bool, error)
 }
 
 // Sender abstracts the sending functionality from libp2p.
 type Sender interface {
-	Send(context.Context, interface{}, peer.ID) (network.Stream, error)
+	Send(ctx context.Context, message interface{}, topic string, pid peer.ID) (network.Stream, error)
+}
+
+// MetadataProvider returns the metadata of the local peer, which is served to other peers on request.
+type MetadataProvider interface {
+	Metadata() *pb.MetaData
+	MetadataSeq() uint64
 }
 
 // PeersProvider abstracts obtaining our current list of known peers status.
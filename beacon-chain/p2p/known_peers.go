@@ -0,0 +1,86 @@
+package p2p
+
+import (
+	"path"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// knownPeersFileName is the name of the file in the data directory the known-good peers are persisted to.
+const knownPeersFileName = "known_peers.json"
+
+const (
+	// knownPeersSaveInterval is how often the known-good peers are persisted.
+	knownPeersSaveInterval = 5 * time.Minute
+	// knownPeerDialAttempts is how many times a known peer is dialled on startup before giving up on it.
+	knownPeerDialAttempts = 4
+	// knownPeerInitialBackoff is the time before the first redial of the known peers which could not be
+	// reached, doubling after every attempt.
+	knownPeerInitialBackoff = 5 * time.Second
+)
+
+// loadKnownPeers restores the peers persisted in the data directory by a previous run, to be dialled on startup.
+func (s *Service) loadKnownPeers() {
+	if s.cfg.DataDir == "" {
+		return
+	}
+	pids, err := s.peers.LoadKnownPeers(path.Join(s.cfg.DataDir, knownPeersFileName))
+	if err != nil {
+		log.WithError(err).Error("Could not load known peers")
+		return
+	}
+	s.knownPeers = pids
+}
+
+// saveKnownPeers persists the known-good peers in the data directory.
+func (s *Service) saveKnownPeers() {
+	if s.cfg.DataDir == "" {
+		return
+	}
+	if err := s.peers.SaveKnownPeers(path.Join(s.cfg.DataDir, knownPeersFileName)); err != nil {
+		log.WithError(err).Error("Could not persist known peers")
+	}
+}
+
+// reconnectKnownPeers dials the peers known from a previous run, best scored first. Dials which fail
+// are retried with an exponential backoff until they run out of attempts. Dialling stops once the peer
+// limit is reached, and peers which turned bad or were banned in the meantime are skipped.
+func (s *Service) reconnectKnownPeers(pids []peer.ID) {
+	if len(pids) > 0 {
+		log.WithField("peers", len(pids)).Debug("Reconnecting to known peers")
+	}
+	backoff := knownPeerInitialBackoff
+	for attempt := 0; attempt < knownPeerDialAttempts && len(pids) > 0; attempt++ {
+		var failed []peer.ID
+		for _, pid := range pids {
+			if uint(len(s.peers.Active())) >= s.cfg.MaxPeers {
+				return
+			}
+			if s.host.Network().Connectedness(pid) == network.Connected || s.peers.IsBad(pid) {
+				continue
+			}
+			addr, err := s.peers.Address(pid)
+			if err != nil || s.peers.IsBannedAddr(addr) {
+				continue
+			}
+			info := peer.AddrInfo{ID: pid, Addrs: []ma.Multiaddr{addr}}
+			if err := s.host.Connect(s.ctx, info); err != nil {
+				log.WithError(err).WithField("peer", pid.Pretty()).Debug("Could not reconnect to known peer")
+				failed = append(failed, pid)
+			}
+		}
+		pids = failed
+		if len(pids) == 0 {
+			return
+		}
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
@@ -1,6 +1,9 @@
 package p2p
 
 import (
+	"fmt"
+	"sync"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -16,16 +19,110 @@ var (
 		Help: "The number of peers in a given state.",
 	},
 		[]string{"state"})
+	p2pProtocolBytesReceived = newTotalCounterVec(prometheus.CounterOpts{
+		Name: "p2p_protocol_bytes_received_total",
+		Help: "The number of bytes received over a given protocol.",
+	}, "protocol")
+	p2pProtocolBytesSent = newTotalCounterVec(prometheus.CounterOpts{
+		Name: "p2p_protocol_bytes_sent_total",
+		Help: "The number of bytes sent over a given protocol.",
+	}, "protocol")
+	p2pPeerBytesReceived = newTotalCounterVec(prometheus.CounterOpts{
+		Name: "p2p_peer_bytes_received_total",
+		Help: "The number of bytes received from a given connected peer.",
+	}, "peer")
+	p2pPeerBytesSent = newTotalCounterVec(prometheus.CounterOpts{
+		Name: "p2p_peer_bytes_sent_total",
+		Help: "The number of bytes sent to a given connected peer.",
+	}, "peer")
+	p2pPeerMessagesReceived = newTotalCounterVec(prometheus.CounterOpts{
+		Name: "p2p_peer_messages_received_total",
+		Help: "The number of gossip messages received from a given connected peer.",
+	}, "peer")
+	p2pTopicBytesSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "p2p_topic_bytes_sent_total",
+		Help: "The number of bytes of the messages published on a given gossip topic.",
+	},
+		[]string{"topic"})
 )
 
 func (s *Service) updateMetrics() {
-	for topic := range GossipTopicMappings {
-		topic += s.Encoding().ProtocolSuffix()
-		p2pTopicPeerCount.WithLabelValues(topic).Set(float64(len(s.pubsub.ListPeers(topic))))
+	// Topics are only known once the fork digest of the chain is. Attestation subnets are
+	// subscribed to dynamically, so they are not tracked.
+	if digest, err := s.forkDigest(); err == nil {
+		for topic := range GossipTopicMappings {
+			if topic == attestationSubnetTopicFormat {
+				continue
+			}
+			topic = fmt.Sprintf(topic, digest) + s.Encoding().ProtocolSuffix()
+			p2pTopicPeerCount.WithLabelValues(topic).Set(float64(len(s.pubsub.ListPeers(topic))))
+		}
 	}
 	p2pPeerCount.WithLabelValues("Connected").Set(float64(len(s.peers.Connected())))
 	p2pPeerCount.WithLabelValues("Disconnected").Set(float64(len(s.peers.Disconnected())))
 	p2pPeerCount.WithLabelValues("Connecting").Set(float64(len(s.peers.Connecting())))
 	p2pPeerCount.WithLabelValues("Disconnecting").Set(float64(len(s.peers.Disconnecting())))
 	p2pPeerCount.WithLabelValues("Bad").Set(float64(len(s.peers.Bad())))
+
+	for protocol, stats := range s.bandwidth.GetBandwidthByProtocol() {
+		p2pProtocolBytesReceived.report(string(protocol), float64(stats.TotalIn))
+		p2pProtocolBytesSent.report(string(protocol), float64(stats.TotalOut))
+	}
+	// Only connected peers are reported, so that the metrics of past peers do not pile up.
+	connected := make(map[string]bool)
+	for _, pid := range s.peers.Connected() {
+		stats := s.bandwidth.GetBandwidthForPeer(pid)
+		s.peers.SetBandwidth(pid, uint64(stats.TotalIn), uint64(stats.TotalOut))
+		traffic, err := s.peers.Traffic(pid)
+		if err != nil {
+			continue
+		}
+		connected[pid.Pretty()] = true
+		p2pPeerBytesReceived.report(pid.Pretty(), float64(traffic.BytesReceived))
+		p2pPeerBytesSent.report(pid.Pretty(), float64(traffic.BytesSent))
+		p2pPeerMessagesReceived.report(pid.Pretty(), float64(traffic.MessagesReceived))
+	}
+	p2pPeerBytesReceived.prune(connected)
+	p2pPeerBytesSent.prune(connected)
+	p2pPeerMessagesReceived.prune(connected)
+}
+
+// totalCounterVec reports cumulative totals, such as the ones of the bandwidth counter of the host,
+// as counters by adding the increase of a total since it was last reported.
+type totalCounterVec struct {
+	*prometheus.CounterVec
+	lock     sync.Mutex
+	reported map[string]float64
+}
+
+func newTotalCounterVec(opts prometheus.CounterOpts, label string) *totalCounterVec {
+	return &totalCounterVec{
+		CounterVec: promauto.NewCounterVec(opts, []string{label}),
+		reported:   make(map[string]float64),
+	}
+}
+
+// report adds the increase of the total of the label value to its counter. A total lower than the
+// reported one started again from zero, such as the traffic of a peer which reconnected.
+func (c *totalCounterVec) report(value string, total float64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delta := total - c.reported[value]
+	if delta < 0 {
+		delta = total
+	}
+	c.WithLabelValues(value).Add(delta)
+	c.reported[value] = total
+}
+
+// prune removes the counters of the label values which are not kept.
+func (c *totalCounterVec) prune(keep map[string]bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for value := range c.reported {
+		if !keep[value] {
+			c.DeleteLabelValues(value)
+			delete(c.reported, value)
+		}
+	}
 }
@@ -7,14 +7,16 @@ import (
 	"time"
 
 	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p-core/metrics"
 	filter "github.com/libp2p/go-maddr-filter"
 	"github.com/multiformats/go-multiaddr"
 	ma "github.com/multiformats/go-multiaddr"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/connmgr"
 )
 
-// buildOptions for the libp2p host.
-func buildOptions(cfg *Config, ip net.IP, priKey *ecdsa.PrivateKey) []libp2p.Option {
+// buildOptions for the libp2p host. The connection manager prunes peers by the quality reported by
+// the valuer, and the traffic of the host is accounted by the bandwidth reporter.
+func buildOptions(cfg *Config, ip net.IP, priKey *ecdsa.PrivateKey, valuer connmgr.PeerValuer, reporter metrics.Reporter) []libp2p.Option {
 	listen, err := ma.NewMultiaddr(fmt.Sprintf("/ip4/%s/tcp/%d", ip, cfg.TCPPort))
 	if err != nil {
 		log.Fatalf("Failed to p2p listen: %v", err)
@@ -26,7 +28,14 @@ func buildOptions(cfg *Config, ip net.IP, priKey *ecdsa.PrivateKey) []libp2p.Opt
 		whitelistSubnet(cfg.WhitelistCIDR),
 		// Add one for the boot node and another for the relay, otherwise when we are close to maxPeers we will be above the high
 		// water mark and continually trigger pruning.
-		libp2p.ConnectionManager(connmgr.NewConnManager(int(cfg.MaxPeers+2), int(cfg.MaxPeers+2), 1*time.Second)),
+		libp2p.ConnectionManager(connmgr.NewConnManager(
+			int(cfg.MaxPeers+2),
+			int(cfg.MaxPeers+2),
+			1*time.Second,
+			connmgr.WithOutboundReserve(int(float64(cfg.MaxPeers)*outboundPeerFraction)),
+			connmgr.WithPeerValuer(valuer),
+		)),
+		libp2p.BandwidthReporter(reporter),
 	}
 	if cfg.EnableUPnP {
 		options = append(options, libp2p.NATPortMap()) //Allow to use UPnP
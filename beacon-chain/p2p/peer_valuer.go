@@ -0,0 +1,40 @@
+package p2p
+
+import (
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+)
+
+// outboundPeerFraction is the fraction of the peer slots reserved for outbound connections, so that
+// an attacker cannot eclipse the node by filling all slots with inbound connections.
+const outboundPeerFraction = 0.2
+
+// peerValuer ranks peers for pruning by the connection manager by their score and usefulness.
+type peerValuer struct {
+	peers *peers.Status
+}
+
+// Score returns the score of the peer.
+func (v *peerValuer) Score(pid peer.ID) (float64, error) {
+	return v.peers.Score(pid)
+}
+
+// IsUseful reports whether the peer is ahead of us in finality, or subscribed to one of the long
+// lived attestation subnets of our validators.
+func (v *peerValuer) IsUseful(pid peer.ID) bool {
+	chainState, err := v.peers.ChainState(pid)
+	if err == nil && chainState != nil && chainState.FinalizedEpoch > v.peers.LocalFinalizedEpoch() {
+		return true
+	}
+	metaData, err := v.peers.Metadata(pid)
+	if err != nil || metaData == nil {
+		return false
+	}
+	for _, idx := range cache.SubnetIDs.GetAllSubnets() {
+		if subnetBitSet(metaData.Attnets, idx) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+)
+
+func TestPeerValuer_IsUseful(t *testing.T) {
+	cache.SubnetIDs.AddPersistentCommittee([]byte{'A'}, []uint64{3}, time.Minute)
+	defer cache.SubnetIDs.AddPersistentCommittee([]byte{'A'}, nil, 0)
+
+	p := peers.NewStatus(maxBadResponses)
+	p.SetLocalFinalizedEpoch(5)
+	v := &peerValuer{peers: p}
+
+	ahead := peer.ID("ahead")
+	p.Add(ahead, nil, network.DirInbound)
+	p.SetChainState(ahead, &pb.Status{FinalizedEpoch: 6})
+	onSubnet := peer.ID("onSubnet")
+	p.Add(onSubnet, nil, network.DirInbound)
+	p.SetMetadata(onSubnet, &pb.MetaData{Attnets: []byte{1 << 3}})
+	behind := peer.ID("behind")
+	p.Add(behind, nil, network.DirInbound)
+	p.SetChainState(behind, &pb.Status{FinalizedEpoch: 5})
+	p.SetMetadata(behind, &pb.MetaData{Attnets: []byte{1 << 2}})
+
+	if !v.IsUseful(ahead) {
+		t.Error("Expected peer ahead in finality to be useful")
+	}
+	if !v.IsUseful(onSubnet) {
+		t.Error("Expected peer on our subnet to be useful")
+	}
+	if v.IsUseful(behind) {
+		t.Error("Expected peer neither ahead nor on our subnets not to be useful")
+	}
+	if v.IsUseful(peer.ID("unknown")) {
+		t.Error("Expected unknown peer not to be useful")
+	}
+}
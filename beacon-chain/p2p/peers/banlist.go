@@ -0,0 +1,139 @@
+package peers
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+)
+
+// banList holds the banned peer IDs and IP addresses along with the time their bans expire. If a
+// path is set, the list is written to it whenever a ban is added.
+type banList struct {
+	path  string
+	peers map[peer.ID]time.Time
+	ips   map[string]time.Time
+}
+
+// banListFile is the format of the ban list on disk, mapping peer IDs and IP addresses to the unix
+// time their bans expire.
+type banListFile struct {
+	Peers map[string]int64 `json:"peers"`
+	IPs   map[string]int64 `json:"ips"`
+}
+
+func newBanList() *banList {
+	return &banList{
+		peers: make(map[peer.ID]time.Time),
+		ips:   make(map[string]time.Time),
+	}
+}
+
+// ban the peer and the IP address of its multiaddress until the given time.
+func (b *banList) ban(pid peer.ID, addr ma.Multiaddr, until time.Time) error {
+	b.peers[pid] = until
+	if ip := ipFromMultiaddr(addr); ip != "" {
+		b.ips[ip] = until
+	}
+	return b.save()
+}
+
+func (b *banList) isBanned(pid peer.ID, now time.Time) bool {
+	until, ok := b.peers[pid]
+	return ok && now.Before(until)
+}
+
+func (b *banList) isBannedAddr(addr ma.Multiaddr, now time.Time) bool {
+	ip := ipFromMultiaddr(addr)
+	if ip == "" {
+		return false
+	}
+	until, ok := b.ips[ip]
+	return ok && now.Before(until)
+}
+
+func (b *banList) bannedPeers(now time.Time) []peer.ID {
+	pids := make([]peer.ID, 0, len(b.peers))
+	for pid, until := range b.peers {
+		if now.Before(until) {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
+
+// load the ban list from the path, which is used to persist the list from now on. A missing file
+// is an empty ban list.
+func (b *banList) load(path string, now time.Time) error {
+	b.path = path
+	enc, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var f banListFile
+	if err := json.Unmarshal(enc, &f); err != nil {
+		return errors.Wrapf(err, "could not decode ban list %s", path)
+	}
+	for id, until := range f.Peers {
+		pid, err := peer.IDB58Decode(id)
+		if err != nil {
+			return errors.Wrapf(err, "invalid peer id %s in ban list", id)
+		}
+		if t := time.Unix(until, 0); now.Before(t) {
+			b.peers[pid] = t
+		}
+	}
+	for ip, until := range f.IPs {
+		if t := time.Unix(until, 0); now.Before(t) {
+			b.ips[ip] = t
+		}
+	}
+	return nil
+}
+
+// save the ban list to its path, replacing the previous list atomically.
+func (b *banList) save() error {
+	if b.path == "" {
+		return nil
+	}
+	f := banListFile{
+		Peers: make(map[string]int64, len(b.peers)),
+		IPs:   make(map[string]int64, len(b.ips)),
+	}
+	for pid, until := range b.peers {
+		f.Peers[peer.IDB58Encode(pid)] = until.Unix()
+	}
+	for ip, until := range b.ips {
+		f.IPs[ip] = until.Unix()
+	}
+	enc, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpPath := b.path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, enc, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, b.path)
+}
+
+// ipFromMultiaddr returns the IPv4 or IPv6 address of the multiaddress, or an empty string if it has none.
+func ipFromMultiaddr(addr ma.Multiaddr) string {
+	if addr == nil {
+		return ""
+	}
+	if ip, err := addr.ValueForProtocol(ma.P_IP4); err == nil {
+		return ip
+	}
+	if ip, err := addr.ValueForProtocol(ma.P_IP6); err == nil {
+		return ip
+	}
+	return ""
+}
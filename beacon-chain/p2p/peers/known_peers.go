@@ -0,0 +1,133 @@
+package peers
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+)
+
+const (
+	// maxKnownPeers is the maximum number of peers persisted across restarts. The peers with the
+	// highest scores are kept.
+	maxKnownPeers = 100
+	// knownPeerExpiry is how long after it was last seen a peer is no longer worth dialling on startup.
+	knownPeerExpiry = 7 * 24 * time.Hour
+)
+
+// knownPeer is the format of a known-good peer on disk.
+type knownPeer struct {
+	ID         string     `json:"id"`
+	Address    string     `json:"address"`
+	LastSeen   int64      `json:"last_seen"`
+	Score      float64    `json:"score"`
+	ChainState *pb.Status `json:"chain_state,omitempty"`
+}
+
+// SaveKnownPeers persists the peers which were connected to at some point and are neither bad nor banned
+// to the given path, replacing the previous list atomically. Only the addresses of outbound connections
+// are dialable, so peers which only ever connected to us are not persisted.
+func (p *Status) SaveKnownPeers(path string) error {
+	p.lock.RLock()
+	now := roughtime.Now()
+	known := make([]*knownPeer, 0, len(p.status))
+	for pid, status := range p.status {
+		if status.address == nil || status.direction != network.DirOutbound || status.lastSeen.IsZero() {
+			continue
+		}
+		if p.isBad(pid, status, now) {
+			continue
+		}
+		known = append(known, &knownPeer{
+			ID:         peer.IDB58Encode(pid),
+			Address:    status.address.String(),
+			LastSeen:   status.lastSeen.Unix(),
+			Score:      status.score,
+			ChainState: status.chainState,
+		})
+	}
+	p.lock.RUnlock()
+
+	sort.Slice(known, func(i, j int) bool {
+		return known[i].Score > known[j].Score
+	})
+	if len(known) > maxKnownPeers {
+		known = known[:maxKnownPeers]
+	}
+	enc, err := json.MarshalIndent(known, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, enc, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// LoadKnownPeers adds the peers persisted at the given path as disconnected peers, restoring their
+// address, score and chain state. It returns the peers worth dialling, best scored first, which excludes
+// peers not seen for a long time and banned peers. A missing file holds no peers.
+func (p *Status) LoadKnownPeers(path string) ([]peer.ID, error) {
+	enc, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var known []*knownPeer
+	if err := json.Unmarshal(enc, &known); err != nil {
+		return nil, errors.Wrapf(err, "could not decode known peers %s", path)
+	}
+	sort.Slice(known, func(i, j int) bool {
+		return known[i].Score > known[j].Score
+	})
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	now := roughtime.Now()
+	pids := make([]peer.ID, 0, len(known))
+	for _, k := range known {
+		lastSeen := time.Unix(k.LastSeen, 0)
+		if now.Sub(lastSeen) > knownPeerExpiry {
+			continue
+		}
+		pid, err := peer.IDB58Decode(k.ID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid peer id %s in known peers", k.ID)
+		}
+		addr, err := ma.NewMultiaddr(k.Address)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid address %s in known peers", k.Address)
+		}
+		if _, ok := p.status[pid]; ok {
+			continue
+		}
+		status := &peerStatus{
+			address:   addr,
+			direction: network.DirOutbound,
+			peerState: PeerDisconnected,
+			score:     k.Score,
+			lastSeen:  lastSeen,
+		}
+		if k.ChainState != nil {
+			status.chainState = k.ChainState
+			status.chainStateLastUpdated = lastSeen
+		}
+		if p.isBad(pid, status, now) {
+			continue
+		}
+		p.status[pid] = status
+		pids = append(pids, pid)
+	}
+	return pids, nil
+}
@@ -0,0 +1,93 @@
+package peers_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/libp2p/go-libp2p-core/network"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestKnownPeers_Persisted(t *testing.T) {
+	dir := path.Join(testutil.TempDir(), fmt.Sprintf("knownpeers-%d", os.Getpid()))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	knownPeersPath := path.Join(dir, "known_peers.json")
+
+	p := peers.NewStatus(100)
+	address, err := ma.NewMultiaddr("/ip4/213.202.254.180/tcp/13000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainState := &pb.Status{HeadSlot: 64, FinalizedEpoch: 1}
+	good := addPeer(t, p, peers.PeerConnected)
+	p.Add(good, address, network.DirOutbound)
+	p.SetChainState(good, chainState)
+	p.Record(good, peers.UsefulContribution)
+
+	// Peers which connected to us, were never connected or are bad are not persisted.
+	inbound := addPeer(t, p, peers.PeerConnected)
+	p.Add(inbound, address, network.DirInbound)
+	neverConnected := addPeer(t, p, peers.PeerDisconnected)
+	p.Add(neverConnected, address, network.DirOutbound)
+	bad := addPeer(t, p, peers.PeerConnected)
+	p.Add(bad, address, network.DirOutbound)
+	for i := 0; i < 5; i++ {
+		p.Record(bad, peers.BadResponse)
+	}
+
+	if err := p.SaveKnownPeers(knownPeersPath); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted := peers.NewStatus(100)
+	pids, err := restarted.LoadKnownPeers(knownPeersPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pids) != 1 || pids[0] != good {
+		t.Fatalf("Expected only the good peer to be loaded, received %v", pids)
+	}
+	if state, err := restarted.ConnectionState(good); err != nil || state != peers.PeerDisconnected {
+		t.Errorf("Expected loaded peer to be disconnected, received %v: %v", state, err)
+	}
+	if addr, err := restarted.Address(good); err != nil || !addr.Equal(address) {
+		t.Errorf("Unexpected address %v: %v", addr, err)
+	}
+	if score, err := restarted.Score(good); err != nil || score != 1 {
+		t.Errorf("Unexpected score %v: %v", score, err)
+	}
+	if state, err := restarted.ChainState(good); err != nil || !proto.Equal(state, chainState) {
+		t.Errorf("Unexpected chain state %v: %v", state, err)
+	}
+}
+
+func TestKnownPeers_MissingAndCorruptFile(t *testing.T) {
+	dir := path.Join(testutil.TempDir(), fmt.Sprintf("knownpeers-corrupt-%d", os.Getpid()))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	knownPeersPath := path.Join(dir, "known_peers.json")
+
+	pids, err := peers.NewStatus(100).LoadKnownPeers(knownPeersPath)
+	if err != nil || len(pids) != 0 {
+		t.Errorf("Expected no peers from a missing file, received %v: %v", pids, err)
+	}
+
+	if err := ioutil.WriteFile(knownPeersPath, []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := peers.NewStatus(100).LoadKnownPeers(knownPeersPath); err == nil {
+		t.Error("Expected error loading a corrupt known peers file")
+	}
+}
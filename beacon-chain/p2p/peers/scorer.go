@@ -0,0 +1,160 @@
+package peers
+
+import (
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/sirupsen/logrus"
+)
+
+// ScoreEvent is a contribution or misbehaviour of a peer which changes its score.
+type ScoreEvent int
+
+const (
+	// BadResponse is an RPC request or response which could not be decoded or broke the protocol.
+	BadResponse ScoreEvent = iota
+	// RPCTimeout is an RPC request the peer did not respond to in time.
+	RPCTimeout
+	// InvalidBlock is a block served by the peer which failed verification or processing.
+	InvalidBlock
+	// GossipValidationFailure is a gossip message propagated by the peer which failed validation.
+	GossipValidationFailure
+	// UsefulContribution is a valid gossip message or a valid response to a request of ours.
+	UsefulContribution
+)
+
+func (e ScoreEvent) String() string {
+	switch e {
+	case BadResponse:
+		return "bad response"
+	case RPCTimeout:
+		return "rpc timeout"
+	case InvalidBlock:
+		return "invalid block"
+	case GossipValidationFailure:
+		return "gossip validation failure"
+	case UsefulContribution:
+		return "useful contribution"
+	default:
+		return "unknown"
+	}
+}
+
+// scoreWeights are the changes to the score of a peer for each event. Honest peers gain score faster
+// through useful contributions than they lose it through the occasional gossip message which was
+// already seen or arrived too late to be valid.
+var scoreWeights = map[ScoreEvent]float64{
+	BadResponse:             -10,
+	RPCTimeout:              -5,
+	InvalidBlock:            -50,
+	GossipValidationFailure: -2,
+	UsefulContribution:      1,
+}
+
+// isMalicious reports whether an event counts towards banning a peer rather than just disconnecting
+// it. Useless peers lose score through bad responses, timeouts and gossip which fails validation, while
+// malicious peers also lose score through invalid blocks, which an honest peer never serves.
+func isMalicious(e ScoreEvent) bool {
+	return e == InvalidBlock
+}
+
+const (
+	// maxScore caps the score earned through useful contributions, so a peer cannot bank enough
+	// score to misbehave for long.
+	maxScore = 100
+	// DisconnectThreshold is the score at or below which a peer is considered bad and disconnected.
+	// It may connect again once its score has decayed above the threshold.
+	DisconnectThreshold = -50
+	// BanThreshold is the score from invalid blocks at or below which a peer is considered malicious
+	// and banned, along with its IP address, for the ban duration.
+	BanThreshold = -100
+	// BanDuration is how long a malicious peer is banned.
+	BanDuration = 24 * time.Hour
+	// scoreDecayFactor is the fraction of its score a peer keeps each time scores decay.
+	scoreDecayFactor = 0.5
+)
+
+// Record adjusts the score of the peer for the event, banning the peer if the event is malicious
+// and its score from invalid data falls to the ban threshold.
+func (p *Status) Record(pid peer.ID, event ScoreEvent) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	status := p.fetch(pid)
+	weight := scoreWeights[event]
+	status.score += weight
+	if status.score > maxScore {
+		status.score = maxScore
+	}
+	if event == BadResponse {
+		status.badResponses++
+	}
+	if !isMalicious(event) {
+		return
+	}
+	status.maliciousScore += weight
+	if status.maliciousScore <= BanThreshold && !p.bans.isBanned(pid, roughtime.Now()) {
+		log.WithFields(logrus.Fields{
+			"peer":    pid.Pretty(),
+			"address": status.address,
+			"reason":  event,
+		}).Info("Banning malicious peer")
+		if err := p.bans.ban(pid, status.address, roughtime.Now().Add(BanDuration)); err != nil {
+			log.WithError(err).Error("Could not persist ban list")
+		}
+	}
+}
+
+// Score returns the score of the given remote peer, negative scores reflect misbehaviour.
+// This will error if the peer does not exist.
+func (p *Status) Score(pid peer.ID) (float64, error) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if status, ok := p.status[pid]; ok {
+		return status.score, nil
+	}
+	return 0, ErrPeerUnknown
+}
+
+// IsBanned states if the peer or the IP address it was last seen at is banned.
+func (p *Status) IsBanned(pid peer.ID) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	now := roughtime.Now()
+	if p.bans.isBanned(pid, now) {
+		return true
+	}
+	if status, ok := p.status[pid]; ok && status.address != nil {
+		return p.bans.isBannedAddr(status.address, now)
+	}
+	return false
+}
+
+// IsBannedAddr states if the IP address of the multiaddress is banned.
+func (p *Status) IsBannedAddr(addr ma.Multiaddr) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.bans.isBannedAddr(addr, roughtime.Now())
+}
+
+// Banned returns the peers that are banned.
+func (p *Status) Banned() []peer.ID {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.bans.bannedPeers(roughtime.Now())
+}
+
+// LoadBanList loads the bans persisted at the given path and persists bans there from now on.
+// Expired bans are dropped.
+func (p *Status) LoadBanList(path string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.bans.load(path, roughtime.Now())
+}
@@ -0,0 +1,181 @@
+package peers_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestPeerScore(t *testing.T) {
+	p := peers.NewStatus(100)
+	pid := addPeer(t, p, peers.PeerConnected)
+
+	p.Record(pid, peers.UsefulContribution)
+	p.Record(pid, peers.UsefulContribution)
+	p.Record(pid, peers.GossipValidationFailure)
+	score, err := p.Score(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score != 0 {
+		t.Errorf("Unexpected score: expected 0, received %v", score)
+	}
+
+	// Useless peers are disconnected, but not banned.
+	for i := 0; i < 10; i++ {
+		p.Record(pid, peers.RPCTimeout)
+	}
+	if !p.IsBad(pid) {
+		t.Error("Expected peer with low score to be bad")
+	}
+	if p.IsBanned(pid) {
+		t.Error("Expected peer without invalid blocks not to be banned")
+	}
+	if len(p.Bad()) != 1 {
+		t.Errorf("Unexpected number of bad peers: expected 1, received %d", len(p.Bad()))
+	}
+
+	// Peers recover as their score decays.
+	p.Decay()
+	if p.IsBad(pid) {
+		t.Error("Expected peer to no longer be bad after decay")
+	}
+}
+
+func TestPeerScore_Capped(t *testing.T) {
+	p := peers.NewStatus(100)
+	pid := addPeer(t, p, peers.PeerConnected)
+	for i := 0; i < 500; i++ {
+		p.Record(pid, peers.UsefulContribution)
+	}
+	p.Record(pid, peers.InvalidBlock)
+	p.Record(pid, peers.InvalidBlock)
+
+	score, err := p.Score(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score != 0 {
+		t.Errorf("Unexpected score: expected 0, received %v", score)
+	}
+	// Useful contributions do not protect against bans.
+	if !p.IsBanned(pid) {
+		t.Error("Expected peer to be banned")
+	}
+}
+
+func TestPeerScore_UnknownPeer(t *testing.T) {
+	p := peers.NewStatus(2)
+	pid := addPeer(t, p, peers.PeerConnected)
+	unknown := addPeer(t, peers.NewStatus(2), peers.PeerConnected)
+
+	if _, err := p.Score(unknown); err != peers.ErrPeerUnknown {
+		t.Errorf("Unexpected error: expected %v, received %v", peers.ErrPeerUnknown, err)
+	}
+	p.Record(unknown, peers.InvalidBlock)
+	if _, err := p.Score(unknown); err != nil {
+		t.Errorf("Expected recording an event to add the peer, received %v", err)
+	}
+	if p.IsBanned(pid) {
+		t.Error("Expected peer not to be banned")
+	}
+}
+
+func TestPeerBan(t *testing.T) {
+	p := peers.NewStatus(100)
+	address, err := ma.NewMultiaddr("/ip4/213.202.254.180/tcp/13000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid := addPeer(t, p, peers.PeerConnected)
+	p.Add(pid, address, network.DirInbound)
+
+	p.Record(pid, peers.InvalidBlock)
+	if p.IsBanned(pid) {
+		t.Error("Expected peer not to be banned after a single invalid block")
+	}
+	p.Record(pid, peers.InvalidBlock)
+	if !p.IsBanned(pid) {
+		t.Error("Expected peer to be banned")
+	}
+	if !p.IsBad(pid) {
+		t.Error("Expected banned peer to be bad")
+	}
+	if banned := p.Banned(); len(banned) != 1 || banned[0] != pid {
+		t.Errorf("Unexpected banned peers: %v", banned)
+	}
+
+	// Other peers at the same IP address are banned as well.
+	other := addPeer(t, p, peers.PeerConnected)
+	sameIP, err := ma.NewMultiaddr("/ip4/213.202.254.180/tcp/13001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.IsBannedAddr(sameIP) {
+		t.Error("Expected address to be banned")
+	}
+	p.Add(other, sameIP, network.DirInbound)
+	if !p.IsBanned(other) {
+		t.Error("Expected peer at a banned address to be banned")
+	}
+	otherIP, err := ma.NewMultiaddr("/ip4/52.23.23.253/tcp/13000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.IsBannedAddr(otherIP) {
+		t.Error("Expected address not to be banned")
+	}
+
+	// Bans outlast decay.
+	p.Decay()
+	if !p.IsBanned(pid) {
+		t.Error("Expected peer to still be banned")
+	}
+}
+
+func TestBanList_Persisted(t *testing.T) {
+	dir := path.Join(testutil.TempDir(), fmt.Sprintf("banlist-%d", os.Getpid()))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	banListPath := path.Join(dir, "banned_peers.json")
+
+	p := peers.NewStatus(100)
+	if err := p.LoadBanList(banListPath); err != nil {
+		t.Fatal(err)
+	}
+	address, err := ma.NewMultiaddr("/ip4/213.202.254.180/tcp/13000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid := addPeer(t, p, peers.PeerConnected)
+	p.Add(pid, address, network.DirInbound)
+	p.Record(pid, peers.InvalidBlock)
+	p.Record(pid, peers.InvalidBlock)
+
+	restarted := peers.NewStatus(100)
+	if err := restarted.LoadBanList(banListPath); err != nil {
+		t.Fatal(err)
+	}
+	if !restarted.IsBanned(pid) {
+		t.Error("Expected peer to be banned after restart")
+	}
+	if !restarted.IsBannedAddr(address) {
+		t.Error("Expected address to be banned after restart")
+	}
+
+	if err := ioutil.WriteFile(banListPath, []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := peers.NewStatus(100).LoadBanList(banListPath); err == nil {
+		t.Error("Expected error loading a corrupt ban list")
+	}
+}
@@ -17,6 +17,11 @@
 //
 // Peer information is persistent for the run of the service.  This allows for collection of useful long-term statistics such as
 // number of bad responses obtained from the peer, giving the basis for decisions to not talk to known-bad peers.
+//
+// Each peer also has a score, which is lowered by misbehaviour, raised by useful contributions and decays towards zero over
+// time. Peers with a low score are considered bad and disconnected, while peers which repeatedly send invalid data are
+// considered malicious and banned along with their IP address. Bans can be persisted across restarts, as can the known-good
+// peers so that they can be redialled quickly on startup.
 package peers
 
 import (
@@ -58,6 +63,9 @@ type Status struct {
 	lock            sync.RWMutex
 	maxBadResponses int
 	status          map[peer.ID]*peerStatus
+	bans            *banList
+	// localFinalizedEpoch is the finalized epoch of our own chain, which peers are compared against.
+	localFinalizedEpoch uint64
 }
 
 // peerStatus is the status of an individual peer at the protocol level.
@@ -67,7 +75,14 @@ type peerStatus struct {
 	peerState             PeerConnectionState
 	chainState            *pb.Status
 	chainStateLastUpdated time.Time
+	metaData              *pb.MetaData
 	badResponses          int
+	score                 float64
+	maliciousScore        float64
+	lastSeen              time.Time
+	bytesReceived         uint64
+	bytesSent             uint64
+	messagesReceived      uint64
 }
 
 // NewStatus creates a new status entity.
@@ -75,6 +90,7 @@ func NewStatus(maxBadResponses int) *Status {
 	return &Status{
 		maxBadResponses: maxBadResponses,
 		status:          make(map[peer.ID]*peerStatus),
+		bans:            newBanList(),
 	}
 }
 
@@ -151,12 +167,56 @@ func (p *Status) ChainState(pid peer.ID) (*pb.Status, error) {
 	return nil, ErrPeerUnknown
 }
 
+// SetMetadata sets the metadata of the given remote peer.
+func (p *Status) SetMetadata(pid peer.ID, metaData *pb.MetaData) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	status := p.fetch(pid)
+	status.metaData = metaData
+}
+
+// Metadata gets the metadata of the given remote peer.
+// This can return nil if there is no known metadata for the peer.
+// This will error if the peer does not exist.
+func (p *Status) Metadata(pid peer.ID) (*pb.MetaData, error) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if status, ok := p.status[pid]; ok {
+		return status.metaData, nil
+	}
+	return nil, ErrPeerUnknown
+}
+
+// SubscribedToSubnet returns the connected peers whose metadata states they are subscribed to the
+// given attestation subnet.
+func (p *Status) SubscribedToSubnet(index uint64) []peer.ID {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	peers := make([]peer.ID, 0)
+	for pid, status := range p.status {
+		if status.peerState != PeerConnected || status.metaData == nil {
+			continue
+		}
+		attnets := status.metaData.Attnets
+		if index/8 < uint64(len(attnets)) && attnets[index/8]&(1<<(index%8)) != 0 {
+			peers = append(peers, pid)
+		}
+	}
+	return peers
+}
+
 // SetConnectionState sets the connection state of the given remote peer.
 func (p *Status) SetConnectionState(pid peer.ID, state PeerConnectionState) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
 	status := p.fetch(pid)
+	if state == PeerConnected || status.peerState == PeerConnected {
+		status.lastSeen = roughtime.Now()
+	}
 	status.peerState = state
 }
 
@@ -184,13 +244,10 @@ func (p *Status) ChainStateLastUpdated(pid peer.ID) (time.Time, error) {
 	return roughtime.Now(), ErrPeerUnknown
 }
 
-// IncrementBadResponses increments the number of bad responses we have received from the given remote peer.
+// IncrementBadResponses increments the number of bad responses we have received from the given remote peer,
+// lowering its score.
 func (p *Status) IncrementBadResponses(pid peer.ID) {
-	p.lock.Lock()
-	defer p.lock.Unlock()
-
-	status := p.fetch(pid)
-	status.badResponses++
+	p.Record(pid, BadResponse)
 }
 
 // BadResponses obtains the number of bad responses we have received from the given remote peer.
@@ -205,16 +262,24 @@ func (p *Status) BadResponses(pid peer.ID) (int, error) {
 	return -1, ErrPeerUnknown
 }
 
-// IsBad states if the peer is to be considered bad.
+// IsBad states if the peer is to be considered bad, which is the case if it gave too many bad responses, its score is
+// at or below the disconnect threshold, or it is banned.
 // If the peer is unknown this will return `false`, which makes using this function easier than returning an error.
 func (p *Status) IsBad(pid peer.ID) bool {
 	p.lock.RLock()
 	defer p.lock.RUnlock()
 
 	if status, ok := p.status[pid]; ok {
-		return status.badResponses >= p.maxBadResponses
+		return p.isBad(pid, status, roughtime.Now())
 	}
-	return false
+	return p.bans.isBanned(pid, roughtime.Now())
+}
+
+func (p *Status) isBad(pid peer.ID, status *peerStatus, now time.Time) bool {
+	if status.badResponses >= p.maxBadResponses || status.score <= DisconnectThreshold {
+		return true
+	}
+	return p.bans.isBanned(pid, now) || (status.address != nil && p.bans.isBannedAddr(status.address, now))
 }
 
 // Connecting returns the peers that are connecting.
@@ -300,8 +365,9 @@ func (p *Status) Bad() []peer.ID {
 	p.lock.RLock()
 	defer p.lock.RUnlock()
 	peers := make([]peer.ID, 0)
+	now := roughtime.Now()
 	for pid, status := range p.status {
-		if status.badResponses >= p.maxBadResponses {
+		if p.isBad(pid, status, now) {
 			peers = append(peers, pid)
 		}
 	}
@@ -319,7 +385,8 @@ func (p *Status) All() []peer.ID {
 	return pids
 }
 
-// Decay reduces the bad responses of all peers, giving reformed peers a chance to join the network.
+// Decay reduces the bad responses and the magnitude of the scores of all peers, giving reformed peers a chance to join the
+// network. Bans are not lifted before they expire.
 // This can be run periodically, although note that each time it runs it does give all bad peers another chance as well to clog up
 // the network with bad responses, so should not be run too frequently; once an hour would be reasonable.
 func (p *Status) Decay() {
@@ -329,6 +396,8 @@ func (p *Status) Decay() {
 		if status.badResponses > 0 {
 			status.badResponses--
 		}
+		status.score *= scoreDecayFactor
+		status.maliciousScore *= scoreDecayFactor
 	}
 }
 
@@ -394,6 +463,20 @@ func (p *Status) fetch(pid peer.ID) *peerStatus {
 	return p.status[pid]
 }
 
+// SetLocalFinalizedEpoch sets the finalized epoch of our own chain.
+func (p *Status) SetLocalFinalizedEpoch(epoch uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.localFinalizedEpoch = epoch
+}
+
+// LocalFinalizedEpoch returns the finalized epoch of our own chain, as last set.
+func (p *Status) LocalFinalizedEpoch() uint64 {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.localFinalizedEpoch
+}
+
 // CurrentEpoch returns the highest reported epoch amongst peers.
 func (p *Status) CurrentEpoch() uint64 {
 	p.lock.RLock()
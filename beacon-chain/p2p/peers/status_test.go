@@ -183,6 +183,46 @@ func TestPeerChainState(t *testing.T) {
 	}
 }
 
+func TestPeerMetadata(t *testing.T) {
+	p := peers.NewStatus(2)
+	subscribed := addPeer(t, p, peers.PeerConnected)
+	unsubscribed := addPeer(t, p, peers.PeerConnected)
+	disconnected := addPeer(t, p, peers.PeerDisconnected)
+	unknown := addPeer(t, p, peers.PeerConnected)
+
+	// Subscribed to subnets 3 and 42.
+	p.SetMetadata(subscribed, &pb.MetaData{SeqNumber: 2, Attnets: []byte{0x08, 0, 0, 0, 0, 0x04, 0, 0}})
+	p.SetMetadata(unsubscribed, &pb.MetaData{SeqNumber: 1, Attnets: make([]byte, 8)})
+	p.SetMetadata(disconnected, &pb.MetaData{SeqNumber: 1, Attnets: []byte{0x08, 0, 0, 0, 0, 0x04, 0, 0}})
+
+	md, err := p.Metadata(subscribed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md.SeqNumber != 2 {
+		t.Errorf("Unexpected sequence number: expected 2, received %d", md.SeqNumber)
+	}
+	md, err = p.Metadata(unknown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md != nil {
+		t.Errorf("Expected no metadata for peer, received %v", md)
+	}
+
+	for _, subnet := range []uint64{3, 42} {
+		pids := p.SubscribedToSubnet(subnet)
+		if len(pids) != 1 || pids[0] != subscribed {
+			t.Errorf("Unexpected peers subscribed to subnet %d: %v", subnet, pids)
+		}
+	}
+	for _, subnet := range []uint64{0, 4, 63, 64} {
+		if pids := p.SubscribedToSubnet(subnet); len(pids) != 0 {
+			t.Errorf("Unexpected peers subscribed to subnet %d: %v", subnet, pids)
+		}
+	}
+}
+
 func TestPeerBadResponses(t *testing.T) {
 	maxBadResponses := 2
 	p := peers.NewStatus(maxBadResponses)
@@ -0,0 +1,50 @@
+package peers
+
+import (
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// Traffic is the data exchanged with a peer over the lifetime of the service.
+type Traffic struct {
+	// BytesReceived is the number of bytes received from the peer, across all protocols.
+	BytesReceived uint64
+	// BytesSent is the number of bytes sent to the peer, across all protocols.
+	BytesSent uint64
+	// MessagesReceived is the number of gossip messages received from the peer.
+	MessagesReceived uint64
+}
+
+// SetBandwidth sets the total number of bytes received from and sent to the given remote peer, as
+// measured by the bandwidth reporter of the host.
+func (p *Status) SetBandwidth(pid peer.ID, received uint64, sent uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	status := p.fetch(pid)
+	status.bytesReceived = received
+	status.bytesSent = sent
+}
+
+// IncrementMessagesReceived increments the number of gossip messages received from the given remote peer.
+func (p *Status) IncrementMessagesReceived(pid peer.ID) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.fetch(pid).messagesReceived++
+}
+
+// Traffic returns the data exchanged with the given remote peer.
+// This will error if the peer does not exist.
+func (p *Status) Traffic(pid peer.ID) (*Traffic, error) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if status, ok := p.status[pid]; ok {
+		return &Traffic{
+			BytesReceived:    status.bytesReceived,
+			BytesSent:        status.bytesSent,
+			MessagesReceived: status.messagesReceived,
+		}, nil
+	}
+	return nil, ErrPeerUnknown
+}
@@ -0,0 +1,30 @@
+package peers_test
+
+import (
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+)
+
+func TestTraffic(t *testing.T) {
+	p := peers.NewStatus(3)
+	id := addPeer(t, p, peers.PeerConnected)
+
+	p.SetBandwidth(id, 2048, 1024)
+	p.IncrementMessagesReceived(id)
+	p.IncrementMessagesReceived(id)
+
+	traffic, err := p.Traffic(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := peers.Traffic{BytesReceived: 2048, BytesSent: 1024, MessagesReceived: 2}
+	if *traffic != want {
+		t.Errorf("Wanted traffic %+v, received %+v", want, *traffic)
+	}
+
+	unknown := addPeer(t, peers.NewStatus(3), peers.PeerConnected)
+	if _, err := p.Traffic(unknown); err != peers.ErrPeerUnknown {
+		t.Errorf("Wanted error %v for unknown peer, received %v", peers.ErrPeerUnknown, err)
+	}
+}
@@ -1,27 +1,33 @@
 package p2p
 
 import (
-	"reflect"
-
 	p2ppb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 )
 
+const (
+	// RPCStatusTopic defines the topic for the status rpc method.
+	RPCStatusTopic = "/eth2/beacon_chain/req/status/1"
+	// RPCGoodByeTopic defines the topic for the goodbye rpc method.
+	RPCGoodByeTopic = "/eth2/beacon_chain/req/goodbye/1"
+	// RPCBlocksByRangeTopic defines the topic for the blocks by range rpc method.
+	RPCBlocksByRangeTopic = "/eth2/beacon_chain/req/beacon_blocks_by_range/1"
+	// RPCBlocksByRootTopic defines the topic for the blocks by root rpc method.
+	RPCBlocksByRootTopic = "/eth2/beacon_chain/req/beacon_blocks_by_root/1"
+	// RPCPingTopic defines the topic for the ping rpc method.
+	RPCPingTopic = "/eth2/beacon_chain/req/ping/1"
+	// RPCMetaDataTopic defines the topic for the metadata rpc method.
+	RPCMetaDataTopic = "/eth2/beacon_chain/req/metadata/1"
+)
+
 // RPCTopicMappings represent the protocol ID to protobuf message type map for easy
 // lookup. These mappings should be used for outbound sending only. Peers may respond
-// with a different message type as defined by the p2p protocol.
+// with a different message type as defined by the p2p protocol. Requests on topics
+// mapped to nil have no payload.
 var RPCTopicMappings = map[string]interface{}{
-	"/eth2/beacon_chain/req/status/1":                 &p2ppb.Status{},
-	"/eth2/beacon_chain/req/goodbye/1":                new(uint64),
-	"/eth2/beacon_chain/req/beacon_blocks_by_range/1": &p2ppb.BeaconBlocksByRangeRequest{},
-	"/eth2/beacon_chain/req/beacon_blocks_by_root/1":  [][32]byte{},
-}
-
-// RPCTypeMapping is the inverse of RPCTopicMappings so that an arbitrary protobuf message
-// can be mapped to a protocol ID string.
-var RPCTypeMapping = make(map[reflect.Type]string)
-
-func init() {
-	for k, v := range RPCTopicMappings {
-		RPCTypeMapping[reflect.TypeOf(v)] = k
-	}
+	RPCStatusTopic:        &p2ppb.Status{},
+	RPCGoodByeTopic:       new(uint64),
+	RPCBlocksByRangeTopic: &p2ppb.BeaconBlocksByRangeRequest{},
+	RPCBlocksByRootTopic:  [][32]byte{},
+	RPCPingTopic:          new(uint64),
+	RPCMetaDataTopic:      nil,
 }
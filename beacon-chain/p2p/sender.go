@@ -2,22 +2,22 @@ package p2p
 
 import (
 	"context"
-	"reflect"
 	"time"
 
 	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/tracer"
 	"github.com/prysmaticlabs/prysm/shared/traceutil"
 	"go.opencensus.io/trace"
 )
 
-// Send a message to a specific peer. The returned stream may be used for reading, but has been
-// closed for writing.
-func (s *Service) Send(ctx context.Context, message interface{}, pid peer.ID) (network.Stream, error) {
+// Send a message to a specific peer on the given base topic, a nil message is sent as a request
+// without payload. The returned stream may be used for reading, but has been closed for writing.
+func (s *Service) Send(ctx context.Context, message interface{}, baseTopic string, pid peer.ID) (network.Stream, error) {
 	ctx, span := trace.StartSpan(ctx, "p2p.Send")
 	defer span.End()
-	topic := RPCTypeMapping[reflect.TypeOf(message)] + s.Encoding().ProtocolSuffix()
+	topic := baseTopic + s.Encoding().ProtocolSuffix()
 	span.AddAttributes(trace.StringAttribute("topic", topic))
 
 	// TTFB_TIME (5s) + RESP_TIMEOUT (10s).
@@ -38,9 +38,12 @@ func (s *Service) Send(ctx context.Context, message interface{}, pid peer.ID) (n
 		traceutil.AnnotateError(span, err)
 		return nil, err
 	}
-	if _, err := s.Encoding().EncodeWithLength(stream, message); err != nil {
-		traceutil.AnnotateError(span, err)
-		return nil, err
+	size := 0
+	if message != nil {
+		if size, err = s.Encoding().EncodeWithLength(stream, message); err != nil {
+			traceutil.AnnotateError(span, err)
+			return nil, err
+		}
 	}
 
 	// Close stream for writing.
@@ -49,5 +52,15 @@ func (s *Service) Send(ctx context.Context, message interface{}, pid peer.ID) (n
 		return nil, err
 	}
 
+	if s.tracer != nil {
+		s.traceRPC(tracer.RPCRequest, tracer.Outbound, pid, topic, size)
+		// The response is traced once its first bytes arrive.
+		return &tracedStream{
+			Stream: stream,
+			onRead: func() {
+				s.traceRPC(tracer.RPCResponse, tracer.Inbound, pid, topic, 0)
+			},
+		}, nil
+	}
 	return stream, nil
 }
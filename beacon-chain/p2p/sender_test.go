@@ -2,7 +2,6 @@ package p2p
 
 import (
 	"context"
-	"reflect"
 	"sync"
 	"testing"
 	"time"
@@ -29,9 +28,6 @@ func TestService_Send(t *testing.T) {
 		Bar: 55,
 	}
 
-	// Register testing topic.
-	RPCTypeMapping[reflect.TypeOf(msg)] = "/testing/1"
-
 	// Register external listener which will repeat the message back.
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -51,7 +47,7 @@ func TestService_Send(t *testing.T) {
 		})
 	}()
 
-	stream, err := svc.Send(context.Background(), msg, p2.Host.ID())
+	stream, err := svc.Send(context.Background(), msg, "/testing/1", p2.Host.ID())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -3,8 +3,10 @@ package p2p
 import (
 	"context"
 	"crypto/ecdsa"
+	"path"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dgraph-io/ristretto"
@@ -13,6 +15,7 @@ import (
 	dsync "github.com/ipfs/go-datastore/sync"
 	"github.com/libp2p/go-libp2p"
 	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/metrics"
 	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/protocol"
@@ -22,9 +25,13 @@ import (
 	rhost "github.com/libp2p/go-libp2p/p2p/host/routed"
 	ma "github.com/multiformats/go-multiaddr"
 	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/encoder"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/tracer"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared"
+	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/runutil"
 )
 
@@ -37,6 +44,13 @@ const prysmProtocolPrefix = "/prysm/0.0.0"
 // maxBadResponses is the maximum number of bad responses from a peer before we stop talking to it.
 const maxBadResponses = 3
 
+// attnetsLength is the length in bytes of the bitfield of the attestation subnets a node is
+// subscribed to.
+const attnetsLength = 8
+
+// banListFileName is the name of the file in the data directory the banned peers are persisted to.
+const banListFileName = "banned_peers.json"
+
 // Service for managing peer to peer (p2p) networking.
 type Service struct {
 	ctx           context.Context
@@ -51,6 +65,14 @@ type Service struct {
 	privKey       *ecdsa.PrivateKey
 	dht           *kaddht.IpfsDHT
 	peers         *peers.Status
+	metaData      *pb.MetaData
+	metaDataLock  sync.RWMutex
+	// genesisValidatorsRoot identifies the chain, and is set once it is initialized.
+	genesisValidatorsRoot []byte
+	forkLock              sync.RWMutex
+	knownPeers            []peer.ID
+	bandwidth             *metrics.BandwidthCounter
+	tracer                *tracer.Tracer
 }
 
 // NewService initializes a new p2p service compatible with shared.Service interface. No
@@ -69,6 +91,9 @@ func NewService(cfg *Config) (*Service, error) {
 		cancel:        cancel,
 		cfg:           cfg,
 		exclusionList: cache,
+		metaData: &pb.MetaData{
+			Attnets: make([]byte, attnetsLength),
+		},
 	}
 
 	dv5Nodes, kadDHTNodes := parseBootStrapAddrs(s.cfg.BootstrapNodeAddr)
@@ -83,7 +108,16 @@ func NewService(cfg *Config) (*Service, error) {
 		return nil, err
 	}
 
-	opts := buildOptions(s.cfg, ipAddr, s.privKey)
+	s.peers = peers.NewStatus(maxBadResponses)
+	if cfg.DataDir != "" {
+		if err := s.peers.LoadBanList(path.Join(cfg.DataDir, banListFileName)); err != nil {
+			log.WithError(err).Error("Could not load peer ban list")
+		}
+	}
+	s.loadKnownPeers()
+
+	s.bandwidth = metrics.NewBandwidthCounter()
+	opts := buildOptions(s.cfg, ipAddr, s.privKey, &peerValuer{peers: s.peers}, s.bandwidth)
 	h, err := libp2p.New(s.ctx, opts...)
 	if err != nil {
 		log.WithError(err).Error("Failed to create p2p host")
@@ -118,6 +152,14 @@ func NewService(cfg *Config) (*Service, error) {
 		pubsub.WithStrictSignatureVerification(false),
 		pubsub.WithMessageIdFn(msgIDFunction),
 	}
+	if cfg.TraceDir != "" {
+		s.tracer, err = tracer.New(cfg.TraceDir, traceFileMaxSize, traceMaxFiles)
+		if err != nil {
+			log.WithError(err).Error("Failed to start p2p tracer")
+			return nil, err
+		}
+		psOpts = append(psOpts, pubsub.WithEventTracer(s.tracer))
+	}
 	gs, err := pubsub.NewGossipSub(s.ctx, s.host, psOpts...)
 	if err != nil {
 		log.WithError(err).Error("Failed to start pubsub")
@@ -125,8 +167,6 @@ func NewService(cfg *Config) (*Service, error) {
 	}
 	s.pubsub = gs
 
-	s.peers = peers.NewStatus(maxBadResponses)
-
 	return s, nil
 }
 
@@ -195,6 +235,14 @@ func (s *Service) Start() {
 		}
 	}
 
+	// Subscribe before starting so that the state initialized event sent on start up of the
+	// blockchain service is not missed.
+	if s.cfg.StateNotifier != nil {
+		stateChannel := make(chan *feed.Event, 1)
+		stateSub := s.cfg.StateNotifier.StateFeed().Subscribe(stateChannel)
+		go s.awaitStateInitialized(stateChannel, stateSub)
+	}
+
 	s.started = true
 
 	if len(s.cfg.StaticPeers) > 0 {
@@ -202,15 +250,28 @@ func (s *Service) Start() {
 		if err != nil {
 			log.Errorf("Could not connect to static peer: %v", err)
 		}
+		// Static peers are trusted, so they are never pruned by the connection manager.
+		for _, addr := range addrs {
+			info, err := peer.AddrInfoFromP2pAddr(addr)
+			if err != nil {
+				log.WithError(err).Error("Could not get peer info of static peer")
+				continue
+			}
+			s.host.ConnManager().Protect(info.ID, "static")
+		}
 		s.connectWithAllPeers(addrs)
 	}
+	go s.reconnectKnownPeers(s.knownPeers)
 
 	// Periodic functions.
 	runutil.RunEvery(s.ctx, 5*time.Second, func() {
 		ensurePeerConnections(s.ctx, s.host, peersToWatch...)
 	})
 	runutil.RunEvery(s.ctx, time.Hour, s.Peers().Decay)
+	runutil.RunEvery(s.ctx, 30*time.Second, s.disconnectBadPeers)
 	runutil.RunEvery(s.ctx, 10*time.Second, s.updateMetrics)
+	runutil.RunEvery(s.ctx, knownPeersSaveInterval, s.saveKnownPeers)
+	runutil.RunEvery(s.ctx, time.Duration(params.BeaconConfig().SecondsPerSlot)*time.Second, s.RefreshENR)
 
 	multiAddrs := s.host.Network().ListenAddresses()
 	logIP4Addr(s.host.ID(), multiAddrs...)
@@ -235,6 +296,12 @@ func (s *Service) Stop() error {
 	if s.dv5Listener != nil {
 		s.dv5Listener.Close()
 	}
+	s.saveKnownPeers()
+	if s.tracer != nil {
+		if err := s.tracer.Close(); err != nil {
+			log.WithError(err).Error("Could not close p2p tracer")
+		}
+	}
 	return nil
 }
 
@@ -273,6 +340,9 @@ func (s *Service) PubSub() *pubsub.PubSub {
 // SetStreamHandler sets the protocol handler on the p2p host multiplexer.
 // This method is a pass through to libp2pcore.Host.SetStreamHandler.
 func (s *Service) SetStreamHandler(topic string, handler network.StreamHandler) {
+	if s.tracer != nil {
+		handler = s.tracedHandler(topic, handler)
+	}
 	s.host.SetStreamHandler(protocol.ID(topic), handler)
 }
 
@@ -291,6 +361,21 @@ func (s *Service) Peers() *peers.Status {
 	return s.peers
 }
 
+// Metadata returns the metadata of the local peer.
+func (s *Service) Metadata() *pb.MetaData {
+	s.metaDataLock.RLock()
+	defer s.metaDataLock.RUnlock()
+	return s.metaData
+}
+
+// MetadataSeq returns the sequence number of the metadata of the local peer, which is
+// increased whenever the metadata changes.
+func (s *Service) MetadataSeq() uint64 {
+	s.metaDataLock.RLock()
+	defer s.metaDataLock.RUnlock()
+	return s.metaData.SeqNumber
+}
+
 // listen for new nodes watches for new nodes in the network and adds them to the peerstore.
 func (s *Service) listenForNewNodes() {
 	bootNode, err := enode.Parse(enode.ValidSchemes, s.cfg.Discv5BootStrapAddr[0])
@@ -298,12 +383,30 @@ func (s *Service) listenForNewNodes() {
 		log.Fatal(err)
 	}
 	runutil.RunEvery(s.ctx, pollingPeriod, func() {
-		nodes := s.dv5Listener.Lookup(bootNode.ID())
+		nodes := s.filterByFork(s.dv5Listener.Lookup(bootNode.ID()))
 		multiAddresses := convertToMultiAddr(nodes)
 		s.connectWithAllPeers(multiAddresses)
 	})
 }
 
+// disconnectBadPeers disconnects the connected peers whose score fell to the disconnect threshold,
+// or which were banned.
+func (s *Service) disconnectBadPeers() {
+	for _, pid := range s.peers.Connected() {
+		if !s.peers.IsBad(pid) {
+			continue
+		}
+		score, err := s.peers.Score(pid)
+		if err != nil {
+			continue
+		}
+		log.WithField("peer", pid.Pretty()).WithField("score", score).Debug("Disconnecting bad peer")
+		if err := s.Disconnect(pid); err != nil {
+			log.WithError(err).Error("Unable to disconnect from peer")
+		}
+	}
+}
+
 func (s *Service) connectWithAllPeers(multiAddrs []ma.Multiaddr) {
 	addrInfos, err := peer.AddrInfosFromP2pAddrs(multiAddrs...)
 	if err != nil {
@@ -320,6 +423,13 @@ func (s *Service) connectWithAllPeers(multiAddrs []ma.Multiaddr) {
 		if s.Peers().IsBad(info.ID) {
 			continue
 		}
+		banned := false
+		for _, addr := range info.Addrs {
+			banned = banned || s.Peers().IsBannedAddr(addr)
+		}
+		if banned {
+			continue
+		}
 		if err := s.host.Connect(s.ctx, info); err != nil {
 			log.Errorf("Could not connect with peer %s: %v", info.String(), err)
 			s.exclusionList.Set(info.ID.String(), true, 1)
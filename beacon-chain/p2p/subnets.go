@@ -0,0 +1,81 @@
+package p2p
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+)
+
+// attSubnetEnrKey is the key of the ENR entry holding the bitfield of the long lived attestation
+// subnets a node is subscribed to.
+const attSubnetEnrKey = "attnets"
+
+// RefreshENR updates the attestation subnets advertised in the ENR and the metadata of the local
+// peer to the persistent subnets of the validators connected to this node. The sequence number of
+// the metadata is increased whenever the subnets change.
+func (s *Service) RefreshENR() {
+	attnets := make([]byte, attnetsLength)
+	for _, idx := range cache.SubnetIDs.GetAllSubnets() {
+		setSubnetBit(attnets, idx)
+	}
+
+	s.metaDataLock.Lock()
+	defer s.metaDataLock.Unlock()
+	if bytes.Equal(s.metaData.Attnets, attnets) {
+		return
+	}
+	s.metaData = &pb.MetaData{
+		SeqNumber: s.metaData.SeqNumber + 1,
+		Attnets:   attnets,
+	}
+	if s.dv5Listener != nil {
+		s.dv5Listener.LocalNode().Set(enr.WithEntry(attSubnetEnrKey, attnets))
+	}
+}
+
+// FindPeersWithSubnet searches the discovery network for nodes which advertise the attestation
+// subnet in their ENR and connects to them. It returns whether any such nodes were found.
+func (s *Service) FindPeersWithSubnet(index uint64) (bool, error) {
+	if s.dv5Listener == nil {
+		return false, nil
+	}
+	var nodes []*enode.Node
+	for _, node := range s.filterByFork(s.dv5Listener.LookupRandom()) {
+		if node.IP() == nil {
+			continue
+		}
+		attnets, err := retrieveAttnets(node.Record())
+		if err != nil || !subnetBitSet(attnets, index) {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 0 {
+		return false, nil
+	}
+	log.WithField("subnet", index).WithField("nodes", len(nodes)).Debug("Found nodes subscribed to subnet")
+	s.connectWithAllPeers(convertToMultiAddr(nodes))
+	return true, nil
+}
+
+// retrieveAttnets reads the bitfield of the attestation subnets from the ENR of a node.
+func retrieveAttnets(record *enr.Record) ([]byte, error) {
+	var attnets []byte
+	if err := record.Load(enr.WithEntry(attSubnetEnrKey, &attnets)); err != nil {
+		return nil, err
+	}
+	return attnets, nil
+}
+
+func setSubnetBit(attnets []byte, index uint64) {
+	if index/8 < uint64(len(attnets)) {
+		attnets[index/8] |= 1 << (index % 8)
+	}
+}
+
+func subnetBitSet(attnets []byte, index uint64) bool {
+	return index/8 < uint64(len(attnets)) && attnets[index/8]&(1<<(index%8)) != 0
+}
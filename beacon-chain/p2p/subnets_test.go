@@ -0,0 +1,42 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+)
+
+func TestRefreshENR_AdvertisesPersistentSubnets(t *testing.T) {
+	port := 4000
+	ipAddr, pkey := createAddrAndPrivKey(t)
+	listener := createListener(ipAddr, pkey, &Config{UDPPort: uint(port)})
+	defer listener.Close()
+
+	s := &Service{
+		dv5Listener: listener,
+		metaData:    &pb.MetaData{Attnets: make([]byte, attnetsLength)},
+	}
+	cache.SubnetIDs.AddPersistentCommittee([]byte{'A'}, []uint64{2, 10}, time.Minute)
+	defer cache.SubnetIDs.AddPersistentCommittee([]byte{'A'}, nil, 0)
+
+	s.RefreshENR()
+	s.RefreshENR()
+	if s.MetadataSeq() != 1 {
+		t.Errorf("Expected metadata to change once, received sequence number %d", s.MetadataSeq())
+	}
+	attnets, err := retrieveAttnets(listener.Self().Record())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := uint64(0); i < attnetsLength*8; i++ {
+		wanted := i == 2 || i == 10
+		if subnetBitSet(attnets, i) != wanted {
+			t.Errorf("Unexpected ENR bit for subnet %d: wanted %v", i, wanted)
+		}
+		if subnetBitSet(s.Metadata().Attnets, i) != wanted {
+			t.Errorf("Unexpected metadata bit for subnet %d: wanted %v", i, wanted)
+		}
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"reflect"
 	"testing"
 	"time"
 
@@ -23,14 +22,6 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// TopicMappings are the protocol ids for the different types of requests.
-var TopicMappings = map[reflect.Type]string{
-	reflect.TypeOf(&pb.Status{}):                     "/eth2/beacon_chain/req/status/1",
-	reflect.TypeOf(new(uint64)):                      "/eth2/beacon_chain/req/goodbye/1",
-	reflect.TypeOf(&pb.BeaconBlocksByRangeRequest{}): "/eth2/beacon_chain/req/beacon_blocks_by_range/1",
-	reflect.TypeOf([][32]byte{}):                     "/eth2/beacon_chain/req/beacon_blocks_by_root/1",
-}
-
 // TestP2P represents a p2p implementation that can be used for testing.
 type TestP2P struct {
 	t               *testing.T
@@ -39,6 +30,7 @@ type TestP2P struct {
 	BroadcastCalled bool
 	DelaySend       bool
 	peers           *peers.Status
+	LocalMetadata   *pb.MetaData
 }
 
 // NewTestP2P initializes a new p2p test service.
@@ -58,6 +50,9 @@ func NewTestP2P(t *testing.T) *TestP2P {
 		Host:   h,
 		pubsub: ps,
 		peers:  peers.NewStatus(5 /* maxBadResponses */),
+		LocalMetadata: &pb.MetaData{
+			Attnets: make([]byte, 8),
+		},
 	}
 }
 
@@ -192,18 +187,19 @@ func (p *TestP2P) AddDisconnectionHandler(f func(ctx context.Context, id peer.ID
 }
 
 // Send a message to a specific peer.
-func (p *TestP2P) Send(ctx context.Context, msg interface{}, pid peer.ID) (network.Stream, error) {
-	protocol := TopicMappings[reflect.TypeOf(msg)]
-	if protocol == "" {
-		return nil, fmt.Errorf("protocol doesnt exist for proto message: %v", msg)
+func (p *TestP2P) Send(ctx context.Context, msg interface{}, topic string, pid peer.ID) (network.Stream, error) {
+	if topic == "" {
+		return nil, fmt.Errorf("no protocol given for message: %v", msg)
 	}
-	stream, err := p.Host.NewStream(ctx, pid, core.ProtocolID(protocol+p.Encoding().ProtocolSuffix()))
+	stream, err := p.Host.NewStream(ctx, pid, core.ProtocolID(topic+p.Encoding().ProtocolSuffix()))
 	if err != nil {
 		return nil, err
 	}
 
-	if _, err := p.Encoding().EncodeWithLength(stream, msg); err != nil {
-		return nil, err
+	if msg != nil {
+		if _, err := p.Encoding().EncodeWithLength(stream, msg); err != nil {
+			return nil, err
+		}
 	}
 
 	// Close stream for writing.
@@ -227,3 +223,21 @@ func (p *TestP2P) Started() bool {
 func (p *TestP2P) Peers() *peers.Status {
 	return p.peers
 }
+
+// Metadata returns the metadata of the test peer.
+func (p *TestP2P) Metadata() *pb.MetaData {
+	return p.LocalMetadata
+}
+
+// MetadataSeq returns the sequence number of the metadata of the test peer.
+func (p *TestP2P) MetadataSeq() uint64 {
+	return p.LocalMetadata.SeqNumber
+}
+
+// RefreshENR mocks the p2p func.
+func (p *TestP2P) RefreshENR() {}
+
+// FindPeersWithSubnet mocks the p2p func.
+func (p *TestP2P) FindPeersWithSubnet(index uint64) (bool, error) {
+	return false, nil
+}
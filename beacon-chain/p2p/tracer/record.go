@@ -0,0 +1,130 @@
+package tracer
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Types of the traced events.
+const (
+	// PublishMessage is a gossip message published by us.
+	PublishMessage = "publish"
+	// DeliverMessage is a gossip message received from a peer which passed validation.
+	DeliverMessage = "deliver"
+	// RejectMessage is a gossip message received from a peer which was rejected.
+	RejectMessage = "reject"
+	// DuplicateMessage is a gossip message received from a peer which was already seen.
+	DuplicateMessage = "duplicate"
+	// RPCRequest is a req/resp request, sent by us or received from a peer.
+	RPCRequest = "rpc_request"
+	// RPCResponse is a req/resp response, sent by us or received from a peer.
+	RPCResponse = "rpc_response"
+)
+
+// Directions of the traced req/resp events.
+const (
+	// Inbound is a request or response received from a peer.
+	Inbound = "inbound"
+	// Outbound is a request or response sent to a peer.
+	Outbound = "outbound"
+)
+
+// Record is a traced gossip or req/resp event.
+type Record struct {
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	// PeerID is the peer a gossip message was received from, or the peer of a req/resp event.
+	PeerID string `json:"peer,omitempty"`
+	// Topic is the gossip topic of a message, or the protocol of a req/resp event.
+	Topic     string `json:"topic,omitempty"`
+	MessageID string `json:"message_id,omitempty"`
+	Direction string `json:"direction,omitempty"`
+	// Size is the number of bytes of a req/resp request or response.
+	Size   int    `json:"size,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Read returns the records of the trace in the given directory matching the filter, oldest first.
+// A nil filter matches all records. Records cut off by a crash at the end of a file are skipped.
+func Read(dir string, match func(*Record) bool) ([]*Record, error) {
+	files, err := traceFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	var records []*Record
+	for _, name := range files {
+		fileRecords, err := readFile(path.Join(dir, name), match)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read trace file %s", name)
+		}
+		records = append(records, fileRecords...)
+	}
+	return records, nil
+}
+
+// traceFiles returns the names of the files of the trace in the given directory, oldest first.
+func traceFiles(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var rotated []string
+	current := false
+	for _, info := range infos {
+		switch name := info.Name(); {
+		case name == currentFileName:
+			current = true
+		case strings.HasPrefix(name, traceFilePrefix+".") && strings.HasSuffix(name, traceFileExtension):
+			rotated = append(rotated, name)
+		}
+	}
+	// Rotated files are suffixed with the time of their rotation, which sorts chronologically.
+	sort.Strings(rotated)
+	if current {
+		rotated = append(rotated, currentFileName)
+	}
+	return rotated, nil
+}
+
+func readFile(filePath string, match func(*Record) bool) ([]*Record, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var records []*Record
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		r := &Record{}
+		if err := json.Unmarshal(scanner.Bytes(), r); err != nil {
+			// A partially written record at the end of the file.
+			continue
+		}
+		if match == nil || match(r) {
+			records = append(records, r)
+		}
+	}
+	if err := scanner.Err(); err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	return records, nil
+}
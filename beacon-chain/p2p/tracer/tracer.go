@@ -0,0 +1,294 @@
+// Package tracer records the gossip and req/resp traffic of the node to disk, so that the
+// propagation of messages can be reconstructed after the fact. Events are written as
+// newline-delimited JSON to gzip compressed files, which are rotated once they grow beyond a
+// maximum size. Only a limited number of rotated files are kept.
+package tracer
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/pkg/errors"
+)
+
+const (
+	traceFilePrefix    = "trace"
+	traceFileExtension = ".ndjson.gz"
+	currentFileName    = traceFilePrefix + traceFileExtension
+	rotatedFileTimeFmt = "20060102T150405.000000000"
+)
+
+const (
+	// recordBufferSize is the number of records waiting to be written above which further records
+	// are dropped, so that a slow disk never blocks the network.
+	recordBufferSize = 4096
+	// recentMessagesSize is the number of recently received gossip messages whose topic and sender
+	// are remembered, to complete the events of the messages which do not carry them.
+	recentMessagesSize = 16384
+)
+
+// Tracer writes trace records to the files of a directory. It implements the event tracer
+// interface of pubsub.
+type Tracer struct {
+	dir         string
+	maxFileSize int64
+	maxFiles    int
+	records     chan *Record
+	quit        chan struct{}
+	done        chan struct{}
+	closeOnce   sync.Once
+	dropped     uint64
+	// messages maps the IDs of recently received gossip messages to the message meta data.
+	messages *lru.Cache
+	file     *os.File
+	gz       *gzip.Writer
+	// size is the compressed size of the current trace file.
+	size int64
+}
+
+// messageMeta is the topic and sender of a received gossip message.
+type messageMeta struct {
+	topic string
+	from  peer.ID
+}
+
+// New creates a tracer writing to the given directory, and starts writing the records it
+// receives. Files are rotated once they reach the max size, and at most max files rotated files
+// are kept.
+func New(dir string, maxFileSize int64, maxFiles int) (*Tracer, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "could not create trace directory")
+	}
+	messages, err := lru.New(recentMessagesSize)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tracer{
+		dir:         dir,
+		maxFileSize: maxFileSize,
+		maxFiles:    maxFiles,
+		records:     make(chan *Record, recordBufferSize),
+		quit:        make(chan struct{}),
+		done:        make(chan struct{}),
+		messages:    messages,
+	}
+	if err := t.open(); err != nil {
+		return nil, err
+	}
+	go t.run()
+	return t, nil
+}
+
+// Trace records a pubsub event. Only the events of gossip messages are recorded.
+func (t *Tracer) Trace(evt *pubsubpb.TraceEvent) {
+	timestamp := time.Unix(0, evt.GetTimestamp())
+	switch evt.GetType() {
+	case pubsubpb.TraceEvent_RECV_RPC:
+		from := peer.ID(evt.GetRecvRPC().GetReceivedFrom())
+		for _, msg := range evt.GetRecvRPC().GetMeta().GetMessages() {
+			meta := &messageMeta{from: from}
+			if len(msg.Topics) > 0 {
+				meta.topic = msg.Topics[0]
+			}
+			// The first peer to send a message is the one it is delivered from.
+			t.messages.ContainsOrAdd(string(msg.MessageID), meta)
+		}
+	case pubsubpb.TraceEvent_PUBLISH_MESSAGE:
+		r := &Record{Type: PublishMessage, Time: timestamp, MessageID: string(evt.GetPublishMessage().GetMessageID())}
+		if topics := evt.GetPublishMessage().GetTopics(); len(topics) > 0 {
+			r.Topic = topics[0]
+		}
+		t.Record(r)
+	case pubsubpb.TraceEvent_DELIVER_MESSAGE:
+		t.Record(t.messageRecord(DeliverMessage, timestamp, evt.GetDeliverMessage().GetMessageID(), nil))
+	case pubsubpb.TraceEvent_REJECT_MESSAGE:
+		reject := evt.GetRejectMessage()
+		r := t.messageRecord(RejectMessage, timestamp, reject.GetMessageID(), reject.GetReceivedFrom())
+		r.Reason = reject.GetReason()
+		t.Record(r)
+	case pubsubpb.TraceEvent_DUPLICATE_MESSAGE:
+		duplicate := evt.GetDuplicateMessage()
+		t.Record(t.messageRecord(DuplicateMessage, timestamp, duplicate.GetMessageID(), duplicate.GetReceivedFrom()))
+	}
+}
+
+// messageRecord creates the record of a received gossip message, completing it with the topic
+// and sender the message was received with.
+func (t *Tracer) messageRecord(typ string, timestamp time.Time, msgID []byte, from []byte) *Record {
+	r := &Record{Type: typ, Time: timestamp, MessageID: string(msgID)}
+	if len(from) > 0 {
+		r.PeerID = peer.ID(from).Pretty()
+	}
+	if v, ok := t.messages.Get(string(msgID)); ok {
+		meta := v.(*messageMeta)
+		r.Topic = meta.topic
+		if r.PeerID == "" {
+			r.PeerID = meta.from.Pretty()
+		}
+	}
+	return r
+}
+
+// Record queues a record to be written. Records are dropped rather than blocking the caller if
+// the writer falls behind.
+func (t *Tracer) Record(r *Record) {
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	select {
+	case t.records <- r:
+	default:
+		atomic.AddUint64(&t.dropped, 1)
+	}
+}
+
+// Close writes the queued records and closes the trace file.
+func (t *Tracer) Close() error {
+	t.closeOnce.Do(func() {
+		close(t.quit)
+	})
+	<-t.done
+	if dropped := atomic.LoadUint64(&t.dropped); dropped > 0 {
+		log.WithField("dropped", dropped).Warn("Trace records were dropped as the trace could not keep up")
+	}
+	if t.gz == nil {
+		return nil
+	}
+	return t.closeFile()
+}
+
+func (t *Tracer) run() {
+	defer close(t.done)
+	for {
+		select {
+		case r := <-t.records:
+			t.write(r)
+			// Flush once the queue is drained, so that the trace survives a crash.
+			if len(t.records) == 0 && t.gz != nil {
+				if err := t.gz.Flush(); err != nil {
+					log.WithError(err).Error("Could not flush trace file")
+				}
+			}
+		case <-t.quit:
+			for {
+				select {
+				case r := <-t.records:
+					t.write(r)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (t *Tracer) write(r *Record) {
+	enc, err := json.Marshal(r)
+	if err != nil {
+		log.WithError(err).Error("Could not encode trace record")
+		return
+	}
+	// The trace file is missing if it could not be reopened after a rotation, it is opened again
+	// so that tracing resumes.
+	if t.gz == nil {
+		if err := t.open(); err != nil {
+			log.WithError(err).Error("Could not open trace file")
+			return
+		}
+	}
+	if t.maxFileSize > 0 && t.size >= t.maxFileSize {
+		if err := t.rotate(); err != nil {
+			log.WithError(err).Error("Could not rotate trace file")
+		}
+		if t.gz == nil {
+			return
+		}
+	}
+	if _, err := t.gz.Write(append(enc, '\n')); err != nil {
+		log.WithError(err).Error("Could not write trace record")
+	}
+}
+
+// open opens the current trace file for appending. Every run appends its own gzip member.
+func (t *Tracer) open() error {
+	f, err := os.OpenFile(path.Join(t.dir, currentFileName), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	t.file = f
+	t.size = info.Size()
+	t.gz = gzip.NewWriter(&countingWriter{w: f, n: &t.size})
+	return nil
+}
+
+// rotate closes the current trace file, moves it aside and deletes the oldest rotated files
+// beyond the max number of files. The current trace file is reopened even if it could not be moved
+// aside, so that tracing continues. It is left closed only if it cannot be reopened.
+func (t *Tracer) rotate() error {
+	err := t.closeFile()
+	if err == nil {
+		err = t.moveAside()
+	}
+	if openErr := t.open(); openErr != nil {
+		return openErr
+	}
+	return err
+}
+
+// moveAside renames the current trace file to a rotated file and deletes the oldest rotated files
+// beyond the max number of files.
+func (t *Tracer) moveAside() error {
+	rotated := fmt.Sprintf("%s.%s%s", traceFilePrefix, time.Now().UTC().Format(rotatedFileTimeFmt), traceFileExtension)
+	if err := os.Rename(path.Join(t.dir, currentFileName), path.Join(t.dir, rotated)); err != nil {
+		return err
+	}
+	files, err := traceFiles(t.dir)
+	if err != nil {
+		return err
+	}
+	for len(files) > t.maxFiles {
+		if err := os.Remove(path.Join(t.dir, files[0])); err != nil {
+			return err
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// closeFile closes the current trace file, which must be opened again before writing to it.
+func (t *Tracer) closeFile() error {
+	gzErr := t.gz.Close()
+	fileErr := t.file.Close()
+	t.gz, t.file = nil, nil
+	if gzErr != nil {
+		return gzErr
+	}
+	return fileErr
+}
+
+// countingWriter counts the bytes written to the underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n *int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	*c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,193 @@
+package tracer
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func newTestTracer(t *testing.T, name string, maxFileSize int64, maxFiles int) (*Tracer, string) {
+	dir := path.Join(testutil.TempDir(), name)
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	tr, err := New(dir, maxFileSize, maxFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tr, dir
+}
+
+func TestTracer_CompletesGossipEvents(t *testing.T) {
+	tr, dir := newTestTracer(t, "tracer_gossip", 0, 0)
+	defer os.RemoveAll(dir)
+
+	from := peer.ID("sender")
+	now := time.Now().UnixNano()
+	recv, deliver, reject := pubsubpb.TraceEvent_RECV_RPC, pubsubpb.TraceEvent_DELIVER_MESSAGE, pubsubpb.TraceEvent_REJECT_MESSAGE
+	reason := "validation failed"
+	events := []*pubsubpb.TraceEvent{
+		{
+			Type:      &recv,
+			Timestamp: &now,
+			RecvRPC: &pubsubpb.TraceEvent_RecvRPC{
+				ReceivedFrom: []byte(from),
+				Meta: &pubsubpb.TraceEvent_RPCMeta{
+					Messages: []*pubsubpb.TraceEvent_MessageMeta{
+						{MessageID: []byte("a"), Topics: []string{"/eth2/00000000/beacon_block/ssz"}},
+						{MessageID: []byte("b"), Topics: []string{"/eth2/00000000/voluntary_exit/ssz"}},
+					},
+				},
+			},
+		},
+		{
+			Type:           &deliver,
+			Timestamp:      &now,
+			DeliverMessage: &pubsubpb.TraceEvent_DeliverMessage{MessageID: []byte("a")},
+		},
+		{
+			Type:      &reject,
+			Timestamp: &now,
+			RejectMessage: &pubsubpb.TraceEvent_RejectMessage{
+				MessageID:    []byte("b"),
+				ReceivedFrom: []byte(from),
+				Reason:       &reason,
+			},
+		},
+	}
+	for _, evt := range events {
+		tr.Trace(evt)
+	}
+	tr.Record(&Record{Type: RPCRequest, Direction: Inbound, PeerID: from.Pretty(), Topic: "/eth2/beacon_chain/req/status/1/ssz"})
+	if err := tr.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := Read(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, received %d", len(records))
+	}
+	if r := records[0]; r.Type != DeliverMessage || r.MessageID != "a" || r.PeerID != from.Pretty() || r.Topic != "/eth2/00000000/beacon_block/ssz" {
+		t.Errorf("Unexpected deliver record %+v", r)
+	}
+	if r := records[1]; r.Type != RejectMessage || r.Reason != reason || r.Topic != "/eth2/00000000/voluntary_exit/ssz" {
+		t.Errorf("Unexpected reject record %+v", r)
+	}
+	if r := records[2]; r.Type != RPCRequest || r.Direction != Inbound || r.Time.IsZero() {
+		t.Errorf("Unexpected request record %+v", r)
+	}
+
+	byMessage, err := Read(dir, func(r *Record) bool { return r.MessageID == "b" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byMessage) != 1 || byMessage[0].Type != RejectMessage {
+		t.Errorf("Expected only the reject record of message b, received %v", byMessage)
+	}
+}
+
+func TestTracer_RotatesFiles(t *testing.T) {
+	// Every record fills a file, and two rotated files are kept.
+	tr, dir := newTestTracer(t, "tracer_rotation", 1, 2)
+	defer os.RemoveAll(dir)
+
+	for _, id := range []string{"1", "2", "3", "4"} {
+		tr.Record(&Record{Type: PublishMessage, MessageID: id})
+		// Wait for the record to be written, so that the files are rotated in order.
+		for len(tr.records) > 0 {
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := tr.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Errorf("Expected two rotated files and the current file, received %d files", len(files))
+	}
+	records, err := Read(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, r := range records {
+		ids = append(ids, r.MessageID)
+	}
+	if len(ids) != 3 || ids[0] != "2" || ids[1] != "3" || ids[2] != "4" {
+		t.Errorf("Expected the records of the kept files in order, received %v", ids)
+	}
+}
+
+func TestTracer_AppendsAcrossRuns(t *testing.T) {
+	tr, dir := newTestTracer(t, "tracer_restart", 0, 0)
+	defer os.RemoveAll(dir)
+
+	tr.Record(&Record{Type: PublishMessage, MessageID: "before"})
+	if err := tr.Close(); err != nil {
+		t.Fatal(err)
+	}
+	tr, err := New(dir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr.Record(&Record{Type: PublishMessage, MessageID: "after"})
+	if err := tr.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := Read(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].MessageID != "before" || records[1].MessageID != "after" {
+		t.Errorf("Expected the records of both runs, received %v", records)
+	}
+}
+
+func TestTracer_ReopensFileAfterFailedRotation(t *testing.T) {
+	tr, dir := newTestTracer(t, "tracer_failed_rotation", 1, 2)
+	defer os.RemoveAll(dir)
+	write := func(id string) {
+		tr.Record(&Record{Type: PublishMessage, MessageID: id})
+		for len(tr.records) > 0 {
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	write("1")
+	// Neither moving the full file aside nor reopening it succeeds without the directory.
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	write("2")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	write("3")
+	if err := tr.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := Read(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].MessageID != "3" {
+		t.Errorf("Expected the records written once the trace file could be reopened, received %v", records)
+	}
+}
@@ -0,0 +1,75 @@
+package p2p
+
+import (
+	"sync"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/tracer"
+)
+
+const (
+	// traceFileMaxSize is the compressed size at which a trace file is rotated.
+	traceFileMaxSize = 64 << 20
+	// traceMaxFiles is the number of rotated trace files kept.
+	traceMaxFiles = 10
+)
+
+// traceRPC records a req/resp request or response if tracing is enabled.
+func (s *Service) traceRPC(typ string, direction string, pid peer.ID, topic string, size int) {
+	if s.tracer == nil {
+		return
+	}
+	s.tracer.Record(&tracer.Record{
+		Type:      typ,
+		Direction: direction,
+		PeerID:    pid.Pretty(),
+		Topic:     topic,
+		Size:      size,
+	})
+}
+
+// tracedHandler wraps a stream handler to record the requests received from peers, and the
+// responses written to them once the request is handled.
+func (s *Service) tracedHandler(topic string, handler network.StreamHandler) network.StreamHandler {
+	return func(stream network.Stream) {
+		pid := stream.Conn().RemotePeer()
+		s.traceRPC(tracer.RPCRequest, tracer.Inbound, pid, topic, 0)
+		traced := &tracedStream{Stream: stream}
+		handler(traced)
+		s.traceRPC(tracer.RPCResponse, tracer.Outbound, pid, topic, traced.bytesWritten())
+	}
+}
+
+// tracedStream counts the bytes written to a stream, and reports the first bytes read from it.
+type tracedStream struct {
+	network.Stream
+	lock     sync.Mutex
+	written  int
+	onRead   func()
+	readOnce sync.Once
+}
+
+func (t *tracedStream) Write(p []byte) (int, error) {
+	n, err := t.Stream.Write(p)
+	t.lock.Lock()
+	t.written += n
+	t.lock.Unlock()
+	return n, err
+}
+
+func (t *tracedStream) Read(p []byte) (int, error) {
+	n, err := t.Stream.Read(p)
+	if n > 0 && t.onRead != nil {
+		t.readOnce.Do(func() {
+			t.onRead()
+		})
+	}
+	return n, err
+}
+
+func (t *tracedStream) bytesWritten() int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.written
+}
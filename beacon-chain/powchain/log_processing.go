@@ -331,7 +331,7 @@ func (s *Service) processPastLogs(ctx context.Context) error {
 	}
 
 	s.latestEth1Data.LastRequestedBlock = currentBlockNum
-	currentState, err := s.beaconDB.HeadState(ctx)
+	currentState, err := s.headState(ctx)
 	if err != nil {
 		return errors.Wrap(err, "could not get head state")
 	}
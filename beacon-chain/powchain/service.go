@@ -22,11 +22,13 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
 	"github.com/prysmaticlabs/prysm/beacon-chain/cache/depositcache"
 	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
 	contracts "github.com/prysmaticlabs/prysm/contracts/deposit-contract"
 	protodb "github.com/prysmaticlabs/prysm/proto/beacon/db"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
@@ -139,6 +141,7 @@ type Service struct {
 	depositTrie             *trieutil.SparseMerkleTrie
 	chainStartData          *protodb.ChainStartData
 	beaconDB                db.HeadAccessDatabase // Circular dep if using HeadFetcher.
+	stateGen                *stategen.State
 	depositCache            *depositcache.DepositCache
 	lastReceivedMerkleIndex int64 // Keeps track of the last received index to prevent log spam.
 	isRunning               bool
@@ -157,6 +160,7 @@ type Web3ServiceConfig struct {
 	BeaconDB        db.HeadAccessDatabase
 	DepositCache    *depositcache.DepositCache
 	StateNotifier   statefeed.Notifier
+	StateGen        *stategen.State
 }
 
 // NewService sets up a new instance with an ethclient when
@@ -200,11 +204,16 @@ func NewService(ctx context.Context, config *Web3ServiceConfig) (*Service, error
 			ChainstartDeposits: make([]*ethpb.Deposit, 0),
 		},
 		beaconDB:                config.BeaconDB,
+		stateGen:                config.StateGen,
 		depositCache:            config.DepositCache,
 		lastReceivedMerkleIndex: -1,
 		preGenesisState:         genState,
 	}
 
+	if s.stateGen == nil {
+		s.stateGen = stategen.New(config.BeaconDB)
+	}
+
 	eth1Data, err := config.BeaconDB.PowchainData(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to retrieve eth1 data")
@@ -423,7 +432,7 @@ func (s *Service) initDepositCaches(ctx context.Context, ctrs []*protodb.Deposit
 		return nil
 	}
 	s.depositCache.InsertDepositContainers(ctx, ctrs)
-	currentState, err := s.beaconDB.HeadState(ctx)
+	currentState, err := s.headState(ctx)
 	if err != nil {
 		return errors.Wrap(err, "could not get head state")
 	}
@@ -446,6 +455,23 @@ func (s *Service) initDepositCaches(ctx context.Context, ctrs []*protodb.Deposit
 	return nil
 }
 
+// headState returns the state of the head block in the DB. Hot states are only saved in full at
+// epoch boundaries, so it is regenerated by stategen. It is nil if there is no head block yet.
+func (s *Service) headState(ctx context.Context) (*stateTrie.BeaconState, error) {
+	head, err := s.beaconDB.HeadBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if head == nil || head.Block == nil {
+		return nil, nil
+	}
+	headRoot, err := ssz.HashTreeRoot(head.Block)
+	if err != nil {
+		return nil, err
+	}
+	return s.stateGen.StateByRoot(ctx, headRoot)
+}
+
 // processSubscribedHeaders adds a newly observed eth1 block to the block cache and
 // updates the latest blockHeight, blockHash, and blockTime properties of the service.
 func (s *Service) processSubscribedHeaders(header *gethTypes.Header) {
@@ -47,6 +47,9 @@ func (bs *Server) ListAttestations(
 	var err error
 	switch q := req.QueryFilter.(type) {
 	case *ethpb.ListAttestationsRequest_Genesis:
+		if err := bs.requireHistory(ctx, 0); err != nil {
+			return nil, err
+		}
 		genBlk, err := bs.BeaconDB.GenesisBlock(ctx)
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "Could not genesis block: %v", err)
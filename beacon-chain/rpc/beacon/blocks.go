@@ -35,6 +35,9 @@ func (bs *Server) ListBlocks(
 
 	switch q := req.QueryFilter.(type) {
 	case *ethpb.ListBlocksRequest_Epoch:
+		if err := bs.requireHistory(ctx, helpers.StartSlot(q.Epoch+1)-1); err != nil {
+			return nil, err
+		}
 		blks, err := bs.BeaconDB.Blocks(ctx, filters.NewFilter().SetStartEpoch(q.Epoch).SetEndEpoch(q.Epoch))
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "Failed to get blocks: %v", err)
@@ -98,6 +101,9 @@ func (bs *Server) ListBlocks(
 		}, nil
 
 	case *ethpb.ListBlocksRequest_Slot:
+		if err := bs.requireHistory(ctx, q.Slot); err != nil {
+			return nil, err
+		}
 		blks, err := bs.BeaconDB.Blocks(ctx, filters.NewFilter().SetStartSlot(q.Slot).SetEndSlot(q.Slot))
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "Could not retrieve blocks for slot %d: %v", q.Slot, err)
@@ -136,6 +142,9 @@ func (bs *Server) ListBlocks(
 			NextPageToken:   nextPageToken,
 		}, nil
 	case *ethpb.ListBlocksRequest_Genesis:
+		if err := bs.requireHistory(ctx, 0); err != nil {
+			return nil, err
+		}
 		genBlk, err := bs.BeaconDB.GenesisBlock(ctx)
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "Could not retrieve blocks for genesis slot: %v", err)
@@ -164,6 +173,30 @@ func (bs *Server) ListBlocks(
 	return nil, status.Error(codes.InvalidArgument, "Must specify a filter criteria for fetching blocks")
 }
 
+// requireHistory returns an error if the node was started by checkpoint sync from a block
+// after the given slot, as the blocks before that block are not in the database until they are
+// backfilled.
+func (bs *Server) requireHistory(ctx context.Context, slot uint64) error {
+	lowest, err := bs.BeaconDB.BackfillBlock(ctx)
+	if err != nil {
+		return status.Errorf(codes.Internal, "Could not retrieve lowest backfilled block: %v", err)
+	}
+	if lowest == nil {
+		lowest, err = bs.BeaconDB.AnchorBlock(ctx)
+		if err != nil {
+			return status.Errorf(codes.Internal, "Could not retrieve checkpoint sync anchor block: %v", err)
+		}
+	}
+	if lowest != nil && slot < lowest.Block.Slot {
+		return status.Errorf(
+			codes.OutOfRange,
+			"History before slot %d is unavailable as the node was started from a checkpoint",
+			lowest.Block.Slot,
+		)
+	}
+	return nil
+}
+
 // GetChainHead retrieves information about the head of the beacon chain from
 // the view of the beacon chain node.
 //
@@ -366,6 +366,57 @@ func TestServer_ListBlocks_Errors(t *testing.T) {
 	}
 }
 
+func TestServer_ListBlocks_BeforeCheckpointAnchor(t *testing.T) {
+	db := dbTest.SetupDB(t)
+	defer dbTest.TeardownDB(t, db)
+	ctx := context.Background()
+
+	anchorSlot := params.BeaconConfig().SlotsPerEpoch + 3
+	blk := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: anchorSlot}}
+	root, err := ssz.HashTreeRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, blk); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveAnchorBlockRoot(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	bs := &Server{
+		BeaconDB: db,
+	}
+
+	unavailable := []*ethpb.ListBlocksRequest{
+		{QueryFilter: &ethpb.ListBlocksRequest_Genesis{Genesis: true}},
+		{QueryFilter: &ethpb.ListBlocksRequest_Slot{Slot: anchorSlot - 1}},
+		{QueryFilter: &ethpb.ListBlocksRequest_Epoch{Epoch: 0}},
+	}
+	for _, req := range unavailable {
+		if _, err := bs.ListBlocks(ctx, req); err == nil || !strings.Contains(err.Error(), "History before slot") {
+			t.Errorf("Expected history unavailable error for %v, received %v", req, err)
+		}
+	}
+
+	available := []*ethpb.ListBlocksRequest{
+		{QueryFilter: &ethpb.ListBlocksRequest_Slot{Slot: anchorSlot}},
+		{QueryFilter: &ethpb.ListBlocksRequest_Epoch{Epoch: 1}},
+		{QueryFilter: &ethpb.ListBlocksRequest_Root{Root: root[:]}},
+	}
+	for _, req := range available {
+		res, err := bs.ListBlocks(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.BlockContainers) != 1 || !proto.Equal(res.BlockContainers[0].Block, blk) {
+			t.Errorf("Expected anchor block for %v, received %v", req, res.BlockContainers)
+		}
+	}
+}
+
 func TestServer_GetChainHead_NoFinalizedBlock(t *testing.T) {
 	db := dbTest.SetupDB(t)
 	defer dbTest.TeardownDB(t, db)
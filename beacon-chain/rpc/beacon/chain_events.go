@@ -0,0 +1,78 @@
+package beacon
+
+import (
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StreamChainEvents to clients every time the head of the chain changes, the chain
+// reorgs or a new checkpoint is finalized.
+func (bs *Server) StreamChainEvents(_ *ptypes.Empty, stream rpcpb.ChainEvents_StreamChainEventsServer) error {
+	stateChannel := make(chan *feed.Event, 1)
+	stateSub := bs.StateNotifier.StateFeed().Subscribe(stateChannel)
+	defer stateSub.Unsubscribe()
+	for {
+		select {
+		case event := <-stateChannel:
+			res := chainEvent(event)
+			if res == nil {
+				continue
+			}
+			if err := stream.Send(res); err != nil {
+				return status.Errorf(codes.Unavailable, "Could not send over stream: %v", err)
+			}
+		case <-stateSub.Err():
+			return status.Error(codes.Aborted, "Subscriber closed, exiting goroutine")
+		case <-bs.Ctx.Done():
+			return status.Error(codes.Canceled, "Context canceled")
+		case <-stream.Context().Done():
+			return status.Error(codes.Canceled, "Context canceled")
+		}
+	}
+}
+
+// chainEvent converts a state feed event to the chain event sent to clients. It returns
+// nil for the events which are not chain events.
+func chainEvent(event *feed.Event) *rpcpb.ChainEvent {
+	switch event.Type {
+	case statefeed.NewHead:
+		data, ok := event.Data.(*statefeed.NewHeadData)
+		if !ok {
+			return nil
+		}
+		return &rpcpb.ChainEvent{Event: &rpcpb.ChainEvent_Head{Head: &rpcpb.HeadEvent{
+			Slot:              data.Slot,
+			BlockRoot:         data.BlockRoot[:],
+			PreviousBlockRoot: data.PreviousBlockRoot[:],
+		}}}
+	case statefeed.Reorg:
+		data, ok := event.Data.(*statefeed.ReorgData)
+		if !ok {
+			return nil
+		}
+		return &rpcpb.ChainEvent{Event: &rpcpb.ChainEvent_Reorg{Reorg: &rpcpb.ReorgEvent{
+			OldHeadSlot:        data.OldHeadSlot,
+			OldHeadRoot:        data.OldHeadRoot[:],
+			NewHeadSlot:        data.NewHeadSlot,
+			NewHeadRoot:        data.NewHeadRoot[:],
+			CommonAncestorSlot: data.CommonAncestorSlot,
+			CommonAncestorRoot: data.CommonAncestorRoot[:],
+			Depth:              data.Depth,
+		}}}
+	case statefeed.FinalizedCheckpoint:
+		data, ok := event.Data.(*statefeed.FinalizedCheckpointData)
+		if !ok {
+			return nil
+		}
+		return &rpcpb.ChainEvent{Event: &rpcpb.ChainEvent_FinalizedCheckpoint{FinalizedCheckpoint: &rpcpb.FinalizedCheckpointEvent{
+			Epoch:     data.Epoch,
+			BlockRoot: data.BlockRoot[:],
+		}}}
+	default:
+		return nil
+	}
+}
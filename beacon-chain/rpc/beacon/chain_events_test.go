@@ -0,0 +1,80 @@
+package beacon
+
+import (
+	"context"
+	"testing"
+
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/golang/mock/gomock"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	mockRPC "github.com/prysmaticlabs/prysm/beacon-chain/rpc/testing"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+)
+
+func TestServer_StreamChainEvents_OnReorg(t *testing.T) {
+	ctx := context.Background()
+	chainService := &mock.ChainService{}
+	server := &Server{
+		Ctx:           ctx,
+		StateNotifier: chainService.StateNotifier(),
+	}
+	reorg := &statefeed.ReorgData{
+		OldHeadSlot:        10,
+		OldHeadRoot:        [32]byte{'a'},
+		NewHeadSlot:        11,
+		NewHeadRoot:        [32]byte{'b'},
+		CommonAncestorSlot: 8,
+		CommonAncestorRoot: [32]byte{'c'},
+		Depth:              2,
+	}
+
+	exitRoutine := make(chan bool)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStream := mockRPC.NewMockChainEvents_StreamChainEventsServer(ctrl)
+	mockStream.EXPECT().Send(
+		&rpcpb.ChainEvent{Event: &rpcpb.ChainEvent_Reorg{Reorg: &rpcpb.ReorgEvent{
+			OldHeadSlot:        10,
+			OldHeadRoot:        reorg.OldHeadRoot[:],
+			NewHeadSlot:        11,
+			NewHeadRoot:        reorg.NewHeadRoot[:],
+			CommonAncestorSlot: 8,
+			CommonAncestorRoot: reorg.CommonAncestorRoot[:],
+			Depth:              2,
+		}}},
+	).Do(func(arg0 interface{}) {
+		exitRoutine <- true
+	})
+	mockStream.EXPECT().Context().Return(ctx).AnyTimes()
+
+	go func(tt *testing.T) {
+		if err := server.StreamChainEvents(&ptypes.Empty{}, mockStream); err != nil {
+			tt.Errorf("Could not call RPC method: %v", err)
+		}
+	}(t)
+
+	// Send in a loop to ensure it is delivered (busy wait for the service to subscribe to the state feed).
+	for sent := 0; sent == 0; {
+		sent = server.StateNotifier.StateFeed().Send(&feed.Event{
+			Type: statefeed.Reorg,
+			Data: reorg,
+		})
+	}
+	<-exitRoutine
+}
+
+func TestChainEvent_IgnoresOtherEvents(t *testing.T) {
+	if res := chainEvent(&feed.Event{Type: statefeed.BlockProcessed, Data: &statefeed.BlockProcessedData{}}); res != nil {
+		t.Errorf("Expected no chain event for a processed block, received %v", res)
+	}
+	res := chainEvent(&feed.Event{
+		Type: statefeed.FinalizedCheckpoint,
+		Data: &statefeed.FinalizedCheckpointData{Epoch: 3, BlockRoot: [32]byte{'f'}},
+	})
+	finalized := res.GetFinalizedCheckpoint()
+	if finalized == nil || finalized.Epoch != 3 || finalized.BlockRoot[0] != 'f' {
+		t.Errorf("Unexpected finalized checkpoint event %v", res)
+	}
+}
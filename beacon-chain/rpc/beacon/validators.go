@@ -12,6 +12,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/validators"
 	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/pagination"
 	"github.com/prysmaticlabs/prysm/shared/params"
@@ -20,8 +21,8 @@ import (
 )
 
 // ListValidatorBalances retrieves the validator balances for a given set of public keys.
-// An optional Epoch parameter is provided to request historical validator balances from
-// archived, persistent data.
+// An optional Epoch parameter is provided to request historical validator balances, which are
+// read from the state at the start of the epoch.
 func (bs *Server) ListValidatorBalances(
 	ctx context.Context,
 	req *ethpb.ListValidatorBalancesRequest) (*ethpb.ValidatorBalances, error) {
@@ -52,17 +53,11 @@ func (bs *Server) ListValidatorBalances(
 	var balances []uint64
 	validators := headState.Validators()
 	if requestingGenesis || epoch < helpers.CurrentEpoch(headState) {
-		balances, err = bs.BeaconDB.ArchivedBalances(ctx, epoch)
+		epochState, err := bs.stateAtEpoch(ctx, headState, epoch)
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "Could not retrieve balances for epoch %d", epoch)
-		}
-		if balances == nil {
-			return nil, status.Errorf(
-				codes.NotFound,
-				"Could not retrieve data for epoch %d, perhaps --archive in the running beacon node is disabled",
-				0,
-			)
+			return nil, status.Errorf(codes.Internal, "Could not retrieve state for epoch %d: %v", epoch, err)
 		}
+		balances = epochState.Balances()
 	} else if epoch == helpers.CurrentEpoch(headState) {
 		balances = headState.Balances()
 	} else {
@@ -649,3 +644,25 @@ func validatorHasExited(validator *ethpb.Validator, currentEpoch uint64) bool {
 	}
 	return true
 }
+
+// stateAtEpoch returns the state at the start slot of a past epoch. Slots still covered by the
+// block roots of the head state are resolved through the canonical block root, as stategen only
+// serves finalized slots by slot.
+func (bs *Server) stateAtEpoch(ctx context.Context, headState *stateTrie.BeaconState, epoch uint64) (*stateTrie.BeaconState, error) {
+	slot := helpers.StartSlot(epoch)
+	if slot >= headState.Slot() || headState.Slot() > slot+params.BeaconConfig().SlotsPerHistoricalRoot {
+		return bs.StateGen.StateBySlot(ctx, slot)
+	}
+	root, err := helpers.BlockRootAtSlot(headState, slot)
+	if err != nil {
+		return nil, err
+	}
+	st, err := bs.StateGen.StateByRoot(ctx, bytesutil.ToBytes32(root))
+	if err != nil {
+		return nil, err
+	}
+	if st.Slot() < slot {
+		return state.ProcessSlots(ctx, st, slot)
+	}
+	return st, nil
+}
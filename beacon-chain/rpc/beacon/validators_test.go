@@ -23,6 +23,7 @@ import (
 	dbTest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
 	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
 	"github.com/prysmaticlabs/prysm/shared/params"
 )
 
@@ -154,7 +155,7 @@ func TestServer_ListValidatorBalances_DefaultResponse_NoArchive(t *testing.T) {
 	}
 }
 
-func TestServer_ListValidatorBalances_DefaultResponse_FromArchive(t *testing.T) {
+func TestServer_ListValidatorBalances_DefaultResponse_FromHistoricalState(t *testing.T) {
 	db := dbTest.SetupDB(t)
 	defer dbTest.TeardownDB(t, db)
 
@@ -184,14 +185,13 @@ func TestServer_ListValidatorBalances_DefaultResponse_FromArchive(t *testing.T)
 			Balance:   params.BeaconConfig().MaxEffectiveBalance,
 		}
 	}
-	// We archive old balances for epoch 50.
-	if err := db.SaveArchivedBalances(ctx, 50, oldBalances); err != nil {
-		t.Fatal(err)
-	}
+	// We save the state of epoch 50 with the old balances.
+	blockRoots := saveEpochState(t, db, 50, oldBalances)
 	st, err := stateTrie.InitializeFromProto(&pbp2p.BeaconState{
 		Slot:       helpers.StartSlot(100 /* epoch 100 */),
 		Validators: validators,
 		Balances:   balances,
+		BlockRoots: blockRoots,
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -201,6 +201,7 @@ func TestServer_ListValidatorBalances_DefaultResponse_FromArchive(t *testing.T)
 		HeadFetcher: &mock.ChainService{
 			State: st,
 		},
+		StateGen: stategen.New(db),
 	}
 	res, err := bs.ListValidatorBalances(
 		ctx,
@@ -434,16 +435,12 @@ func TestServer_ListValidatorBalances_OutOfRange(t *testing.T) {
 	}
 }
 
-func TestServer_ListValidatorBalances_FromArchive(t *testing.T) {
+func TestServer_ListValidatorBalances_FromHistoricalState(t *testing.T) {
 	db := dbTest.SetupDB(t)
 	defer dbTest.TeardownDB(t, db)
-	ctx := context.Background()
 	epoch := uint64(0)
 	validators, balances := setupValidators(t, db, 100)
-
-	if err := db.SaveArchivedBalances(ctx, epoch, balances); err != nil {
-		t.Fatal(err)
-	}
+	blockRoots := saveEpochState(t, db, epoch, balances)
 
 	newerBalances := make([]uint64, len(balances))
 	for i := 0; i < len(newerBalances); i++ {
@@ -453,6 +450,7 @@ func TestServer_ListValidatorBalances_FromArchive(t *testing.T) {
 		Slot:       params.BeaconConfig().SlotsPerEpoch * 3,
 		Validators: validators,
 		Balances:   newerBalances,
+		BlockRoots: blockRoots,
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -462,6 +460,7 @@ func TestServer_ListValidatorBalances_FromArchive(t *testing.T) {
 		HeadFetcher: &mock.ChainService{
 			State: st,
 		},
+		StateGen: stategen.New(db),
 	}
 
 	req := &ethpb.ListValidatorBalancesRequest{
@@ -486,22 +485,19 @@ func TestServer_ListValidatorBalances_FromArchive(t *testing.T) {
 	}
 }
 
-func TestServer_ListValidatorBalances_FromArchive_NewValidatorNotFound(t *testing.T) {
+func TestServer_ListValidatorBalances_FromHistoricalState_NewValidatorNotFound(t *testing.T) {
 	db := dbTest.SetupDB(t)
 	defer dbTest.TeardownDB(t, db)
-	ctx := context.Background()
 	epoch := uint64(0)
 	_, balances := setupValidators(t, db, 100)
-
-	if err := db.SaveArchivedBalances(ctx, epoch, balances); err != nil {
-		t.Fatal(err)
-	}
+	blockRoots := saveEpochState(t, db, epoch, balances)
 
 	newValidators, newBalances := setupValidators(t, db, 200)
 	st, err := stateTrie.InitializeFromProto(&pbp2p.BeaconState{
 		Slot:       params.BeaconConfig().SlotsPerEpoch * 3,
 		Validators: newValidators,
 		Balances:   newBalances,
+		BlockRoots: blockRoots,
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -511,6 +507,7 @@ func TestServer_ListValidatorBalances_FromArchive_NewValidatorNotFound(t *testin
 		HeadFetcher: &mock.ChainService{
 			State: st,
 		},
+		StateGen: stategen.New(db),
 	}
 
 	req := &ethpb.ListValidatorBalancesRequest{
@@ -1790,7 +1787,7 @@ func BenchmarkListValidatorBalances(b *testing.B) {
 	}
 }
 
-func BenchmarkListValidatorBalances_FromArchive(b *testing.B) {
+func BenchmarkListValidatorBalances_FromHistoricalState(b *testing.B) {
 	b.StopTimer()
 	db := dbTest.SetupDB(b)
 	defer dbTest.TeardownDB(b, db)
@@ -1808,13 +1805,12 @@ func BenchmarkListValidatorBalances_FromArchive(b *testing.B) {
 	for i := 0; i < numOldBalances; i++ {
 		oldBalances[i] = params.BeaconConfig().MaxEffectiveBalance
 	}
-	// We archive old balances for epoch 50.
-	if err := db.SaveArchivedBalances(ctx, 50, oldBalances); err != nil {
-		b.Fatal(err)
-	}
+	// We save the state of epoch 50 with the old balances.
+	blockRoots := saveEpochState(b, db, 50, oldBalances)
 	s, err := stateTrie.InitializeFromProto(&pbp2p.BeaconState{
 		Slot:       helpers.StartSlot(100 /* epoch 100 */),
 		Validators: validators,
+		BlockRoots: blockRoots,
 	})
 	if err != nil {
 		b.Fatal(err)
@@ -1824,6 +1820,7 @@ func BenchmarkListValidatorBalances_FromArchive(b *testing.B) {
 		HeadFetcher: &mock.ChainService{
 			State: s,
 		},
+		StateGen: stategen.New(db),
 	}
 
 	b.StartTimer()
@@ -1884,3 +1881,26 @@ func setupValidators(t testing.TB, db db.Database, count int) ([]*ethpb.Validato
 	}
 	return validators, balances
 }
+
+// saveEpochState saves a state with the input balances at the start slot of the epoch, and returns
+// head state block roots which point to it.
+func saveEpochState(t testing.TB, db db.Database, epoch uint64, balances []uint64) [][]byte {
+	slot := helpers.StartSlot(epoch)
+	root := [32]byte{'e', 'p', 'o', 'c', 'h'}
+	st, err := stateTrie.InitializeFromProto(&pbp2p.BeaconState{
+		Slot:     slot,
+		Balances: balances,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(context.Background(), st, root); err != nil {
+		t.Fatal(err)
+	}
+	blockRoots := make([][]byte, params.BeaconConfig().SlotsPerHistoricalRoot)
+	for i := range blockRoots {
+		blockRoots[i] = make([]byte, 32)
+	}
+	blockRoots[slot%params.BeaconConfig().SlotsPerHistoricalRoot] = root[:]
+	return blockRoots
+}
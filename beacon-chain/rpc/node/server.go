@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strconv"
 
 	ptypes "github.com/gogo/protobuf/types"
 	"github.com/libp2p/go-libp2p-core/network"
@@ -12,25 +13,48 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/db"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
 	"github.com/prysmaticlabs/prysm/beacon-chain/sync"
+	"github.com/prysmaticlabs/prysm/beacon-chain/sync/backfill"
 	"github.com/prysmaticlabs/prysm/shared/version"
+	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
+var log logrus.FieldLogger
+
+func init() {
+	log = logrus.WithField("prefix", "rpc/node")
+}
+
 // Server defines a server implementation of the gRPC Node service,
 // providing RPC endpoints for verifying a beacon node's sync status, genesis and
 // version information, and services the node implements and runs.
 type Server struct {
 	SyncChecker        sync.Checker
+	BackfillFetcher    backfill.ProgressFetcher
 	Server             *grpc.Server
 	BeaconDB           db.ReadOnlyDatabase
 	PeersFetcher       p2p.PeersProvider
 	GenesisTimeFetcher blockchain.TimeFetcher
 }
 
-// GetSyncStatus checks the current network sync status of the node.
+// GetSyncStatus checks the current network sync status of the node. If the node was started from
+// a checkpoint, the progress of the backfill of the blocks before it is reported in the
+// backfill-lowest-slot and backfill-complete response headers when they can be set.
 func (ns *Server) GetSyncStatus(ctx context.Context, _ *ptypes.Empty) (*ethpb.SyncStatus, error) {
+	if ns.BackfillFetcher != nil {
+		if progress := ns.BackfillFetcher.Progress(); progress != nil {
+			md := metadata.Pairs(
+				"backfill-lowest-slot", strconv.FormatUint(progress.LowestSlot, 10),
+				"backfill-complete", strconv.FormatBool(progress.Complete),
+			)
+			if err := grpc.SetHeader(ctx, md); err != nil {
+				log.WithError(err).Debug("Could not set backfill progress headers")
+			}
+		}
+	}
 	return &ethpb.SyncStatus{
 		Syncing: ns.SyncChecker.Syncing(),
 	}, nil
@@ -77,9 +101,13 @@ func (ns *Server) ListImplementedServices(ctx context.Context, _ *ptypes.Empty)
 	}, nil
 }
 
-// ListPeers lists the peers connected to this node.
+// ListPeers lists the peers connected to this node. When the response headers can be set, the
+// scores of the peers are reported as peer=value entries of the peer-score header, their traffic
+// the same way in the peer-bytes-received, peer-bytes-sent and peer-messages-received headers,
+// and the number of banned peers in the banned-peers header.
 func (ns *Server) ListPeers(ctx context.Context, _ *ptypes.Empty) (*ethpb.Peers, error) {
 	res := make([]*ethpb.Peer, 0)
+	md := metadata.MD{}
 	for _, pid := range ns.PeersFetcher.Peers().Connected() {
 		multiaddr, err := ns.PeersFetcher.Peers().Address(pid)
 		if err != nil {
@@ -89,7 +117,6 @@ func (ns *Server) ListPeers(ctx context.Context, _ *ptypes.Empty) (*ethpb.Peers,
 		if err != nil {
 			continue
 		}
-
 		address := fmt.Sprintf("%s/p2p/%s", multiaddr.String(), pid.Pretty())
 		pbDirection := ethpb.PeerDirection_UNKNOWN
 		switch direction {
@@ -102,6 +129,18 @@ func (ns *Server) ListPeers(ctx context.Context, _ *ptypes.Empty) (*ethpb.Peers,
 			Address:   address,
 			Direction: pbDirection,
 		})
+		if score, err := ns.PeersFetcher.Peers().Score(pid); err == nil {
+			md.Append("peer-score", fmt.Sprintf("%s=%s", pid.Pretty(), strconv.FormatFloat(score, 'f', 2, 64)))
+		}
+		if traffic, err := ns.PeersFetcher.Peers().Traffic(pid); err == nil {
+			md.Append("peer-bytes-received", fmt.Sprintf("%s=%d", pid.Pretty(), traffic.BytesReceived))
+			md.Append("peer-bytes-sent", fmt.Sprintf("%s=%d", pid.Pretty(), traffic.BytesSent))
+			md.Append("peer-messages-received", fmt.Sprintf("%s=%d", pid.Pretty(), traffic.MessagesReceived))
+		}
+	}
+	md.Set("banned-peers", strconv.Itoa(len(ns.PeersFetcher.Peers().Banned())))
+	if err := grpc.SetHeader(ctx, md); err != nil {
+		log.WithError(err).Debug("Could not set peer score and traffic headers")
 	}
 
 	return &ethpb.Peers{
@@ -3,6 +3,7 @@ package node
 import (
 	"bytes"
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,12 +13,44 @@ import (
 	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
 	dbutil "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
 	mockP2p "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/sync/backfill"
 	mockSync "github.com/prysmaticlabs/prysm/beacon-chain/sync/initial-sync/testing"
 	"github.com/prysmaticlabs/prysm/shared/version"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
 )
 
+type mockBackfill struct {
+	progress *backfill.Progress
+}
+
+func (m *mockBackfill) Progress() *backfill.Progress {
+	return m.progress
+}
+
+// mockTransportStream records the headers set by a handler outside of a running gRPC server.
+type mockTransportStream struct {
+	header metadata.MD
+}
+
+func (m *mockTransportStream) Method() string {
+	return "/ethereum.eth.v1alpha1.Node/GetSyncStatus"
+}
+
+func (m *mockTransportStream) SetHeader(md metadata.MD) error {
+	m.header = metadata.Join(m.header, md)
+	return nil
+}
+
+func (m *mockTransportStream) SendHeader(md metadata.MD) error {
+	return m.SetHeader(md)
+}
+
+func (m *mockTransportStream) SetTrailer(md metadata.MD) error {
+	return nil
+}
+
 func TestNodeServer_GetSyncStatus(t *testing.T) {
 	mSync := &mockSync.Sync{IsSyncing: false}
 	ns := &Server{
@@ -40,6 +73,41 @@ func TestNodeServer_GetSyncStatus(t *testing.T) {
 	}
 }
 
+func TestNodeServer_GetSyncStatus_BackfillProgress(t *testing.T) {
+	ns := &Server{
+		SyncChecker:     &mockSync.Sync{IsSyncing: false},
+		BackfillFetcher: &mockBackfill{progress: &backfill.Progress{LowestSlot: 320}},
+	}
+	stream := &mockTransportStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	if _, err := ns.GetSyncStatus(ctx, &ptypes.Empty{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := stream.header.Get("backfill-lowest-slot"); len(got) != 1 || got[0] != "320" {
+		t.Errorf("Wanted backfill-lowest-slot header 320, received %v", got)
+	}
+	if got := stream.header.Get("backfill-complete"); len(got) != 1 || got[0] != "false" {
+		t.Errorf("Wanted backfill-complete header false, received %v", got)
+	}
+
+	// No progress is reported by nodes started from genesis.
+	ns.BackfillFetcher = &mockBackfill{}
+	stream = &mockTransportStream{}
+	ctx = grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	if _, err := ns.GetSyncStatus(ctx, &ptypes.Empty{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(stream.header) != 0 {
+		t.Errorf("Wanted no headers, received %v", stream.header)
+	}
+
+	// The status is still returned when the headers cannot be set.
+	ns.BackfillFetcher = &mockBackfill{progress: &backfill.Progress{LowestSlot: 320}}
+	if _, err := ns.GetSyncStatus(context.Background(), &ptypes.Empty{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestNodeServer_GetGenesis(t *testing.T) {
 	db := dbutil.SetupDB(t)
 	defer dbutil.TeardownDB(t, db)
@@ -107,13 +175,41 @@ func TestNodeServer_ListPeers(t *testing.T) {
 	ethpb.RegisterNodeServer(server, ns)
 	reflection.Register(server)
 
-	res, err := ns.ListPeers(context.Background(), &ptypes.Empty{})
+	stream := &mockTransportStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	res, err := ns.ListPeers(ctx, &ptypes.Empty{})
 	if err != nil {
 		t.Fatal(err)
 	}
 	if len(res.Peers) != 2 {
 		t.Fatalf("Expected 2 peers, received %d: %v", len(res.Peers), res.Peers)
 	}
+	wantScores := map[string]bool{
+		"16Uiu2HAkyWZ4Ni1TpvDS8dPxsozmHY85KaiFjodQuV6Tz5tkHVeR=0.00": true,
+		"16Uiu2HAm4HgJ9N1o222xK61o7LSgToYWoAy1wNTJRkh9gLZapVAy=0.00": true,
+	}
+	scores := stream.header.Get("peer-score")
+	if len(scores) != 2 || !wantScores[scores[0]] || !wantScores[scores[1]] || scores[0] == scores[1] {
+		t.Errorf("Expected a zero score for each peer, received %v", scores)
+	}
+	for _, header := range []string{"peer-bytes-received", "peer-bytes-sent", "peer-messages-received"} {
+		values := stream.header.Get(header)
+		if len(values) != 2 || !strings.HasSuffix(values[0], "=0") || !strings.HasSuffix(values[1], "=0") {
+			t.Errorf("Expected no traffic in the %s header of each peer, received %v", header, values)
+		}
+	}
+	if banned := stream.header.Get("banned-peers"); len(banned) != 1 || banned[0] != "0" {
+		t.Errorf("Expected no banned peers, received %v", banned)
+	}
+
+	// The peers are listed without a transport stream to set the headers on.
+	res, err = ns.ListPeers(context.Background(), &ptypes.Empty{})
+	if err != nil {
+		t.Fatalf("Could not list peers without response headers: %v", err)
+	}
+	if len(res.Peers) != 2 {
+		t.Fatalf("Expected 2 peers, received %d: %v", len(res.Peers), res.Peers)
+	}
 
 	if int(res.Peers[0].Direction) != int(ethpb.PeerDirection_INBOUND) {
 		t.Errorf("Expected 1st peer to be an inbound (%d) connection, received %d", ethpb.PeerDirection_INBOUND, res.Peers[0].Direction)
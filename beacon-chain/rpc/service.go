@@ -29,7 +29,9 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/beacon"
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/node"
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/validator"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
 	"github.com/prysmaticlabs/prysm/beacon-chain/sync"
+	"github.com/prysmaticlabs/prysm/beacon-chain/sync/backfill"
 	pbp2p "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
 	slashpb "github.com/prysmaticlabs/prysm/proto/slashing"
@@ -55,6 +57,7 @@ type Service struct {
 	ctx                    context.Context
 	cancel                 context.CancelFunc
 	beaconDB               db.HeadAccessDatabase
+	stateGen               *stategen.State
 	headFetcher            blockchain.HeadFetcher
 	forkFetcher            blockchain.ForkFetcher
 	finalizationFetcher    blockchain.FinalizationFetcher
@@ -69,6 +72,7 @@ type Service struct {
 	exitPool               *voluntaryexits.Pool
 	slashingsPool          *slashings.Pool
 	syncService            sync.Checker
+	backfillFetcher        backfill.ProgressFetcher
 	host                   string
 	port                   string
 	listener               net.Listener
@@ -99,6 +103,7 @@ type Config struct {
 	CertFlag              string
 	KeyFlag               string
 	BeaconDB              db.HeadAccessDatabase
+	StateGen              *stategen.State
 	HeadFetcher           blockchain.HeadFetcher
 	ForkFetcher           blockchain.ForkFetcher
 	FinalizationFetcher   blockchain.FinalizationFetcher
@@ -113,6 +118,7 @@ type Config struct {
 	ExitPool              *voluntaryexits.Pool
 	SlashingsPool         *slashings.Pool
 	SyncService           sync.Checker
+	BackfillFetcher       backfill.ProgressFetcher
 	Broadcaster           p2p.Broadcaster
 	PeersFetcher          p2p.PeersProvider
 	DepositFetcher        depositcache.DepositFetcher
@@ -132,6 +138,7 @@ func NewService(ctx context.Context, cfg *Config) *Service {
 		ctx:                   ctx,
 		cancel:                cancel,
 		beaconDB:              cfg.BeaconDB,
+		stateGen:              cfg.StateGen,
 		headFetcher:           cfg.HeadFetcher,
 		forkFetcher:           cfg.ForkFetcher,
 		finalizationFetcher:   cfg.FinalizationFetcher,
@@ -148,6 +155,7 @@ func NewService(ctx context.Context, cfg *Config) *Service {
 		exitPool:              cfg.ExitPool,
 		slashingsPool:         cfg.SlashingsPool,
 		syncService:           cfg.SyncService,
+		backfillFetcher:       cfg.BackfillFetcher,
 		host:                  cfg.Host,
 		port:                  cfg.Port,
 		withCert:              cfg.CertFlag,
@@ -210,6 +218,7 @@ func (s *Service) Start() {
 	validatorServer := &validator.Server{
 		Ctx:                    s.ctx,
 		BeaconDB:               s.beaconDB,
+		StateGen:               s.stateGen,
 		AttestationCache:       cache.NewAttestationCache(),
 		AttPool:                s.attestationsPool,
 		ExitPool:               s.exitPool,
@@ -238,6 +247,7 @@ func (s *Service) Start() {
 		BeaconDB:           s.beaconDB,
 		Server:             s.grpcServer,
 		SyncChecker:        s.syncService,
+		BackfillFetcher:    s.backfillFetcher,
 		GenesisTimeFetcher: s.genesisTimeFetcher,
 		PeersFetcher:       s.peersFetcher,
 	}
@@ -246,6 +256,7 @@ func (s *Service) Start() {
 		BeaconDB:             s.beaconDB,
 		AttestationsPool:     s.attestationsPool,
 		SlashingsPool:        s.slashingsPool,
+		StateGen:             s.stateGen,
 		HeadFetcher:          s.headFetcher,
 		FinalizationFetcher:  s.finalizationFetcher,
 		ParticipationFetcher: s.participationFetcher,
@@ -268,6 +279,7 @@ func (s *Service) Start() {
 	pb.RegisterAggregatorServiceServer(s.grpcServer, aggregatorServer)
 	ethpb.RegisterNodeServer(s.grpcServer, nodeServer)
 	ethpb.RegisterBeaconChainServer(s.grpcServer, beaconChainServer)
+	pb.RegisterChainEventsServer(s.grpcServer, beaconChainServer)
 	ethpb.RegisterBeaconNodeValidatorServer(s.grpcServer, validatorServer)
 
 	// Register reflection service on gRPC server.
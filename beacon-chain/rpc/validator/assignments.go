@@ -2,14 +2,29 @@ package validator
 
 import (
 	"context"
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	"math/rand"
+	"sync"
+	"time"
 
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// subnetRand picks the persistent subnets of validators. It is seeded from crypto/rand, so nodes
+// started at the same time do not all pick the same subnets.
+var (
+	subnetRand     = rand.New(rand.NewSource(randomSeed()))
+	subnetRandLock sync.Mutex
+)
+
 // GetDuties returns the committee assignment response from a given validator public key.
 // The committee assignment response contains the following fields for the current and previous epoch:
 //	1.) The list of validators in the committee.
@@ -63,6 +78,11 @@ func (vs *Server) GetDuties(ctx context.Context, req *ethpb.DutiesRequest) (*eth
 				assignment.AttesterSlot = ca.AttesterSlot
 				assignment.ProposerSlot = proposerIndexToSlot[idx]
 				assignment.CommitteeIndex = ca.CommitteeIndex
+
+				// The node subscribes to the subnet of the committee ahead of the attester slot, and
+				// to the long lived subnets of the validator for as long as they last.
+				cache.SubnetIDs.AddAttesterSubnetID(ca.AttesterSlot, ca.CommitteeIndex)
+				assignPersistentSubnets(pubKey)
 			}
 		}
 
@@ -73,3 +93,31 @@ func (vs *Server) GetDuties(ctx context.Context, req *ethpb.DutiesRequest) (*eth
 		Duties: validatorAssignments,
 	}, nil
 }
+
+// assignPersistentSubnets picks random long lived attestation subnets for the validator, unless it
+// still has subnets assigned. Subnets last for a random number of epochs between one and two
+// subscription periods, so validators do not all rotate their subnets at once.
+func assignPersistentSubnets(pubKey []byte) {
+	if _, ok := cache.SubnetIDs.GetPersistentSubnets(pubKey); ok {
+		return
+	}
+	cfg := params.BeaconConfig()
+	subnets := make([]uint64, cfg.RandomSubnetsPerValidator)
+	subnetRandLock.Lock()
+	for i := range subnets {
+		subnets[i] = uint64(subnetRand.Int63n(int64(cfg.AttestationSubnetCount)))
+	}
+	epochs := cfg.EpochsPerRandomSubnetSubscription + uint64(subnetRand.Int63n(int64(cfg.EpochsPerRandomSubnetSubscription)))
+	subnetRandLock.Unlock()
+	duration := time.Duration(epochs*cfg.SlotsPerEpoch*cfg.SecondsPerSlot) * time.Second
+	cache.SubnetIDs.AddPersistentCommittee(pubKey, subnets, duration)
+}
+
+// randomSeed reads a seed from crypto/rand, falling back to the current time if it cannot be read.
+func randomSeed() int64 {
+	b := make([]byte, 8)
+	if _, err := cryptorand.Read(b); err != nil {
+		return roughtime.Now().UnixNano()
+	}
+	return int64(binary.LittleEndian.Uint64(b))
+}
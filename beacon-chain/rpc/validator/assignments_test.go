@@ -10,12 +10,14 @@ import (
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/go-ssz"
 	mockChain "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
 	blk "github.com/prysmaticlabs/prysm/beacon-chain/core/blocks"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
 	dbutil "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
 	mockSync "github.com/prysmaticlabs/prysm/beacon-chain/sync/initial-sync/testing"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/sliceutil"
 	"github.com/prysmaticlabs/prysm/shared/testutil"
 )
 
@@ -283,6 +285,21 @@ func TestGetDuties_MultipleKeys_OK(t *testing.T) {
 	if res.Duties[1].AttesterSlot != 3 {
 		t.Errorf("Expected res.Duties[1].AttesterSlot == 3, got %d", res.Duties[0].AttesterSlot)
 	}
+
+	for _, duty := range res.Duties {
+		if !sliceutil.IsInUint64(duty.CommitteeIndex, cache.SubnetIDs.GetAttesterSubnetIDs(duty.AttesterSlot)) {
+			t.Errorf("Expected subnet %d to be cached for slot %d", duty.CommitteeIndex, duty.AttesterSlot)
+		}
+		subnets, ok := cache.SubnetIDs.GetPersistentSubnets(duty.PublicKey)
+		if !ok || uint64(len(subnets)) != params.BeaconConfig().RandomSubnetsPerValidator {
+			t.Errorf("Expected persistent subnets to be assigned, received %v", subnets)
+		}
+		for _, subnet := range subnets {
+			if subnet >= params.BeaconConfig().AttestationSubnetCount {
+				t.Errorf("Expected persistent subnet below %d, received %d", params.BeaconConfig().AttestationSubnetCount, subnet)
+			}
+		}
+	}
 }
 
 func TestGetDuties_SyncNotReady(t *testing.T) {
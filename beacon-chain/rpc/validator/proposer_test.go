@@ -22,6 +22,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/operations/voluntaryexits"
 	mockPOW "github.com/prysmaticlabs/prysm/beacon-chain/powchain/testing"
 	beaconstate "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
 	mockSync "github.com/prysmaticlabs/prysm/beacon-chain/sync/initial-sync/testing"
 	dbpb "github.com/prysmaticlabs/prysm/proto/beacon/db"
 	pbp2p "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
@@ -69,6 +70,7 @@ func TestGetBlock_OK(t *testing.T) {
 
 	proposerServer := &Server{
 		BeaconDB:          db,
+		StateGen:          stategen.New(db),
 		HeadFetcher:       &mock.ChainService{State: beaconState, Root: parentRoot[:]},
 		SyncChecker:       &mockSync.Sync{IsSyncing: false},
 		BlockReceiver:     &mock.ChainService{},
@@ -174,6 +176,7 @@ func TestGetBlock_AddsUnaggregatedAtts(t *testing.T) {
 
 	proposerServer := &Server{
 		BeaconDB:          db,
+		StateGen:          stategen.New(db),
 		HeadFetcher:       &mock.ChainService{State: beaconState, Root: parentRoot[:]},
 		SyncChecker:       &mockSync.Sync{IsSyncing: false},
 		BlockReceiver:     &mock.ChainService{},
@@ -290,6 +293,7 @@ func TestProposeBlock_OK(t *testing.T) {
 	c := &mock.ChainService{}
 	proposerServer := &Server{
 		BeaconDB:          db,
+		StateGen:          stategen.New(db),
 		ChainStartFetcher: &mockPOW.POWChain{},
 		Eth1InfoFetcher:   &mockPOW.POWChain{},
 		Eth1BlockFetcher:  &mockPOW.POWChain{},
@@ -342,6 +346,7 @@ func TestComputeStateRoot_OK(t *testing.T) {
 
 	proposerServer := &Server{
 		BeaconDB:          db,
+		StateGen:          stategen.New(db),
 		ChainStartFetcher: &mockPOW.POWChain{},
 		Eth1InfoFetcher:   &mockPOW.POWChain{},
 		Eth1BlockFetcher:  &mockPOW.POWChain{},
@@ -0,0 +1,193 @@
+package stategen
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"go.opencensus.io/trace"
+)
+
+// errUnknownBlock is returned when the state of a block which is not in the DB is requested.
+var errUnknownBlock = errors.New("block does not exist in db")
+
+// ErrHotSlot is returned when a state is requested by slot in the hot section of the DB, where
+// a slot may belong to more than one fork. Hot states have to be requested by block root.
+var ErrHotSlot = errors.New("slot is not finalized, request the state by block root instead")
+
+// StateByRoot retrieves the post state of the block with the input root. The state is read from
+// the DB if it was saved in full, otherwise it is regenerated by replaying blocks on top of the
+// nearest ancestor state saved in the DB. Recently used hot states are cached in memory.
+func (s *State) StateByRoot(ctx context.Context, blockRoot [32]byte) (*state.BeaconState, error) {
+	ctx, span := trace.StartSpan(ctx, "stateGen.StateByRoot")
+	defer span.End()
+
+	if cached, ok := s.hotStateCache.Get(blockRoot); ok {
+		return cached.(*state.BeaconState).Copy(), nil
+	}
+	st, err := s.beaconDB.State(ctx, blockRoot)
+	if err != nil {
+		return nil, err
+	}
+	if st != nil {
+		return st, nil
+	}
+
+	b, err := s.beaconDB.Block(ctx, blockRoot)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil || b.Block == nil {
+		return nil, errUnknownBlock
+	}
+	st, err = s.regenerateState(ctx, b, blockRoot)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not regenerate state of slot %d", b.Block.Slot)
+	}
+	split, err := s.split(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if b.Block.Slot >= split.slot {
+		s.hotStateCache.Add(blockRoot, st.Copy())
+	}
+	return st, nil
+}
+
+// StateBySlot retrieves the canonical state at the input slot, processing empty slots after the
+// last block at or before the slot if needed. Only finalized slots are supported, as they are the
+// only ones with a single canonical chain in the DB.
+func (s *State) StateBySlot(ctx context.Context, slot uint64) (*state.BeaconState, error) {
+	ctx, span := trace.StartSpan(ctx, "stateGen.StateBySlot")
+	defer span.End()
+
+	split, err := s.split(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if slot > split.slot {
+		return nil, ErrHotSlot
+	}
+	root, err := s.lastCanonicalRoot(ctx, slot)
+	if err != nil {
+		return nil, err
+	}
+	st, err := s.StateByRoot(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	if st.Slot() < slot {
+		return processSlotsStateGen(ctx, st, slot)
+	}
+	return st, nil
+}
+
+// HasState returns true if the state of the input block root is saved in the DB or can be
+// regenerated from it.
+func (s *State) HasState(ctx context.Context, blockRoot [32]byte) bool {
+	if s.hotStateCache.Contains(blockRoot) {
+		return true
+	}
+	return s.beaconDB.HasState(ctx, blockRoot) || s.beaconDB.HasBlock(ctx, blockRoot)
+}
+
+// regenerateState replays the blocks between the nearest saved ancestor state and the input
+// block to regenerate the block's post state.
+func (s *State) regenerateState(ctx context.Context, b *ethpb.SignedBeaconBlock, blockRoot [32]byte) (*state.BeaconState, error) {
+	ctx, span := trace.StartSpan(ctx, "stateGen.regenerateState")
+	defer span.End()
+
+	startState, err := s.lastAncestorState(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := s.LoadBlocks(ctx, startState.Slot()+1, b.Block.Slot, blockRoot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load blocks")
+	}
+	return s.ReplayBlocks(ctx, startState, blocks, b.Block.Slot)
+}
+
+// lastAncestorState walks up the ancestry chain of the input block and returns the state of the
+// first ancestor which is either cached or saved in the DB.
+func (s *State) lastAncestorState(ctx context.Context, b *ethpb.SignedBeaconBlock) (*state.BeaconState, error) {
+	for {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		parentRoot := bytesutil.ToBytes32(b.Block.ParentRoot)
+		if cached, ok := s.hotStateCache.Get(parentRoot); ok {
+			return cached.(*state.BeaconState).Copy(), nil
+		}
+		st, err := s.beaconDB.State(ctx, parentRoot)
+		if err != nil {
+			return nil, err
+		}
+		if st != nil {
+			return st, nil
+		}
+		b, err = s.beaconDB.Block(ctx, parentRoot)
+		if err != nil {
+			return nil, err
+		}
+		if b == nil || b.Block == nil {
+			return nil, fmt.Errorf("no saved ancestor state, missing block %#x", bytesutil.Trunc(parentRoot[:]))
+		}
+	}
+}
+
+// lastCanonicalRoot returns the root of the last finalized canonical block at or before the input
+// slot. The blocks are searched backwards one archived point interval at a time.
+func (s *State) lastCanonicalRoot(ctx context.Context, slot uint64) ([32]byte, error) {
+	endSlot := slot
+	for {
+		startSlot := uint64(0)
+		if endSlot > s.slotsPerArchivedPoint {
+			startSlot = endSlot - s.slotsPerArchivedPoint
+		}
+		filter := filters.NewFilter().SetStartSlot(startSlot).SetEndSlot(endSlot)
+		blocks, err := s.beaconDB.Blocks(ctx, filter)
+		if err != nil {
+			return [32]byte{}, err
+		}
+		roots, err := s.beaconDB.BlockRoots(ctx, filter)
+		if err != nil {
+			return [32]byte{}, err
+		}
+		if len(blocks) != len(roots) {
+			return [32]byte{}, errors.New("length of blocks and roots don't match")
+		}
+		var found bool
+		var bestSlot uint64
+		var bestRoot [32]byte
+		for i, b := range blocks {
+			if found && b.Block.Slot <= bestSlot {
+				continue
+			}
+			if s.beaconDB.IsFinalizedBlock(ctx, roots[i]) {
+				found = true
+				bestSlot = b.Block.Slot
+				bestRoot = roots[i]
+			}
+		}
+		if found {
+			return bestRoot, nil
+		}
+		if startSlot == 0 {
+			// The genesis block is not part of the finalized block roots index.
+			genesisRoot, err := s.genesisRoot(ctx)
+			if err != nil {
+				return [32]byte{}, err
+			}
+			if genesisRoot == [32]byte{} {
+				return [32]byte{}, errUnknownBlock
+			}
+			return genesisRoot, nil
+		}
+		endSlot = startSlot - 1
+	}
+}
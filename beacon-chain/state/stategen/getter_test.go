@@ -0,0 +1,154 @@
+package stategen
+
+import (
+	"context"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestStateByRoot_ReadsSavedState(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	service := New(db)
+
+	root := [32]byte{'A'}
+	st, err := stateTrie.InitializeFromProto(&pb.BeaconState{Slot: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, st, root); err != nil {
+		t.Fatal(err)
+	}
+	received, err := service.StateByRoot(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received.Slot() != 10 {
+		t.Errorf("Wanted slot 10, received %d", received.Slot())
+	}
+}
+
+func TestStateByRoot_ReadsCachedState(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	service := New(db)
+
+	root := [32]byte{'A'}
+	st, err := stateTrie.InitializeFromProto(&pb.BeaconState{Slot: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.hotStateCache.Add(root, st)
+	received, err := service.StateByRoot(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received.Slot() != 10 {
+		t.Errorf("Wanted slot 10, received %d", received.Slot())
+	}
+	if !service.HasState(ctx, root) {
+		t.Error("Expected cached state to be available")
+	}
+}
+
+func TestStateByRoot_RegeneratesState(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	service := New(db)
+
+	beaconState, privs := testutil.DeterministicGenesisState(t, 32)
+	blk, err := testutil.GenerateFullBlock(beaconState.Copy(), privs, testutil.DefaultBlockGenConfig(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The pre state of the block is saved under its parent root.
+	if err := db.SaveState(ctx, beaconState, bytesutil.ToBytes32(blk.Block.ParentRoot)); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, blk); err != nil {
+		t.Fatal(err)
+	}
+	root, err := ssz.HashTreeRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received, err := service.StateByRoot(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received.Slot() != 1 {
+		t.Errorf("Wanted slot 1, received %d", received.Slot())
+	}
+	if !service.hotStateCache.Contains(root) {
+		t.Error("Expected regenerated hot state to be cached")
+	}
+}
+
+func TestStateByRoot_UnknownBlock(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	service := New(db)
+
+	if _, err := service.StateByRoot(ctx, [32]byte{'A'}); err != errUnknownBlock {
+		t.Errorf("Wanted error %v, received %v", errUnknownBlock, err)
+	}
+	if service.HasState(ctx, [32]byte{'A'}) {
+		t.Error("Expected unknown block to have no state")
+	}
+}
+
+func TestStateBySlot_HotSlot(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	service := New(db)
+	service.setSplit(10, [32]byte{'A'})
+
+	if _, err := service.StateBySlot(ctx, 11); err != ErrHotSlot {
+		t.Errorf("Wanted error %v, received %v", ErrHotSlot, err)
+	}
+}
+
+func TestStateBySlot_ProcessesSkipSlots(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	service := New(db)
+
+	beaconState, _ := testutil.DeterministicGenesisState(t, 32)
+	genesis := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 0}}
+	genesisRoot, err := ssz.HashTreeRoot(genesis.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, genesis); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, beaconState, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	service.setSplit(5, [32]byte{'A'})
+
+	received, err := service.StateBySlot(ctx, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received.Slot() != 3 {
+		t.Errorf("Wanted slot 3, received %d", received.Slot())
+	}
+}
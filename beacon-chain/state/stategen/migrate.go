@@ -0,0 +1,91 @@
+package stategen
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+// MigrateToCold advances the split point between the hot and the cold section of the DB to the
+// input finalized block root. The finalized state is saved in full as the new anchor of the hot
+// section, then the states of every block from the previous up to the new split point are
+// deleted unless they are canonical archived points.
+func (s *State) MigrateToCold(ctx context.Context, finalizedRoot [32]byte) error {
+	ctx, span := trace.StartSpan(ctx, "stateGen.MigrateToCold")
+	defer span.End()
+
+	split, err := s.split(ctx)
+	if err != nil {
+		return err
+	}
+	finalizedBlock, err := s.beaconDB.Block(ctx, finalizedRoot)
+	if err != nil {
+		return err
+	}
+	if finalizedBlock == nil || finalizedBlock.Block == nil {
+		return errUnknownBlock
+	}
+	finalizedSlot := finalizedBlock.Block.Slot
+	if finalizedSlot <= split.slot {
+		return nil
+	}
+
+	// The finalized state is the anchor every hot state is regenerated from.
+	if err := s.ForceCheckpoint(ctx, finalizedRoot); err != nil {
+		return errors.Wrap(err, "could not save finalized state")
+	}
+
+	genesisRoot, err := s.genesisRoot(ctx)
+	if err != nil {
+		return err
+	}
+	filter := filters.NewFilter().SetStartSlot(split.slot).SetEndSlot(finalizedSlot - 1)
+	blocks, err := s.beaconDB.Blocks(ctx, filter)
+	if err != nil {
+		return err
+	}
+	roots, err := s.beaconDB.BlockRoots(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if len(blocks) != len(roots) {
+		return errors.New("length of blocks and roots don't match")
+	}
+
+	var deleted, archived int
+	for i, b := range blocks {
+		root := roots[i]
+		s.hotStateCache.Remove(root)
+		if root == genesisRoot || !s.beaconDB.HasState(ctx, root) {
+			continue
+		}
+		if s.beaconDB.IsFinalizedBlock(ctx, root) {
+			archivedPoint, err := s.isBoundary(ctx, b.Block.Slot, bytesutil.ToBytes32(b.Block.ParentRoot), s.slotsPerArchivedPoint)
+			if err != nil {
+				return err
+			}
+			if archivedPoint {
+				archived++
+				continue
+			}
+		}
+		// The DB refuses to delete protected states such as the head state, which are kept.
+		if err := s.beaconDB.DeleteState(ctx, root); err != nil {
+			log.WithError(err).WithField("slot", b.Block.Slot).Debug("Could not delete state")
+			continue
+		}
+		deleted++
+	}
+
+	s.setSplit(finalizedSlot, finalizedRoot)
+	log.WithFields(logrus.Fields{
+		"splitSlot":      finalizedSlot,
+		"deletedStates":  deleted,
+		"archivedStates": archived,
+	}).Debug("Migrated states to the cold section")
+	return nil
+}
@@ -0,0 +1,96 @@
+package stategen
+
+import (
+	"context"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+)
+
+func TestMigrateToCold_KeepsArchivedPoints(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	service := New(db)
+	service.slotsPerArchivedPoint = 4
+
+	st, err := stateTrie.InitializeFromProto(&pb.BeaconState{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Build a linear chain of blocks from slot 0 to 12 and save every state in full.
+	roots := make([][32]byte, 13)
+	parentRoot := [32]byte{}
+	for i := range roots {
+		b := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: uint64(i), ParentRoot: parentRoot[:]}}
+		r, err := ssz.HashTreeRoot(b.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.SaveBlock(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.SaveState(ctx, st.Copy(), r); err != nil {
+			t.Fatal(err)
+		}
+		roots[i] = r
+		parentRoot = r
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, roots[0]); err != nil {
+		t.Fatal(err)
+	}
+	service.setSplit(0, roots[0])
+	finalizedRoot := roots[10]
+	if err := db.SaveFinalizedCheckpoint(ctx, &ethpb.Checkpoint{Root: finalizedRoot[:]}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := service.MigrateToCold(ctx, finalizedRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	kept := map[int]bool{0: true, 4: true, 8: true, 10: true, 11: true, 12: true}
+	for i, r := range roots {
+		if db.HasState(ctx, r) != kept[i] {
+			t.Errorf("Slot %d: wanted state kept %v, received %v", i, kept[i], db.HasState(ctx, r))
+		}
+	}
+	splitSlot, err := service.SplitSlot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if splitSlot != 10 {
+		t.Errorf("Wanted split slot 10, received %d", splitSlot)
+	}
+}
+
+func TestMigrateToCold_NoOpBeforeSplit(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	service := New(db)
+
+	b := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 5}}
+	r, err := ssz.HashTreeRoot(b.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	service.setSplit(10, [32]byte{'A'})
+	if err := service.MigrateToCold(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	splitSlot, err := service.SplitSlot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if splitSlot != 10 {
+		t.Errorf("Wanted split slot to stay at 10, received %d", splitSlot)
+	}
+}
@@ -1,18 +1,126 @@
 package stategen
 
 import (
+	"context"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/prysmaticlabs/go-ssz"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 )
 
+// defaultSlotsPerArchivedPoint is the distance between two full states kept in the cold
+// section when it is not configured.
+const defaultSlotsPerArchivedPoint = 2048
+
+// hotStateCacheSize is the number of recently used hot states kept in memory to avoid
+// replaying the same blocks over and over again.
+const hotStateCacheSize = 16
+
 // State represents a management object that handles the internal
 // logic of maintaining both hot and cold states in DB.
+//
+// The DB is split in two sections at the last finalized block. States of blocks
+// at or after the split point are hot; they are saved in full at the first block
+// of every epoch. States of blocks before the split point are cold; only the
+// canonical states at archived points are kept, every slotsPerArchivedPoint slots.
+// Every other state is regenerated by replaying blocks on top of the nearest
+// ancestor state saved in the DB.
 type State struct {
-	beaconDB db.NoHeadAccessDatabase
+	beaconDB              db.NoHeadAccessDatabase
+	slotsPerArchivedPoint uint64
+	hotStateCache         *lru.Cache
+	splitInfo             *splitSlotAndRoot
+	splitLock             sync.RWMutex
+}
+
+// splitSlotAndRoot tracks the split point between the cold section and the hot section of
+// the DB, which is the last finalized block.
+type splitSlotAndRoot struct {
+	slot uint64
+	root [32]byte
 }
 
 // New returns a new state management object.
 func New(db db.NoHeadAccessDatabase) *State {
+	slotsPerArchivedPoint := flags.Get().SlotsPerArchivedPoint
+	if slotsPerArchivedPoint == 0 {
+		slotsPerArchivedPoint = defaultSlotsPerArchivedPoint
+	}
+	cache, err := lru.New(hotStateCacheSize)
+	if err != nil {
+		panic(err)
+	}
 	return &State{
-		beaconDB: db,
+		beaconDB:              db,
+		slotsPerArchivedPoint: slotsPerArchivedPoint,
+		hotStateCache:         cache,
+	}
+}
+
+// SplitSlot returns the slot of the split point between the cold and the hot section of the DB.
+func (s *State) SplitSlot(ctx context.Context) (uint64, error) {
+	split, err := s.split(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return split.slot, nil
+}
+
+// split returns the split point between the cold and the hot section of the DB. It is lazily
+// initialized from the finalized checkpoint saved in the DB.
+func (s *State) split(ctx context.Context) (*splitSlotAndRoot, error) {
+	s.splitLock.RLock()
+	split := s.splitInfo
+	s.splitLock.RUnlock()
+	if split != nil {
+		return split, nil
+	}
+
+	s.splitLock.Lock()
+	defer s.splitLock.Unlock()
+	if s.splitInfo != nil {
+		return s.splitInfo, nil
+	}
+	cp, err := s.beaconDB.FinalizedCheckpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Nothing has been finalized yet, every state is hot.
+	if cp == nil || len(cp.Root) == 0 {
+		return &splitSlotAndRoot{}, nil
+	}
+	root := bytesutil.ToBytes32(cp.Root)
+	b, err := s.beaconDB.Block(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil || b.Block == nil {
+		// The finalized checkpoint of a chain which is yet to be initialized only holds
+		// a zero root, every state is hot until the first finalization.
+		return &splitSlotAndRoot{}, nil
+	}
+	s.splitInfo = &splitSlotAndRoot{slot: b.Block.Slot, root: root}
+	return s.splitInfo, nil
+}
+
+// setSplit moves the split point to the input block.
+func (s *State) setSplit(slot uint64, root [32]byte) {
+	s.splitLock.Lock()
+	defer s.splitLock.Unlock()
+	s.splitInfo = &splitSlotAndRoot{slot: slot, root: root}
+}
+
+// genesisRoot returns the block root of the genesis block saved in the DB.
+func (s *State) genesisRoot(ctx context.Context) ([32]byte, error) {
+	b, err := s.beaconDB.GenesisBlock(ctx)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	if b == nil || b.Block == nil {
+		return [32]byte{}, nil
 	}
+	return ssz.HashTreeRoot(b.Block)
 }
@@ -0,0 +1,75 @@
+package stategen
+
+import (
+	"context"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"go.opencensus.io/trace"
+)
+
+// SaveState saves the post state of the block with the input root. In the hot section the state
+// is only saved in full in the DB if the block is the first block of its epoch, in the cold section
+// only if the block is the first block after an archived point. Other states are regenerated on
+// demand, but recent hot states are kept in memory.
+func (s *State) SaveState(ctx context.Context, st *state.BeaconState, blockRoot [32]byte) error {
+	ctx, span := trace.StartSpan(ctx, "stateGen.SaveState")
+	defer span.End()
+
+	split, err := s.split(ctx)
+	if err != nil {
+		return err
+	}
+	period := params.BeaconConfig().SlotsPerEpoch
+	hot := st.Slot() >= split.slot
+	if !hot {
+		period = s.slotsPerArchivedPoint
+	}
+	boundary, err := s.isBoundary(ctx, st.Slot(), bytesutil.ToBytes32(st.LatestBlockHeader().ParentRoot), period)
+	if err != nil {
+		return err
+	}
+	if boundary {
+		if err := s.beaconDB.SaveState(ctx, st, blockRoot); err != nil {
+			return err
+		}
+	}
+	if hot {
+		s.hotStateCache.Add(blockRoot, st.Copy())
+	}
+	return nil
+}
+
+// ForceCheckpoint saves the state of the input block root in full in the DB if it is not already
+// there. This is used for the head and checkpoint states, which the DB requires to be saved in full.
+func (s *State) ForceCheckpoint(ctx context.Context, blockRoot [32]byte) error {
+	ctx, span := trace.StartSpan(ctx, "stateGen.ForceCheckpoint")
+	defer span.End()
+
+	if s.beaconDB.HasState(ctx, blockRoot) {
+		return nil
+	}
+	st, err := s.StateByRoot(ctx, blockRoot)
+	if err != nil {
+		return err
+	}
+	return s.beaconDB.SaveState(ctx, st, blockRoot)
+}
+
+// isBoundary returns true if the block at the input slot is the first block of a period of the
+// input length, that is if its parent block belongs to an earlier period. States of blocks without
+// a parent in the DB are always treated as boundary states since they cannot be regenerated.
+func (s *State) isBoundary(ctx context.Context, slot uint64, parentRoot [32]byte, period uint64) (bool, error) {
+	if slot == 0 || period == 0 {
+		return true, nil
+	}
+	parent, err := s.beaconDB.Block(ctx, parentRoot)
+	if err != nil {
+		return false, err
+	}
+	if parent == nil || parent.Block == nil {
+		return true, nil
+	}
+	return slot/period != parent.Block.Slot/period, nil
+}
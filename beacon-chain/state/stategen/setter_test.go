@@ -0,0 +1,98 @@
+package stategen
+
+import (
+	"context"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestSaveState_HotEpochBoundary(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	service := New(db)
+
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	parent := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: slotsPerEpoch - 2}}
+	parentRoot, err := ssz.HashTreeRoot(parent.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, parent); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		slot      uint64
+		savedInDB bool
+	}{
+		{name: "same epoch as parent", slot: slotsPerEpoch - 1, savedInDB: false},
+		{name: "first block of next epoch", slot: slotsPerEpoch + 1, savedInDB: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, err := stateTrie.InitializeFromProto(&pb.BeaconState{
+				Slot:              tt.slot,
+				LatestBlockHeader: &ethpb.BeaconBlockHeader{Slot: tt.slot, ParentRoot: parentRoot[:]},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			root := [32]byte{byte(tt.slot)}
+			if err := service.SaveState(ctx, st, root); err != nil {
+				t.Fatal(err)
+			}
+			if db.HasState(ctx, root) != tt.savedInDB {
+				t.Errorf("Wanted state saved in DB %v, received %v", tt.savedInDB, db.HasState(ctx, root))
+			}
+			if !service.hotStateCache.Contains(root) {
+				t.Error("Expected hot state to be cached")
+			}
+		})
+	}
+}
+
+func TestSaveState_ColdArchivedPoint(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	service := New(db)
+	service.slotsPerArchivedPoint = 8
+	service.setSplit(100, [32]byte{'S'})
+
+	parent := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 6}}
+	parentRoot, err := ssz.HashTreeRoot(parent.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, parent); err != nil {
+		t.Fatal(err)
+	}
+
+	for slot, wanted := range map[uint64]bool{7: false, 9: true} {
+		st, err := stateTrie.InitializeFromProto(&pb.BeaconState{
+			Slot:              slot,
+			LatestBlockHeader: &ethpb.BeaconBlockHeader{Slot: slot, ParentRoot: parentRoot[:]},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		root := [32]byte{byte(slot)}
+		if err := service.SaveState(ctx, st, root); err != nil {
+			t.Fatal(err)
+		}
+		if db.HasState(ctx, root) != wanted {
+			t.Errorf("Slot %d: wanted state saved in DB %v, received %v", slot, wanted, db.HasState(ctx, root))
+		}
+		if service.hotStateCache.Contains(root) {
+			t.Errorf("Slot %d: cold state should not be cached", slot)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package backfill
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	backfillLowestSlot = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "backfill_lowest_slot",
+		Help: "Slot of the lowest block of the contiguous history in the database.",
+	})
+	backfillBlocksSaved = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "backfill_blocks_saved_total",
+		Help: "Count of blocks saved by the backfill.",
+	})
+	backfillBatchesFailed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "backfill_batches_failed_total",
+		Help: "Count of block batches which were rejected by the backfill.",
+	})
+)
@@ -0,0 +1,319 @@
+// Package backfill fills in the blocks before the anchor block of a node started by checkpoint
+// sync, requesting them backwards from the anchor to genesis from peers.
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/kevinms/leakybucket-go"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+	prysmsync "github.com/prysmaticlabs/prysm/beacon-chain/sync"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = shared.Service(&Service{})
+
+const (
+	refreshTime = 6 * time.Second
+
+	allowedBlocksPerSecond = 32.0
+)
+
+// ProgressFetcher reports the progress of the backfill.
+type ProgressFetcher interface {
+	Progress() *Progress
+}
+
+// Progress of the backfill towards genesis.
+type Progress struct {
+	// LowestSlot is the slot of the lowest block of the contiguous history in the DB.
+	LowestSlot uint64
+	// Complete is true once the history is filled in back to the genesis block.
+	Complete bool
+}
+
+// Config to set up the backfill service.
+type Config struct {
+	P2P       p2p.P2P
+	DB        db.NoHeadAccessDatabase
+	BatchSize uint64
+}
+
+// Service requests the blocks before the lowest block in the DB from peers in batches, links them to
+// the lowest block by their parent roots and saves them without running the state transition. The
+// hash chain of parent roots back from the trusted anchor block is all that is verified, the
+// signatures of the blocks are not, as the validator registry at the anchor cannot tell the
+// proposers of older blocks.
+type Service struct {
+	ctx               context.Context
+	cancel            context.CancelFunc
+	p2p               p2p.P2P
+	db                db.NoHeadAccessDatabase
+	batchSize         uint64
+	lowest            *ethpb.SignedBeaconBlock
+	complete          bool
+	progressLock      sync.RWMutex
+	blocksRateLimiter *leakybucket.Collector
+}
+
+// NewService configures the backfill service.
+func NewService(ctx context.Context, cfg *Config) *Service {
+	ctx, cancel := context.WithCancel(ctx)
+	batchSize := cfg.BatchSize
+	if batchSize == 0 {
+		batchSize = 64
+	}
+	return &Service{
+		ctx:               ctx,
+		cancel:            cancel,
+		p2p:               cfg.P2P,
+		db:                cfg.DB,
+		batchSize:         batchSize,
+		blocksRateLimiter: leakybucket.NewCollector(allowedBlocksPerSecond, allowedBlocksPerSecond, false /* deleteEmptyBuckets */),
+	}
+}
+
+// Start the backfill in the background if the node was started by checkpoint sync.
+func (s *Service) Start() {
+	if err := s.initialize(s.ctx); err != nil {
+		log.WithError(err).Error("Could not initialize backfill")
+		return
+	}
+	if s.lowest == nil {
+		log.Debug("Node was started from genesis, nothing to backfill")
+		return
+	}
+	if s.Progress().Complete {
+		log.Debug("History is complete, nothing to backfill")
+		return
+	}
+	go s.run()
+}
+
+// Stop the backfill.
+func (s *Service) Stop() error {
+	s.cancel()
+	return nil
+}
+
+// Status always returns nil, an incomplete backfill does not affect the health of the node.
+func (s *Service) Status() error {
+	return nil
+}
+
+// Progress returns the progress of the backfill, or nil if the node was started from genesis.
+func (s *Service) Progress() *Progress {
+	s.progressLock.RLock()
+	defer s.progressLock.RUnlock()
+	if s.lowest == nil {
+		return nil
+	}
+	return &Progress{
+		LowestSlot: s.lowest.Block.Slot,
+		Complete:   s.complete,
+	}
+}
+
+// initialize loads the lowest block of the contiguous history, which is the lowest backfilled
+// block, or the anchor block if the backfill has not saved any block yet.
+func (s *Service) initialize(ctx context.Context) error {
+	anchor, err := s.db.AnchorBlock(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get anchor block")
+	}
+	if anchor == nil || anchor.Block == nil {
+		return nil
+	}
+	lowest, err := s.db.BackfillBlock(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get lowest backfilled block")
+	}
+	if lowest == nil || lowest.Block == nil {
+		lowest = anchor
+	}
+	s.setLowest(lowest, lowest.Block.Slot == 0)
+	return nil
+}
+
+func (s *Service) setLowest(blk *ethpb.SignedBeaconBlock, complete bool) {
+	s.progressLock.Lock()
+	defer s.progressLock.Unlock()
+	s.lowest = blk
+	s.complete = complete
+	backfillLowestSlot.Set(float64(blk.Block.Slot))
+}
+
+// run requests batches of blocks backwards from the lowest block until the genesis block is saved.
+// Ranges of skipped slots are stepped over by lowering the end of the next request once two peers
+// returned no block for them. Peers which fail a request or return an invalid batch are excluded
+// until the next batch is saved, or until no other peer is left.
+func (s *Service) run() {
+	randGenerator := rand.New(rand.NewSource(time.Now().Unix()))
+	end := s.Progress().LowestSlot
+	excluded := make(map[peer.ID]bool)
+	// emptyBy is the peer which returned no block for the range ending at end, if any.
+	var emptyBy peer.ID
+	log.WithField("slot", end).Info("Backfilling blocks before the checkpoint sync anchor")
+	for !s.Progress().Complete {
+		if s.ctx.Err() != nil {
+			return
+		}
+		lowestEpoch := helpers.SlotToEpoch(s.Progress().LowestSlot)
+		root, _, pids := s.p2p.Peers().BestFinalized(params.BeaconConfig().MaxPeersToSync, lowestEpoch)
+		candidates := make([]peer.ID, 0, len(pids))
+		for _, pid := range pids {
+			if !excluded[pid] {
+				candidates = append(candidates, pid)
+			}
+		}
+		if len(candidates) == 0 {
+			log.Debug("Waiting for a suitable peer to backfill from")
+			excluded = make(map[peer.ID]bool)
+			time.Sleep(refreshTime)
+			continue
+		}
+		pid := candidates[randGenerator.Intn(len(candidates))]
+
+		start := uint64(0)
+		if end > s.batchSize {
+			start = end - s.batchSize
+		}
+		req := &pb.BeaconBlocksByRangeRequest{
+			HeadBlockRoot: root,
+			StartSlot:     start,
+			Count:         end - start,
+			Step:          1,
+		}
+		blks, err := s.requestBlocks(s.ctx, req, pid)
+		if err != nil {
+			log.WithError(err).WithField("peer", pid).Debug("Could not request blocks")
+			excluded[pid] = true
+			time.Sleep(refreshTime)
+			continue
+		}
+		saved, err := s.processBatch(s.ctx, blks, start, end)
+		if err != nil {
+			backfillBatchesFailed.Inc()
+			s.p2p.Peers().Record(pid, peers.InvalidBlock)
+			log.WithError(err).WithField("peer", pid).Warn("Rejected backfill batch")
+			excluded[pid] = true
+			// A range may have been stepped over on the word of peers which omitted its blocks,
+			// request the blocks right below the lowest block again.
+			end = s.Progress().LowestSlot
+			emptyBy = ""
+			continue
+		}
+		if saved == 0 {
+			excluded[pid] = true
+			if start == 0 {
+				backfillBatchesFailed.Inc()
+				s.p2p.Peers().IncrementBadResponses(pid)
+				log.WithField("peer", pid).Debug("Peer returned no blocks down to genesis")
+				continue
+			}
+			if emptyBy == "" || emptyBy == pid {
+				emptyBy = pid
+				continue
+			}
+			// A second peer confirmed that the slots of the range were skipped.
+			end = start
+			emptyBy = ""
+			continue
+		}
+		s.p2p.Peers().Record(pid, peers.UsefulContribution)
+		end = s.Progress().LowestSlot
+		emptyBy = ""
+		excluded = make(map[peer.ID]bool)
+		log.WithFields(logrus.Fields{
+			"lowestSlot": end,
+			"blocks":     saved,
+		}).Info("Backfilled blocks")
+	}
+	log.Info("Backfill complete, the history of the chain is available back to genesis")
+}
+
+// processBatch saves the blocks of the batch which link to the lowest block by their parent roots,
+// moving the lowest backfilled block down. The anchor block remains the genesis block root of the
+// DB, as it is the block with the state every other state is regenerated from. It returns an
+// error if the batch contains any block which is outside of the requested range or not an
+// ancestor of the lowest block.
+func (s *Service) processBatch(ctx context.Context, blks []*ethpb.SignedBeaconBlock, start uint64, end uint64) (int, error) {
+	s.progressLock.RLock()
+	lowest := s.lowest
+	s.progressLock.RUnlock()
+
+	chain, err := linkBlocks(blks, lowest.Block.ParentRoot, start, end)
+	if err != nil {
+		return 0, err
+	}
+	if len(chain) == 0 {
+		return 0, nil
+	}
+	if err := s.db.SaveBlocks(ctx, chain); err != nil {
+		return 0, errors.Wrap(err, "could not save blocks")
+	}
+
+	newLowest := chain[len(chain)-1]
+	root, err := ssz.HashTreeRoot(newLowest.Block)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.db.SaveBackfillBlockRoot(ctx, root); err != nil {
+		return 0, errors.Wrap(err, "could not save backfill block root")
+	}
+	s.setLowest(newLowest, newLowest.Block.Slot == 0)
+	backfillBlocksSaved.Add(float64(len(chain)))
+	log.WithFields(logrus.Fields{
+		"from": lowest.Block.Slot,
+		"to":   newLowest.Block.Slot,
+	}).Debug("Saved backfill batch")
+	return len(chain), nil
+}
+
+// requestBlocks by range to a specific peer.
+func (s *Service) requestBlocks(ctx context.Context, req *pb.BeaconBlocksByRangeRequest, pid peer.ID) ([]*ethpb.SignedBeaconBlock, error) {
+	if s.blocksRateLimiter.Remaining(pid.String()) < int64(req.Count) {
+		log.WithField("peer", pid).Debug("Slowing down for rate limit")
+		time.Sleep(s.blocksRateLimiter.TillEmpty(pid.String()))
+	}
+	s.blocksRateLimiter.Add(pid.String(), int64(req.Count))
+	log.WithFields(logrus.Fields{
+		"peer":  pid,
+		"start": req.StartSlot,
+		"count": req.Count,
+		"head":  fmt.Sprintf("%#x", req.HeadBlockRoot),
+	}).Debug("Requesting blocks")
+	stream, err := s.p2p.Send(ctx, req, p2p.RPCBlocksByRangeTopic, pid)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request to peer")
+	}
+	defer stream.Close()
+
+	resp := make([]*ethpb.SignedBeaconBlock, 0, req.Count)
+	for {
+		blk, err := prysmsync.ReadChunkedBlock(stream, s.p2p)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read chunked block")
+		}
+		resp = append(resp, blk)
+	}
+	return resp, nil
+}
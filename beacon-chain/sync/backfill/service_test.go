@@ -0,0 +1,188 @@
+package backfill
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	dbTest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+)
+
+// makeChain returns a chain of blocks at the given ascending slots, starting with a block whose
+// parent root is zero.
+func makeChain(t *testing.T, slots ...uint64) []*ethpb.SignedBeaconBlock {
+	blks := make([]*ethpb.SignedBeaconBlock, len(slots))
+	parentRoot := make([]byte, 32)
+	for i, slot := range slots {
+		blks[i] = &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: slot, ParentRoot: parentRoot}}
+		root, err := ssz.HashTreeRoot(blks[i].Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parentRoot = root[:]
+	}
+	return blks
+}
+
+func TestLinkBlocks(t *testing.T) {
+	chain := makeChain(t, 0, 1, 3, 4, 7)
+	anchor := chain[len(chain)-1]
+
+	// Blocks are linked regardless of the order they were received in.
+	linked, err := linkBlocks([]*ethpb.SignedBeaconBlock{chain[2], chain[0], chain[3], chain[1]}, anchor.Block.ParentRoot, 0, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(linked) != 4 {
+		t.Fatalf("Expected 4 linked blocks, received %d", len(linked))
+	}
+	for i, blk := range linked {
+		if !proto.Equal(chain[3-i], blk) {
+			t.Errorf("Expected block at slot %d at position %d, received slot %d", chain[3-i].Block.Slot, i, blk.Block.Slot)
+		}
+	}
+
+	linked, err = linkBlocks(nil, anchor.Block.ParentRoot, 0, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(linked) != 0 {
+		t.Errorf("Expected no linked blocks, received %d", len(linked))
+	}
+
+	if _, err := linkBlocks(chain[:4], anchor.Block.ParentRoot, 2, 7); err == nil || !strings.Contains(err.Error(), "outside of the requested range") {
+		t.Errorf("Expected out of range error, received %v", err)
+	}
+	fork := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 5, ParentRoot: chain[3].Block.ParentRoot}}
+	if _, err := linkBlocks([]*ethpb.SignedBeaconBlock{chain[3], fork}, anchor.Block.ParentRoot, 0, 7); err == nil || !strings.Contains(err.Error(), "not the expected ancestor") {
+		t.Errorf("Expected unlinked block error, received %v", err)
+	}
+}
+
+func TestProcessBatch_BackfillsToGenesis(t *testing.T) {
+	db := dbTest.SetupDB(t)
+	defer dbTest.TeardownDB(t, db)
+	ctx := context.Background()
+
+	chain := makeChain(t, 0, 2, 3, 5, 6, 9)
+	anchor := chain[len(chain)-1]
+	anchorRoot, err := ssz.HashTreeRoot(anchor.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, anchor); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, anchorRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveAnchorBlockRoot(ctx, anchorRoot); err != nil {
+		t.Fatal(err)
+	}
+	s := NewService(ctx, &Config{DB: db, BatchSize: 5})
+	if err := s.initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if p := s.Progress(); p == nil || p.LowestSlot != 9 || p.Complete {
+		t.Fatalf("Unexpected progress %v", p)
+	}
+
+	saved, err := s.processBatch(ctx, chain[3:5], 4, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved != 2 {
+		t.Errorf("Expected 2 saved blocks, received %d", saved)
+	}
+	lowest, err := db.BackfillBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(chain[3], lowest) {
+		t.Errorf("Expected the backfill block to move to slot 5, received %v", lowest)
+	}
+	// A restarted backfill resumes from the lowest backfilled block.
+	restarted := NewService(ctx, &Config{DB: db, BatchSize: 5})
+	if err := restarted.initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if p := restarted.Progress(); p == nil || p.LowestSlot != 5 {
+		t.Errorf("Expected a restarted backfill to resume from slot 5, received %v", p)
+	}
+	if p := s.Progress(); p.LowestSlot != 5 || p.Complete {
+		t.Errorf("Unexpected progress %v", p)
+	}
+
+	saved, err = s.processBatch(ctx, chain[:3], 0, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved != 3 {
+		t.Errorf("Expected 3 saved blocks, received %d", saved)
+	}
+	// The anchor keeps its state, so it remains the genesis and anchor block of the DB.
+	for name, get := range map[string]func(context.Context) (*ethpb.SignedBeaconBlock, error){
+		"genesis": db.GenesisBlock,
+		"anchor":  db.AnchorBlock,
+	} {
+		retrieved, err := get(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(anchor, retrieved) {
+			t.Errorf("Expected the %s block to stay at the anchor, received %v", name, retrieved)
+		}
+	}
+	if p := s.Progress(); p.LowestSlot != 0 || !p.Complete {
+		t.Errorf("Expected backfill to be complete, received %v", p)
+	}
+	for _, blk := range chain {
+		root, err := ssz.HashTreeRoot(blk.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !db.HasBlock(ctx, root) {
+			t.Errorf("Expected block at slot %d to be saved", blk.Block.Slot)
+		}
+	}
+}
+
+func TestProcessBatch_RejectsUnlinkedBlocks(t *testing.T) {
+	db := dbTest.SetupDB(t)
+	defer dbTest.TeardownDB(t, db)
+	ctx := context.Background()
+
+	chain := makeChain(t, 0, 1, 2)
+	anchorRoot, err := ssz.HashTreeRoot(chain[2].Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, chain[2]); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveAnchorBlockRoot(ctx, anchorRoot); err != nil {
+		t.Fatal(err)
+	}
+	s := NewService(ctx, &Config{DB: db})
+	if err := s.initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	other := makeChain(t, 1)
+	if _, err := s.processBatch(ctx, append(other, chain[0]), 0, 2); err == nil {
+		t.Fatal("Expected error for blocks of another chain")
+	}
+	root, err := ssz.HashTreeRoot(other[0].Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.HasBlock(ctx, root) {
+		t.Error("Expected rejected blocks not to be saved")
+	}
+	if p := s.Progress(); p.LowestSlot != 2 {
+		t.Errorf("Expected the lowest slot to stay at 2, received %d", p.LowestSlot)
+	}
+}
@@ -0,0 +1,43 @@
+package backfill
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+)
+
+// linkBlocks returns the blocks of the batch which form the chain ending at the parent root, from
+// the highest to the lowest slot. Every block must be within the requested range [start, end) and
+// on that chain, as peers only serve finalized blocks.
+func linkBlocks(blks []*ethpb.SignedBeaconBlock, parentRoot []byte, start uint64, end uint64) ([]*ethpb.SignedBeaconBlock, error) {
+	sorted := make([]*ethpb.SignedBeaconBlock, 0, len(blks))
+	for _, blk := range blks {
+		if blk == nil || blk.Block == nil {
+			return nil, errors.New("nil block")
+		}
+		if blk.Block.Slot < start || blk.Block.Slot >= end {
+			return nil, fmt.Errorf("block at slot %d is outside of the requested range [%d, %d)", blk.Block.Slot, start, end)
+		}
+		sorted = append(sorted, blk)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Block.Slot > sorted[j].Block.Slot
+	})
+
+	expected := parentRoot
+	for _, blk := range sorted {
+		root, err := ssz.HashTreeRoot(blk.Block)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(root[:], expected) {
+			return nil, fmt.Errorf("block at slot %d with root %#x is not the expected ancestor %#x", blk.Block.Slot, root, expected)
+		}
+		expected = blk.Block.ParentRoot
+	}
+	return sorted, nil
+}
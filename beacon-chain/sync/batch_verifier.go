@@ -0,0 +1,135 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/traceutil"
+	"go.opencensus.io/trace"
+)
+
+const (
+	// verifierLimit is the number of pending signature sets verified as soon as they are collected.
+	verifierLimit = 50
+	// batchPeriod is how long pending signature sets are collected before they are verified.
+	batchPeriod = 10 * time.Millisecond
+	// verifierWorkers is the number of batches verified at once, so that a slow batch does not
+	// hold up the verification of the next ones.
+	verifierWorkers = 4
+)
+
+var errVerifierStopped = errors.New("signature verifier stopped")
+
+// signatureVerifier is the signature set of a gossip message pending verification, along with
+// the channel receiving its verification result.
+type signatureVerifier struct {
+	set     *bls.SignatureBatch
+	resChan chan error
+}
+
+// verifierRoutine collects the signature sets of gossip messages into batches, once every batch
+// period or as soon as verifierLimit sets are pending, and hands the batches to a pool of
+// verifierWorkers routines verifying them.
+func (r *Service) verifierRoutine() {
+	batches := make(chan []*signatureVerifier)
+	defer close(batches)
+	for i := 0; i < verifierWorkers; i++ {
+		go func() {
+			for batch := range batches {
+				verifyBatch(batch)
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(batchPeriod)
+	defer ticker.Stop()
+	var pending []*signatureVerifier
+	for {
+		select {
+		case <-r.ctx.Done():
+			stopVerifiers(pending)
+			return
+		case v := <-r.signatureChan:
+			pending = append(pending, v)
+			if len(pending) < verifierLimit {
+				continue
+			}
+		case <-ticker.C:
+			if len(pending) == 0 {
+				continue
+			}
+		}
+		select {
+		case batches <- pending:
+			pending = nil
+		case <-r.ctx.Done():
+			stopVerifiers(pending)
+			return
+		}
+	}
+}
+
+// stopVerifiers fails the verifiers which are still pending once the service is stopped.
+func stopVerifiers(verifiers []*signatureVerifier) {
+	for _, v := range verifiers {
+		v.resChan <- errVerifierStopped
+	}
+}
+
+// verifyBatch verifies the signature sets of all the pending verifiers at once. If the batch does
+// not verify, each set is verified on its own so that only the invalid messages are rejected.
+func verifyBatch(verifiers []*signatureVerifier) {
+	start := time.Now()
+	batch := bls.NewSignatureBatch()
+	for _, v := range verifiers {
+		batch.Join(v.set)
+	}
+	batchVerificationSize.Observe(float64(batch.Len()))
+
+	valid, err := bls.VerifyMultipleSignatures(batch.Signatures, batch.PublicKeys, batch.Messages, batch.Domains)
+	if err == nil && valid {
+		for _, v := range verifiers {
+			v.resChan <- nil
+		}
+	} else {
+		batchVerificationFallbackCounter.Inc()
+		for _, v := range verifiers {
+			v.resChan <- v.set.Verify()
+		}
+	}
+	batchVerificationDuration.Observe(time.Since(start).Seconds())
+}
+
+// validateWithBatchVerifier queues the signature set of a gossip message for batch verification,
+// and returns whether its signatures are valid once the batch is verified.
+func (r *Service) validateWithBatchVerifier(ctx context.Context, message string, set *bls.SignatureBatch) bool {
+	ctx, span := trace.StartSpan(ctx, "sync.validateWithBatchVerifier")
+	defer span.End()
+
+	if set.Len() == 0 {
+		return true
+	}
+	start := time.Now()
+	resChan := make(chan error, 1)
+	select {
+	case r.signatureChan <- &signatureVerifier{set: set, resChan: resChan}:
+	case <-ctx.Done():
+		return false
+	case <-r.ctx.Done():
+		return false
+	}
+	select {
+	case err := <-resChan:
+		batchVerificationLatency.Observe(time.Since(start).Seconds())
+		if err != nil {
+			log.WithError(err).Debugf("Could not verify %s signature", message)
+			traceutil.AnnotateError(span, err)
+			return false
+		}
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,48 @@
+package sync
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bls"
+)
+
+func TestValidateWithBatchVerifier(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := &Service{
+		ctx:           ctx,
+		signatureChan: make(chan *signatureVerifier, verifierLimit),
+	}
+	go r.verifierRoutine()
+
+	signatureSet := func(valid bool, i int) *bls.SignatureBatch {
+		priv := bls.RandKey()
+		msg := [32]byte{byte(i)}
+		sig := priv.Sign(msg[:], 0)
+		if !valid {
+			msg[1] = 'x'
+		}
+		set := bls.NewSignatureBatch()
+		set.Add(sig, priv.PublicKey(), msg, 0, "test")
+		return set
+	}
+
+	if !r.validateWithBatchVerifier(ctx, "empty", bls.NewSignatureBatch()) {
+		t.Error("Expected an empty signature set to be valid")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2*verifierLimit; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			valid := i%10 != 0
+			if res := r.validateWithBatchVerifier(ctx, "test", signatureSet(valid, i)); res != valid {
+				t.Errorf("Signature set %d: wanted validation %v, got %v", i, valid, res)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,207 @@
+// Package checkpointsync initializes an empty beacon node database from a trusted finalized
+// state and its block, so the node syncs forward from that checkpoint instead of from genesis.
+package checkpointsync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// Config options for checkpoint sync.
+type Config struct {
+	// StatePath is the SSZ file of the trusted finalized state.
+	StatePath string
+	// BlockPath is the SSZ file of the signed block of the state.
+	BlockPath string
+	// BlockProvider is the gRPC endpoint of a beacon node the block is fetched from if
+	// BlockPath is not set.
+	BlockProvider string
+	// GenesisValidatorsRoot identifies the chain of the state on the network. It cannot be
+	// computed from a state after genesis.
+	GenesisValidatorsRoot []byte
+}
+
+// Initialize saves the trusted finalized state and its block from the config as the anchor of an
+// empty database. The database is left untouched if it already holds a chain.
+func Initialize(ctx context.Context, beaconDB db.HeadAccessDatabase, cfg *Config) error {
+	genesis, err := beaconDB.GenesisBlock(ctx)
+	if err != nil {
+		return err
+	}
+	if genesis != nil {
+		log.Warn("Database already holds a chain, ignoring checkpoint state")
+		return nil
+	}
+	if len(cfg.GenesisValidatorsRoot) != 32 {
+		return errors.New("the 32 byte genesis validators root of the chain of the checkpoint state is required")
+	}
+
+	st, err := loadState(cfg.StatePath)
+	if err != nil {
+		return err
+	}
+	var blk *ethpb.SignedBeaconBlock
+	switch {
+	case cfg.BlockPath != "":
+		blk, err = loadBlock(cfg.BlockPath)
+	case cfg.BlockProvider != "":
+		blk, err = fetchBlock(ctx, cfg.BlockProvider, st)
+	default:
+		return errors.New("the block of the checkpoint state is required, either from a file or a block provider")
+	}
+	if err != nil {
+		return err
+	}
+	return SaveAnchor(ctx, beaconDB, st, blk, bytesutil.ToBytes32(cfg.GenesisValidatorsRoot))
+}
+
+// SaveAnchor saves the block and the state as the genesis-equivalent anchor of the database, along
+// with the head block root, the justified and finalized checkpoints pointing at the block and the
+// genesis validators root of the chain. The state is either the post state of the block or that
+// state advanced through empty slots, such as to the start of the finalized epoch, and its epoch is
+// the epoch of the checkpoints. The anchor block root is recorded so history before the anchor is
+// known to be missing.
+func SaveAnchor(ctx context.Context, beaconDB db.HeadAccessDatabase, st *stateTrie.BeaconState, blk *ethpb.SignedBeaconBlock, genesisValidatorsRoot [32]byte) error {
+	if blk == nil || blk.Block == nil {
+		return errors.New("nil checkpoint block")
+	}
+	blockRoot, err := ssz.HashTreeRoot(blk.Block)
+	if err != nil {
+		return errors.Wrap(err, "could not hash checkpoint block")
+	}
+	headerRoot, err := latestBlockRoot(st)
+	if err != nil {
+		return errors.Wrap(err, "could not hash latest block header of checkpoint state")
+	}
+	if headerRoot != blockRoot {
+		return fmt.Errorf("checkpoint block root %#x does not match the latest block header root %#x of the state", blockRoot, headerRoot)
+	}
+
+	if err := beaconDB.SaveBlock(ctx, blk); err != nil {
+		return errors.Wrap(err, "could not save checkpoint block")
+	}
+	if err := beaconDB.SaveState(ctx, st, blockRoot); err != nil {
+		return errors.Wrap(err, "could not save checkpoint state")
+	}
+	if err := beaconDB.SaveGenesisBlockRoot(ctx, blockRoot); err != nil {
+		return errors.Wrap(err, "could not save genesis block root")
+	}
+	if err := beaconDB.SaveAnchorBlockRoot(ctx, blockRoot); err != nil {
+		return errors.Wrap(err, "could not save anchor block root")
+	}
+	if err := beaconDB.SaveGenesisValidatorsRoot(ctx, genesisValidatorsRoot); err != nil {
+		return errors.Wrap(err, "could not save genesis validators root")
+	}
+	if err := beaconDB.SaveHeadBlockRoot(ctx, blockRoot); err != nil {
+		return errors.Wrap(err, "could not save head block root")
+	}
+	pubkeys := make([][48]byte, st.NumValidators())
+	indices := make([]uint64, st.NumValidators())
+	for i := 0; i < st.NumValidators(); i++ {
+		pubkeys[i] = st.PubkeyAtIndex(uint64(i))
+		indices[i] = uint64(i)
+	}
+	if err := beaconDB.SaveValidatorIndices(ctx, pubkeys, indices); err != nil {
+		return errors.Wrap(err, "could not save validator indices")
+	}
+	checkpoint := &ethpb.Checkpoint{Epoch: helpers.CurrentEpoch(st), Root: blockRoot[:]}
+	if err := beaconDB.SaveJustifiedCheckpoint(ctx, checkpoint); err != nil {
+		return errors.Wrap(err, "could not save justified checkpoint")
+	}
+	if err := beaconDB.SaveFinalizedCheckpoint(ctx, checkpoint); err != nil {
+		return errors.Wrap(err, "could not save finalized checkpoint")
+	}
+
+	log.WithFields(logrus.Fields{
+		"slot":      st.Slot(),
+		"epoch":     checkpoint.Epoch,
+		"blockRoot": fmt.Sprintf("%#x", blockRoot),
+	}).Info("Initialized database from checkpoint state")
+	return nil
+}
+
+func loadState(statePath string) (*stateTrie.BeaconState, error) {
+	data, err := ioutil.ReadFile(statePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read checkpoint state")
+	}
+	st := &pb.BeaconState{}
+	if err := ssz.Unmarshal(data, st); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal checkpoint state")
+	}
+	return stateTrie.InitializeFromProto(st)
+}
+
+func loadBlock(blockPath string) (*ethpb.SignedBeaconBlock, error) {
+	data, err := ioutil.ReadFile(blockPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read checkpoint block")
+	}
+	blk := &ethpb.SignedBeaconBlock{}
+	if err := ssz.Unmarshal(data, blk); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal checkpoint block")
+	}
+	return blk, nil
+}
+
+// fetchBlock requests the block of the state from the beacon node at the gRPC endpoint.
+func fetchBlock(ctx context.Context, endpoint string, st *stateTrie.BeaconState) (*ethpb.SignedBeaconBlock, error) {
+	conn, err := grpc.DialContext(ctx, endpoint, grpc.WithInsecure())
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not dial block provider %s", endpoint)
+	}
+	defer conn.Close()
+	return requestBlock(ctx, ethpb.NewBeaconChainClient(conn), st)
+}
+
+// requestBlock requests the block whose root is the latest block header of the state.
+func requestBlock(ctx context.Context, client ethpb.BeaconChainClient, st *stateTrie.BeaconState) (*ethpb.SignedBeaconBlock, error) {
+	root, err := latestBlockRoot(st)
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.ListBlocks(ctx, &ethpb.ListBlocksRequest{
+		QueryFilter: &ethpb.ListBlocksRequest_Root{Root: root[:]},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not request checkpoint block %#x", root)
+	}
+	if len(res.BlockContainers) == 0 {
+		return nil, fmt.Errorf("block provider does not have checkpoint block %#x", root)
+	}
+	return res.BlockContainers[0].Block, nil
+}
+
+// latestBlockRoot computes the root of the block the state was last processed with. The state root
+// of the latest block header is only filled in on the next slot, so it is set to the root of the
+// state if it is still empty.
+func latestBlockRoot(st *stateTrie.BeaconState) ([32]byte, error) {
+	header := st.LatestBlockHeader()
+	if header == nil {
+		return [32]byte{}, errors.New("checkpoint state has no latest block header")
+	}
+	zeroHash := params.BeaconConfig().ZeroHash
+	if header.StateRoot == nil || bytes.Equal(header.StateRoot, zeroHash[:]) {
+		stateRoot, err := st.HashTreeRoot()
+		if err != nil {
+			return [32]byte{}, err
+		}
+		header.StateRoot = stateRoot[:]
+	}
+	return stateutil.BlockHeaderRoot(header)
+}
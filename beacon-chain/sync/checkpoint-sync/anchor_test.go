@@ -0,0 +1,267 @@
+package checkpointsync
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	dbTest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	"google.golang.org/grpc"
+)
+
+// anchorStateAndBlock returns a state two epochs after genesis and the block it is the post state of.
+func anchorStateAndBlock(t *testing.T) (*stateTrie.BeaconState, *ethpb.SignedBeaconBlock) {
+	st, _ := testutil.DeterministicGenesisState(t, 16)
+	slot := 2*params.BeaconConfig().SlotsPerEpoch + 1
+	body := &ethpb.BeaconBlockBody{}
+	bodyRoot, err := ssz.HashTreeRoot(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parentRoot := []byte("parent-parent-parent-parent-root")
+	if err := st.SetSlot(slot); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.SetLatestBlockHeader(&ethpb.BeaconBlockHeader{
+		Slot:       slot,
+		ParentRoot: parentRoot,
+		StateRoot:  make([]byte, 32),
+		BodyRoot:   bodyRoot[:],
+	}); err != nil {
+		t.Fatal(err)
+	}
+	stateRoot, err := st.HashTreeRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk := &ethpb.SignedBeaconBlock{
+		Block: &ethpb.BeaconBlock{
+			Slot:       slot,
+			ParentRoot: parentRoot,
+			StateRoot:  stateRoot[:],
+			Body:       body,
+		},
+	}
+	return st, blk
+}
+
+func TestSaveAnchor(t *testing.T) {
+	db := dbTest.SetupDB(t)
+	defer dbTest.TeardownDB(t, db)
+	ctx := context.Background()
+	st, blk := anchorStateAndBlock(t)
+	root, err := ssz.HashTreeRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validatorsRoot := [32]byte{'v'}
+	if err := SaveAnchor(ctx, db, st, blk, validatorsRoot); err != nil {
+		t.Fatal(err)
+	}
+	for name, get := range map[string]func(context.Context) (*ethpb.SignedBeaconBlock, error){
+		"genesis": db.GenesisBlock,
+		"anchor":  db.AnchorBlock,
+		"head":    db.HeadBlock,
+	} {
+		retrieved, err := get(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(blk, retrieved) {
+			t.Errorf("Wanted %s block %v, received %v", name, blk, retrieved)
+		}
+	}
+	finalized, err := db.FinalizedCheckpoint(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wanted := &ethpb.Checkpoint{Epoch: 2, Root: root[:]}
+	if !proto.Equal(wanted, finalized) {
+		t.Errorf("Wanted finalized checkpoint %v, received %v", wanted, finalized)
+	}
+	if !db.IsFinalizedBlock(ctx, root) {
+		t.Error("Expected the anchor block to be finalized")
+	}
+	headState, err := db.HeadState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headState == nil || headState.Slot() != blk.Block.Slot {
+		t.Errorf("Expected head state at slot %d, received %v", blk.Block.Slot, headState)
+	}
+	pubkey := st.PubkeyAtIndex(3)
+	if idx, ok, err := db.ValidatorIndex(ctx, pubkey[:]); err != nil || !ok || idx != 3 {
+		t.Errorf("Expected validator index 3, received %d, %v, %v", idx, ok, err)
+	}
+	genesisValidatorsRoot, err := db.GenesisValidatorsRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(genesisValidatorsRoot, validatorsRoot[:]) {
+		t.Errorf("Wanted genesis validators root %#x, received %#x", validatorsRoot, genesisValidatorsRoot)
+	}
+}
+
+func TestSaveAnchor_EpochBoundaryState(t *testing.T) {
+	db := dbTest.SetupDB(t)
+	defer dbTest.TeardownDB(t, db)
+	ctx := context.Background()
+	st, blk := anchorStateAndBlock(t)
+	root, err := ssz.HashTreeRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Advance the state through the empty slots to the start of the next epoch, which fills in
+	// the state root of the latest block header.
+	header := st.LatestBlockHeader()
+	header.StateRoot = blk.Block.StateRoot
+	if err := st.SetLatestBlockHeader(header); err != nil {
+		t.Fatal(err)
+	}
+	boundary := 3 * params.BeaconConfig().SlotsPerEpoch
+	if err := st.SetSlot(boundary); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SaveAnchor(ctx, db, st, blk, [32]byte{'v'}); err != nil {
+		t.Fatal(err)
+	}
+	finalized, err := db.FinalizedCheckpoint(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wanted := &ethpb.Checkpoint{Epoch: 3, Root: root[:]}
+	if !proto.Equal(wanted, finalized) {
+		t.Errorf("Wanted finalized checkpoint %v, received %v", wanted, finalized)
+	}
+	headState, err := db.HeadState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headState == nil || headState.Slot() != boundary {
+		t.Errorf("Expected head state at slot %d, received %v", boundary, headState)
+	}
+}
+
+func TestSaveAnchor_StateRootMismatch(t *testing.T) {
+	db := dbTest.SetupDB(t)
+	defer dbTest.TeardownDB(t, db)
+	st, blk := anchorStateAndBlock(t)
+	blk.Block.StateRoot = make([]byte, 32)
+
+	err := SaveAnchor(context.Background(), db, st, blk, [32]byte{'v'})
+	if err == nil || !strings.Contains(err.Error(), "does not match the latest block header root") {
+		t.Errorf("Expected block mismatch error, received %v", err)
+	}
+	root, err := ssz.HashTreeRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.HasBlock(context.Background(), root) {
+		t.Error("Expected the block not to be saved")
+	}
+}
+
+func TestInitialize_FromFiles(t *testing.T) {
+	db := dbTest.SetupDB(t)
+	defer dbTest.TeardownDB(t, db)
+	ctx := context.Background()
+	dir := path.Join(testutil.TempDir(), "checkpoint_sync")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	st, blk := anchorStateAndBlock(t)
+	stateEnc, err := ssz.Marshal(st.InnerStateUnsafe())
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockEnc, err := ssz.Marshal(blk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{
+		StatePath: path.Join(dir, "state.ssz"),
+		BlockPath: path.Join(dir, "block.ssz"),
+	}
+	if err := ioutil.WriteFile(cfg.StatePath, stateEnc, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(cfg.BlockPath, blockEnc, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Initialize(ctx, db, cfg); err == nil || !strings.Contains(err.Error(), "genesis validators root") {
+		t.Fatalf("Expected the genesis validators root to be required, received %v", err)
+	}
+	cfg.GenesisValidatorsRoot = bytes.Repeat([]byte{'v'}, 32)
+	if err := Initialize(ctx, db, cfg); err != nil {
+		t.Fatal(err)
+	}
+	anchor, err := db.AnchorBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(blk, anchor) {
+		t.Errorf("Wanted anchor block %v, received %v", blk, anchor)
+	}
+	// An initialized database is left untouched, even if the checkpoint could not be loaded.
+	cfg.StatePath = path.Join(dir, "missing.ssz")
+	if err := Initialize(ctx, db, cfg); err != nil {
+		t.Errorf("Expected checkpoint to be ignored, received %v", err)
+	}
+}
+
+type mockBlockProvider struct {
+	ethpb.BeaconChainClient
+	blocks map[[32]byte]*ethpb.SignedBeaconBlock
+}
+
+func (m *mockBlockProvider) ListBlocks(
+	_ context.Context, req *ethpb.ListBlocksRequest, _ ...grpc.CallOption,
+) (*ethpb.ListBlocksResponse, error) {
+	var root [32]byte
+	copy(root[:], req.QueryFilter.(*ethpb.ListBlocksRequest_Root).Root)
+	blk, ok := m.blocks[root]
+	if !ok {
+		return &ethpb.ListBlocksResponse{}, nil
+	}
+	return &ethpb.ListBlocksResponse{
+		BlockContainers: []*ethpb.BeaconBlockContainer{{Block: blk, BlockRoot: root[:]}},
+		TotalSize:       1,
+	}, nil
+}
+
+func TestRequestBlock(t *testing.T) {
+	st, blk := anchorStateAndBlock(t)
+	root, err := ssz.HashTreeRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := &mockBlockProvider{blocks: map[[32]byte]*ethpb.SignedBeaconBlock{root: blk}}
+
+	retrieved, err := requestBlock(context.Background(), provider, st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(blk, retrieved) {
+		t.Errorf("Wanted block %v, received %v", blk, retrieved)
+	}
+
+	provider.blocks = nil
+	if _, err := requestBlock(context.Background(), provider, st); err == nil {
+		t.Error("Expected error when the provider does not have the block")
+	}
+}
@@ -16,6 +16,7 @@ func (r *Service) decodePubsubMessage(msg *pubsub.Message) (proto.Message, error
 	}
 	topic := msg.TopicIDs[0]
 	topic = strings.TrimSuffix(topic, r.p2p.Encoding().ProtocolSuffix())
+	topic = replaceForkDigest(topic)
 	base, ok := p2p.GossipTopicMappings[topic]
 	if !ok {
 		return nil, fmt.Errorf("no message mapped for topic %s", topic)
@@ -26,3 +27,13 @@ func (r *Service) decodePubsubMessage(msg *pubsub.Message) (proto.Message, error
 	}
 	return m, nil
 }
+
+// replaceForkDigest replaces the fork digest in a topic with the format verb it is mapped with.
+func replaceForkDigest(topic string) string {
+	subStrings := strings.Split(topic, "/")
+	if len(subStrings) != 4 {
+		return topic
+	}
+	subStrings[2] = "%x"
+	return strings.Join(subStrings, "/")
+}
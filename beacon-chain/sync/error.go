@@ -11,9 +11,13 @@ import (
 const genericError = "internal service error"
 const rateLimitedError = "rate limited"
 
-var errWrongForkVersion = errors.New("wrong fork version")
+var errWrongForkDigestVersion = errors.New("wrong fork digest version")
 var errInvalidEpoch = errors.New("invalid epoch")
 
+// ErrRateLimited is returned when a peer refused a request because the request quota was
+// exceeded, which is not a fault of the peer.
+var ErrRateLimited = errors.New(rateLimitedError)
+
 var responseCodeSuccess = byte(0x00)
 var responseCodeInvalidRequest = byte(0x01)
 var responseCodeServerError = byte(0x02)
@@ -0,0 +1,273 @@
+package initialsync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	p2ppb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/mathutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+)
+
+// blockRequestTimeout is how long a peer has to serve a range of blocks before the range is
+// requested from another peer.
+const blockRequestTimeout = 10 * time.Second
+
+var (
+	errNoPeersAvailable = errors.New("no peers available to request blocks from")
+	errRequestTimeout   = errors.New("block range request timed out")
+	errIncompleteRange  = errors.New("peer returned fewer blocks than its status claims")
+)
+
+// blocksRequester requests a range of blocks from a peer.
+type blocksRequester func(ctx context.Context, req *p2ppb.BeaconBlocksByRangeRequest, pid peer.ID) ([]*eth.SignedBeaconBlock, error)
+
+// fetchRequest is a range of slots [start, start+count) to fetch the blocks of.
+type fetchRequest struct {
+	start uint64
+	count uint64
+	// excluded are the peers which already failed to serve this range.
+	excluded map[peer.ID]bool
+	// partial is an incomplete response of another peer, which is accepted once a second peer
+	// returns as many blocks, as the missing slots were then most likely skipped.
+	partial *fetchResponse
+}
+
+// fetchResponse holds the blocks of a range, or the error which occurred while fetching them
+// from the peer. The blocks of an incomplete range are kept along with the error.
+type fetchResponse struct {
+	req    *fetchRequest
+	pid    peer.ID
+	blocks []*eth.SignedBeaconBlock
+	err    error
+}
+
+type blocksFetcherConfig struct {
+	p2p            p2p.P2P
+	requestBlocks  blocksRequester
+	headRoot       []byte
+	finalizedEpoch uint64
+	requestTimeout time.Duration
+	excludedPeers  *peerSet
+}
+
+// peerSet is a set of peers which is safe for concurrent use. A nil set is empty.
+type peerSet struct {
+	lock  sync.RWMutex
+	peers map[peer.ID]bool
+}
+
+func newPeerSet() *peerSet {
+	return &peerSet{peers: make(map[peer.ID]bool)}
+}
+
+func (s *peerSet) add(pid peer.ID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.peers[pid] = true
+}
+
+func (s *peerSet) has(pid peer.ID) bool {
+	if s == nil {
+		return false
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.peers[pid]
+}
+
+// without returns the given peers which are not in the set.
+func (s *peerSet) without(pids []peer.ID) []peer.ID {
+	remaining := make([]peer.ID, 0, len(pids))
+	for _, pid := range pids {
+		if !s.has(pid) {
+			remaining = append(remaining, pid)
+		}
+	}
+	return remaining
+}
+
+// blocksFetcher fetches ranges of blocks concurrently, spreading them over the peers at or beyond
+// the finalized epoch which are serving the fewest requests.
+type blocksFetcher struct {
+	p2p            p2p.P2P
+	requestBlocks  blocksRequester
+	headRoot       []byte
+	finalizedEpoch uint64
+	requestTimeout time.Duration
+	excludedPeers  *peerSet
+	randGenerator  *rand.Rand
+	inFlight       map[peer.ID]int
+	lock           sync.Mutex
+	responses      chan *fetchResponse
+}
+
+func newBlocksFetcher(cfg *blocksFetcherConfig) *blocksFetcher {
+	timeout := cfg.requestTimeout
+	if timeout == 0 {
+		timeout = blockRequestTimeout
+	}
+	return &blocksFetcher{
+		p2p:            cfg.p2p,
+		requestBlocks:  cfg.requestBlocks,
+		headRoot:       cfg.headRoot,
+		finalizedEpoch: cfg.finalizedEpoch,
+		requestTimeout: timeout,
+		excludedPeers:  cfg.excludedPeers,
+		randGenerator:  rand.New(rand.NewSource(roughtime.Now().Unix())),
+		inFlight:       make(map[peer.ID]int),
+		responses:      make(chan *fetchResponse),
+	}
+}
+
+// fetch the blocks of the range in the background, the result is sent to the responses channel.
+func (f *blocksFetcher) fetch(ctx context.Context, req *fetchRequest) {
+	go func() {
+		resp := f.handleRequest(ctx, req)
+		select {
+		case f.responses <- resp:
+		case <-ctx.Done():
+		}
+	}()
+}
+
+// handleRequest requests the range from a peer, waiting for a peer to become available if every
+// suitable peer already failed to serve it.
+func (f *blocksFetcher) handleRequest(ctx context.Context, req *fetchRequest) *fetchResponse {
+	pid, err := f.selectPeer(req.excluded)
+	for err == errNoPeersAvailable {
+		log.WithField("start", req.start).Debug("No peers left to request blocks from, waiting")
+		select {
+		case <-time.After(refreshTime):
+		case <-ctx.Done():
+			return &fetchResponse{req: req, err: ctx.Err()}
+		}
+		// Give the peers which failed before another chance, peers which failed too often are
+		// marked as bad and never selected again.
+		req.excluded = nil
+		pid, err = f.selectPeer(req.excluded)
+	}
+	defer f.release(pid)
+
+	ctx, cancel := context.WithTimeout(ctx, f.requestTimeout)
+	defer cancel()
+	start := roughtime.Now()
+	blocks, err := f.requestBlocks(ctx, &p2ppb.BeaconBlocksByRangeRequest{
+		HeadBlockRoot: f.headRoot,
+		StartSlot:     req.start,
+		Count:         req.count,
+		Step:          1,
+	}, pid)
+	if ctx.Err() == context.DeadlineExceeded {
+		err = errRequestTimeout
+	}
+	if err == nil {
+		err = validateBlocks(req, blocks)
+	}
+	if err != nil {
+		return &fetchResponse{req: req, pid: pid, err: err}
+	}
+	if f.incomplete(req, pid, blocks) {
+		return &fetchResponse{req: req, pid: pid, blocks: blocks, err: errIncompleteRange}
+	}
+	if elapsed := roughtime.Since(start).Seconds(); elapsed > 0 {
+		peerThroughputGauge.WithLabelValues(pid.String()).Set(float64(len(blocks)) / elapsed)
+	}
+	return &fetchResponse{req: req, pid: pid, blocks: blocks}
+}
+
+// selectPeer returns a random one of the peers serving the fewest requests, ignoring bad peers,
+// peers excluded from the sync and peers excluded from the range. The selected peer is counted as serving one more request until it is released.
+func (f *blocksFetcher) selectPeer(excluded map[peer.ID]bool) (peer.ID, error) {
+	_, _, pids := f.p2p.Peers().BestFinalized(params.BeaconConfig().MaxPeersToSync, f.finalizedEpoch)
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	var candidates []peer.ID
+	for _, pid := range pids {
+		if excluded[pid] || f.excludedPeers.has(pid) || f.p2p.Peers().IsBad(pid) {
+			continue
+		}
+		if len(candidates) > 0 && f.inFlight[pid] > f.inFlight[candidates[0]] {
+			continue
+		}
+		if len(candidates) > 0 && f.inFlight[pid] < f.inFlight[candidates[0]] {
+			candidates = candidates[:0]
+		}
+		candidates = append(candidates, pid)
+	}
+	if len(candidates) == 0 {
+		return "", errNoPeersAvailable
+	}
+	pid := candidates[f.randGenerator.Intn(len(candidates))]
+	f.inFlight[pid]++
+	return pid, nil
+}
+
+// incomplete returns true if the blocks end before the last slot of the range which the peer claims
+// to have by its status, that is up to its head slot or the start of its finalized epoch.
+func (f *blocksFetcher) incomplete(req *fetchRequest, pid peer.ID, blocks []*eth.SignedBeaconBlock) bool {
+	status, err := f.p2p.Peers().ChainState(pid)
+	if err != nil || status == nil {
+		return false
+	}
+	claimed := mathutil.Max(status.HeadSlot, helpers.StartSlot(status.FinalizedEpoch))
+	if claimed < req.start {
+		return false
+	}
+	last := mathutil.Min(claimed, req.start+req.count-1)
+	return len(blocks) == 0 || blocks[len(blocks)-1].Block.Slot < last
+}
+
+// release the peer once it served a request. Peers serving no request are removed from the
+// count, so that it does not grow with every peer ever selected.
+func (f *blocksFetcher) release(pid peer.ID) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.inFlight[pid]--
+	if f.inFlight[pid] <= 0 {
+		delete(f.inFlight, pid)
+	}
+}
+
+// validateBlocks checks that a response holds at most the requested number of blocks, in
+// increasing slot order within the requested range, where each block is the parent of the next.
+func validateBlocks(req *fetchRequest, blocks []*eth.SignedBeaconBlock) error {
+	if uint64(len(blocks)) > req.count {
+		return fmt.Errorf("received %d blocks, requested at most %d", len(blocks), req.count)
+	}
+	var prevRoot [32]byte
+	for i, blk := range blocks {
+		if blk == nil || blk.Block == nil {
+			return errors.New("received nil block")
+		}
+		if blk.Block.Slot < req.start || blk.Block.Slot >= req.start+req.count {
+			return fmt.Errorf("received block at slot %d outside of the requested range [%d, %d)", blk.Block.Slot, req.start, req.start+req.count)
+		}
+		if i > 0 {
+			if blk.Block.Slot <= blocks[i-1].Block.Slot {
+				return fmt.Errorf("received block at slot %d after block at slot %d", blk.Block.Slot, blocks[i-1].Block.Slot)
+			}
+			if !bytes.Equal(blk.Block.ParentRoot, prevRoot[:]) {
+				return fmt.Errorf("block at slot %d is not a child of the block at slot %d", blk.Block.Slot, blocks[i-1].Block.Slot)
+			}
+		}
+		root, err := ssz.HashTreeRoot(blk.Block)
+		if err != nil {
+			return err
+		}
+		prevRoot = root
+	}
+	return nil
+}
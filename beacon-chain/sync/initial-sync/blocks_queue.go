@@ -0,0 +1,189 @@
+package initialsync
+
+import (
+	"context"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+	prysmsync "github.com/prysmaticlabs/prysm/beacon-chain/sync"
+	"github.com/prysmaticlabs/prysm/shared/mathutil"
+	"github.com/sirupsen/logrus"
+)
+
+// maxPendingRequests is the number of block ranges which may be in flight or waiting to be
+// processed at once, bounding how far the queue fetches ahead of block processing.
+const maxPendingRequests = 8
+
+type blocksQueueConfig struct {
+	p2p                 p2p.P2P
+	requestBlocks       blocksRequester
+	headRoot            []byte
+	finalizedEpoch      uint64
+	startSlot           uint64
+	highestExpectedSlot uint64
+	requestTimeout      time.Duration
+	// excludedPeers are never requested blocks from, such as peers which served invalid blocks.
+	excludedPeers *peerSet
+}
+
+// fetchedBlocks are the blocks of a range in increasing slot order, with the peer which served them.
+type fetchedBlocks struct {
+	pid    peer.ID
+	blocks []*eth.SignedBeaconBlock
+}
+
+// blocksQueue keeps several consecutive ranges of slots in flight with the blocks fetcher and
+// delivers their blocks strictly in slot order, up to and including the highest expected slot.
+// Ranges which time out, fail or are invalid lower the score of the peer and are requested again
+// from another peer. Ranges which end before the slots the peer claims to have are also requested
+// again, and only delivered once two peers returned the same number of blocks.
+type blocksQueue struct {
+	ctx                 context.Context
+	cancel              context.CancelFunc
+	p2p                 p2p.P2P
+	fetcher             *blocksFetcher
+	startSlot           uint64
+	highestExpectedSlot uint64
+	// fetchedBlocks is closed once every range up to the highest expected slot is delivered, or
+	// the queue is stopped.
+	fetchedBlocks chan *fetchedBlocks
+	quit          chan struct{}
+}
+
+func newBlocksQueue(ctx context.Context, cfg *blocksQueueConfig) *blocksQueue {
+	ctx, cancel := context.WithCancel(ctx)
+	return &blocksQueue{
+		ctx:    ctx,
+		cancel: cancel,
+		p2p:    cfg.p2p,
+		fetcher: newBlocksFetcher(&blocksFetcherConfig{
+			p2p:            cfg.p2p,
+			requestBlocks:  cfg.requestBlocks,
+			headRoot:       cfg.headRoot,
+			finalizedEpoch: cfg.finalizedEpoch,
+			requestTimeout: cfg.requestTimeout,
+			excludedPeers:  cfg.excludedPeers,
+		}),
+		startSlot:           cfg.startSlot,
+		highestExpectedSlot: cfg.highestExpectedSlot,
+		fetchedBlocks:       make(chan *fetchedBlocks, maxPendingRequests),
+		quit:                make(chan struct{}),
+	}
+}
+
+// start fetching blocks in the background.
+func (q *blocksQueue) start() {
+	go q.loop()
+}
+
+// stop the queue and wait for it to exit. Requests in flight are cancelled.
+func (q *blocksQueue) stop() {
+	q.cancel()
+	<-q.quit
+}
+
+func (q *blocksQueue) loop() {
+	defer close(q.quit)
+	defer close(q.fetchedBlocks)
+	defer pendingRequestsGauge.Set(0)
+	defer queueDepthGauge.Set(0)
+
+	// Ranges which have been fetched but are waiting for a lower range, by start slot.
+	fetched := make(map[uint64]*fetchResponse)
+	nextRequest := q.startSlot
+	nextDelivery := q.startSlot
+	inFlight := 0
+	for {
+		for inFlight+len(fetched) < maxPendingRequests && nextRequest <= q.highestExpectedSlot {
+			count := mathutil.Min(blockBatchSize, q.highestExpectedSlot-nextRequest+1)
+			q.fetcher.fetch(q.ctx, &fetchRequest{start: nextRequest, count: count})
+			nextRequest += count
+			inFlight++
+		}
+		pendingRequestsGauge.Set(float64(inFlight))
+		queueDepthGauge.Set(float64(len(fetched) + len(q.fetchedBlocks)))
+		if inFlight == 0 && len(fetched) == 0 {
+			return
+		}
+
+		var resp *fetchResponse
+		select {
+		case resp = <-q.fetcher.responses:
+		case <-q.ctx.Done():
+			return
+		}
+		if resp.err == errIncompleteRange {
+			q.checkIncomplete(resp)
+		}
+		if resp.err != nil {
+			if q.ctx.Err() != nil {
+				return
+			}
+			failedRequestsCounter.Inc()
+			switch errors.Cause(resp.err) {
+			case errRequestTimeout:
+				q.p2p.Peers().Record(resp.pid, peers.RPCTimeout)
+			case prysmsync.ErrRateLimited:
+				// The peer enforces its request quota, which is not a bad response.
+			case errIncompleteRange:
+				// Penalized by checkIncomplete once another peer returned more blocks.
+			default:
+				q.p2p.Peers().IncrementBadResponses(resp.pid)
+			}
+			log.WithError(resp.err).WithFields(logrus.Fields{
+				"peer":  resp.pid,
+				"start": resp.req.start,
+				"count": resp.req.count,
+			}).Debug("Block range request failed, requesting it from another peer")
+			if resp.req.excluded == nil {
+				resp.req.excluded = make(map[peer.ID]bool)
+			}
+			resp.req.excluded[resp.pid] = true
+			q.fetcher.fetch(q.ctx, resp.req)
+			continue
+		}
+		inFlight--
+		if partial := resp.req.partial; partial != nil && len(partial.blocks) < len(resp.blocks) {
+			// The peer of the incomplete response withheld blocks of the range.
+			q.p2p.Peers().IncrementBadResponses(partial.pid)
+		}
+		q.p2p.Peers().Record(resp.pid, peers.UsefulContribution)
+		fetched[resp.req.start] = resp
+
+		// Deliver the ranges which directly follow the ranges delivered before.
+		for next, ok := fetched[nextDelivery]; ok; next, ok = fetched[nextDelivery] {
+			delete(fetched, nextDelivery)
+			nextDelivery += next.req.count
+			if len(next.blocks) == 0 {
+				continue
+			}
+			select {
+			case q.fetchedBlocks <- &fetchedBlocks{pid: next.pid, blocks: next.blocks}:
+			case <-q.ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// checkIncomplete compares an incomplete response with the incomplete response of another peer for
+// the same range. The response is accepted if both hold as many blocks, otherwise the peer which
+// returned fewer blocks is penalized and the range is requested again.
+func (q *blocksQueue) checkIncomplete(resp *fetchResponse) {
+	partial := resp.req.partial
+	switch {
+	case partial == nil:
+		resp.req.partial = resp
+	case len(resp.blocks) == len(partial.blocks):
+		resp.err = nil
+	case len(resp.blocks) > len(partial.blocks):
+		q.p2p.Peers().IncrementBadResponses(partial.pid)
+		resp.req.partial = resp
+	default:
+		q.p2p.Peers().IncrementBadResponses(resp.pid)
+	}
+}
@@ -0,0 +1,375 @@
+package initialsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+	p2pt "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
+	prysmsync "github.com/prysmaticlabs/prysm/beacon-chain/sync"
+	p2ppb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+)
+
+// makeChain returns the blocks of a chain with a block at every given slot, by slot.
+func makeChain(t *testing.T, slots []uint64) map[uint64]*eth.SignedBeaconBlock {
+	chain := make(map[uint64]*eth.SignedBeaconBlock, len(slots))
+	parentRoot := make([]byte, 32)
+	for _, slot := range slots {
+		blk := &eth.SignedBeaconBlock{Block: &eth.BeaconBlock{Slot: slot, ParentRoot: parentRoot}}
+		root, err := ssz.HashTreeRoot(blk.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parentRoot = root[:]
+		chain[slot] = blk
+	}
+	return chain
+}
+
+// serveChain returns the blocks of the chain in the requested range.
+func serveChain(chain map[uint64]*eth.SignedBeaconBlock, req *p2ppb.BeaconBlocksByRangeRequest) []*eth.SignedBeaconBlock {
+	var blks []*eth.SignedBeaconBlock
+	for slot := req.StartSlot; slot < req.StartSlot+req.Count; slot++ {
+		if blk, ok := chain[slot]; ok {
+			blks = append(blks, blk)
+		}
+	}
+	return blks
+}
+
+// addPeers adds connected peers finalized at the given epoch to the peer status of the test host.
+func addPeers(p *p2pt.TestP2P, n int, finalizedEpoch uint64) []peer.ID {
+	pids := make([]peer.ID, n)
+	for i := range pids {
+		pids[i] = peer.ID(fmt.Sprintf("peer %d", i))
+		p.Peers().Add(pids[i], nil, network.DirOutbound)
+		p.Peers().SetConnectionState(pids[i], peers.PeerConnected)
+		p.Peers().SetChainState(pids[i], &p2ppb.Status{
+			FinalizedRoot:  []byte("finalized_root"),
+			FinalizedEpoch: finalizedEpoch,
+		})
+	}
+	return pids
+}
+
+// collect reads every block delivered by the queue, failing if they are not in increasing slot order.
+func collect(t *testing.T, queue *blocksQueue) []uint64 {
+	var slots []uint64
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case fetched, ok := <-queue.fetchedBlocks:
+			if !ok {
+				return slots
+			}
+			for _, blk := range fetched.blocks {
+				if len(slots) > 0 && blk.Block.Slot <= slots[len(slots)-1] {
+					t.Fatalf("Received block at slot %d after slot %d", blk.Block.Slot, slots[len(slots)-1])
+				}
+				slots = append(slots, blk.Block.Slot)
+			}
+		case <-timeout:
+			t.Fatal("Timed out waiting for blocks")
+		}
+	}
+}
+
+func TestBlocksQueue_DeliversBlocksInOrder(t *testing.T) {
+	p := p2pt.NewTestP2P(t)
+	addPeers(p, 4, 10)
+	slots := append(makeSequence(1, 100), makeSequence(300, 320)...)
+	chain := makeChain(t, slots)
+
+	queue := newBlocksQueue(context.Background(), &blocksQueueConfig{
+		p2p: p,
+		requestBlocks: func(ctx context.Context, req *p2ppb.BeaconBlocksByRangeRequest, pid peer.ID) ([]*eth.SignedBeaconBlock, error) {
+			// Lower ranges take longer, so ranges complete out of order.
+			time.Sleep(time.Duration(1000-req.StartSlot) * time.Microsecond)
+			return serveChain(chain, req), nil
+		},
+		finalizedEpoch:      10,
+		startSlot:           1,
+		highestExpectedSlot: 320,
+	})
+	queue.start()
+	received := collect(t, queue)
+	queue.stop()
+
+	if len(received) != len(slots) {
+		t.Fatalf("Expected %d blocks, received %d", len(slots), len(received))
+	}
+	for i := range slots {
+		if received[i] != slots[i] {
+			t.Errorf("Expected block at slot %d, received %d", slots[i], received[i])
+		}
+	}
+}
+
+func TestBlocksQueue_RequestsFailedRangesFromOtherPeers(t *testing.T) {
+	p := p2pt.NewTestP2P(t)
+	pids := addPeers(p, 4, 10)
+	failing, slow, invalid, good := pids[0], pids[1], pids[2], pids[3]
+
+	slots := makeSequence(1, 200)
+	chain := makeChain(t, slots)
+	var lock sync.Mutex
+	servedBy := make(map[peer.ID]int)
+	queue := newBlocksQueue(context.Background(), &blocksQueueConfig{
+		p2p: p,
+		requestBlocks: func(ctx context.Context, req *p2ppb.BeaconBlocksByRangeRequest, pid peer.ID) ([]*eth.SignedBeaconBlock, error) {
+			switch pid {
+			case failing:
+				return nil, errors.New("failed")
+			case slow:
+				<-ctx.Done()
+				return nil, ctx.Err()
+			case invalid:
+				blks := serveChain(chain, req)
+				if len(blks) > 1 {
+					blks = append(blks[:1], blks[2:]...)
+				}
+				return blks, nil
+			}
+			lock.Lock()
+			servedBy[pid]++
+			lock.Unlock()
+			return serveChain(chain, req), nil
+		},
+		finalizedEpoch:      10,
+		startSlot:           1,
+		highestExpectedSlot: 200,
+		requestTimeout:      50 * time.Millisecond,
+	})
+	queue.start()
+	received := collect(t, queue)
+	queue.stop()
+
+	// The ranges with a block omitted by the invalid peer must have been requested again.
+	if len(received) != len(slots) {
+		t.Fatalf("Expected %d blocks, received %d", len(slots), len(received))
+	}
+	if servedBy[good] == 0 {
+		t.Error("Expected the good peer to serve the ranges")
+	}
+}
+
+func TestBlocksQueue_DoesNotRequestExcludedPeers(t *testing.T) {
+	p := p2pt.NewTestP2P(t)
+	pids := addPeers(p, 2, 10)
+	excluded := newPeerSet()
+	excluded.add(pids[0])
+
+	slots := makeSequence(1, 200)
+	chain := makeChain(t, slots)
+	queue := newBlocksQueue(context.Background(), &blocksQueueConfig{
+		p2p: p,
+		requestBlocks: func(ctx context.Context, req *p2ppb.BeaconBlocksByRangeRequest, pid peer.ID) ([]*eth.SignedBeaconBlock, error) {
+			if pid == pids[0] {
+				t.Errorf("Requested blocks from excluded peer %s", pid)
+			}
+			return serveChain(chain, req), nil
+		},
+		finalizedEpoch:      10,
+		startSlot:           1,
+		highestExpectedSlot: 200,
+		excludedPeers:       excluded,
+	})
+	queue.start()
+	received := collect(t, queue)
+	queue.stop()
+
+	if len(received) != len(slots) {
+		t.Fatalf("Expected %d blocks, received %d", len(slots), len(received))
+	}
+	if remaining := excluded.without(pids); len(remaining) != 1 || remaining[0] != pids[1] {
+		t.Errorf("Expected only %s to remain, received %v", pids[1], remaining)
+	}
+}
+
+func TestBlocksQueue_DoesNotPenalizeRateLimitingPeers(t *testing.T) {
+	p := p2pt.NewTestP2P(t)
+	pids := addPeers(p, 2, 10)
+	limiting, good := pids[0], pids[1]
+
+	slots := makeSequence(1, 200)
+	chain := makeChain(t, slots)
+	queue := newBlocksQueue(context.Background(), &blocksQueueConfig{
+		p2p: p,
+		requestBlocks: func(ctx context.Context, req *p2ppb.BeaconBlocksByRangeRequest, pid peer.ID) ([]*eth.SignedBeaconBlock, error) {
+			if pid == limiting {
+				return nil, prysmsync.ErrRateLimited
+			}
+			return serveChain(chain, req), nil
+		},
+		finalizedEpoch:      10,
+		startSlot:           1,
+		highestExpectedSlot: 200,
+	})
+	queue.start()
+	received := collect(t, queue)
+	queue.stop()
+
+	if len(received) != len(slots) {
+		t.Fatalf("Expected %d blocks, received %d", len(slots), len(received))
+	}
+	if n, err := p.Peers().BadResponses(limiting); err != nil || n != 0 {
+		t.Errorf("Expected no bad responses for the rate limiting peer, received %d", n)
+	}
+	if n, err := p.Peers().BadResponses(good); err != nil || n != 0 {
+		t.Errorf("Expected no bad responses for the good peer, received %d", n)
+	}
+}
+
+func TestBlocksQueue_RequestsIncompleteRangesFromOtherPeers(t *testing.T) {
+	p := p2pt.NewTestP2P(t)
+	pids := addPeers(p, 2, 10)
+	lazy, good := pids[0], pids[1]
+	for _, pid := range pids {
+		p.Peers().SetChainState(pid, &p2ppb.Status{
+			FinalizedRoot:  []byte("finalized_root"),
+			FinalizedEpoch: 10,
+			HeadSlot:       200,
+		})
+	}
+
+	slots := makeSequence(1, 200)
+	chain := makeChain(t, slots)
+	queue := newBlocksQueue(context.Background(), &blocksQueueConfig{
+		p2p: p,
+		requestBlocks: func(ctx context.Context, req *p2ppb.BeaconBlocksByRangeRequest, pid peer.ID) ([]*eth.SignedBeaconBlock, error) {
+			if pid == lazy {
+				// Claims the range in its status but returns none of its blocks.
+				return nil, nil
+			}
+			time.Sleep(10 * time.Millisecond)
+			return serveChain(chain, req), nil
+		},
+		finalizedEpoch:      10,
+		startSlot:           1,
+		highestExpectedSlot: 200,
+	})
+	queue.start()
+	received := collect(t, queue)
+	queue.stop()
+
+	if len(received) != len(slots) {
+		t.Fatalf("Expected %d blocks, received %d", len(slots), len(received))
+	}
+	if n, err := p.Peers().BadResponses(lazy); err != nil || n == 0 {
+		t.Errorf("Expected the peer returning empty ranges to be penalized, received %d bad responses", n)
+	}
+	if n, err := p.Peers().BadResponses(good); err != nil || n != 0 {
+		t.Errorf("Expected no bad responses for the good peer, received %d", n)
+	}
+}
+
+func TestBlocksQueue_Stop(t *testing.T) {
+	p := p2pt.NewTestP2P(t)
+	addPeers(p, 2, 10)
+	queue := newBlocksQueue(context.Background(), &blocksQueueConfig{
+		p2p: p,
+		requestBlocks: func(ctx context.Context, req *p2ppb.BeaconBlocksByRangeRequest, pid peer.ID) ([]*eth.SignedBeaconBlock, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+		finalizedEpoch:      10,
+		startSlot:           1,
+		highestExpectedSlot: 320,
+	})
+	queue.start()
+
+	done := make(chan struct{})
+	go func() {
+		queue.stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out stopping the queue")
+	}
+	if _, ok := <-queue.fetchedBlocks; ok {
+		t.Error("Expected no blocks to be delivered")
+	}
+}
+
+func TestBlocksFetcher_ReleaseRemovesIdlePeers(t *testing.T) {
+	p := p2pt.NewTestP2P(t)
+	addPeers(p, 1, 10)
+	fetcher := newBlocksFetcher(&blocksFetcherConfig{p2p: p, finalizedEpoch: 10})
+
+	first, err := fetcher.selectPeer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := fetcher.selectPeer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetcher.release(first)
+	if fetcher.inFlight[second] != 1 {
+		t.Errorf("Expected 1 request in flight, received %d", fetcher.inFlight[second])
+	}
+	fetcher.release(second)
+	if len(fetcher.inFlight) != 0 {
+		t.Errorf("Expected no peers to be tracked, received %d", len(fetcher.inFlight))
+	}
+}
+
+func TestValidateBlocks(t *testing.T) {
+	chain := makeChain(t, []uint64{1, 2, 4, 5})
+	req := &fetchRequest{start: 1, count: 4}
+	tests := []struct {
+		name   string
+		blocks []*eth.SignedBeaconBlock
+		errMsg string
+	}{
+		{
+			name:   "valid",
+			blocks: []*eth.SignedBeaconBlock{chain[1], chain[2], chain[4]},
+		},
+		{
+			name: "empty",
+		},
+		{
+			name:   "too many blocks",
+			blocks: []*eth.SignedBeaconBlock{chain[1], chain[2], chain[4], chain[4], chain[4]},
+			errMsg: "requested at most 4",
+		},
+		{
+			name:   "outside of range",
+			blocks: []*eth.SignedBeaconBlock{chain[4], chain[5]},
+			errMsg: "outside of the requested range",
+		},
+		{
+			name:   "out of order",
+			blocks: []*eth.SignedBeaconBlock{chain[2], chain[1]},
+			errMsg: "after block at slot",
+		},
+		{
+			name:   "not linked",
+			blocks: []*eth.SignedBeaconBlock{chain[1], chain[4]},
+			errMsg: "is not a child",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBlocks(req, tt.blocks)
+			if tt.errMsg == "" && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if tt.errMsg != "" && (err == nil || !strings.Contains(err.Error(), tt.errMsg)) {
+				t.Errorf("Expected error containing %q, received %v", tt.errMsg, err)
+			}
+		})
+	}
+}
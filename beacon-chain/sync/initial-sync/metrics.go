@@ -0,0 +1,40 @@
+package initialsync
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	blocksPerSecondGauge = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "initial_sync_blocks_per_second",
+			Help: "The rate at which blocks are processed during initial sync.",
+		},
+	)
+	queueDepthGauge = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "initial_sync_queue_depth",
+			Help: "The number of fetched block ranges waiting to be processed in order.",
+		},
+	)
+	pendingRequestsGauge = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "initial_sync_pending_requests",
+			Help: "The number of block range requests in flight.",
+		},
+	)
+	failedRequestsCounter = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "initial_sync_failed_requests_total",
+			Help: "Count of block range requests which timed out, failed or returned invalid blocks.",
+		},
+	)
+	peerThroughputGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "initial_sync_peer_blocks_per_second",
+			Help: "The rate at which a peer served the blocks of its last block range request.",
+		},
+		[]string{"peer"},
+	)
+)
@@ -4,19 +4,19 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"math/rand"
-	"sort"
-	"sync/atomic"
 	"time"
 
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/paulbellamy/ratecounter"
 	"github.com/pkg/errors"
 	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
 	blockfeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/block"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
 	prysmsync "github.com/prysmaticlabs/prysm/beacon-chain/sync"
 	p2ppb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
@@ -30,11 +30,17 @@ const blockBatchSize = 64
 const counterSeconds = 20
 const refreshTime = 6 * time.Second
 
+// maxPassesWithoutProgress is the number of times the blocks up to the finalized epoch are
+// fetched without the head advancing before syncing to the head of the chain instead.
+const maxPassesWithoutProgress = 3
+
 // Round Robin sync looks at the latest peer statuses and syncs with the highest
 // finalized peer.
 //
 // Step 1 - Sync to finalized epoch.
-// Sync with peers of lowest finalized root with epoch greater than head state.
+// Sync with peers of lowest finalized root with epoch greater than head state. A blocks queue
+// keeps several ranges of blocks in flight across different peers and delivers them in slot
+// order, so a single slow or faulty peer does not stall the sync.
 //
 // Step 2 - Sync to head from finalized epoch.
 // Using the finalized root as the head_block_root and the epoch start slot
@@ -56,12 +62,14 @@ func (s *Service) roundRobinSync(genesis time.Time) error {
 	}
 
 	counter := ratecounter.NewRateCounter(counterSeconds * time.Second)
-	randGenerator := rand.New(rand.NewSource(time.Now().Unix()))
-	var lastEmptyRequests int
 	highestFinalizedSlot := helpers.StartSlot(s.highestFinalizedEpoch() + 1)
+	passesWithoutProgress := 0
+	// Peers which served an invalid block are not synced from again.
+	excludedPeers := newPeerSet()
 	// Step 1 - Sync to end of finalized epoch.
-	for s.chain.HeadSlot() < highestFinalizedSlot {
+	for s.chain.HeadSlot() < highestFinalizedSlot && passesWithoutProgress < maxPassesWithoutProgress {
 		root, finalizedEpoch, peers := s.p2p.Peers().BestFinalized(params.BeaconConfig().MaxPeersToSync, helpers.SlotToEpoch(s.chain.HeadSlot()))
+		peers = excludedPeers.without(peers)
 		if len(peers) == 0 {
 			log.Warn("No peers; waiting for reconnect")
 			time.Sleep(refreshTime)
@@ -72,167 +80,34 @@ func (s *Service) roundRobinSync(genesis time.Time) error {
 			highestFinalizedSlot = helpers.StartSlot(finalizedEpoch + 1)
 		}
 
-		// shuffle peers to prevent a bad peer from
-		// stalling sync with invalid blocks
-		randGenerator.Shuffle(len(peers), func(i, j int) {
-			peers[i], peers[j] = peers[j], peers[i]
-		})
-
-		// request a range of blocks to be requested from multiple peers.
-		// Example:
-		//   - number of peers = 4
-		//   - range of block slots is 64...128
-		//   Four requests will be spread across the peers using step argument to distribute the load
-		//   i.e. the first peer is asked for block 64, 68, 72... while the second peer is asked for
-		//   65, 69, 73... and so on for other peers.
-		var request func(start uint64, step uint64, count uint64, peers []peer.ID, remainder int) ([]*eth.SignedBeaconBlock, error)
-		request = func(start uint64, step uint64, count uint64, peers []peer.ID, remainder int) ([]*eth.SignedBeaconBlock, error) {
-			if len(peers) == 0 {
-				return nil, errors.WithStack(errors.New("no peers left to request blocks"))
-			}
-			var p2pRequestCount int32
-			errChan := make(chan error)
-			blocksChan := make(chan []*eth.SignedBeaconBlock)
-
-			// Handle block large block ranges of skipped slots.
-			start += count * uint64(lastEmptyRequests*len(peers))
-			if count <= 1 {
-				step = 1
-			}
-
-			// Short circuit start far exceeding the highest finalized epoch in some infinite loop.
-			if start > highestFinalizedSlot {
-				return nil, errors.Errorf("attempted to ask for a start slot of %d which is greater than the next highest slot of %d", start, highestFinalizedSlot)
-			}
-
-			atomic.AddInt32(&p2pRequestCount, int32(len(peers)))
-			for i, pid := range peers {
-				if ctx.Err() != nil {
-					return nil, ctx.Err()
-				}
-				start := start + uint64(i)*step
-				step := step * uint64(len(peers))
-				count := mathutil.Min(count, (helpers.StartSlot(finalizedEpoch+1)-start)/step)
-				// If the count was divided by an odd number of peers, there will be some blocks
-				// missing from the first requests so we accommodate that scenario.
-				if i < remainder {
-					count++
-				}
-				// asking for no blocks may cause the client to hang. This should never happen and
-				// the peer may return an error anyway, but we'll ask for at least one block.
-				if count == 0 {
-					count = 1
-				}
-				req := &p2ppb.BeaconBlocksByRangeRequest{
-					HeadBlockRoot: root,
-					StartSlot:     start,
-					Count:         count,
-					Step:          step,
-				}
-
-				go func(i int, pid peer.ID) {
-					defer func() {
-						zeroIfIAmTheLast := atomic.AddInt32(&p2pRequestCount, -1)
-						if zeroIfIAmTheLast == 0 {
-							close(blocksChan)
-						}
-					}()
-
-					resp, err := s.requestBlocks(ctx, req, pid)
-					if err != nil {
-						// fail over to other peers by splitting this requests evenly across them.
-						ps := append(peers[:i], peers[i+1:]...)
-						log.WithError(err).WithField(
-							"remaining peers",
-							len(ps),
-						).WithField(
-							"peer",
-							pid.Pretty(),
-						).Debug("Request failed, trying to round robin with other peers")
-						if len(ps) == 0 {
-							errChan <- errors.WithStack(errors.New("no peers left to request blocks"))
-							return
-						}
-						resp, err = request(start, step, count/uint64(len(ps)) /*count*/, ps, int(count)%len(ps) /*remainder*/)
-						if err != nil {
-							errChan <- err
-							return
-						}
-					}
-					log.WithField("peer", pid).WithField("count", len(resp)).Debug("Received blocks")
-					blocksChan <- resp
-				}(i, pid)
-			}
-
-			var unionRespBlocks []*eth.SignedBeaconBlock
-			for {
-				select {
-				case err := <-errChan:
-					return nil, err
-				case resp, ok := <-blocksChan:
-					if ok {
-						//  if this synchronization becomes a bottleneck:
-						//    think about immediately allocating space for all peers in unionRespBlocks,
-						//    and write without synchronization
-						unionRespBlocks = append(unionRespBlocks, resp...)
-					} else {
-						return unionRespBlocks, nil
-					}
-				}
-			}
-		}
-		startBlock := s.chain.HeadSlot() + 1
-		skippedBlocks := blockBatchSize * uint64(lastEmptyRequests*len(peers))
-		if startBlock+skippedBlocks > helpers.StartSlot(finalizedEpoch+1) {
+		headSlot := s.chain.HeadSlot()
+		if headSlot >= helpers.StartSlot(finalizedEpoch+1) {
 			log.WithField("finalizedEpoch", finalizedEpoch).Debug("Requested block range is greater than the finalized epoch")
 			break
 		}
-
-		blocks, err := request(
-			s.chain.HeadSlot()+1, // start
-			1,                    // step
-			blockBatchSize,       // count
-			peers,                // peers
-			0,                    // remainder
-		)
+		queue := newBlocksQueue(ctx, &blocksQueueConfig{
+			p2p:                 s.p2p,
+			requestBlocks:       s.requestBlocks,
+			headRoot:            root,
+			finalizedEpoch:      finalizedEpoch,
+			startSlot:           headSlot + 1,
+			highestExpectedSlot: helpers.StartSlot(finalizedEpoch + 1),
+			excludedPeers:       excludedPeers,
+		})
+		queue.start()
+		err := s.processFetchedBlocks(ctx, genesis, queue, peers, excludedPeers, counter)
+		queue.stop()
 		if err != nil {
-			log.WithError(err).Error("Round robing sync request failed")
-			continue
+			return err
 		}
 
-		// Since the block responses were appended to the list, we must sort them in order to
-		// process sequentially. This method doesn't make much wall time compared to block
-		// processing.
-		sort.Slice(blocks, func(i, j int) bool {
-			return blocks[i].Block.Slot < blocks[j].Block.Slot
-		})
-
-		for _, blk := range blocks {
-			s.logSyncStatus(genesis, blk.Block, peers, counter)
-			if !s.db.HasBlock(ctx, bytesutil.ToBytes32(blk.Block.ParentRoot)) {
-				log.Debugf("Beacon node doesn't have a block in db with root %#x", blk.Block.ParentRoot)
-				continue
-			}
-			s.blockNotifier.BlockFeed().Send(&feed.Event{
-				Type: blockfeed.ReceivedBlock,
-				Data: &blockfeed.ReceivedBlockData{SignedBlock: blk},
-			})
-			if featureconfig.Get().InitSyncNoVerify {
-				if err := s.chain.ReceiveBlockNoVerify(ctx, blk); err != nil {
-					return err
-				}
-			} else {
-				if err := s.chain.ReceiveBlockNoPubsubForkchoice(ctx, blk); err != nil {
-					return err
-				}
-			}
-		}
-		// If there were no blocks in the last request range, increment the counter so the same
-		// range isn't requested again on the next loop as the headSlot didn't change.
-		if len(blocks) == 0 {
-			lastEmptyRequests++
+		// Blocks which do not descend from the head are skipped, for example when a peer omitted
+		// blocks of the range before them or served an invalid block. Those ranges are fetched
+		// again in the next pass.
+		if s.chain.HeadSlot() > headSlot {
+			passesWithoutProgress = 0
 		} else {
-			lastEmptyRequests = 0
+			passesWithoutProgress++
 		}
 	}
 
@@ -270,7 +145,8 @@ func (s *Service) roundRobinSync(genesis time.Time) error {
 
 		resp, err := s.requestBlocks(ctx, req, best)
 		if err != nil {
-			return err
+			log.WithError(err).Error("Failed to request blocks, exiting init sync")
+			return nil
 		}
 
 		for _, blk := range resp {
@@ -288,6 +164,49 @@ func (s *Service) roundRobinSync(genesis time.Time) error {
 	return nil
 }
 
+// processFetchedBlocks receives the blocks delivered by the queue in slot order until the queue
+// is done, skipping blocks whose parent is unknown. A block which is invalid, or descends from a
+// bad block, is recorded as an invalid block of the peer which served it. The peer is excluded
+// from the sync and the rest of its range is skipped, to be fetched again from another peer in
+// the next pass. Only failures of the node itself are returned.
+func (s *Service) processFetchedBlocks(ctx context.Context, genesis time.Time, queue *blocksQueue, syncingPeers []peer.ID, excludedPeers *peerSet, counter *ratecounter.RateCounter) error {
+	for fetched := range queue.fetchedBlocks {
+		for _, blk := range fetched.blocks {
+			s.logSyncStatus(genesis, blk.Block, syncingPeers, counter)
+			if s.chain.IsBadBlock(bytesutil.ToBytes32(blk.Block.ParentRoot)) {
+				s.p2p.Peers().Record(fetched.pid, peers.InvalidBlock)
+				excludedPeers.add(fetched.pid)
+				log.WithField("peer", fetched.pid).Debugf("Skipping blocks descending from bad block %#x", bytesutil.Trunc(blk.Block.ParentRoot))
+				break
+			}
+			if !s.db.HasBlock(ctx, bytesutil.ToBytes32(blk.Block.ParentRoot)) {
+				log.WithField("peer", fetched.pid).Debugf("Beacon node doesn't have a block in db with root %#x", blk.Block.ParentRoot)
+				continue
+			}
+			s.blockNotifier.BlockFeed().Send(&feed.Event{
+				Type: blockfeed.ReceivedBlock,
+				Data: &blockfeed.ReceivedBlockData{SignedBlock: blk},
+			})
+			var err error
+			if featureconfig.Get().InitSyncNoVerify {
+				err = s.chain.ReceiveBlockNoVerify(ctx, blk)
+			} else {
+				err = s.chain.ReceiveBlockNoPubsubForkchoice(ctx, blk)
+			}
+			if err != nil {
+				if !blockchain.IsInvalidBlock(err) {
+					return err
+				}
+				s.p2p.Peers().Record(fetched.pid, peers.InvalidBlock)
+				excludedPeers.add(fetched.pid)
+				log.WithError(err).WithField("peer", fetched.pid).Debug("Skipping the rest of a range with an invalid block")
+				break
+			}
+		}
+	}
+	return nil
+}
+
 // requestBlocks by range to a specific peer.
 func (s *Service) requestBlocks(ctx context.Context, req *p2ppb.BeaconBlocksByRangeRequest, pid peer.ID) ([]*eth.SignedBeaconBlock, error) {
 	if s.blocksRateLimiter.Remaining(pid.String()) < int64(req.Count) {
@@ -302,11 +221,16 @@ func (s *Service) requestBlocks(ctx context.Context, req *p2ppb.BeaconBlocksByRa
 		"step":  req.Step,
 		"head":  fmt.Sprintf("%#x", req.HeadBlockRoot),
 	}).Debug("Requesting blocks")
-	stream, err := s.p2p.Send(ctx, req, pid)
+	stream, err := s.p2p.Send(ctx, req, p2p.RPCBlocksByRangeTopic, pid)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to send request to peer")
 	}
 	defer stream.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := stream.SetReadDeadline(deadline); err != nil {
+			return nil, errors.Wrap(err, "failed to set stream read deadline")
+		}
+	}
 
 	resp := make([]*eth.SignedBeaconBlock, 0, req.Count)
 	for {
@@ -341,6 +265,7 @@ func (s *Service) highestFinalizedEpoch() uint64 {
 func (s *Service) logSyncStatus(genesis time.Time, blk *eth.BeaconBlock, syncingPeers []peer.ID, counter *ratecounter.RateCounter) {
 	counter.Incr(1)
 	rate := float64(counter.Rate()) / counterSeconds
+	blocksPerSecondGauge.Set(rate)
 	if rate == 0 {
 		rate = 1
 	}
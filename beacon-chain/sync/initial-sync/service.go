@@ -27,6 +27,7 @@ type blockchainService interface {
 	blockchain.HeadFetcher
 	ClearCachedStates()
 	blockchain.FinalizationFetcher
+	blockchain.BadBlockChecker
 }
 
 const (
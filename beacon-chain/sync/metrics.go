@@ -13,6 +13,34 @@ var (
 		},
 		[]string{"topic"},
 	)
+	messageReceivedBytesCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "p2p_message_received_bytes_total",
+			Help: "Count of bytes of the messages received.",
+		},
+		[]string{"topic"},
+	)
+	messageAcceptedCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "p2p_message_accepted_total",
+			Help: "Count of messages that passed validation and were propagated.",
+		},
+		[]string{"topic"},
+	)
+	messageRejectedCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "p2p_message_rejected_total",
+			Help: "Count of messages that failed validation, penalizing the peer which propagated them.",
+		},
+		[]string{"topic"},
+	)
+	messageIgnoredCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "p2p_message_ignored_total",
+			Help: "Count of messages that were dropped without penalizing the peer which propagated them, such as duplicate or stale messages.",
+		},
+		[]string{"topic"},
+	)
 	messageFailedValidationCounter = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "p2p_message_failed_validation_total",
@@ -27,6 +55,13 @@ var (
 		},
 		[]string{"topic"},
 	)
+	rateLimitedCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "p2p_rpc_rate_limited_total",
+			Help: "Count of RPC requests rejected because the peer exceeded its rate limit.",
+		},
+		[]string{"topic"},
+	)
 	numberOfTimesResyncedCounter = promauto.NewCounter(
 		prometheus.CounterOpts{
 			Name: "number_of_times_resynced",
@@ -51,6 +86,31 @@ var (
 			Help: "Count the number of times attestation recovered because of missing block",
 		},
 	)
+	batchVerificationSize = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "p2p_batch_verification_signatures",
+			Help:    "Number of signatures of gossip messages verified in a single batch.",
+			Buckets: []float64{1, 2, 4, 8, 16, 32, 64, 128, 256},
+		},
+	)
+	batchVerificationDuration = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name: "p2p_batch_verification_duration_seconds",
+			Help: "Time taken to verify a batch of gossip message signatures.",
+		},
+	)
+	batchVerificationLatency = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name: "p2p_batch_verification_latency_seconds",
+			Help: "Time a gossip message waits for the verification of its signatures, including batch collection.",
+		},
+	)
+	batchVerificationFallbackCounter = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "p2p_batch_verification_fallback_total",
+			Help: "Count the number of times a batch failed verification and its signatures were verified one by one.",
+		},
+	)
 	numberOfAttsNotRecovered = promauto.NewCounter(
 		prometheus.CounterOpts{
 			Name: "beacon_attestations_not_recovered_total",
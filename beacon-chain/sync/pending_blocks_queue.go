@@ -62,6 +62,21 @@ func (r *Service) processPendingBlocks(ctx context.Context) error {
 			continue
 		}
 		r.pendingQueueLock.RUnlock()
+
+		blkRoot, err := ssz.HashTreeRoot(b.Block)
+		if err != nil {
+			traceutil.AnnotateError(span, err)
+			span.End()
+			return err
+		}
+
+		// Drop the block without requesting its parent if it descends from a bad block.
+		if r.hasBadBlock(b.Block, blkRoot) {
+			r.removePendingBlock(uint64(s), blkRoot)
+			span.End()
+			continue
+		}
+
 		inPendingQueue := r.seenPendingBlocks[bytesutil.ToBytes32(b.Block.ParentRoot)]
 
 		inDB := r.db.HasBlock(ctx, bytesutil.ToBytes32(b.Block.ParentRoot))
@@ -102,6 +117,9 @@ func (r *Service) processPendingBlocks(ctx context.Context) error {
 		if err := r.chain.ReceiveBlockNoPubsub(ctx, b); err != nil {
 			log.Errorf("Could not process block from slot %d: %v", b.Block.Slot, err)
 			traceutil.AnnotateError(span, err)
+			r.removePendingBlock(uint64(s), blkRoot)
+			span.End()
+			continue
 		}
 
 		// Broadcasting the block again once a node is able to process it.
@@ -109,17 +127,7 @@ func (r *Service) processPendingBlocks(ctx context.Context) error {
 			log.WithError(err).Error("Failed to broadcast block")
 		}
 
-		blkRoot, err := ssz.HashTreeRoot(b.Block)
-		if err != nil {
-			traceutil.AnnotateError(span, err)
-			span.End()
-			return err
-		}
-
-		r.pendingQueueLock.Lock()
-		delete(r.slotToPendingBlocks, uint64(s))
-		delete(r.seenPendingBlocks, blkRoot)
-		r.pendingQueueLock.Unlock()
+		r.removePendingBlock(uint64(s), blkRoot)
 
 		log.WithFields(logrus.Fields{
 			"slot":      s,
@@ -180,6 +188,14 @@ func (r *Service) validatePendingSlots() error {
 	return nil
 }
 
+// removePendingBlock removes the block of the slot from the pending queue.
+func (r *Service) removePendingBlock(slot uint64, blkRoot [32]byte) {
+	r.pendingQueueLock.Lock()
+	defer r.pendingQueueLock.Unlock()
+	delete(r.slotToPendingBlocks, slot)
+	delete(r.seenPendingBlocks, blkRoot)
+}
+
 func (r *Service) clearPendingSlots() {
 	r.pendingQueueLock.Lock()
 	defer r.pendingQueueLock.Unlock()
@@ -0,0 +1,122 @@
+package sync
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/kevinms/leakybucket-go"
+	libp2pcore "github.com/libp2p/go-libp2p-core"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+)
+
+const (
+	defaultRequestsPerSecond = 2
+	defaultRequestsBurst     = 10
+	defaultBlocksPerSecond   = 32
+	defaultBlocksBurstFactor = 10
+)
+
+// RateLimits are the per-peer quotas of the req/resp protocols. Zero values are replaced by the
+// defaults.
+type RateLimits struct {
+	// RequestsPerSecond and RequestsBurst limit the requests a peer makes on each protocol.
+	RequestsPerSecond int
+	RequestsBurst     int
+	// BlocksPerSecond and BlocksBurstFactor limit the blocks a peer requests by range or by root.
+	BlocksPerSecond   int
+	BlocksBurstFactor int
+}
+
+// rateLimiter holds a token bucket per peer for every registered protocol, limiting the requests
+// made by the peer, and a token bucket per peer for the blocks it requests. Peers exceeding their
+// quota are sent a rate limited error response and penalized.
+type rateLimiter struct {
+	p2p               p2p.P2P
+	requestsPerSecond float64
+	requestsBurst     int64
+	requests          map[string]*leakybucket.Collector
+	requestsLock      sync.RWMutex
+	blocks            *leakybucket.Collector
+}
+
+func newRateLimiter(p2p p2p.P2P, limits *RateLimits) *rateLimiter {
+	cfg := RateLimits{}
+	if limits != nil {
+		cfg = *limits
+	}
+	if cfg.RequestsPerSecond <= 0 {
+		cfg.RequestsPerSecond = defaultRequestsPerSecond
+	}
+	if cfg.RequestsBurst <= 0 {
+		cfg.RequestsBurst = defaultRequestsBurst
+	}
+	if cfg.BlocksPerSecond <= 0 {
+		cfg.BlocksPerSecond = defaultBlocksPerSecond
+	}
+	if cfg.BlocksBurstFactor <= 0 {
+		cfg.BlocksBurstFactor = defaultBlocksBurstFactor
+	}
+	return &rateLimiter{
+		p2p:               p2p,
+		requestsPerSecond: float64(cfg.RequestsPerSecond),
+		requestsBurst:     int64(cfg.RequestsBurst),
+		requests:          make(map[string]*leakybucket.Collector),
+		blocks: leakybucket.NewCollector(
+			float64(cfg.BlocksPerSecond),
+			int64(cfg.BlocksPerSecond*cfg.BlocksBurstFactor),
+			false, /* deleteEmptyBuckets */
+		),
+	}
+}
+
+// register the protocol of the topic, giving each peer its own quota of requests on it.
+func (l *rateLimiter) register(topic string) {
+	l.requestsLock.Lock()
+	defer l.requestsLock.Unlock()
+	l.requests[topic] = leakybucket.NewCollector(l.requestsPerSecond, l.requestsBurst, false /* deleteEmptyBuckets */)
+}
+
+// validateRequest takes a request from the quota of the peer on the topic, or rejects the request
+// if the peer has none left.
+func (l *rateLimiter) validateRequest(stream libp2pcore.Stream, topic string) error {
+	l.requestsLock.RLock()
+	collector, ok := l.requests[topic]
+	l.requestsLock.RUnlock()
+	if !ok {
+		return nil
+	}
+	return l.take(collector, stream, topic, 1)
+}
+
+// validateBlocks takes the number of blocks requested from the quota of the peer, or rejects the
+// request if the peer has too few left.
+func (l *rateLimiter) validateBlocks(stream libp2pcore.Stream, topic string, count uint64) error {
+	return l.take(l.blocks, stream, topic, int64(count))
+}
+
+func (l *rateLimiter) take(collector *leakybucket.Collector, stream libp2pcore.Stream, topic string, amount int64) error {
+	pid := stream.Conn().RemotePeer()
+	if amount <= collector.Remaining(pid.String()) {
+		collector.Add(pid.String(), amount)
+		return nil
+	}
+
+	rateLimitedCounter.WithLabelValues(topic).Inc()
+	log := log.WithField("topic", topic).WithField("peer", pid.Pretty())
+	log.Debug("Peer exceeded its rate limit")
+	buf := bytes.NewBuffer([]byte{responseCodeInvalidRequest})
+	if _, err := l.p2p.Encoding().EncodeWithLength(buf, []byte(rateLimitedError)); err != nil {
+		log.WithError(err).Error("Failed to generate a response error")
+	} else if _, err := stream.Write(buf.Bytes()); err != nil {
+		log.WithError(err).Error("Failed to write to stream")
+	}
+
+	l.p2p.Peers().IncrementBadResponses(pid)
+	if l.p2p.Peers().IsBad(pid) {
+		log.Debug("Disconnecting bad peer")
+		if err := l.p2p.Disconnect(pid); err != nil {
+			log.WithError(err).Error("Failed to disconnect peer")
+		}
+	}
+	return ErrRateLimited
+}
@@ -0,0 +1,113 @@
+package sync
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	libp2pcore "github.com/libp2p/go-libp2p-core"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	p2ptest "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
+	pb "github.com/prysmaticlabs/prysm/proto/testing"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestRateLimiter_LimitsRequestsPerPeer(t *testing.T) {
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+	r := &Service{
+		ctx:         context.Background(),
+		p2p:         p1,
+		rateLimiter: newRateLimiter(p1, &RateLimits{RequestsPerSecond: 1, RequestsBurst: 2}),
+	}
+
+	topic := "/testing/foobar/1"
+	var lock sync.Mutex
+	handled := 0
+	handler := func(ctx context.Context, msg interface{}, stream libp2pcore.Stream) error {
+		lock.Lock()
+		handled++
+		lock.Unlock()
+		_, err := stream.Write([]byte{responseCodeSuccess})
+		return err
+	}
+	r.registerRPC(topic, &pb.TestSimpleMessage{}, handler)
+
+	request := func() (uint8, string) {
+		stream, err := p2.Host.NewStream(context.Background(), p1.PeerID(), protocol.ID(topic+p2.Encoding().ProtocolSuffix()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stream.Close()
+		if _, err := p2.Encoding().EncodeWithLength(stream, &pb.TestSimpleMessage{Foo: []byte("foo")}); err != nil {
+			t.Fatal(err)
+		}
+		code, errMsg, err := ReadStatusCode(stream, p2.Encoding())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code, errMsg
+	}
+
+	for i := 0; i < 2; i++ {
+		if code, errMsg := request(); code != responseCodeSuccess {
+			t.Fatalf("Expected request %d to succeed, received code %d: %s", i, code, errMsg)
+		}
+	}
+	code, errMsg := request()
+	if code != responseCodeInvalidRequest || errMsg != rateLimitedError {
+		t.Errorf("Expected request to be rate limited, received code %d: %s", code, errMsg)
+	}
+	lock.Lock()
+	if handled != 2 {
+		t.Errorf("Expected 2 requests to be handled, handled %d", handled)
+	}
+	lock.Unlock()
+
+	score, err := p1.Peers().Score(p2.PeerID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score >= 0 {
+		t.Errorf("Expected rate limited peer to be penalized, received score %v", score)
+	}
+}
+
+func TestRateLimiter_LimitsBlocksPerPeer(t *testing.T) {
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+	limiter := newRateLimiter(p1, &RateLimits{BlocksPerSecond: 1, BlocksBurstFactor: 64})
+
+	pcl := protocol.ID("/testing")
+	var wg sync.WaitGroup
+	wg.Add(1)
+	p2.Host.SetStreamHandler(pcl, func(stream network.Stream) {
+		defer wg.Done()
+		code, errMsg, err := ReadStatusCode(stream, p2.Encoding())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != responseCodeInvalidRequest || errMsg != rateLimitedError {
+			t.Errorf("Expected rate limited response, received code %d: %s", code, errMsg)
+		}
+	})
+	stream, err := p1.Host.NewStream(context.Background(), p2.PeerID(), pcl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := limiter.validateBlocks(stream, p2p.RPCBlocksByRangeTopic, 48); err != nil {
+		t.Fatalf("Expected blocks within the quota to be allowed, received %v", err)
+	}
+	if err := limiter.validateBlocks(stream, p2p.RPCBlocksByRootTopic, 48); err != ErrRateLimited {
+		t.Errorf("Expected blocks beyond the quota to be rate limited, received %v", err)
+	}
+	if testutil.WaitTimeout(&wg, time.Second) {
+		t.Fatal("Did not receive rate limited response in 1 second")
+	}
+}
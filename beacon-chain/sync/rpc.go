@@ -7,6 +7,7 @@ import (
 
 	libp2pcore "github.com/libp2p/go-libp2p-core"
 	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/roughtime"
 	"github.com/prysmaticlabs/prysm/shared/traceutil"
@@ -31,30 +32,43 @@ type rpcHandler func(context.Context, interface{}, libp2pcore.Stream) error
 // registerRPCHandlers for p2p RPC.
 func (r *Service) registerRPCHandlers() {
 	r.registerRPC(
-		"/eth2/beacon_chain/req/status/1",
+		p2p.RPCStatusTopic,
 		&pb.Status{},
 		r.statusRPCHandler,
 	)
 	r.registerRPC(
-		"/eth2/beacon_chain/req/goodbye/1",
+		p2p.RPCGoodByeTopic,
 		new(uint64),
 		r.goodbyeRPCHandler,
 	)
 	r.registerRPC(
-		"/eth2/beacon_chain/req/beacon_blocks_by_range/1",
+		p2p.RPCBlocksByRangeTopic,
 		&pb.BeaconBlocksByRangeRequest{},
 		r.beaconBlocksByRangeRPCHandler,
 	)
 	r.registerRPC(
-		"/eth2/beacon_chain/req/beacon_blocks_by_root/1",
+		p2p.RPCBlocksByRootTopic,
 		[][32]byte{},
 		r.beaconBlocksRootRPCHandler,
 	)
+	r.registerRPC(
+		p2p.RPCPingTopic,
+		new(uint64),
+		r.pingHandler,
+	)
+	r.registerRPC(
+		p2p.RPCMetaDataTopic,
+		nil,
+		r.metaDataHandler,
+	)
 }
 
-// registerRPC for a given topic with an expected protobuf message type.
+// registerRPC for a given topic with an expected protobuf message type, or nil if requests on the
+// topic have no payload. Requests from peers which exceeded their quota of requests on the topic
+// are rejected before being decoded.
 func (r *Service) registerRPC(topic string, base interface{}, handle rpcHandler) {
 	topic += r.p2p.Encoding().ProtocolSuffix()
+	r.rateLimiter.register(topic)
 	log := log.WithField("topic", topic)
 	r.p2p.SetStreamHandler(topic, func(stream network.Stream) {
 		ctx, cancel := context.WithTimeout(context.Background(), ttfbTimeout)
@@ -74,11 +88,22 @@ func (r *Service) registerRPC(topic string, base interface{}, handle rpcHandler)
 		// Increment message received counter.
 		messageReceivedCounter.WithLabelValues(topic).Inc()
 
-		// Given we have an input argument that can be pointer or [][32]byte, this gives us
+		if err := r.rateLimiter.validateRequest(stream, topic); err != nil {
+			traceutil.AnnotateError(span, err)
+			return
+		}
+
+		// Given we have an input argument that can be nil, pointer or [][32]byte, this gives us
 		// a way to check for its reflect.Kind and based on the result, we can decode
 		// accordingly.
 		t := reflect.TypeOf(base)
-		if t.Kind() == reflect.Ptr {
+		if t == nil {
+			if err := handle(ctx, nil, stream); err != nil {
+				messageFailedProcessingCounter.WithLabelValues(topic).Inc()
+				log.WithError(err).Warn("Failed to handle p2p RPC")
+				traceutil.AnnotateError(span, err)
+			}
+		} else if t.Kind() == reflect.Ptr {
 			msg := reflect.New(t.Elem())
 			if err := r.p2p.Encoding().DecodeWithLength(stream, msg.Interface()); err != nil {
 				log.WithError(err).Warn("Failed to decode stream message")
@@ -87,7 +112,7 @@ func (r *Service) registerRPC(topic string, base interface{}, handle rpcHandler)
 			}
 			if err := handle(ctx, msg.Interface(), stream); err != nil {
 				messageFailedProcessingCounter.WithLabelValues(topic).Inc()
-				if err != errWrongForkVersion {
+				if err != errWrongForkDigestVersion {
 					log.WithError(err).Warn("Failed to handle p2p RPC")
 				}
 				traceutil.AnnotateError(span, err)
@@ -101,7 +126,7 @@ func (r *Service) registerRPC(topic string, base interface{}, handle rpcHandler)
 			}
 			if err := handle(ctx, msg.Elem().Interface(), stream); err != nil {
 				messageFailedProcessingCounter.WithLabelValues(topic).Inc()
-				if err != errWrongForkVersion {
+				if err != errWrongForkDigestVersion {
 					log.WithError(err).Warn("Failed to handle p2p RPC")
 				}
 				traceutil.AnnotateError(span, err)
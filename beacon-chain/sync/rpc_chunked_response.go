@@ -47,6 +47,9 @@ func readResponseChunk(stream libp2pcore.Stream, p2p p2p.P2P, to interface{}) er
 		return err
 	}
 
+	if code == responseCodeInvalidRequest && errMsg == rateLimitedError {
+		return ErrRateLimited
+	}
 	if code != 0 {
 		return errors.New(errMsg)
 	}
@@ -0,0 +1,54 @@
+package sync
+
+import (
+	"context"
+	"time"
+
+	libp2pcore "github.com/libp2p/go-libp2p-core"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+)
+
+// metaDataHandler responds to a metadata request, which has no payload, with the metadata of the
+// local peer.
+func (r *Service) metaDataHandler(ctx context.Context, msg interface{}, stream libp2pcore.Stream) error {
+	defer stream.Close()
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	setRPCStreamDeadlines(stream)
+
+	if _, err := stream.Write([]byte{responseCodeSuccess}); err != nil {
+		return err
+	}
+	_, err := r.p2p.Encoding().EncodeWithLength(stream, r.p2p.Metadata())
+	return err
+}
+
+// sendMetaDataRequest requests the metadata of the peer, storing it in the peer status.
+func (r *Service) sendMetaDataRequest(ctx context.Context, id peer.ID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	stream, err := r.p2p.Send(ctx, nil, p2p.RPCMetaDataTopic, id)
+	if err != nil {
+		return err
+	}
+
+	code, errMsg, err := ReadStatusCode(stream, r.p2p.Encoding())
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		r.p2p.Peers().IncrementBadResponses(id)
+		return errors.New(errMsg)
+	}
+
+	msg := &pb.MetaData{}
+	if err := r.p2p.Encoding().DecodeWithLength(stream, msg); err != nil {
+		return err
+	}
+	r.p2p.Peers().SetMetadata(id, msg)
+	return nil
+}
@@ -0,0 +1,105 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	libp2pcore "github.com/libp2p/go-libp2p-core"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/shared/runutil"
+)
+
+// pingInterval is how often the connected peers are pinged to detect dead connections and changes
+// of their metadata.
+const pingInterval = 30 * time.Second
+
+// maintainPeerPings by periodically pinging the connected peers, disconnecting the peers which
+// do not respond.
+func (r *Service) maintainPeerPings() {
+	runutil.RunEvery(r.ctx, pingInterval, func() {
+		for _, pid := range r.p2p.Peers().Connected() {
+			go func(id peer.ID) {
+				if err := r.sendPingRequest(r.ctx, id); err != nil {
+					log.WithField("peer", id).WithError(err).Debug("Failed to ping peer, disconnecting")
+					if err := r.p2p.Disconnect(id); err != nil {
+						log.WithError(err).Error("Failed to disconnect from peer")
+					}
+				}
+			}(pid)
+		}
+	})
+}
+
+// pingHandler reads the incoming ping rpc message from the peer and responds with the sequence
+// number of our metadata. The metadata of the peer is requested if its sequence number changed.
+func (r *Service) pingHandler(ctx context.Context, msg interface{}, stream libp2pcore.Stream) error {
+	defer stream.Close()
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	setRPCStreamDeadlines(stream)
+
+	m, ok := msg.(*uint64)
+	if !ok {
+		return fmt.Errorf("wrong message type for ping, got %T, wanted *uint64", msg)
+	}
+	if _, err := stream.Write([]byte{responseCodeSuccess}); err != nil {
+		return err
+	}
+	seq := r.p2p.MetadataSeq()
+	if _, err := r.p2p.Encoding().EncodeWithLength(stream, &seq); err != nil {
+		return err
+	}
+
+	pid := stream.Conn().RemotePeer()
+	if r.metadataOutdated(pid, *m) {
+		go func() {
+			if err := r.sendMetaDataRequest(r.ctx, pid); err != nil {
+				log.WithField("peer", pid).WithError(err).Debug("Failed to request peer metadata")
+			}
+		}()
+	}
+	return nil
+}
+
+// sendPingRequest pings the peer with the sequence number of our metadata, requesting the
+// metadata of the peer if the sequence number it responds with changed.
+func (r *Service) sendPingRequest(ctx context.Context, id peer.ID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	seq := r.p2p.MetadataSeq()
+	stream, err := r.p2p.Send(ctx, &seq, p2p.RPCPingTopic, id)
+	if err != nil {
+		return err
+	}
+
+	code, errMsg, err := ReadStatusCode(stream, r.p2p.Encoding())
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		r.p2p.Peers().IncrementBadResponses(id)
+		return errors.New(errMsg)
+	}
+
+	msg := new(uint64)
+	if err := r.p2p.Encoding().DecodeWithLength(stream, msg); err != nil {
+		return err
+	}
+	if r.metadataOutdated(id, *msg) {
+		if err := r.sendMetaDataRequest(ctx, id); err != nil {
+			log.WithField("peer", id).WithError(err).Debug("Failed to request peer metadata")
+		}
+	}
+	return nil
+}
+
+// metadataOutdated reports whether the metadata we know of the peer is missing or differs from the
+// sequence number the peer reported.
+func (r *Service) metadataOutdated(id peer.ID, seq uint64) bool {
+	md, err := r.p2p.Peers().Metadata(id)
+	return err != nil || md == nil || md.SeqNumber != seq
+}
@@ -0,0 +1,107 @@
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	p2ptest "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+)
+
+// newTestPeerService returns a sync service for the test peer serving pings and metadata.
+func newTestPeerService(p *p2ptest.TestP2P) *Service {
+	r := &Service{
+		ctx:         context.Background(),
+		p2p:         p,
+		rateLimiter: newRateLimiter(p, nil),
+	}
+	r.registerRPC(p2p.RPCPingTopic, new(uint64), r.pingHandler)
+	r.registerRPC(p2p.RPCMetaDataTopic, nil, r.metaDataHandler)
+	return r
+}
+
+func TestPingRPCHandler_RequestsChangedMetadata(t *testing.T) {
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+	p1.LocalMetadata.SeqNumber = 3
+	p1.LocalMetadata.Attnets[0] = 0x01
+	p2.LocalMetadata.SeqNumber = 5
+	p2.LocalMetadata.Attnets[1] = 0x01
+	newTestPeerService(p1)
+	r2 := newTestPeerService(p2)
+
+	if err := r2.sendPingRequest(context.Background(), p1.PeerID()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The pinging peer requests the metadata of the peer it did not know yet.
+	md, err := p2.Peers().Metadata(p1.PeerID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md == nil || md.SeqNumber != 3 || md.Attnets[0] != 0x01 {
+		t.Errorf("Unexpected metadata of peer: %v", md)
+	}
+
+	// The pinged peer requests the metadata of the pinging peer in the background.
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if md, err := p1.Peers().Metadata(p2.PeerID()); err == nil && md != nil {
+			if md.SeqNumber != 5 || md.Attnets[1] != 0x01 {
+				t.Errorf("Unexpected metadata of peer: %v", md)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("Did not receive metadata of pinging peer within 1 sec")
+}
+
+func TestPingRPCHandler_KnownMetadataNotRequested(t *testing.T) {
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+	p1.LocalMetadata.SeqNumber = 3
+	newTestPeerService(p1)
+	r2 := newTestPeerService(p2)
+
+	// Metadata with the same sequence number is up to date, so it is not requested again.
+	p2.Peers().SetMetadata(p1.PeerID(), &pb.MetaData{SeqNumber: 3, Attnets: []byte{0xff, 0, 0, 0, 0, 0, 0, 0}})
+	if err := r2.sendPingRequest(context.Background(), p1.PeerID()); err != nil {
+		t.Fatal(err)
+	}
+	md, err := p2.Peers().Metadata(p1.PeerID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md.Attnets[0] != 0xff {
+		t.Errorf("Expected known metadata to be kept, received %v", md)
+	}
+
+	p1.LocalMetadata.SeqNumber = 4
+	if err := r2.sendPingRequest(context.Background(), p1.PeerID()); err != nil {
+		t.Fatal(err)
+	}
+	md, err = p2.Peers().Metadata(p1.PeerID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md.SeqNumber != 4 || md.Attnets[0] != 0 {
+		t.Errorf("Expected changed metadata to be requested, received %v", md)
+	}
+}
+
+func TestSendPingRequest_NoResponse(t *testing.T) {
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+	r2 := newTestPeerService(p2)
+
+	// The peer does not serve pings.
+	if err := r2.sendPingRequest(context.Background(), p1.PeerID()); err == nil {
+		t.Error("Expected error pinging peer")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/roughtime"
@@ -23,6 +24,8 @@ func (r *Service) maintainPeerStatuses() {
 	// Run twice per epoch.
 	interval := time.Duration(params.BeaconConfig().SecondsPerSlot*params.BeaconConfig().SlotsPerEpoch/2) * time.Second
 	runutil.RunEvery(r.ctx, interval, func() {
+		// Peers ahead of us in finality are preferred when pruning connections.
+		r.p2p.Peers().SetLocalFinalizedEpoch(r.chain.FinalizedCheckpt().Epoch)
 		for _, pid := range r.p2p.Peers().Connected() {
 			go func(id peer.ID) {
 				// If the status hasn't been updated in the recent interval time.
@@ -77,14 +80,18 @@ func (r *Service) sendRPCStatusRequest(ctx context.Context, id peer.ID) error {
 		return err
 	}
 
+	forkDigest, err := r.forkDigest()
+	if err != nil {
+		return err
+	}
 	resp := &pb.Status{
-		HeadForkVersion: r.chain.CurrentFork().CurrentVersion,
-		FinalizedRoot:   r.chain.FinalizedCheckpt().Root,
-		FinalizedEpoch:  r.chain.FinalizedCheckpt().Epoch,
-		HeadRoot:        headRoot,
-		HeadSlot:        r.chain.HeadSlot(),
+		ForkDigest:     forkDigest[:],
+		FinalizedRoot:  r.chain.FinalizedCheckpt().Root,
+		FinalizedEpoch: r.chain.FinalizedCheckpt().Epoch,
+		HeadRoot:       headRoot,
+		HeadSlot:       r.chain.HeadSlot(),
 	}
-	stream, err := r.p2p.Send(ctx, resp, id)
+	stream, err := r.p2p.Send(ctx, resp, p2p.RPCStatusTopic, id)
 	if err != nil {
 		return err
 	}
@@ -117,7 +124,7 @@ func (r *Service) removeDisconnectedPeerStatus(ctx context.Context, pid peer.ID)
 }
 
 // statusRPCHandler reads the incoming Status RPC from the peer and responds with our version of a status message.
-// This handler will disconnect any peer that does not match our fork version.
+// This handler will disconnect any peer that does not match our fork digest.
 func (r *Service) statusRPCHandler(ctx context.Context, msg interface{}, stream libp2pcore.Stream) error {
 	defer stream.Close()
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
@@ -127,7 +134,7 @@ func (r *Service) statusRPCHandler(ctx context.Context, msg interface{}, stream
 	m := msg.(*pb.Status)
 
 	if err := r.validateStatusMessage(m, stream); err != nil {
-		log.WithField("peer", stream.Conn().RemotePeer()).Debug("Invalid fork version from peer")
+		log.WithField("peer", stream.Conn().RemotePeer()).Debug("Invalid fork digest from peer")
 		r.p2p.Peers().IncrementBadResponses(stream.Conn().RemotePeer())
 		originalErr := err
 		resp, err := r.generateErrorResponse(responseCodeInvalidRequest, err.Error())
@@ -135,7 +142,7 @@ func (r *Service) statusRPCHandler(ctx context.Context, msg interface{}, stream
 			log.WithError(err).Error("Failed to generate a response error")
 		} else {
 			if _, err := stream.Write(resp); err != nil {
-				// The peer may already be ignoring us, as we disagree on fork version, so log this as debug only.
+				// The peer may already be ignoring us, as we disagree on fork digest, so log this as debug only.
 				log.WithError(err).Debug("Failed to write to stream")
 			}
 		}
@@ -155,12 +162,16 @@ func (r *Service) statusRPCHandler(ctx context.Context, msg interface{}, stream
 		return err
 	}
 
+	forkDigest, err := r.forkDigest()
+	if err != nil {
+		return err
+	}
 	resp := &pb.Status{
-		HeadForkVersion: r.chain.CurrentFork().CurrentVersion,
-		FinalizedRoot:   r.chain.FinalizedCheckpt().Root,
-		FinalizedEpoch:  r.chain.FinalizedCheckpt().Epoch,
-		HeadRoot:        headRoot,
-		HeadSlot:        r.chain.HeadSlot(),
+		ForkDigest:     forkDigest[:],
+		FinalizedRoot:  r.chain.FinalizedCheckpt().Root,
+		FinalizedEpoch: r.chain.FinalizedCheckpt().Epoch,
+		HeadRoot:       headRoot,
+		HeadSlot:       r.chain.HeadSlot(),
 	}
 
 	if _, err := stream.Write([]byte{responseCodeSuccess}); err != nil {
@@ -172,8 +183,12 @@ func (r *Service) statusRPCHandler(ctx context.Context, msg interface{}, stream
 }
 
 func (r *Service) validateStatusMessage(msg *pb.Status, stream network.Stream) error {
-	if !bytes.Equal(params.BeaconConfig().GenesisForkVersion, msg.HeadForkVersion) {
-		return errWrongForkVersion
+	forkDigest, err := r.forkDigest()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(forkDigest[:], msg.ForkDigest) {
+		return errWrongForkDigestVersion
 	}
 	genesis := r.chain.GenesisTime()
 	maxEpoch := slotutil.EpochsSinceGenesis(genesis)
@@ -34,7 +34,7 @@ func TestHelloRPCHandler_Disconnects_OnForkVersionMismatch(t *testing.T) {
 		t.Error("Expected peers to be connected")
 	}
 
-	r := &Service{p2p: p1}
+	r := &Service{p2p: p1, chain: &mock.ChainService{}}
 	pcl := protocol.ID("/testing")
 
 	var wg sync.WaitGroup
@@ -48,9 +48,9 @@ func TestHelloRPCHandler_Disconnects_OnForkVersionMismatch(t *testing.T) {
 		if code == 0 {
 			t.Error("Expected a non-zero code")
 		}
-		if errMsg != errWrongForkVersion.Error() {
-			t.Logf("Received error string len %d, wanted error string len %d", len(errMsg), len(errWrongForkVersion.Error()))
-			t.Errorf("Received unexpected message response in the stream: %s. Wanted %s.", errMsg, errWrongForkVersion.Error())
+		if errMsg != errWrongForkDigestVersion.Error() {
+			t.Logf("Received error string len %d, wanted error string len %d", len(errMsg), len(errWrongForkDigestVersion.Error()))
+			t.Errorf("Received unexpected message response in the stream: %s. Wanted %s.", errMsg, errWrongForkDigestVersion.Error())
 		}
 	})
 
@@ -59,9 +59,9 @@ func TestHelloRPCHandler_Disconnects_OnForkVersionMismatch(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = r.statusRPCHandler(context.Background(), &pb.Status{HeadForkVersion: []byte("fake")}, stream1)
-	if err != errWrongForkVersion {
-		t.Errorf("Expected error %v, got %v", errWrongForkVersion, err)
+	err = r.statusRPCHandler(context.Background(), &pb.Status{ForkDigest: []byte("fake")}, stream1)
+	if err != errWrongForkDigestVersion {
+		t.Errorf("Expected error %v, got %v", errWrongForkDigestVersion, err)
 	}
 
 	if testutil.WaitTimeout(&wg, 1*time.Second) {
@@ -117,6 +117,10 @@ func TestHelloRPCHandler_ReturnsHelloMessage(t *testing.T) {
 			},
 		},
 	}
+	digest, err := r.forkDigest()
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	// Setup streams
 	pcl := protocol.ID("/testing")
@@ -130,11 +134,11 @@ func TestHelloRPCHandler_ReturnsHelloMessage(t *testing.T) {
 			t.Fatal(err)
 		}
 		expected := &pb.Status{
-			HeadForkVersion: params.BeaconConfig().GenesisForkVersion,
-			HeadSlot:        genesisState.Slot(),
-			HeadRoot:        headRoot[:],
-			FinalizedEpoch:  5,
-			FinalizedRoot:   finalizedRoot[:],
+			ForkDigest:     digest[:],
+			HeadSlot:       genesisState.Slot(),
+			HeadRoot:       headRoot[:],
+			FinalizedEpoch: 5,
+			FinalizedRoot:  finalizedRoot[:],
 		}
 		if !proto.Equal(out, expected) {
 			t.Errorf("Did not receive expected message. Got %+v wanted %+v", out, expected)
@@ -145,7 +149,7 @@ func TestHelloRPCHandler_ReturnsHelloMessage(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = r.statusRPCHandler(context.Background(), &pb.Status{HeadForkVersion: params.BeaconConfig().GenesisForkVersion}, stream1)
+	err = r.statusRPCHandler(context.Background(), &pb.Status{ForkDigest: digest[:]}, stream1)
 	if err != nil {
 		t.Errorf("Unxpected error: %v", err)
 	}
@@ -179,6 +183,10 @@ func TestHandshakeHandlers_Roundtrip(t *testing.T) {
 		},
 		ctx: context.Background(),
 	}
+	digest, err := r.forkDigest()
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	r.Start()
 
@@ -194,7 +202,7 @@ func TestHandshakeHandlers_Roundtrip(t *testing.T) {
 		}
 		log.WithField("status", out).Warn("received status")
 
-		resp := &pb.Status{HeadSlot: 100, HeadForkVersion: params.BeaconConfig().GenesisForkVersion}
+		resp := &pb.Status{HeadSlot: 100, ForkDigest: digest[:]}
 
 		if _, err := stream.Write([]byte{responseCodeSuccess}); err != nil {
 			t.Fatal(err)
@@ -288,6 +296,10 @@ func TestStatusRPCRequest_RequestSent(t *testing.T) {
 		},
 		ctx: context.Background(),
 	}
+	digest, err := r.forkDigest()
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	// Setup streams
 	pcl := protocol.ID("/eth2/beacon_chain/req/status/1/ssz")
@@ -300,11 +312,11 @@ func TestStatusRPCRequest_RequestSent(t *testing.T) {
 			t.Fatal(err)
 		}
 		expected := &pb.Status{
-			HeadForkVersion: params.BeaconConfig().GenesisForkVersion,
-			HeadSlot:        genesisState.Slot(),
-			HeadRoot:        headRoot[:],
-			FinalizedEpoch:  5,
-			FinalizedRoot:   finalizedRoot[:],
+			ForkDigest:     digest[:],
+			HeadSlot:       genesisState.Slot(),
+			HeadRoot:       headRoot[:],
+			FinalizedEpoch: 5,
+			FinalizedRoot:  finalizedRoot[:],
 		}
 		if !proto.Equal(out, expected) {
 			t.Errorf("Did not receive expected message. Got %+v wanted %+v", out, expected)
@@ -378,11 +390,11 @@ func TestStatusRPCRequest_BadPeerHandshake(t *testing.T) {
 			t.Fatal(err)
 		}
 		expected := &pb.Status{
-			HeadForkVersion: []byte{1, 1, 1, 1},
-			HeadSlot:        genesisState.Slot(),
-			HeadRoot:        headRoot[:],
-			FinalizedEpoch:  5,
-			FinalizedRoot:   finalizedRoot[:],
+			ForkDigest:     []byte{1, 1, 1, 1},
+			HeadSlot:       genesisState.Slot(),
+			HeadRoot:       headRoot[:],
+			FinalizedEpoch: 5,
+			FinalizedRoot:  finalizedRoot[:],
 		}
 		if _, err := stream.Write([]byte{responseCodeSuccess}); err != nil {
 			log.WithError(err).Error("Failed to write to stream")
@@ -41,8 +41,9 @@ func expectResetStream(t *testing.T, r *Service, stream network.Stream) {
 func TestRegisterRPC_ReceivesValidMessage(t *testing.T) {
 	p2p := p2ptest.NewTestP2P(t)
 	r := &Service{
-		ctx: context.Background(),
-		p2p: p2p,
+		ctx:         context.Background(),
+		p2p:         p2p,
+		rateLimiter: newRateLimiter(p2p, nil),
 	}
 
 	var wg sync.WaitGroup
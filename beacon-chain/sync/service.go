@@ -4,7 +4,6 @@ import (
 	"context"
 	"sync"
 
-	"github.com/kevinms/leakybucket-go"
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
@@ -16,18 +15,18 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations"
 	"github.com/prysmaticlabs/prysm/beacon-chain/operations/voluntaryexits"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
 	"github.com/prysmaticlabs/prysm/shared"
+	"github.com/prysmaticlabs/prysm/shared/params"
 )
 
 var _ = shared.Service(&Service{})
 
-const allowedBlocksPerSecond = 32.0
-const allowedBlocksBurst = 10 * allowedBlocksPerSecond
-
 // Config to set up the regular sync service.
 type Config struct {
 	P2P                 p2p.P2P
 	DB                  db.NoHeadAccessDatabase
+	StateGen            *stategen.State
 	AttPool             attestations.Pool
 	ExitPool            *voluntaryexits.Pool
 	Chain               blockchainService
@@ -35,6 +34,7 @@ type Config struct {
 	StateNotifier       statefeed.Notifier
 	BlockNotifier       blockfeed.Notifier
 	AttestationNotifier operation.Notifier
+	RateLimits          *RateLimits
 }
 
 // This defines the interface for interacting with block chain service
@@ -45,6 +45,8 @@ type blockchainService interface {
 	blockchain.ForkFetcher
 	blockchain.AttestationReceiver
 	blockchain.TimeFetcher
+	blockchain.GenesisFetcher
+	blockchain.BadBlockChecker
 }
 
 // NewRegularSync service.
@@ -54,6 +56,7 @@ func NewRegularSync(cfg *Config) *Service {
 		ctx:                  ctx,
 		cancel:               cancel,
 		db:                   cfg.DB,
+		stateGen:             cfg.StateGen,
 		p2p:                  cfg.P2P,
 		attPool:              cfg.AttPool,
 		exitPool:             cfg.ExitPool,
@@ -65,7 +68,9 @@ func NewRegularSync(cfg *Config) *Service {
 		blkRootToPendingAtts: make(map[[32]byte][]*ethpb.AggregateAttestationAndProof),
 		stateNotifier:        cfg.StateNotifier,
 		blockNotifier:        cfg.BlockNotifier,
-		blocksRateLimiter:    leakybucket.NewCollector(allowedBlocksPerSecond, allowedBlocksBurst, false /* deleteEmptyBuckets */),
+		rateLimiter:          newRateLimiter(cfg.P2P, cfg.RateLimits),
+		signatureChan:        make(chan *signatureVerifier, verifierLimit),
+		subnetSearches:       make(map[uint64]bool),
 	}
 
 	r.registerRPCHandlers()
@@ -81,6 +86,7 @@ type Service struct {
 	cancel               context.CancelFunc
 	p2p                  p2p.P2P
 	db                   db.NoHeadAccessDatabase
+	stateGen             *stategen.State
 	attPool              attestations.Pool
 	exitPool             *voluntaryexits.Pool
 	chain                blockchainService
@@ -94,8 +100,11 @@ type Service struct {
 	validateBlockLock    sync.RWMutex
 	stateNotifier        statefeed.Notifier
 	blockNotifier        blockfeed.Notifier
-	blocksRateLimiter    *leakybucket.Collector
+	rateLimiter          *rateLimiter
 	attestationNotifier  operation.Notifier
+	signatureChan        chan *signatureVerifier
+	subnetSearches       map[uint64]bool
+	subnetSearchLock     sync.Mutex
 }
 
 // Start the regular sync service.
@@ -105,7 +114,9 @@ func (r *Service) Start() {
 	r.processPendingBlocksQueue()
 	r.processPendingAttsQueue()
 	r.maintainPeerStatuses()
+	r.maintainPeerPings()
 	r.resyncIfBehind()
+	go r.verifierRoutine()
 }
 
 // Stop the regular sync service.
@@ -130,6 +141,12 @@ func (r *Service) Status() error {
 	return nil
 }
 
+// forkDigest returns the digest of the current fork of the chain, which identifies the chain in
+// the gossip topics and status messages exchanged with peers.
+func (r *Service) forkDigest() ([4]byte, error) {
+	return helpers.ComputeForkDigest(params.BeaconConfig().GenesisForkVersion, r.chain.GenesisValidatorRoot())
+}
+
 // Checker defines a struct which can verify whether a node is currently
 // synchronizing a chain with the rest of peers in the network.
 type Checker interface {
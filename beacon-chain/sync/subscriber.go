@@ -9,17 +9,31 @@ import (
 	"github.com/gogo/protobuf/proto"
 	"github.com/libp2p/go-libp2p-core/peer"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
 	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
 	"github.com/prysmaticlabs/prysm/shared/messagehandler"
+	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/prysmaticlabs/prysm/shared/sliceutil"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
 	"github.com/prysmaticlabs/prysm/shared/traceutil"
 	"go.opencensus.io/trace"
 )
 
 const pubsubMessageTimeout = 30 * time.Second
 
+const (
+	// subnetLookaheadSlots is how many slots ahead of an attestation the node subscribes to the
+	// subnet of its committee, giving the subnet mesh time to form.
+	subnetLookaheadSlots = 2
+	// minPeersPerSubnet is the number of peers on a subscribed subnet below which more peers are
+	// searched for.
+	minPeersPerSubnet = 4
+)
+
 // subHandler represents handler for a given subscription.
 type subHandler func(context.Context, proto.Message) error
 
@@ -61,55 +75,61 @@ func (r *Service) registerSubscribers() {
 				return
 			}
 		}
+		// Topics carry the fork digest of the chain, so subscriptions wait until chain start.
+		r.subscribe(
+			"/eth2/%x/beacon_block",
+			r.validateBeaconBlockPubSub,
+			r.beaconBlockSubscriber,
+		)
+		r.subscribe(
+			"/eth2/%x/beacon_aggregate_and_proof",
+			r.validateAggregateAndProof,
+			r.beaconAggregateProofSubscriber,
+		)
+		r.subscribe(
+			"/eth2/%x/voluntary_exit",
+			r.validateVoluntaryExit,
+			r.voluntaryExitSubscriber,
+		)
+		r.subscribe(
+			"/eth2/%x/proposer_slashing",
+			r.validateProposerSlashing,
+			r.proposerSlashingSubscriber,
+		)
+		r.subscribe(
+			"/eth2/%x/attester_slashing",
+			r.validateAttesterSlashing,
+			r.attesterSlashingSubscriber,
+		)
+		r.subscribeDynamicWithSubnets(
+			"/eth2/%x/committee_index%d_beacon_attestation",
+			r.validateCommitteeIndexBeaconAttestation,   /* validator */
+			r.committeeIndexBeaconAttestationSubscriber, /* message handler */
+		)
 	}()
-	r.subscribe(
-		"/eth2/beacon_block",
-		r.validateBeaconBlockPubSub,
-		r.beaconBlockSubscriber,
-	)
-	r.subscribe(
-		"/eth2/beacon_aggregate_and_proof",
-		r.validateAggregateAndProof,
-		r.beaconAggregateProofSubscriber,
-	)
-	r.subscribe(
-		"/eth2/voluntary_exit",
-		r.validateVoluntaryExit,
-		r.voluntaryExitSubscriber,
-	)
-	r.subscribe(
-		"/eth2/proposer_slashing",
-		r.validateProposerSlashing,
-		r.proposerSlashingSubscriber,
-	)
-	r.subscribe(
-		"/eth2/attester_slashing",
-		r.validateAttesterSlashing,
-		r.attesterSlashingSubscriber,
-	)
-	r.subscribeDynamic(
-		"/eth2/committee_index%d_beacon_attestation",
-		r.currentCommitteeIndex,                     /* determineSubsLen */
-		r.validateCommitteeIndexBeaconAttestation,   /* validator */
-		r.committeeIndexBeaconAttestationSubscriber, /* message handler */
-	)
 }
 
 // subscribe to a given topic with a given validator and subscription handler.
-// The base protobuf message is used to initialize new messages for decoding.
-func (r *Service) subscribe(topic string, validator pubsub.Validator, handle subHandler) *pubsub.Subscription {
-	base := p2p.GossipTopicMappings[topic]
+// The base protobuf message is used to initialize new messages for decoding. The topic format is
+// expected to take the fork digest.
+func (r *Service) subscribe(topicFormat string, validator pubsub.Validator, handle subHandler) *pubsub.Subscription {
+	base := p2p.GossipTopicMappings[topicFormat]
 	if base == nil {
-		panic(fmt.Sprintf("%s is not mapped to any message in GossipTopicMappings", topic))
+		panic(fmt.Sprintf("%s is not mapped to any message in GossipTopicMappings", topicFormat))
+	}
+	forkDigest, err := r.forkDigest()
+	if err != nil {
+		log.WithError(err).Error("Could not compute fork digest")
+		return nil
 	}
-	return r.subscribeWithBase(base, topic, validator, handle)
+	return r.subscribeWithBase(base, fmt.Sprintf(topicFormat, forkDigest), validator, handle)
 }
 
 func (r *Service) subscribeWithBase(base proto.Message, topic string, validator pubsub.Validator, handle subHandler) *pubsub.Subscription {
 	topic += r.p2p.Encoding().ProtocolSuffix()
 	log := log.WithField("topic", topic)
 
-	if err := r.p2p.PubSub().RegisterTopicValidator(wrapAndReportValidation(topic, validator)); err != nil {
+	if err := r.p2p.PubSub().RegisterTopicValidator(r.wrapAndReportValidation(topic, validator)); err != nil {
 		log.WithError(err).Error("Failed to register validator")
 	}
 
@@ -161,6 +181,10 @@ func (r *Service) subscribeWithBase(base proto.Message, topic string, validator
 				log.WithError(err).Error("Subscription next failed")
 				return
 			}
+			// A cancelled subscription returns no message.
+			if msg == nil {
+				return
+			}
 
 			if msg.ReceivedFrom == r.p2p.PeerID() {
 				continue
@@ -174,63 +198,153 @@ func (r *Service) subscribeWithBase(base proto.Message, topic string, validator
 	return sub
 }
 
+// ignoredMessage is set as the validator data of a message which is not propagated without being
+// invalid, such as a message which was already seen, is outside of the slots currently accepted or
+// could not be validated locally.
+type ignoredMessage struct{}
+
+// ignore marks the message as ignored rather than rejected, returning false for the validator to
+// return. The message is nil when the validation steps run for an object which was not received
+// over gossip.
+func ignore(msg *pubsub.Message) bool {
+	if msg != nil {
+		msg.ValidatorData = ignoredMessage{}
+	}
+	return false
+}
+
 // Wrap the pubsub validator with a metric monitoring function. This function increments the
-// appropriate counter if the particular message fails to validate.
-func wrapAndReportValidation(topic string, v pubsub.Validator) (string, pubsub.Validator) {
+// appropriate counters for the message and the peer which propagated it. Only rejected messages
+// lower the score of the peer, messages are ignored while the node is syncing, when the validation
+// times out, or when the validator marks them as ignored.
+func (r *Service) wrapAndReportValidation(topic string, v pubsub.Validator) (string, pubsub.Validator) {
 	return topic, func(ctx context.Context, pid peer.ID, msg *pubsub.Message) bool {
 		defer messagehandler.HandlePanic(ctx, msg)
 		ctx, _ = context.WithTimeout(ctx, pubsubMessageTimeout)
 		messageReceivedCounter.WithLabelValues(topic).Inc()
+		messageReceivedBytesCounter.WithLabelValues(topic).Add(float64(len(msg.Data)))
+		if pid != r.p2p.PeerID() {
+			r.p2p.Peers().IncrementMessagesReceived(pid)
+		}
+		syncing := r.initialSync.Syncing()
 		b := v(ctx, pid, msg)
+		_, ignored := msg.ValidatorData.(ignoredMessage)
+		ignored = !b && (ignored || syncing || ctx.Err() != nil)
+		switch {
+		case b:
+			messageAcceptedCounter.WithLabelValues(topic).Inc()
+		case ignored:
+			messageIgnoredCounter.WithLabelValues(topic).Inc()
+		default:
+			messageRejectedCounter.WithLabelValues(topic).Inc()
+		}
 		if !b {
 			messageFailedValidationCounter.WithLabelValues(topic).Inc()
 		}
+		if pid != r.p2p.PeerID() && !ignored {
+			if b {
+				r.p2p.Peers().Record(pid, peers.UsefulContribution)
+			} else {
+				r.p2p.Peers().Record(pid, peers.GossipValidationFailure)
+			}
+		}
 		return b
 	}
 }
 
-// subscribe to a dynamically increasing index of topics. This method expects a fmt compatible
-// string for the topic name and a maxID to represent the number of subscribed topics that should be
-// maintained. As the state feed emits a newly updated state, the maxID function will be called to
-// determine the appropriate number of topics. This method supports only sequential number ranges
-// for topics.
-func (r *Service) subscribeDynamic(topicFormat string, determineSubsLen func() int, validate pubsub.Validator, handle subHandler) {
+// subscribeDynamicWithSubnets subscribes to the attestation subnets needed by the validators of
+// this node, updating the subscriptions every slot. These are the long lived subnets of the
+// validators, and the subnets of the committees the validators attest in within the next few slots.
+// Subnets which are no longer needed are unsubscribed from, and peers are searched for on subnets
+// with too few of them. The topic format is expected to take the fork digest and the subnet index.
+func (r *Service) subscribeDynamicWithSubnets(topicFormat string, validate pubsub.Validator, handle subHandler) {
 	base := p2p.GossipTopicMappings[topicFormat]
 	if base == nil {
 		panic(fmt.Sprintf("%s is not mapped to any message in GossipTopicMappings", topicFormat))
 	}
 
-	var subscriptions []*pubsub.Subscription
-
-	stateChannel := make(chan *feed.Event, 1)
-	stateSub := r.stateNotifier.StateFeed().Subscribe(stateChannel)
+	forkDigest, err := r.forkDigest()
+	if err != nil {
+		log.WithError(err).Error("Could not compute fork digest")
+		return
+	}
+	subscriptions := make(map[uint64]*pubsub.Subscription, params.BeaconConfig().MaxCommitteesPerSlot)
+	genesis := r.chain.GenesisTime()
+	ticker := slotutil.GetSlotTicker(genesis, params.BeaconConfig().SecondsPerSlot)
 	go func() {
+		if !r.initialSync.Syncing() {
+			r.updateSubnetSubscriptions(subscriptions, slotutil.SlotsSinceGenesis(genesis), topicFormat, forkDigest, base, validate, handle)
+		}
 		for {
 			select {
 			case <-r.ctx.Done():
-				stateSub.Unsubscribe()
+				ticker.Done()
 				return
-			case <-stateChannel:
-				if r.chainStarted && r.initialSync.Syncing() {
+			case currentSlot := <-ticker.C():
+				if r.initialSync.Syncing() {
 					continue
 				}
-				// Update topic count.
-				wantedSubs := determineSubsLen()
-				// Resize as appropriate.
-				if len(subscriptions) > wantedSubs { // Reduce topics
-					var cancelSubs []*pubsub.Subscription
-					subscriptions, cancelSubs = subscriptions[:wantedSubs-1], subscriptions[wantedSubs:]
-					for i, sub := range cancelSubs {
-						sub.Cancel()
-						r.p2p.PubSub().UnregisterTopicValidator(fmt.Sprintf(topicFormat, i+wantedSubs))
-					}
-				} else if len(subscriptions) < wantedSubs { // Increase topics
-					for i := len(subscriptions); i < wantedSubs; i++ {
-						sub := r.subscribeWithBase(base, fmt.Sprintf(topicFormat, i), validate, handle)
-						subscriptions = append(subscriptions, sub)
-					}
-				}
+				r.updateSubnetSubscriptions(subscriptions, currentSlot, topicFormat, forkDigest, base, validate, handle)
 			}
 		}
 	}()
 }
+
+func (r *Service) updateSubnetSubscriptions(
+	subscriptions map[uint64]*pubsub.Subscription,
+	currentSlot uint64,
+	topicFormat string,
+	forkDigest [4]byte,
+	base proto.Message,
+	validate pubsub.Validator,
+	handle subHandler,
+) {
+	wantedSubnets := cache.SubnetIDs.GetAllSubnets()
+	for slot := currentSlot; slot <= currentSlot+subnetLookaheadSlots; slot++ {
+		wantedSubnets = sliceutil.UnionUint64(wantedSubnets, cache.SubnetIDs.GetAttesterSubnetIDs(slot))
+	}
+
+	for idx, sub := range subscriptions {
+		if sliceutil.IsInUint64(idx, wantedSubnets) {
+			continue
+		}
+		sub.Cancel()
+		topic := fmt.Sprintf(topicFormat, forkDigest, idx) + r.p2p.Encoding().ProtocolSuffix()
+		if err := r.p2p.PubSub().UnregisterTopicValidator(topic); err != nil {
+			log.WithError(err).WithField("topic", topic).Error("Failed to unregister validator")
+		}
+		delete(subscriptions, idx)
+	}
+
+	for _, idx := range wantedSubnets {
+		topic := fmt.Sprintf(topicFormat, forkDigest, idx)
+		if _, ok := subscriptions[idx]; !ok {
+			subscriptions[idx] = r.subscribeWithBase(base, topic, validate, handle)
+		}
+		if len(r.p2p.PubSub().ListPeers(topic+r.p2p.Encoding().ProtocolSuffix())) >= minPeersPerSubnet {
+			continue
+		}
+		r.findPeersWithSubnet(idx)
+	}
+}
+
+// findPeersWithSubnet searches for peers on the subnet in the background, unless a search for the
+// subnet is still in flight from a previous slot.
+func (r *Service) findPeersWithSubnet(idx uint64) {
+	r.subnetSearchLock.Lock()
+	defer r.subnetSearchLock.Unlock()
+	if r.subnetSearches[idx] {
+		return
+	}
+	r.subnetSearches[idx] = true
+	go func() {
+		defer func() {
+			r.subnetSearchLock.Lock()
+			delete(r.subnetSearches, idx)
+			r.subnetSearchLock.Unlock()
+		}()
+		if _, err := r.p2p.FindPeersWithSubnet(idx); err != nil {
+			log.WithError(err).WithField("subnet", idx).Error("Failed to find peers on subnet")
+		}
+	}()
+}
@@ -2,6 +2,7 @@ package sync
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/prysmaticlabs/go-bitfield"
 	"github.com/prysmaticlabs/go-ssz"
 	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
 	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
 	dbtest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
@@ -59,6 +61,7 @@ func TestService_committeeIndexBeaconAttestationSubscriber_ValidMessage(t *testi
 		attestationNotifier: (&mock.ChainService{}).OperationNotifier(),
 		initialSync:         &mockSync.Sync{IsSyncing: false},
 	}
+	cache.SubnetIDs.AddAttesterSubnetID(0, 0)
 	r.registerSubscribers()
 	r.stateNotifier.StateFeed().Send(&feed.Event{
 		Type: statefeed.Initialized,
@@ -76,7 +79,11 @@ func TestService_committeeIndexBeaconAttestationSubscriber_ValidMessage(t *testi
 		Signature:       sKeys[0].Sign([]byte("foo"), 0).Marshal(),
 	}
 
-	p.ReceivePubSub("/eth2/committee_index0_beacon_attestation", att)
+	digest, err := r.forkDigest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.ReceivePubSub(fmt.Sprintf("/eth2/%x/committee_index0_beacon_attestation", digest), att)
 
 	time.Sleep(time.Second)
 
@@ -2,14 +2,20 @@ package sync
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
 	pb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	mockChain "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
 	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
@@ -25,13 +31,19 @@ func TestSubscribe_ReceivesValidMessage(t *testing.T) {
 	r := Service{
 		ctx:         context.Background(),
 		p2p:         p2p,
+		chain:       &mockChain.ChainService{},
 		initialSync: &mockSync.Sync{IsSyncing: false},
 	}
-	topic := "/eth2/voluntary_exit"
+	topicFormat := "/eth2/%x/voluntary_exit"
+	digest, err := r.forkDigest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic := fmt.Sprintf(topicFormat, digest)
 	var wg sync.WaitGroup
 	wg.Add(1)
 
-	r.subscribe(topic, r.noopValidator, func(_ context.Context, msg proto.Message) error {
+	r.subscribe(topicFormat, r.noopValidator, func(_ context.Context, msg proto.Message) error {
 		m := msg.(*pb.SignedVoluntaryExit)
 		if m.Exit == nil || m.Exit.Epoch != 55 {
 			t.Errorf("Unexpected incoming message: %+v", m)
@@ -50,7 +62,7 @@ func TestSubscribe_ReceivesValidMessage(t *testing.T) {
 
 func TestSubscribe_WaitToSync(t *testing.T) {
 	p2p := p2ptest.NewTestP2P(t)
-	chainService := &mockChain.ChainService{}
+	chainService := &mockChain.ChainService{Genesis: time.Now()}
 	r := Service{
 		ctx:           context.Background(),
 		p2p:           p2p,
@@ -59,7 +71,11 @@ func TestSubscribe_WaitToSync(t *testing.T) {
 		initialSync:   &mockSync.Sync{IsSyncing: false},
 	}
 
-	topic := "/eth2/beacon_block"
+	digest, err := r.forkDigest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic := fmt.Sprintf("/eth2/%x/beacon_block", digest)
 	r.registerSubscribers()
 	i := r.stateNotifier.StateFeed().Send(&feed.Event{
 		Type: statefeed.Initialized,
@@ -95,15 +111,22 @@ func TestSubscribe_WaitToSync(t *testing.T) {
 func TestSubscribe_HandlesPanic(t *testing.T) {
 	p := p2ptest.NewTestP2P(t)
 	r := Service{
-		ctx: context.Background(),
-		p2p: p,
+		ctx:         context.Background(),
+		p2p:         p,
+		chain:       &mockChain.ChainService{},
+		initialSync: &mockSync.Sync{IsSyncing: false},
 	}
 
-	topic := p2p.GossipTypeMapping[reflect.TypeOf(&pb.SignedVoluntaryExit{})]
+	topicFormat := p2p.GossipTypeMapping[reflect.TypeOf(&pb.SignedVoluntaryExit{})]
+	digest, err := r.forkDigest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic := fmt.Sprintf(topicFormat, digest)
 	var wg sync.WaitGroup
 	wg.Add(1)
 
-	r.subscribe(topic, r.noopValidator, func(_ context.Context, msg proto.Message) error {
+	r.subscribe(topicFormat, r.noopValidator, func(_ context.Context, msg proto.Message) error {
 		defer wg.Done()
 		panic("bad")
 	})
@@ -114,3 +137,141 @@ func TestSubscribe_HandlesPanic(t *testing.T) {
 		t.Fatal("Did not receive PubSub in 1 second")
 	}
 }
+
+func TestSubscribeDynamicWithSubnets_FollowsDuties(t *testing.T) {
+	p := p2ptest.NewTestP2P(t)
+	r := Service{
+		ctx:            context.Background(),
+		p2p:            p,
+		initialSync:    &mockSync.Sync{IsSyncing: false},
+		subnetSearches: make(map[uint64]bool),
+	}
+	topicFormat := "/eth2/%x/committee_index%d_beacon_attestation"
+	base := p2p.GossipTopicMappings[topicFormat]
+	digest := [4]byte{1, 2, 3, 4}
+	topic := fmt.Sprintf(topicFormat, digest, 5) + p.Encoding().ProtocolSuffix()
+	subscribed := func() bool {
+		for _, tp := range p.PubSub().GetTopics() {
+			if tp == topic {
+				return true
+			}
+		}
+		return false
+	}
+
+	slot := uint64(1000)
+	cache.SubnetIDs.AddAttesterSubnetID(slot, 5)
+	subscriptions := make(map[uint64]*pubsub.Subscription)
+	r.updateSubnetSubscriptions(subscriptions, slot-subnetLookaheadSlots-1, topicFormat, digest, base, r.noopValidator, nil)
+	if len(subscriptions) != 0 {
+		t.Errorf("Expected no subscriptions before the lookahead, received %d", len(subscriptions))
+	}
+
+	r.updateSubnetSubscriptions(subscriptions, slot-subnetLookaheadSlots, topicFormat, digest, base, r.noopValidator, nil)
+	if _, ok := subscriptions[5]; !ok || len(subscriptions) != 1 {
+		t.Errorf("Expected subscription to subnet 5 ahead of the attester slot, received %v", subscriptions)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if !subscribed() {
+		t.Error("Expected to be subscribed to the subnet topic")
+	}
+
+	r.updateSubnetSubscriptions(subscriptions, slot+1, topicFormat, digest, base, r.noopValidator, nil)
+	if len(subscriptions) != 0 {
+		t.Errorf("Expected subscription to be cancelled after the attester slot, received %v", subscriptions)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if subscribed() {
+		t.Error("Expected to be unsubscribed from the subnet topic")
+	}
+}
+
+// subnetSearchP2P blocks subnet searches until released and counts them.
+type subnetSearchP2P struct {
+	*p2ptest.TestP2P
+	lock     sync.Mutex
+	searches int
+	release  chan struct{}
+	done     sync.WaitGroup
+}
+
+func (p *subnetSearchP2P) FindPeersWithSubnet(index uint64) (bool, error) {
+	defer p.done.Done()
+	p.lock.Lock()
+	p.searches++
+	p.lock.Unlock()
+	<-p.release
+	return false, nil
+}
+
+func TestFindPeersWithSubnet_DeduplicatesInFlightSearches(t *testing.T) {
+	p := &subnetSearchP2P{TestP2P: p2ptest.NewTestP2P(t), release: make(chan struct{})}
+	r := Service{
+		ctx:            context.Background(),
+		p2p:            p,
+		subnetSearches: make(map[uint64]bool),
+	}
+
+	p.done.Add(1)
+	r.findPeersWithSubnet(5)
+	r.findPeersWithSubnet(5)
+	close(p.release)
+	if testutil.WaitTimeout(&p.done, time.Second) {
+		t.Fatal("Did not finish the subnet search in 1 second")
+	}
+	if p.searches != 1 {
+		t.Errorf("Expected a single search while one is in flight, received %d", p.searches)
+	}
+
+	// The search is released from the in flight searches right after it returns.
+	time.Sleep(100 * time.Millisecond)
+	p.done.Add(1)
+	r.findPeersWithSubnet(5)
+	if testutil.WaitTimeout(&p.done, time.Second) {
+		t.Fatal("Did not finish the subnet search in 1 second")
+	}
+	if p.searches != 2 {
+		t.Errorf("Expected another search once the first one finished, received %d", p.searches)
+	}
+}
+
+func TestWrapAndReportValidation_ScoresOnlyRejectedMessages(t *testing.T) {
+	p := p2ptest.NewTestP2P(t)
+	r := Service{
+		p2p:         p,
+		initialSync: &mockSync.Sync{IsSyncing: false},
+	}
+	pid := peer.ID("remote")
+	p.Peers().Add(pid, nil, network.DirInbound)
+	score := func() float64 {
+		s, err := p.Peers().Score(pid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	newMessage := func() *pubsub.Message {
+		return &pubsub.Message{Message: &pubsubpb.Message{}}
+	}
+
+	_, ignoring := r.wrapAndReportValidation("ignored", func(_ context.Context, _ peer.ID, msg *pubsub.Message) bool {
+		return ignore(msg)
+	})
+	before := score()
+	if ignoring(context.Background(), pid, newMessage()) {
+		t.Error("Expected the ignored message not to be propagated")
+	}
+	if s := score(); s != before {
+		t.Errorf("Expected the score to stay at %v for an ignored message, received %v", before, s)
+	}
+
+	_, rejecting := r.wrapAndReportValidation("rejected", func(_ context.Context, _ peer.ID, _ *pubsub.Message) bool {
+		return false
+	})
+	if rejecting(context.Background(), pid, newMessage()) {
+		t.Error("Expected the rejected message not to be propagated")
+	}
+	if s := score(); s >= before {
+		t.Errorf("Expected the score to drop below %v for a rejected message, received %v", before, s)
+	}
+}
@@ -16,7 +16,6 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/attestationutil"
 	"github.com/prysmaticlabs/prysm/shared/bls"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
-	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/roughtime"
 	"github.com/prysmaticlabs/prysm/shared/traceutil"
@@ -54,20 +53,22 @@ func (r *Service) validateAggregateAndProof(ctx context.Context, pid peer.ID, ms
 	seen, err := r.attPool.HasAggregatedAttestation(m.Aggregate)
 	if err != nil {
 		traceutil.AnnotateError(span, err)
-		return false
+		return ignore(msg)
 	}
 	if seen {
-		return false
+		return ignore(msg)
 	}
 	if !r.validateBlockInAttestation(ctx, m) {
-		return false
+		return ignore(msg)
 	}
 
-	if !r.validateAggregatedAtt(ctx, m) {
+	set, ok := r.validateAggregatedAtt(ctx, msg, m)
+	if !ok {
 		return false
 	}
 
-	if !featureconfig.Get().DisableStrictAttestationPubsubVerification && !r.chain.IsValidAttestation(ctx, m.Aggregate) {
+	// Verify the selection proof and the aggregated attestation signatures along with other gossip messages.
+	if !r.validateWithBatchVerifier(ctx, "aggregate and proof", set) {
 		return false
 	}
 
@@ -76,7 +77,9 @@ func (r *Service) validateAggregateAndProof(ctx context.Context, pid peer.ID, ms
 	return true
 }
 
-func (r *Service) validateAggregatedAtt(ctx context.Context, a *ethpb.AggregateAttestationAndProof) bool {
+// validateAggregatedAtt runs the aggregate and proof validation steps which don't require signature
+// verification, and returns the signature sets of the selection proof and the aggregated attestation.
+func (r *Service) validateAggregatedAtt(ctx context.Context, msg *pubsub.Message, a *ethpb.AggregateAttestationAndProof) (*bls.SignatureBatch, bool) {
 	ctx, span := trace.StartSpan(ctx, "sync.validateAggregatedAtt")
 	defer span.End()
 
@@ -86,14 +89,14 @@ func (r *Service) validateAggregatedAtt(ctx context.Context, a *ethpb.AggregateA
 	currentSlot := uint64(roughtime.Now().Unix()-r.chain.GenesisTime().Unix()) / params.BeaconConfig().SecondsPerSlot
 	if attSlot > currentSlot || currentSlot > attSlot+params.BeaconConfig().AttestationPropagationSlotRange {
 		traceutil.AnnotateError(span, fmt.Errorf("attestation slot out of range %d <= %d <= %d", attSlot, currentSlot, attSlot+params.BeaconConfig().AttestationPropagationSlotRange))
-		return false
+		return nil, ignore(msg)
 
 	}
 
 	s, err := r.chain.HeadState(ctx)
 	if err != nil {
 		traceutil.AnnotateError(span, err)
-		return false
+		return nil, ignore(msg)
 	}
 
 	// Only advance state if different epoch as the committee can only change on an epoch transition.
@@ -101,34 +104,50 @@ func (r *Service) validateAggregatedAtt(ctx context.Context, a *ethpb.AggregateA
 		s, err = state.ProcessSlots(ctx, s, helpers.StartSlot(helpers.SlotToEpoch(attSlot)))
 		if err != nil {
 			traceutil.AnnotateError(span, err)
-			return false
+			return nil, ignore(msg)
 		}
 	}
 
 	// Verify validator index is within the aggregate's committee.
 	if err := validateIndexInCommittee(ctx, s, a.Aggregate, a.AggregatorIndex); err != nil {
 		traceutil.AnnotateError(span, errors.Wrapf(err, "Could not validate index in committee"))
-		return false
+		return nil, false
 	}
 
-	// Verify selection proof reflects to the right validator and signature is valid.
-	if err := validateSelection(ctx, s, a.Aggregate.Data, a.AggregatorIndex, a.SelectionProof); err != nil {
+	// Verify selection proof reflects to the right validator, its signature is verified with the batch.
+	set, err := selectionSignatureBatch(ctx, s, a.Aggregate.Data, a.AggregatorIndex, a.SelectionProof)
+	if err != nil {
 		traceutil.AnnotateError(span, errors.Wrapf(err, "Could not validate selection for validator %d", a.AggregatorIndex))
-		return false
+		return nil, false
 	}
 
-	// Verify aggregated attestation has a valid signature.
-	if err := blocks.VerifyAttestation(ctx, s, a.Aggregate); err != nil {
+	// Collect the signature set of the aggregated attestation.
+	attSet, err := blocks.AttestationSignatureBatch(ctx, s, a.Aggregate)
+	if err != nil {
 		traceutil.AnnotateError(span, err)
-		return false
+		return nil, false
 	}
 
+	return set.Join(attSet), true
+}
+
+// validAggregatedAttSignatures runs the aggregate and proof validation steps and verifies its signatures right
+// away, without waiting for other gossip messages to be batched with.
+func (r *Service) validAggregatedAttSignatures(ctx context.Context, a *ethpb.AggregateAttestationAndProof) bool {
+	set, ok := r.validateAggregatedAtt(ctx, nil /*msg*/, a)
+	if !ok {
+		return false
+	}
+	if err := set.Verify(); err != nil {
+		log.WithError(err).Debug("Could not verify aggregate and proof signature")
+		return false
+	}
 	return true
 }
 
 func (r *Service) validateBlockInAttestation(ctx context.Context, a *ethpb.AggregateAttestationAndProof) bool {
 	// Verify the block being voted and the processed state is in DB. The block should have passed validation if it's in the DB.
-	hasState := r.db.HasState(ctx, bytesutil.ToBytes32(a.Aggregate.Data.BeaconBlockRoot))
+	hasState := r.stateGen.HasState(ctx, bytesutil.ToBytes32(a.Aggregate.Data.BeaconBlockRoot))
 	hasBlock := r.db.HasBlock(ctx, bytesutil.ToBytes32(a.Aggregate.Data.BeaconBlockRoot))
 	if !(hasState && hasBlock) {
 		// A node doesn't have the block, it'll request from peer while saving the pending attestation to a queue.
@@ -165,41 +184,40 @@ func validateIndexInCommittee(ctx context.Context, s *stateTrie.BeaconState, a *
 	return nil
 }
 
-// This validates selection proof by validating it's from the correct validator index of the slot and selection
-// proof is a valid signature.
-func validateSelection(ctx context.Context, s *stateTrie.BeaconState, data *ethpb.AttestationData, validatorIndex uint64, proof []byte) error {
-	_, span := trace.StartSpan(ctx, "sync.validateSelection")
+// This validates selection proof by validating it's from the correct validator index of the slot, and returns
+// the signature set of the selection proof to be verified.
+func selectionSignatureBatch(ctx context.Context, s *stateTrie.BeaconState, data *ethpb.AttestationData, validatorIndex uint64, proof []byte) (*bls.SignatureBatch, error) {
+	_, span := trace.StartSpan(ctx, "sync.selectionSignatureBatch")
 	defer span.End()
 
 	committee, err := helpers.BeaconCommitteeFromState(s, data.Slot, data.CommitteeIndex)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	aggregator, err := helpers.IsAggregator(uint64(len(committee)), proof)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if !aggregator {
-		return fmt.Errorf("validator is not an aggregator for slot %d", data.Slot)
+		return nil, fmt.Errorf("validator is not an aggregator for slot %d", data.Slot)
 	}
 
 	domain := helpers.Domain(s.Fork(), helpers.SlotToEpoch(data.Slot), params.BeaconConfig().DomainBeaconAttester)
 	slotMsg, err := ssz.HashTreeRoot(data.Slot)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	pubkeyState := s.PubkeyAtIndex(validatorIndex)
 	pubKey, err := bls.PublicKeyFromBytes(pubkeyState[:])
 	if err != nil {
-		return err
+		return nil, err
 	}
 	slotSig, err := bls.SignatureFromBytes(proof)
 	if err != nil {
-		return err
-	}
-	if !slotSig.Verify(slotMsg[:], pubKey, domain) {
-		return errors.New("could not validate slot signature")
+		return nil, err
 	}
 
-	return nil
+	set := bls.NewSignatureBatch()
+	set.Add(slotSig, pubKey, slotMsg, domain, "selection proof")
+	return set, nil
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
 	p2ptest "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
 	beaconstate "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
 	mockSync "github.com/prysmaticlabs/prysm/beacon-chain/sync/initial-sync/testing"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/attestationutil"
@@ -74,7 +75,7 @@ func TestVerifySelection_NotAnAggregator(t *testing.T) {
 	data := &ethpb.AttestationData{}
 
 	wanted := "validator is not an aggregator for slot"
-	if err := validateSelection(ctx, beaconState, data, 0, sig.Marshal()); !strings.Contains(err.Error(), wanted) {
+	if _, err := selectionSignatureBatch(ctx, beaconState, data, 0, sig.Marshal()); !strings.Contains(err.Error(), wanted) {
 		t.Error("Did not receive wanted error")
 	}
 }
@@ -87,8 +88,12 @@ func TestVerifySelection_BadSignature(t *testing.T) {
 	sig := privKeys[0].Sign([]byte{}, 0)
 	data := &ethpb.AttestationData{}
 
-	wanted := "could not validate slot signature"
-	if err := validateSelection(ctx, beaconState, data, 0, sig.Marshal()); !strings.Contains(err.Error(), wanted) {
+	set, err := selectionSignatureBatch(ctx, beaconState, data, 0, sig.Marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wanted := "selection proof signature did not verify"
+	if err := set.Verify(); err == nil || !strings.Contains(err.Error(), wanted) {
 		t.Error("Did not receive wanted error")
 	}
 }
@@ -106,7 +111,11 @@ func TestVerifySelection_CanVerify(t *testing.T) {
 	domain := helpers.Domain(beaconState.Fork(), 0, params.BeaconConfig().DomainBeaconAttester)
 	sig := privKeys[0].Sign(slotRoot[:], domain)
 
-	if err := validateSelection(ctx, beaconState, data, 0, sig.Marshal()); err != nil {
+	set, err := selectionSignatureBatch(ctx, beaconState, data, 0, sig.Marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := set.Verify(); err != nil {
 		t.Fatal(err)
 	}
 }
@@ -132,6 +141,7 @@ func TestValidateAggregateAndProof_NoBlock(t *testing.T) {
 	r := &Service{
 		p2p:                  p,
 		db:                   db,
+		stateGen:             stategen.New(db),
 		initialSync:          &mockSync.Sync{IsSyncing: false},
 		attPool:              attestations.NewPool(),
 		blkRootToPendingAtts: make(map[[32]byte][]*ethpb.AggregateAttestationAndProof),
@@ -192,6 +202,7 @@ func TestValidateAggregateAndProof_NotWithinSlotRange(t *testing.T) {
 	r := &Service{
 		p2p:         p,
 		db:          db,
+		stateGen:    stategen.New(db),
 		initialSync: &mockSync.Sync{IsSyncing: false},
 		chain: &mock.ChainService{Genesis: time.Now(),
 			State: beaconState},
@@ -271,6 +282,7 @@ func TestValidateAggregateAndProof_ExistedInPool(t *testing.T) {
 		attPool:     attestations.NewPool(),
 		p2p:         p,
 		db:          db,
+		stateGen:    stategen.New(db),
 		initialSync: &mockSync.Sync{IsSyncing: false},
 		chain: &mock.ChainService{Genesis: time.Now(),
 			State: beaconState},
@@ -358,9 +370,13 @@ func TestValidateAggregateAndProof_CanValidate(t *testing.T) {
 	if err := beaconState.SetGenesisTime(uint64(time.Now().Unix())); err != nil {
 		t.Fatal(err)
 	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 	r := &Service{
+		ctx:         ctx,
 		p2p:         p,
 		db:          db,
+		stateGen:    stategen.New(db),
 		initialSync: &mockSync.Sync{IsSyncing: false},
 		chain: &mock.ChainService{Genesis: time.Now(),
 			State:            beaconState,
@@ -368,8 +384,10 @@ func TestValidateAggregateAndProof_CanValidate(t *testing.T) {
 			FinalizedCheckPoint: &ethpb.Checkpoint{
 				Epoch: 0,
 			}},
-		attPool: attestations.NewPool(),
+		attPool:       attestations.NewPool(),
+		signatureChan: make(chan *signatureVerifier, verifierLimit),
 	}
+	go r.verifierRoutine()
 
 	buf := new(bytes.Buffer)
 	if _, err := p.Encoding().Encode(buf, aggregateAndProof); err != nil {
@@ -385,7 +403,7 @@ func TestValidateAggregateAndProof_CanValidate(t *testing.T) {
 		},
 	}
 
-	if !r.validateAggregateAndProof(context.Background(), "", msg) {
+	if !r.validateAggregateAndProof(ctx, "", msg) {
 		t.Fatal("Validated status is false")
 	}
 
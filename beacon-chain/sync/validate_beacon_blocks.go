@@ -9,6 +9,7 @@ import (
 	"github.com/prysmaticlabs/go-ssz"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/traceutil"
 	"go.opencensus.io/trace"
 )
@@ -51,21 +52,27 @@ func (r *Service) validateBeaconBlockPubSub(ctx context.Context, pid peer.ID, ms
 		return false
 	}
 
+	// Ignore blocks which failed processing and their descendants. Honest peers relay a block
+	// before processing it, so they are not penalized for propagating it.
+	if r.hasBadBlock(blk.Block, blockRoot) {
+		return ignore(msg)
+	}
+
 	r.pendingQueueLock.RLock()
 	if r.seenPendingBlocks[blockRoot] {
 		r.pendingQueueLock.RUnlock()
-		return false
+		return ignore(msg)
 	}
 	r.pendingQueueLock.RUnlock()
 
 	if err := helpers.VerifySlotTime(uint64(r.chain.GenesisTime().Unix()), blk.Block.Slot); err != nil {
-		log.WithError(err).WithField("blockSlot", blk.Block.Slot).Warn("Rejecting incoming block.")
-		return false
+		log.WithError(err).WithField("blockSlot", blk.Block.Slot).Warn("Ignoring incoming block.")
+		return ignore(msg)
 	}
 
 	if r.chain.FinalizedCheckpt().Epoch > helpers.SlotToEpoch(blk.Block.Slot) {
-		log.Debug("Block older than finalized checkpoint received,rejecting it")
-		return false
+		log.Debug("Block older than finalized checkpoint received, ignoring it")
+		return ignore(msg)
 	}
 
 	if _, err = bls.SignatureFromBytes(blk.Signature); err != nil {
@@ -75,3 +82,16 @@ func (r *Service) validateBeaconBlockPubSub(ctx context.Context, pid peer.ID, ms
 	msg.ValidatorData = blk // Used in downstream subscriber
 	return true
 }
+
+// hasBadBlock returns true if the block or its parent failed processing. A block descending from
+// a bad block is marked as bad itself, so that its own descendants are rejected too.
+func (r *Service) hasBadBlock(blk *ethpb.BeaconBlock, blockRoot [32]byte) bool {
+	if r.chain.IsBadBlock(blockRoot) {
+		return true
+	}
+	if r.chain.IsBadBlock(bytesutil.ToBytes32(blk.ParentRoot)) {
+		r.chain.MarkBadBlock(blockRoot)
+		return true
+	}
+	return false
+}
@@ -7,9 +7,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/libp2p/go-libp2p-core/peer"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
 	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
 	dbtest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
@@ -160,6 +162,68 @@ func TestValidateBeaconBlockPubSub_ValidSignature(t *testing.T) {
 	}
 }
 
+func TestValidateBeaconBlockPubSub_IgnoreDescendantsOfBadBlocks(t *testing.T) {
+	db := dbtest.SetupDB(t)
+	defer dbtest.TeardownDB(t, db)
+	p := p2ptest.NewTestP2P(t)
+	ctx := context.Background()
+	b := []byte("sk")
+	b32 := bytesutil.ToBytes32(b)
+	sk, err := bls.SecretKeyFromBytes(b32[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	badRoot := testutil.Random32Bytes(t)
+	msg := &ethpb.SignedBeaconBlock{
+		Block: &ethpb.BeaconBlock{
+			ParentRoot: badRoot,
+		},
+		Signature: sk.Sign([]byte("data"), 0).Marshal(),
+	}
+	blockRoot, err := ssz.HashTreeRoot(msg.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain := &mock.ChainService{Genesis: time.Now(),
+		FinalizedCheckPoint: &ethpb.Checkpoint{
+			Epoch: 0,
+		}}
+	chain.MarkBadBlock(bytesutil.ToBytes32(badRoot))
+	r := &Service{
+		db:          db,
+		p2p:         p,
+		initialSync: &mockSync.Sync{IsSyncing: false},
+		chain:       chain,
+	}
+
+	buf := new(bytes.Buffer)
+	if _, err := p.Encoding().Encode(buf, msg); err != nil {
+		t.Fatal(err)
+	}
+	m := &pubsub.Message{
+		Message: &pubsubpb.Message{
+			Data: buf.Bytes(),
+			TopicIDs: []string{
+				p2p.GossipTypeMapping[reflect.TypeOf(msg)],
+			},
+		},
+	}
+	pid := peer.ID("sender")
+	if r.validateBeaconBlockPubSub(ctx, pid, m) {
+		t.Error("Expected false result, got true")
+	}
+	if !chain.IsBadBlock(blockRoot) {
+		t.Error("Expected the descendant of a bad block to be marked as bad")
+	}
+	if _, ok := m.ValidatorData.(ignoredMessage); !ok {
+		t.Error("Expected the block to be ignored")
+	}
+	if badResponses, _ := p.Peers().BadResponses(pid); badResponses > 0 {
+		t.Errorf("Expected the sender not to be penalized, got %d bad responses", badResponses)
+	}
+}
+
 func TestValidateBeaconBlockPubSub_Syncing(t *testing.T) {
 	db := dbtest.SetupDB(t)
 	defer dbtest.TeardownDB(t, db)
@@ -56,7 +56,13 @@ func (s *Service) validateCommitteeIndexBeaconAttestation(ctx context.Context, p
 	}
 
 	// The attestation's committee index (attestation.data.index) is for the correct subnet.
-	if !strings.HasPrefix(originalTopic, fmt.Sprintf(format, att.Data.CommitteeIndex)) {
+	forkDigest, err := s.forkDigest()
+	if err != nil {
+		log.WithError(err).Error("Failed to compute fork digest")
+		traceutil.AnnotateError(span, err)
+		return ignore(msg)
+	}
+	if !strings.HasPrefix(originalTopic, fmt.Sprintf(format, forkDigest, att.Data.CommitteeIndex)) {
 		return false
 	}
 
@@ -70,21 +76,28 @@ func (s *Service) validateCommitteeIndexBeaconAttestation(ctx context.Context, p
 	upper := att.Data.Slot + params.BeaconConfig().AttestationPropagationSlotRange
 	lower := att.Data.Slot
 	if currentSlot > upper || currentSlot < lower {
-		return false
+		return ignore(msg)
 	}
 
 	// Verify the block being voted and the processed state is in DB and. The block should have passed validation if it's in the DB.
-	hasState := s.db.HasState(ctx, bytesutil.ToBytes32(att.Data.BeaconBlockRoot))
+	hasState := s.stateGen.HasState(ctx, bytesutil.ToBytes32(att.Data.BeaconBlockRoot))
 	hasBlock := s.db.HasBlock(ctx, bytesutil.ToBytes32(att.Data.BeaconBlockRoot))
 	if !(hasState && hasBlock) {
 		// A node doesn't have the block, it'll request from peer while saving the pending attestation to a queue.
 		s.savePendingAtt(&eth.AggregateAttestationAndProof{Aggregate: att})
-		return false
+		return ignore(msg)
 	}
 
 	// Attestation's signature is a valid BLS signature and belongs to correct public key..
-	if !featureconfig.Get().DisableStrictAttestationPubsubVerification && !s.chain.IsValidAttestation(ctx, att) {
-		return false
+	if !featureconfig.Get().DisableStrictAttestationPubsubVerification {
+		set, err := s.chain.AttestationSignatureBatch(ctx, att)
+		if err != nil {
+			traceutil.AnnotateError(span, err)
+			return false
+		}
+		if !s.validateWithBatchVerifier(ctx, "attestation", set) {
+			return false
+		}
 	}
 
 	msg.ValidatorData = att
@@ -3,6 +3,7 @@ package sync
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	dbtest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
 	p2ptest "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
 	beaconstate "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
 	mockSync "github.com/prysmaticlabs/prysm/beacon-chain/sync/initial-sync/testing"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/params"
@@ -33,9 +35,14 @@ func TestService_validateCommitteeIndexBeaconAttestation(t *testing.T) {
 		initialSync:          &mockSync.Sync{IsSyncing: false},
 		p2p:                  p,
 		db:                   db,
+		stateGen:             stategen.New(db),
 		chain:                chain,
 		blkRootToPendingAtts: make(map[[32]byte][]*ethpb.AggregateAttestationAndProof),
 	}
+	digest, err := s.forkDigest()
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	blk := &ethpb.SignedBeaconBlock{
 		Block: &ethpb.BeaconBlock{
@@ -71,7 +78,7 @@ func TestService_validateCommitteeIndexBeaconAttestation(t *testing.T) {
 					Slot:            63,
 				},
 			},
-			topic:                     "/eth2/committee_index1_beacon_attestation",
+			topic:                     fmt.Sprintf("/eth2/%x/committee_index1_beacon_attestation", digest),
 			validAttestationSignature: true,
 			want:                      true,
 		},
@@ -85,7 +92,7 @@ func TestService_validateCommitteeIndexBeaconAttestation(t *testing.T) {
 					Slot:            63,
 				},
 			},
-			topic:                     "/eth2/committee_index3_beacon_attestation",
+			topic:                     fmt.Sprintf("/eth2/%x/committee_index3_beacon_attestation", digest),
 			validAttestationSignature: true,
 			want:                      false,
 		},
@@ -99,7 +106,7 @@ func TestService_validateCommitteeIndexBeaconAttestation(t *testing.T) {
 					Slot:            63,
 				},
 			},
-			topic:                     "/eth2/committee_index1_beacon_attestation",
+			topic:                     fmt.Sprintf("/eth2/%x/committee_index1_beacon_attestation", digest),
 			validAttestationSignature: true,
 			want:                      false,
 		},
@@ -113,7 +120,7 @@ func TestService_validateCommitteeIndexBeaconAttestation(t *testing.T) {
 					Slot:            63,
 				},
 			},
-			topic:                     "/eth2/committee_index1_beacon_attestation",
+			topic:                     fmt.Sprintf("/eth2/%x/committee_index1_beacon_attestation", digest),
 			validAttestationSignature: true,
 			want:                      false,
 		},
@@ -127,7 +134,7 @@ func TestService_validateCommitteeIndexBeaconAttestation(t *testing.T) {
 					Slot:            63,
 				},
 			},
-			topic:                     "/eth2/committee_index1_beacon_attestation",
+			topic:                     fmt.Sprintf("/eth2/%x/committee_index1_beacon_attestation", digest),
 			validAttestationSignature: false,
 			want:                      false,
 		},
@@ -44,17 +44,17 @@ func (r *Service) validateProposerSlashing(ctx context.Context, pid peer.ID, msg
 	// Retrieve head state, advance state to the epoch slot used specified in slashing message.
 	s, err := r.chain.HeadState(ctx)
 	if err != nil {
-		return false
+		return ignore(msg)
 	}
 	slashSlot := slashing.Header_1.Header.Slot
 	if s.Slot() < slashSlot {
 		if ctx.Err() != nil {
-			return false
+			return ignore(msg)
 		}
 		var err error
 		s, err = state.ProcessSlots(ctx, s, slashSlot)
 		if err != nil {
-			return false
+			return ignore(msg)
 		}
 	}
 
@@ -43,7 +43,7 @@ func (r *Service) validateVoluntaryExit(ctx context.Context, pid peer.ID, msg *p
 
 	s, err := r.chain.HeadState(ctx)
 	if err != nil {
-		return false
+		return ignore(msg)
 	}
 
 	exitedEpochSlot := exit.Exit.Epoch * params.BeaconConfig().SlotsPerEpoch
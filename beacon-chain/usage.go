@@ -91,6 +91,20 @@ var appHelpFlagGroups = []flagGroup{
 			flags.HTTPWeb3ProviderFlag,
 			flags.SetGCPercent,
 			flags.UnsafeSync,
+			flags.DBBackend,
+			flags.DBMigrationsDryRun,
+			flags.BackupRetentionCount,
+			flags.BackupRetentionAge,
+			flags.SlotsPerArchivedPoint,
+			flags.ExportSinks,
+			flags.ExportFileMaxSize,
+			flags.ExportWebhookRetries,
+			flags.CheckpointStateFlag,
+			flags.CheckpointBlockFlag,
+			flags.CheckpointBlockProviderFlag,
+			flags.CheckpointGenesisValidatorsRootFlag,
+			flags.DisableBackfillFlag,
+			flags.BackfillBatchSizeFlag,
 		},
 	},
 	{
@@ -106,6 +120,11 @@ var appHelpFlagGroups = []flagGroup{
 			cmd.EnableUPnPFlag,
 			cmd.P2PEncoding,
 			flags.MinSyncPeers,
+			flags.RPCRequestsPerSecondFlag,
+			flags.RPCRequestsBurstFlag,
+			flags.RPCBlocksPerSecondFlag,
+			flags.RPCBlocksBurstFactorFlag,
+			flags.P2PTraceDirFlag,
 		},
 	},
 	{
@@ -3,6 +3,7 @@ package bls_test
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/prysmaticlabs/prysm/shared/bls"
@@ -290,3 +291,66 @@ func TestPublicKey_Copy(t *testing.T) {
 		t.Fatal("Pubkey was mutated after copy")
 	}
 }
+
+func TestVerifyMultipleSignatures(t *testing.T) {
+	var sigs []*bls.Signature
+	var pubkeys []*bls.PublicKey
+	var msgs [][32]byte
+	var domains []uint64
+	for i := 0; i < 10; i++ {
+		msg := [32]byte{'h', 'e', 'l', 'l', 'o', byte(i)}
+		domain := uint64(i % 3)
+		priv := bls.RandKey()
+		sigs = append(sigs, priv.Sign(msg[:], domain))
+		pubkeys = append(pubkeys, priv.PublicKey())
+		msgs = append(msgs, msg)
+		domains = append(domains, domain)
+	}
+	valid, err := bls.VerifyMultipleSignatures(sigs, pubkeys, msgs, domains)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !valid {
+		t.Error("Signatures did not verify")
+	}
+
+	// Swapping two signatures keeps the aggregate signature unchanged, the batch check
+	// must still reject it.
+	sigs[0], sigs[1] = sigs[1], sigs[0]
+	valid, err = bls.VerifyMultipleSignatures(sigs, pubkeys, msgs, domains)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if valid {
+		t.Error("Expected swapped signatures not to verify")
+	}
+}
+
+func TestSignatureBatch_VerifyReportsInvalidSet(t *testing.T) {
+	batch := bls.NewSignatureBatch()
+	for i := 0; i < 5; i++ {
+		msg := [32]byte{'h', 'e', 'l', 'l', 'o', byte(i)}
+		priv := bls.RandKey()
+		batch.Add(priv.Sign(msg[:], 0), priv.PublicKey(), msg, 0, fmt.Sprintf("message %d", i))
+	}
+	if err := batch.Verify(); err != nil {
+		t.Fatalf("Could not verify valid batch: %v", err)
+	}
+
+	priv := bls.RandKey()
+	msg := [32]byte{'b', 'a', 'd'}
+	invalid := bls.NewSignatureBatch()
+	invalid.Add(priv.Sign(msg[:], 0), priv.PublicKey(), msg, 1, "bad message")
+	batch.Join(invalid)
+	if batch.Len() != 6 {
+		t.Errorf("Expected 6 signature sets, received %d", batch.Len())
+	}
+	want := "bad message signature did not verify"
+	err := batch.Verify()
+	if err == nil || err.Error() != want {
+		t.Errorf("Expected %q, received %v", want, err)
+	}
+	if sigErr, ok := err.(*bls.SignatureError); !ok || sigErr.Description != "bad message" {
+		t.Errorf("Expected a signature error for the bad message, received %#v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package bls
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	bls12 "github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+)
+
+// SignatureBatch holds signature sets, each a signature of a message under a domain by a
+// public key, to be verified together.
+type SignatureBatch struct {
+	Signatures   []*Signature
+	PublicKeys   []*PublicKey
+	Messages     [][32]byte
+	Domains      []uint64
+	Descriptions []string
+}
+
+// NewSignatureBatch creates an empty signature batch.
+func NewSignatureBatch() *SignatureBatch {
+	return &SignatureBatch{}
+}
+
+// Add a signature set to the batch. The description names the signed object in the error
+// returned when the signature is invalid.
+func (b *SignatureBatch) Add(sig *Signature, pub *PublicKey, msg [32]byte, domain uint64, description string) {
+	b.Signatures = append(b.Signatures, sig)
+	b.PublicKeys = append(b.PublicKeys, pub)
+	b.Messages = append(b.Messages, msg)
+	b.Domains = append(b.Domains, domain)
+	b.Descriptions = append(b.Descriptions, description)
+}
+
+// Join adds the signature sets of another batch to the batch.
+func (b *SignatureBatch) Join(other *SignatureBatch) *SignatureBatch {
+	b.Signatures = append(b.Signatures, other.Signatures...)
+	b.PublicKeys = append(b.PublicKeys, other.PublicKeys...)
+	b.Messages = append(b.Messages, other.Messages...)
+	b.Domains = append(b.Domains, other.Domains...)
+	b.Descriptions = append(b.Descriptions, other.Descriptions...)
+	return b
+}
+
+// SignatureError is returned when a signature set of a batch does not verify, it names the
+// signed object of the invalid set.
+type SignatureError struct {
+	Description string
+}
+
+func (e *SignatureError) Error() string {
+	return fmt.Sprintf("%s signature did not verify", e.Description)
+}
+
+// Len returns the number of signature sets in the batch.
+func (b *SignatureBatch) Len() int {
+	return len(b.Signatures)
+}
+
+// Verify the signature sets of the batch in a single check. When the batch does not verify,
+// the signature sets are verified one by one and the returned error describes the first
+// invalid one.
+func (b *SignatureBatch) Verify() error {
+	if featureconfig.Get().SkipBLSVerify || b.Len() == 0 {
+		return nil
+	}
+	if b.Len() > 1 {
+		valid, err := VerifyMultipleSignatures(b.Signatures, b.PublicKeys, b.Messages, b.Domains)
+		if err != nil {
+			return errors.Wrap(err, "could not verify signature batch")
+		}
+		if valid {
+			return nil
+		}
+	}
+	for i, sig := range b.Signatures {
+		if !sig.Verify(b.Messages[i][:], b.PublicKeys[i], b.Domains[i]) {
+			return &SignatureError{Description: b.Descriptions[i]}
+		}
+	}
+	// Valid signature sets always pass the batch check, this is not expected to happen.
+	return errors.New("signature batch did not verify")
+}
+
+// VerifyMultipleSignatures verifies signatures of different messages and domains with a
+// single multi-pairing check. Each signature and its public key are multiplied by a random
+// 64 bit scalar first, so that invalid signatures cannot cancel each other out in the
+// aggregate signature.
+func VerifyMultipleSignatures(sigs []*Signature, pubKeys []*PublicKey, msgs [][32]byte, domains []uint64) (bool, error) {
+	if featureconfig.Get().SkipBLSVerify {
+		return true, nil
+	}
+	size := len(sigs)
+	if size == 0 {
+		return false, nil
+	}
+	if len(pubKeys) != size || len(msgs) != size || len(domains) != size {
+		return false, fmt.Errorf(
+			"mismatched signature set lengths, %d signatures, %d public keys, %d messages and %d domains",
+			size, len(pubKeys), len(msgs), len(domains),
+		)
+	}
+
+	var aggregated *bls12.Sign
+	rawKeys := make([]bls12.PublicKey, size)
+	hashWithDomains := make([]byte, 0, size*concatMsgDomainSize)
+	for i := 0; i < size; i++ {
+		r, err := randomScalar()
+		if err != nil {
+			return false, err
+		}
+		sig, err := mulSignature(sigs[i].s, r)
+		if err != nil {
+			return false, err
+		}
+		pub, err := mulPublicKey(pubKeys[i].p, r)
+		if err != nil {
+			return false, err
+		}
+		if aggregated == nil {
+			aggregated = sig
+		} else {
+			aggregated.Add(sig)
+		}
+		rawKeys[i] = *pub
+		hashWithDomains = append(hashWithDomains, concatMsgAndDomain(msgs[i][:], domains[i])...)
+	}
+	return aggregated.VerifyAggregateHashWithDomain(rawKeys, hashWithDomains), nil
+}
+
+// randomScalar returns a non zero scalar of 63 random bits.
+func randomScalar() (*bls12.Fr, error) {
+	b := [8]byte{}
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, errors.Wrap(err, "could not read random bytes")
+	}
+	r := &bls12.Fr{}
+	r.SetInt64(int64(binary.LittleEndian.Uint64(b[:])&math.MaxInt64 | 1))
+	return r, nil
+}
+
+func mulSignature(sig *bls12.Sign, r *bls12.Fr) (*bls12.Sign, error) {
+	point := &bls12.G2{}
+	if err := point.Deserialize(sig.Serialize()); err != nil {
+		return nil, errors.Wrap(err, "could not convert signature to a curve point")
+	}
+	bls12.G2Mul(point, point, r)
+	res := &bls12.Sign{}
+	if err := res.Deserialize(point.Serialize()); err != nil {
+		return nil, errors.Wrap(err, "could not convert curve point to a signature")
+	}
+	return res, nil
+}
+
+func mulPublicKey(pub *bls12.PublicKey, r *bls12.Fr) (*bls12.PublicKey, error) {
+	point := &bls12.G1{}
+	if err := point.Deserialize(pub.Serialize()); err != nil {
+		return nil, errors.Wrap(err, "could not convert public key to a curve point")
+	}
+	bls12.G1Mul(point, point, r)
+	res := &bls12.PublicKey{}
+	if err := res.Deserialize(point.Serialize()); err != nil {
+		return nil, errors.Wrap(err, "could not convert curve point to a public key")
+	}
+	return res, nil
+}
@@ -46,7 +46,7 @@ var (
 	}
 	kafkaBootstrapServersFlag = cli.StringFlag{
 		Name:  "kafka-url",
-		Usage: "Stream attestations and blocks to specified kafka servers. This field is used for bootstrap.servers kafka config field.",
+		Usage: "Stream attestations and blocks to specified kafka servers. This field is used for bootstrap.servers kafka config field. Equivalent to --export-sink=blocks,attestations=kafka:<servers>.",
 	}
 	initSyncVerifyEverythingFlag = cli.BoolFlag{
 		Name: "initial-sync-verify-all-signatures",
@@ -95,6 +95,11 @@ type BeaconChainConfig struct {
 	DefaultPageSize           int           // DefaultPageSize defines the default page size for RPC server request.
 	MaxPeersToSync            int           // MaxPeersToSync describes the limit for number of peers in round robin sync.
 
+	// Networking constants.
+	RandomSubnetsPerValidator         uint64 // RandomSubnetsPerValidator is the number of long lived attestation subnets each validator keeps its node subscribed to.
+	EpochsPerRandomSubnetSubscription uint64 // EpochsPerRandomSubnetSubscription is the minimum number of epochs a validator stays subscribed to its long lived attestation subnets.
+	AttestationSubnetCount            uint64 // AttestationSubnetCount is the number of attestation subnets of the network.
+
 	// Slasher constants.
 	WeakSubjectivityPeriod    uint64 // WeakSubjectivityPeriod defines the time period expressed in number of epochs were proof of stake network should validate block headers and attestations for slashable events.
 	PruneSlasherStoragePeriod uint64 // PruneSlasherStoragePeriod defines the time period expressed in number of epochs were proof of stake network should prune attestation and block header store.
@@ -186,6 +191,11 @@ var defaultBeaconConfig = &BeaconChainConfig{
 	DefaultPageSize:           250,
 	MaxPeersToSync:            15,
 
+	// Networking values.
+	RandomSubnetsPerValidator:         1,
+	EpochsPerRandomSubnetSubscription: 256,
+	AttestationSubnetCount:            64,
+
 	// Slasher related values.
 	WeakSubjectivityPeriod:    54000,
 	PruneSlasherStoragePeriod: 10,
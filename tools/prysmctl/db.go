@@ -0,0 +1,423 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	"github.com/prysmaticlabs/prysm/beacon-chain/node"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/cmd"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+const (
+	jsonFormat = "json"
+	sszFormat  = "ssz"
+)
+
+var (
+	rootFlag = cli.StringFlag{
+		Name:  "root",
+		Usage: "Hex encoded block root of the object to print.",
+	}
+	slotFlag = cli.Int64Flag{
+		Name:  "slot",
+		Usage: "Slot of the object to print.",
+		Value: -1,
+	}
+	formatFlag = cli.StringFlag{
+		Name:  "format",
+		Usage: "Output format, json or ssz.",
+		Value: jsonFormat,
+	}
+	outFlag = cli.StringFlag{
+		Name:  "out",
+		Usage: "File to write the output to instead of stdout.",
+	}
+
+	marshaler = &jsonpb.Marshaler{Indent: "  "}
+)
+
+var dbCommand = cli.Command{
+	Name:  "db",
+	Usage: "inspects the beacon node database in the data directory",
+	Subcommands: cli.Commands{
+		{
+			Name:   "buckets",
+			Usage:  "lists the buckets of the database with their key counts and sizes",
+			Action: withDB(printBuckets),
+		},
+		{
+			Name:   "block",
+			Usage:  "prints the block with a root or the blocks at a slot",
+			Flags:  []cli.Flag{rootFlag, slotFlag, formatFlag, outFlag},
+			Action: withDB(printBlock),
+		},
+		{
+			Name: "state",
+			Usage: "exports the state of a block root, or the state at a finalized slot. States which " +
+				"are not saved in full are regenerated from the blocks in the database",
+			Flags:  []cli.Flag{rootFlag, slotFlag, formatFlag, outFlag},
+			Action: withDB(printState),
+		},
+		{
+			Name:   "checkpoints",
+			Usage:  "prints the genesis, head, justified and finalized checkpoints",
+			Action: withDB(printCheckpoints),
+		},
+		{
+			Name:   "deposit-contract",
+			Usage:  "prints the deposit contract address",
+			Action: withDB(printDepositContract),
+		},
+		{
+			Name:   "powchain",
+			Usage:  "prints the saved ETH1 chain data as json",
+			Flags:  []cli.Flag{outFlag},
+			Action: withDB(printPowchainData),
+		},
+		{
+			Name:   "verify-chain",
+			Usage:  "walks the chain from the head block to genesis and checks the parent links of every block",
+			Action: withDB(verifyChain),
+		},
+	},
+}
+
+// withDB opens the database of the data directory read-only for the duration of a command, so
+// that inspecting it never migrates or otherwise modifies it.
+func withDB(action func(cliCtx *cli.Context, d db.Database) error) func(*cli.Context) error {
+	return func(cliCtx *cli.Context) error {
+		backend := cliCtx.GlobalString(flags.DBBackend.Name)
+		if backend == db.MemoryBackend {
+			return errors.New("the memory backend does not persist any data to inspect")
+		}
+		flags.Init(&flags.GlobalFlags{DBBackend: backend})
+		dbPath := path.Join(cliCtx.GlobalString(cmd.DataDirFlag.Name), node.BeaconChainDBName)
+		if _, err := os.Stat(dbPath); err != nil {
+			return errors.Wrap(err, "could not find beacon node database")
+		}
+		d, err := db.NewReadOnlyDB(dbPath)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := d.Close(); err != nil {
+				log.WithError(err).Error("Failed to close database")
+			}
+		}()
+		return action(cliCtx, d)
+	}
+}
+
+func printBuckets(cliCtx *cli.Context, d db.Database) error {
+	stats, err := d.BucketStats(context.Background())
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "BUCKET\tKEYS\tSIZE (BYTES)")
+	for _, s := range stats {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", s.Name, s.KeyCount, s.Size)
+	}
+	return w.Flush()
+}
+
+func printBlock(cliCtx *cli.Context, d db.Database) error {
+	ctx := context.Background()
+	var blocks []*ethpb.SignedBeaconBlock
+	switch {
+	case cliCtx.IsSet(rootFlag.Name):
+		root, err := parseRoot(cliCtx.String(rootFlag.Name))
+		if err != nil {
+			return err
+		}
+		b, err := d.Block(ctx, root)
+		if err != nil {
+			return err
+		}
+		if b == nil {
+			return fmt.Errorf("no block with root %#x", root)
+		}
+		blocks = append(blocks, b)
+	case cliCtx.Int64(slotFlag.Name) >= 0:
+		var err error
+		blocks, err = blocksAtSlot(ctx, d, uint64(cliCtx.Int64(slotFlag.Name)))
+		if err != nil {
+			return err
+		}
+		if len(blocks) == 0 {
+			return fmt.Errorf("no block at slot %d", cliCtx.Int64(slotFlag.Name))
+		}
+	default:
+		return fmt.Errorf("--%s or --%s is required", rootFlag.Name, slotFlag.Name)
+	}
+
+	if cliCtx.String(formatFlag.Name) == sszFormat && len(blocks) > 1 {
+		return fmt.Errorf("%d blocks at slot %d, select one with --%s", len(blocks), cliCtx.Int64(slotFlag.Name), rootFlag.Name)
+	}
+	buf := bytes.NewBuffer(nil)
+	for _, b := range blocks {
+		enc, err := encode(cliCtx.String(formatFlag.Name), b)
+		if err != nil {
+			return err
+		}
+		buf.Write(enc)
+	}
+	return writeOutput(cliCtx, buf.Bytes())
+}
+
+func printState(cliCtx *cli.Context, d db.Database) error {
+	ctx := context.Background()
+	sg := stategen.New(d)
+	var st *state.BeaconState
+	var err error
+	switch {
+	case cliCtx.IsSet(rootFlag.Name):
+		root, err := parseRoot(cliCtx.String(rootFlag.Name))
+		if err != nil {
+			return err
+		}
+		st, err = sg.StateByRoot(ctx, root)
+		if err != nil {
+			return err
+		}
+	case cliCtx.Int64(slotFlag.Name) >= 0:
+		slot := uint64(cliCtx.Int64(slotFlag.Name))
+		st, err = sg.StateBySlot(ctx, slot)
+		if err == stategen.ErrHotSlot {
+			// Unfinalized slots are only unambiguous if a single block was saved at the slot.
+			blocks, err := blocksAtSlot(ctx, d, slot)
+			if err != nil {
+				return err
+			}
+			if len(blocks) != 1 {
+				return fmt.Errorf("%d blocks at unfinalized slot %d, select one with --%s", len(blocks), slot, rootFlag.Name)
+			}
+			root, err := ssz.HashTreeRoot(blocks[0].Block)
+			if err != nil {
+				return err
+			}
+			st, err = sg.StateByRoot(ctx, root)
+			if err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("--%s or --%s is required", rootFlag.Name, slotFlag.Name)
+	}
+
+	enc, err := encode(cliCtx.String(formatFlag.Name), st.InnerStateUnsafe())
+	if err != nil {
+		return err
+	}
+	return writeOutput(cliCtx, enc)
+}
+
+func printCheckpoints(cliCtx *cli.Context, d db.Database) error {
+	ctx := context.Background()
+	genesis, err := d.GenesisBlock(ctx)
+	if err != nil {
+		return err
+	}
+	head, err := d.HeadBlock(ctx)
+	if err != nil {
+		return err
+	}
+	for _, b := range []struct {
+		name  string
+		block *ethpb.SignedBeaconBlock
+	}{{"genesis", genesis}, {"head", head}} {
+		if b.block == nil {
+			fmt.Printf("%-10s none\n", b.name)
+			continue
+		}
+		root, err := ssz.HashTreeRoot(b.block.Block)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%-10s slot=%d root=%#x\n", b.name, b.block.Block.Slot, root)
+	}
+	justified, err := d.JustifiedCheckpoint(ctx)
+	if err != nil {
+		return err
+	}
+	finalized, err := d.FinalizedCheckpoint(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%-10s epoch=%d root=%#x\n", "justified", justified.Epoch, justified.Root)
+	fmt.Printf("%-10s epoch=%d root=%#x\n", "finalized", finalized.Epoch, finalized.Root)
+	return nil
+}
+
+func printDepositContract(cliCtx *cli.Context, d db.Database) error {
+	addr, err := d.DepositContractAddress(context.Background())
+	if err != nil {
+		return err
+	}
+	if addr == nil {
+		return errors.New("no deposit contract address saved")
+	}
+	fmt.Printf("%#x\n", addr)
+	return nil
+}
+
+func printPowchainData(cliCtx *cli.Context, d db.Database) error {
+	data, err := d.PowchainData(context.Background())
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return errors.New("no powchain data saved")
+	}
+	enc, err := encode(jsonFormat, data)
+	if err != nil {
+		return err
+	}
+	return writeOutput(cliCtx, enc)
+}
+
+// verifyChain walks from the head block to the genesis block, checking that the parent of every
+// block is in the database at a lower slot and is stored under its own hash tree root.
+func verifyChain(cliCtx *cli.Context, d db.Database) error {
+	ctx := context.Background()
+	genesis, err := d.GenesisBlock(ctx)
+	if err != nil {
+		return err
+	}
+	if genesis == nil {
+		return errors.New("no genesis block saved")
+	}
+	genesisRoot, err := ssz.HashTreeRoot(genesis.Block)
+	if err != nil {
+		return err
+	}
+	head, err := d.HeadBlock(ctx)
+	if err != nil {
+		return err
+	}
+	if head == nil {
+		return errors.New("no head block saved")
+	}
+	root, err := ssz.HashTreeRoot(head.Block)
+	if err != nil {
+		return err
+	}
+
+	block := head
+	count := 1
+	for root != genesisRoot {
+		if block.Block.Slot == 0 {
+			return fmt.Errorf("block %#x at slot 0 is not the genesis block %#x", root, genesisRoot)
+		}
+		parentRoot := bytesutil.ToBytes32(block.Block.ParentRoot)
+		parent, err := d.Block(ctx, parentRoot)
+		if err != nil {
+			return err
+		}
+		if parent == nil {
+			return fmt.Errorf("parent %#x of block %#x at slot %d is missing", parentRoot, root, block.Block.Slot)
+		}
+		if parent.Block.Slot >= block.Block.Slot {
+			return fmt.Errorf(
+				"parent %#x at slot %d of block %#x is not at a lower slot than %d",
+				parentRoot,
+				parent.Block.Slot,
+				root,
+				block.Block.Slot,
+			)
+		}
+		computed, err := ssz.HashTreeRoot(parent.Block)
+		if err != nil {
+			return err
+		}
+		if computed != parentRoot {
+			return fmt.Errorf("block at slot %d is stored under root %#x instead of %#x", parent.Block.Slot, parentRoot, computed)
+		}
+		root, block = parentRoot, parent
+		count++
+		if count%10000 == 0 {
+			log.WithField("slot", block.Block.Slot).Info("Verifying chain")
+		}
+	}
+	log.WithFields(logrus.Fields{
+		"blocks":   count,
+		"headSlot": head.Block.Slot,
+	}).Info("Chain from head to genesis is intact")
+	return nil
+}
+
+// blocksAtSlot returns every block saved at the slot, which may be more than one before the slot
+// is finalized.
+func blocksAtSlot(ctx context.Context, d db.Database, slot uint64) ([]*ethpb.SignedBeaconBlock, error) {
+	// A slot range ending at 0 is unbounded, so the genesis block is looked up directly.
+	if slot == 0 {
+		genesis, err := d.GenesisBlock(ctx)
+		if err != nil || genesis == nil {
+			return nil, err
+		}
+		return []*ethpb.SignedBeaconBlock{genesis}, nil
+	}
+	return d.Blocks(ctx, filters.NewFilter().SetStartSlot(slot).SetEndSlot(slot))
+}
+
+func parseRoot(s string) ([32]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "invalid root")
+	}
+	if len(b) != 32 {
+		return [32]byte{}, fmt.Errorf("invalid root length %d, expected 32 bytes", len(b))
+	}
+	return bytesutil.ToBytes32(b), nil
+}
+
+func encode(format string, msg proto.Message) ([]byte, error) {
+	switch format {
+	case jsonFormat:
+		buf := bytes.NewBuffer(nil)
+		if err := marshaler.Marshal(buf, msg); err != nil {
+			return nil, err
+		}
+		buf.WriteByte('\n')
+		return buf.Bytes(), nil
+	case sszFormat:
+		return ssz.Marshal(msg)
+	default:
+		return nil, fmt.Errorf("unknown format %q, expected %s or %s", format, jsonFormat, sszFormat)
+	}
+}
+
+func writeOutput(cliCtx *cli.Context, data []byte) error {
+	out := cliCtx.String(outFlag.Name)
+	if out == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := ioutil.WriteFile(out, data, 0600); err != nil {
+		return err
+	}
+	log.WithField("file", out).Info("Wrote output")
+	return nil
+}
@@ -0,0 +1,43 @@
+// Prysmctl is a command line tool for inspecting the data of a Prysm beacon node. The beacon
+// node must be stopped while its database is inspected, but not while its p2p trace is.
+//
+// Usage:
+//   prysmctl --datadir=/path/to/datadir db buckets
+//   prysmctl --datadir=/path/to/datadir db block --slot=100 --format=json
+//   prysmctl --datadir=/path/to/datadir db state --slot=100 --out=state.ssz
+//   prysmctl --datadir=/path/to/datadir db verify-chain
+//   prysmctl trace --dir=/path/to/tracedir --message-id=<id>
+//   prysmctl trace --dir=/path/to/tracedir --peer=<peer id>
+package main
+
+import (
+	"os"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	"github.com/prysmaticlabs/prysm/shared/cmd"
+	"github.com/prysmaticlabs/prysm/shared/version"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var log = logrus.WithField("prefix", "prysmctl")
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "prysmctl"
+	app.Usage = "command line tool for inspecting the data of a beacon node"
+	app.Version = version.GetVersion()
+	app.Flags = []cli.Flag{
+		cmd.DataDirFlag,
+		flags.DBBackend,
+	}
+	app.Commands = []cli.Command{
+		dbCommand,
+		traceCommand,
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/tracer"
+	"github.com/urfave/cli"
+)
+
+var (
+	traceDirFlag = cli.StringFlag{
+		Name:  "dir",
+		Usage: "Directory of the trace, as passed to the beacon node with --p2p-trace-dir.",
+	}
+	messageIDFlag = cli.StringFlag{
+		Name:  "message-id",
+		Usage: "Only print the events of the gossip message with this ID.",
+	}
+	peerFlag = cli.StringFlag{
+		Name:  "peer",
+		Usage: "Only print the events of the peer with this ID.",
+	}
+)
+
+var traceCommand = cli.Command{
+	Name: "trace",
+	Usage: "prints the gossip and req/resp events traced by a beacon node, optionally filtered by message " +
+		"or peer. The beacon node may keep running while its trace is read",
+	Flags:  []cli.Flag{traceDirFlag, messageIDFlag, peerFlag},
+	Action: printTrace,
+}
+
+func printTrace(cliCtx *cli.Context) error {
+	dir := cliCtx.String(traceDirFlag.Name)
+	if dir == "" {
+		return fmt.Errorf("--%s is required", traceDirFlag.Name)
+	}
+	messageID := cliCtx.String(messageIDFlag.Name)
+	pid := cliCtx.String(peerFlag.Name)
+	records, err := tracer.Read(dir, func(r *tracer.Record) bool {
+		return (messageID == "" || r.MessageID == messageID) && (pid == "" || r.PeerID == pid)
+	})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tTYPE\tDIRECTION\tPEER\tTOPIC\tMESSAGE ID\tSIZE\tREASON")
+	for _, r := range records {
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+			r.Time.UTC().Format(time.RFC3339Nano), r.Type, r.Direction, r.PeerID, r.Topic, r.MessageID, r.Size, r.Reason,
+		)
+	}
+	return w.Flush()
+}